@@ -0,0 +1,226 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// APIModel is a language-neutral view of all endpoints under one ServerAPI.
+// APIModel 是 ServerAPI 下全部端点的语言无关元数据视图。
+type APIModel struct {
+	BasePath  string             `json:"basePath"`
+	GroupPath string             `json:"groupPath"`
+	Endpoints []APIEndpointModel `json:"endpoints"`
+}
+
+// APIEndpointModel describes one HTTP endpoint in APIModel.
+// APIEndpointModel 描述 APIModel 中的单个 HTTP 端点。
+type APIEndpointModel struct {
+	Name               string             `json:"name"`
+	FuncName           string             `json:"funcName"`
+	Method             HTTPMethod         `json:"method"`
+	Path               string             `json:"path"`
+	FullPath           string             `json:"fullPath"`
+	Description        string             `json:"description,omitempty"`
+	RequestDescription string             `json:"requestDescription,omitempty"`
+	PathParams         []APIParamModel    `json:"pathParams,omitempty"`
+	QueryParams        []APIParamModel    `json:"queryParams,omitempty"`
+	HeaderParams       []APIParamModel    `json:"headerParams,omitempty"`
+	CookieParams       []APIParamModel    `json:"cookieParams,omitempty"`
+	RequestType        string             `json:"requestType,omitempty"`
+	RequestKind        TSKind             `json:"requestKind"`
+	ResponseKind       TSKind             `json:"responseKind"`
+	Responses          []APIResponseModel `json:"responses"`
+}
+
+// APIParamModel describes one path/query/header/cookie parameter.
+// APIParamModel 描述一个 path/query/header/cookie 参数。
+type APIParamModel struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// APIResponseModel describes one declared response.
+// APIResponseModel 描述一个已声明的响应。
+type APIResponseModel struct {
+	StatusCode  int    `json:"statusCode"`
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+}
+
+// CollectAPIModel walks all endpoints and builds an APIModel.
+// Type names are the same names used by the generated TypeScript.
+// CollectAPIModel 遍历全部端点并构建 APIModel，类型名与生成的 TS 保持一致。
+func (s ServerAPI) CollectAPIModel() (APIModel, error) {
+	endpoints := s.Endpoints
+	registry := newTSInterfaceRegistry()
+	model := APIModel{
+		BasePath:  normalizePathSegment(s.BasePath),
+		GroupPath: normalizePathSegment(s.GroupPath),
+		Endpoints: make([]APIEndpointModel, 0, len(endpoints)),
+	}
+	fullPathPrefix := resolveAPIPath(model.BasePath, model.GroupPath)
+
+	for i, e := range endpoints {
+		if !isEndpointEnabled(e) {
+			continue
+		}
+		meta := e.EndpointMeta()
+		if err := validateEndpointMeta(meta); err != nil {
+			return APIModel{}, fmt.Errorf("endpoint[%d] validation failed: %w", i, err)
+		}
+		requestKind, responseKind := resolveEndpointKinds(e)
+
+		item := APIEndpointModel{
+			Name:               meta.Name,
+			FuncName:           toLowerCamel(schemaBaseName(meta, i)),
+			Method:             meta.Method,
+			Path:               meta.Path,
+			FullPath:           joinURLPath(fullPathPrefix, meta.Path),
+			Description:        strings.TrimSpace(meta.Description),
+			RequestDescription: strings.TrimSpace(meta.RequestDescription),
+			RequestKind:        requestKind,
+			ResponseKind:       responseKind,
+		}
+
+		var err error
+		if item.PathParams, err = collectParamModels(registry, meta.PathParamsType, "uri"); err != nil {
+			return APIModel{}, fmt.Errorf("collect path params for endpoint[%d]: %w", i, err)
+		}
+		if item.QueryParams, err = collectParamModels(registry, meta.QueryParamsType, "form"); err != nil {
+			return APIModel{}, fmt.Errorf("collect query params for endpoint[%d]: %w", i, err)
+		}
+		if item.HeaderParams, err = collectParamModels(registry, meta.HeaderParamsType, "header"); err != nil {
+			return APIModel{}, fmt.Errorf("collect header params for endpoint[%d]: %w", i, err)
+		}
+		if item.CookieParams, err = collectParamModels(registry, meta.CookieParamsType, "cookie"); err != nil {
+			return APIModel{}, fmt.Errorf("collect cookie params for endpoint[%d]: %w", i, err)
+		}
+		if isValidType(meta.RequestBodyType) {
+			if item.RequestType, _, err = tsTypeFromType(meta.RequestBodyType, registry); err != nil {
+				return APIModel{}, fmt.Errorf("build request type for endpoint[%d]: %w", i, err)
+			}
+		}
+
+		item.Responses = make([]APIResponseModel, 0, len(meta.Responses))
+		for j, r := range meta.Responses {
+			respType := "void"
+			if r.BodyType != nil && r.BodyType.Kind() != reflect.Invalid && !isNoType(r.BodyType) {
+				if respType, _, err = tsTypeFromType(r.BodyType, registry); err != nil {
+					return APIModel{}, fmt.Errorf("build response[%d] type for endpoint[%d]: %w", j, i, err)
+				}
+			}
+			item.Responses = append(item.Responses, APIResponseModel{
+				StatusCode:  r.StatusCode,
+				Type:        respType,
+				Description: strings.TrimSpace(r.Description),
+			})
+		}
+		model.Endpoints = append(model.Endpoints, item)
+	}
+	return model, nil
+}
+
+// ExportMetadataJSON writes the APIModel of all endpoints to a relative JSON file.
+// ExportMetadataJSON 将全部端点的 APIModel 写入相对路径的 JSON 文件。
+func (s ServerAPI) ExportMetadataJSON(relativePath string) error {
+	if !shouldExportTSInCurrentEnv() {
+		return nil
+	}
+	if strings.TrimSpace(relativePath) == "" {
+		return fmt.Errorf("relative json path is required")
+	}
+	if filepath.IsAbs(relativePath) {
+		return fmt.Errorf("json file path must be relative to cwd")
+	}
+	model, err := s.CollectAPIModel()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(model, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeRelativeTSFile(relativePath, string(data)+"\n")
+}
+
+func resolveEndpointKinds(e EndpointLike) (TSKind, TSKind) {
+	requestKind := TSKindJSON
+	responseKind := TSKindJSON
+	if hintProvider, ok := e.(EndpointTSHintsProvider); ok {
+		hints := hintProvider.EndpointTSHints()
+		if hints.RequestKind != "" {
+			requestKind = hints.RequestKind
+		}
+		if hints.ResponseKind != "" {
+			responseKind = hints.ResponseKind
+		}
+	}
+	// FormData marker bodies and structs with file fields are always multipart, even without an explicit hint.
+	// StreamRequest marker bodies are always streamed.
+	if requestKind == TSKindJSON {
+		bodyType := e.EndpointMeta().RequestBodyType
+		switch {
+		case isMultipartBodyType(bodyType):
+			requestKind = TSKindMultipart
+		case bodyType == reflect.TypeOf(StreamRequest{}):
+			requestKind = TSKindStream
+		}
+	}
+	return requestKind, responseKind
+}
+
+func isMultipartBodyType(t reflect.Type) bool {
+	if !isValidType(t) {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t == reflect.TypeOf(FormData{}) || hasMultipartFileFields(t)
+}
+
+func collectParamModels(registry *tsInterfaceRegistry, t reflect.Type, primaryTag string) ([]APIParamModel, error) {
+	if !isValidType(t) {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	out := make([]APIParamModel, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, ok := resolveParamFieldName(f, primaryTag)
+		if !ok {
+			continue
+		}
+		_, optional, _ := jsonFieldMeta(f)
+		fieldType, _, err := tsTypeFromType(f.Type, registry)
+		if err != nil {
+			return nil, err
+		}
+		if unionValues, ok, err := tsUnionValuesFromField(f); err != nil {
+			return nil, err
+		} else if ok {
+			fieldType = tsUnionType(unionValues)
+		}
+		out = append(out, APIParamModel{
+			Name:        name,
+			Type:        fieldType,
+			Required:    !optional,
+			Description: strings.TrimSpace(f.Tag.Get("tsdoc")),
+		})
+	}
+	return out, nil
+}
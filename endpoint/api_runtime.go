@@ -3,6 +3,8 @@ package endpoint
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -58,6 +60,138 @@ func (s ServerAPI) ExportTS(relativeTSPath string) error {
 	return exportAxiosFromEndpointsToTSFile(s.BasePath, s.GroupPath, s.Endpoints, relativeTSPath)
 }
 
+// ClientKind selects the HTTP library a generated TS client is built on.
+// ClientKind 选择生成的 TS 客户端所基于的 HTTP 库。
+type ClientKind string
+
+const (
+	// ClientKindAxios generates the axios client, as ExportTS does.
+	// ClientKindAxios 生成 axios 客户端（与 ExportTS 相同）。
+	ClientKindAxios ClientKind = "axios"
+	// ClientKindFetch generates the dependency-free Fetch API client, as ExportFetchTS does.
+	// ClientKindFetch 生成无依赖的 Fetch API 客户端（与 ExportFetchTS 相同）。
+	ClientKindFetch ClientKind = "fetch"
+)
+
+// TSExportOptions controls the features emitted by ServerAPI.ExportTSWithOptions and ExportUnifiedAPIsToTSFiles.
+// The zero value matches ExportTS: an axios client with validators and date normalization.
+// TSExportOptions 控制 ServerAPI.ExportTSWithOptions 与 ExportUnifiedAPIsToTSFiles 输出的功能；
+// 零值与 ExportTS 一致：包含校验函数与日期转换的 axios 客户端。
+type TSExportOptions struct {
+	// OmitValidators drops validateX/ensureX and the validation of SSE events.
+	// OmitValidators 不生成 validateX/ensureX，也不校验 SSE 事件。
+	OmitValidators bool
+	// OmitDateParsing keeps time.Time response fields as strings instead of turning them into Date.
+	// OmitDateParsing 保留 time.Time 响应字段为字符串，不转换为 Date。
+	OmitDateParsing bool
+	// EmitVueComposables adds a Vue 3 composable (useXxx) per HTTP endpoint and imports vue; axios client only.
+	// EmitVueComposables 为每个 HTTP 端点额外生成 Vue 3 组合式函数(useXxx)并导入 vue；仅支持 axios 客户端。
+	EmitVueComposables bool
+	// EmitTanstackQuery adds TanStack Query hooks: useXxxQuery for GET endpoints, useXxxMutation otherwise; axios client only.
+	// EmitTanstackQuery 额外生成 TanStack Query hooks：GET 端点为 useXxxQuery，其余为 useXxxMutation；仅支持 axios 客户端。
+	EmitTanstackQuery bool
+	// TanstackQueryModule is the hooks package, e.g. "@tanstack/react-query". Default is "@tanstack/vue-query".
+	// TanstackQueryModule 为 hooks 所在的包，如 "@tanstack/react-query"；默认 "@tanstack/vue-query"。
+	TanstackQueryModule string
+	// EmitZodSchemas adds a Zod schema (XSchema) per HTTP interface and imports zod; axios client only.
+	// EmitZodSchemas 为每个 HTTP 接口额外生成 Zod schema(XSchema)并导入 zod；仅支持 axios 客户端。
+	EmitZodSchemas bool
+	// EmitMocks adds a deterministic mockX(overrides) factory per HTTP interface; axios client only.
+	// EmitMocks 为每个 HTTP 接口额外生成确定性的 mockX(overrides) 工厂函数；仅支持 axios 客户端。
+	EmitMocks bool
+	// EmitAbortAll tracks in-flight requests and exports abortAllRequests() to cancel them all at once,
+	// e.g. on SPA route changes; axios client only.
+	// EmitAbortAll 跟踪进行中的请求并导出 abortAllRequests() 以一次性取消（如 SPA 路由切换时）；仅支持 axios 客户端。
+	EmitAbortAll bool
+	// Client is the HTTP library of the generated client; empty means axios.
+	// Client 为生成客户端使用的 HTTP 库；为空时使用 axios。
+	Client ClientKind
+}
+
+// DefaultTSExportOptions returns the options matching ExportTS; it equals the zero value.
+// DefaultTSExportOptions 返回与 ExportTS 一致的选项，等同于零值。
+func DefaultTSExportOptions() TSExportOptions {
+	return TSExportOptions{Client: ClientKindAxios}
+}
+
+func (o TSExportOptions) renderOptions() axiosRenderOptions {
+	return axiosRenderOptions{
+		VueComposables:      o.EmitVueComposables,
+		TanstackQuery:       o.EmitTanstackQuery,
+		TanstackQueryModule: o.TanstackQueryModule,
+		ZodSchemas:          o.EmitZodSchemas,
+		Mocks:               o.EmitMocks,
+		OmitValidators:      o.OmitValidators,
+		OmitDateParsing:     o.OmitDateParsing,
+		AbortAll:            o.EmitAbortAll,
+	}
+}
+
+// axiosOnlyFeature names the first selected feature the fetch client does not generate.
+func (o TSExportOptions) axiosOnlyFeature() string {
+	switch {
+	case o.EmitVueComposables:
+		return "vue composables"
+	case o.EmitTanstackQuery:
+		return "tanstack query hooks"
+	case o.EmitZodSchemas:
+		return "zod schemas"
+	case o.EmitMocks:
+		return "mocks"
+	case o.EmitAbortAll:
+		return "abort-all helpers"
+	}
+	return ""
+}
+
+func (o TSExportOptions) generate(basePath string, groupPath string, endpoints []EndpointLike) (string, error) {
+	registry, metas, err := collectAxiosFuncMetas(endpoints)
+	if err != nil {
+		return "", err
+	}
+	switch o.Client {
+	case "", ClientKindAxios:
+		return renderAxiosTS(basePath, groupPath, registry, metas, o.renderOptions())
+	case ClientKindFetch:
+		if feature := o.axiosOnlyFeature(); feature != "" {
+			return "", fmt.Errorf("%s are only generated for the axios client", feature)
+		}
+		return renderFetchTS(basePath, groupPath, registry, metas, o.renderOptions()), nil
+	default:
+		return "", fmt.Errorf("unsupported ts client kind %q", o.Client)
+	}
+}
+
+// ExportTSWithOptions generates TypeScript to a relative path with the features selected by opts.
+// If relativeTSPath is empty, it defaults to vue/composables/my-schemas.ts.
+// ExportTSWithOptions 按 opts 选择的功能生成 TypeScript 到相对路径；
+// 若 relativeTSPath 为空，则默认 vue/composables/my-schemas.ts。
+func (s ServerAPI) ExportTSWithOptions(relativeTSPath string, opts TSExportOptions) error {
+	if !shouldExportTSInCurrentEnv() {
+		return nil
+	}
+	if strings.TrimSpace(relativeTSPath) == "" {
+		relativeTSPath = "vue/composables/my-schemas.ts"
+	}
+	if filepath.IsAbs(relativeTSPath) {
+		return fmt.Errorf("ts file path must be relative to cwd")
+	}
+	code, err := opts.generate(s.BasePath, s.GroupPath, s.Endpoints)
+	if err != nil {
+		return err
+	}
+	return writeRelativeTSFile(relativeTSPath, code)
+}
+
+// ExportFetchTS generates a fetch-based TypeScript client (no axios dependency) to a relative path.
+// ExportFetchTS 会生成基于 fetch 的 TypeScript 客户端（不依赖 axios）到相对路径。
+func (s ServerAPI) ExportFetchTS(relativeTSPath string) error {
+	if !shouldExportTSInCurrentEnv() {
+		return nil
+	}
+	return exportFetchFromEndpointsToTSFile(s.BasePath, s.GroupPath, s.Endpoints, relativeTSPath)
+}
+
 // Build builds gin.RouterGroup and exports TS in one call.
 // Build 一次性完成 RouterGroup 构建与 TS 导出。
 func (s ServerAPI) Build(engine *gin.Engine, relativeTSPath string) (*gin.RouterGroup, error) {
@@ -77,6 +211,14 @@ func GenerateAxiosFromEndpoints(basePath string, endpoints []EndpointLike) (stri
 	return generateAxiosFromEndpoints(basePath, "", endpoints)
 }
 
+// GenerateFetchFromEndpoints generates a TypeScript client built on the Fetch API from endpoints.
+// It emits the same classes and request functions as GenerateAxiosFromEndpoints without depending on axios.
+// GenerateFetchFromEndpoints 根据 Endpoint 列表生成基于 Fetch API 的 TypeScript 客户端代码；
+// 生成与 GenerateAxiosFromEndpoints 相同的类与请求函数，但不依赖 axios。
+func GenerateFetchFromEndpoints(basePath string, endpoints []EndpointLike) (string, error) {
+	return generateFetchFromEndpoints(basePath, "", endpoints)
+}
+
 // ExportAxiosFromEndpointsToTSFile writes generated TS code from endpoints to a file.
 // ExportAxiosFromEndpointsToTSFile 将 Endpoint 生成的 TS 代码写入文件。
 func ExportAxiosFromEndpointsToTSFile(basePath string, endpoints []EndpointLike, relativeTSPath string) error {
@@ -246,6 +388,16 @@ func ApplyWebSocketEndpointsDevOnly(engine *gin.Engine, endpoints []WebSocketEnd
 }
 
 func registerWebSocketHandlers(router gin.IRouter, groupPath string, endpoints []WebSocketEndpointLike) error {
+	seen := map[string]int{}
+	for i := range endpoints {
+		path := endpoints[i].WebSocketMeta().Path
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		if err := checkDuplicateRoute(seen, http.MethodGet, path, i); err != nil {
+			return err
+		}
+	}
 	for i := range endpoints {
 		meta := endpoints[i].WebSocketMeta()
 		if strings.TrimSpace(meta.Path) == "" {
@@ -2,6 +2,8 @@ package endpoint
 
 import (
 	"net/http"
+	"reflect"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -22,7 +24,20 @@ type CustomEndpoint[PP, QP, HP, CP, Req, Resp any] struct {
 	Responses          []Response[Resp]
 	RequestKind        TSKind
 	ResponseKind       TSKind
-	HandlerFunc        gin.HandlerFunc
+	QueryArrayFormat   TSQueryArrayFormat
+	Middlewares        []gin.HandlerFunc
+	Enabled            func() bool
+	// Deprecated adds `@deprecated <reason>` to the generated TS docs when non-empty.
+	// Deprecated 非空时在生成的 TS 文档中添加 `@deprecated <reason>`。
+	Deprecated string
+	// ClientTimeout is emitted as the generated axios request timeout; zero emits none.
+	// ClientTimeout 会作为生成的 axios 请求超时时间输出；为 0 时不输出。
+	ClientTimeout time.Duration
+	// RequestTypeOverride / ResponseTypeOverride force the TS request/response types; nil keeps generic inference.
+	// RequestTypeOverride / ResponseTypeOverride 强制指定 TS 请求/响应类型；为 nil 时按泛型推断。
+	RequestTypeOverride  reflect.Type
+	ResponseTypeOverride reflect.Type
+	HandlerFunc          gin.HandlerFunc
 }
 
 // EndpointMeta exposes metadata for TS generation.
@@ -39,12 +54,14 @@ func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMeta() EndpointMeta {
 		HeaderParamsType:   typeOf[HP](),
 		CookieParamsType:   typeOf[CP](),
 		RequestBodyType:    typeOf[Req](),
+		Deprecated:         s.Deprecated,
 	}
 	if len(s.Responses) == 0 {
 		meta.Responses = []ResponseMeta{{
 			StatusCode: 200,
 			BodyType:   typeOf[Resp](),
 		}}
+		applyEndpointTypeOverrides(&meta, s.RequestTypeOverride, s.ResponseTypeOverride)
 		return meta
 	}
 	meta.Responses = make([]ResponseMeta, 0, len(s.Responses))
@@ -55,6 +72,7 @@ func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMeta() EndpointMeta {
 			Description: r.Description,
 		})
 	}
+	applyEndpointTypeOverrides(&meta, s.RequestTypeOverride, s.ResponseTypeOverride)
 	return meta
 }
 
@@ -62,17 +80,31 @@ func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMeta() EndpointMeta {
 // EndpointTSHints 自定义 TS 生成。
 func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointTSHints() EndpointTSHints {
 	return EndpointTSHints{
-		RequestKind:  s.RequestKind,
-		ResponseKind: s.ResponseKind,
+		RequestKind:      s.RequestKind,
+		ResponseKind:     s.ResponseKind,
+		QueryArrayFormat: s.QueryArrayFormat,
+		Timeout:          s.ClientTimeout,
 	}
 }
 
+// EndpointMiddlewares returns middleware registered before HandlerFunc.
+// EndpointMiddlewares 返回在 HandlerFunc 之前注册的中间件。
+func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMiddlewares() []gin.HandlerFunc {
+	return s.Middlewares
+}
+
+// EndpointEnabled reports whether this endpoint should be registered and exported.
+// EndpointEnabled 返回该 endpoint 是否应被注册与导出。
+func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointEnabled() bool {
+	return s.Enabled == nil || s.Enabled()
+}
+
 // GinHandler builds a gin.HandlerFunc that binds params/body and calls HandlerFunc.
 // GinHandler 会绑定参数/请求体并调用 HandlerFunc。
 func (s CustomEndpoint[PP, QP, HP, CP, Req, Resp]) GinHandler() gin.HandlerFunc {
 	if s.HandlerFunc == nil {
 		return func(ctx *gin.Context) {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "custom endpoint handler is nil"})
+			ctx.JSON(http.StatusInternalServerError, ErrorResponse{Error: "custom endpoint handler is nil"})
 		}
 	}
 	return s.HandlerFunc
@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BatchItemResult is the per-item result of a batch endpoint (207 Multi-Status style).
+// Exactly one of Data / Error is usually set; Status is the HTTP-like status of that item.
+// BatchItemResult 是批量接口中单个条目的结果（207 Multi-Status 风格）；
+// 通常 Data 与 Error 只设置其一，Status 为该条目对应的 HTTP 状态码。
+type BatchItemResult[T, E any] struct {
+	Index  int `json:"index"`
+	Status int `json:"status"`
+	Data   *T  `json:"data,omitempty"`
+	Error  *E  `json:"error,omitempty"`
+}
+
+// BatchItemOK builds a successful item result for the request item at index.
+// BatchItemOK 为 index 处的请求条目构建成功结果。
+func BatchItemOK[T, E any](index int, status int, data T) BatchItemResult[T, E] {
+	return BatchItemResult[T, E]{Index: index, Status: status, Data: &data}
+}
+
+// BatchItemFailed builds a failed item result for the request item at index.
+// BatchItemFailed 为 index 处的请求条目构建失败结果。
+func BatchItemFailed[T, E any](index int, status int, err E) BatchItemResult[T, E] {
+	return BatchItemResult[T, E]{Index: index, Status: status, Error: &err}
+}
+
+// NewBatchResponse assembles a 207 Multi-Status response from per-item results, ordered by Index.
+// Use it as the return value of an Endpoint declared with Batch: true.
+// NewBatchResponse 将各条目结果按 Index 排序后组装为 207 Multi-Status 响应；
+// 用作 Batch: true 的 Endpoint 的返回值。
+func NewBatchResponse[T, E any](results []BatchItemResult[T, E]) Response[[]BatchItemResult[T, E]] {
+	sorted := make([]BatchItemResult[T, E], len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+	return Response[[]BatchItemResult[T, E]]{
+		StatusCode: http.StatusMultiStatus,
+		Body:       sorted,
+	}
+}
+
+// batchItemTypesOf reports the data / error types of a []BatchItemResult[T, E] body.
+func batchItemTypesOf(t reflect.Type) (reflect.Type, reflect.Type, bool) {
+	if t == nil || t.Kind() != reflect.Slice {
+		return nil, nil, false
+	}
+	item := t.Elem()
+	if item.Kind() != reflect.Struct || item.PkgPath() != "github.com/RapboyGao/nuxtGin/endpoint" || !strings.HasPrefix(item.Name(), "BatchItemResult[") {
+		return nil, nil, false
+	}
+	data, ok := item.FieldByName("Data")
+	if !ok {
+		return nil, nil, false
+	}
+	failure, ok := item.FieldByName("Error")
+	if !ok {
+		return nil, nil, false
+	}
+	return data.Type.Elem(), failure.Type.Elem(), true
+}
@@ -3,24 +3,89 @@ package endpoint
 import (
 	"errors"
 	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/mitchellh/mapstructure"
 )
 
 func registerEndpointHandlers(router gin.IRouter, endpoints []EndpointLike) error {
+	if err := checkDuplicateEndpointRoutes(endpoints); err != nil {
+		return err
+	}
 	for i := range endpoints {
+		if !isEndpointEnabled(endpoints[i]) {
+			continue
+		}
 		handler, method, path, err := buildGinHandler(endpoints[i])
 		if err != nil {
 			return fmt.Errorf("register endpoint[%d] failed: %w", i, err)
 		}
-		router.Handle(method, path, handler)
+		handlers := make([]gin.HandlerFunc, 0, 1)
+		if provider, ok := endpoints[i].(EndpointMiddlewareProvider); ok {
+			for _, mw := range provider.EndpointMiddlewares() {
+				if mw != nil {
+					handlers = append(handlers, mw)
+				}
+			}
+		}
+		handlers = append(handlers, handler)
+		router.Handle(method, path, handlers...)
 	}
 	return nil
 }
 
+// checkDuplicateEndpointRoutes reports method+path collisions up front, because gin panics on them.
+func checkDuplicateEndpointRoutes(endpoints []EndpointLike) error {
+	seen := map[string]int{}
+	for i := range endpoints {
+		if !isEndpointEnabled(endpoints[i]) {
+			continue
+		}
+		meta := endpoints[i].EndpointMeta()
+		if strings.TrimSpace(string(meta.Method)) == "" || strings.TrimSpace(meta.Path) == "" {
+			continue
+		}
+		if err := checkDuplicateRoute(seen, string(meta.Method), meta.Path, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDuplicateRoute records method+path at index i and errors if an earlier index already claimed it.
+// Placeholder names are ignored, so `/users/:id` and `/users/:userID` collide just like in gin.
+func checkDuplicateRoute(seen map[string]int, method string, path string, i int) error {
+	key := strings.ToUpper(strings.TrimSpace(method)) + " " + routePathKey(path)
+	if prev, ok := seen[key]; ok {
+		return fmt.Errorf("duplicate route %s %s at endpoints[%d] and [%d]", strings.ToUpper(strings.TrimSpace(method)), strings.TrimSpace(path), prev, i)
+	}
+	seen[key] = i
+	return nil
+}
+
+func routePathKey(path string) string {
+	segments := strings.Split("/"+strings.TrimLeft(strings.TrimSpace(path), "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = segment[:1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isEndpointEnabled(e EndpointLike) bool {
+	if provider, ok := e.(EndpointEnabledProvider); ok {
+		return provider.EndpointEnabled()
+	}
+	return true
+}
+
 func buildGinHandler(e EndpointLike) (gin.HandlerFunc, string, string, error) {
 	meta := e.EndpointMeta()
 	if strings.TrimSpace(string(meta.Method)) == "" {
@@ -46,6 +111,133 @@ func bindStructT[T any](bind func(any) error) (T, error) {
 	return v, nil
 }
 
+// bindRequestParamsT binds the path, query, header and cookie params of a request.
+func bindRequestParamsT[PP, QP, HP, CP any](ctx *gin.Context) (PP, QP, HP, CP, error) {
+	var (
+		pathParams   PP
+		queryParams  QP
+		headerParams HP
+		cookieParams CP
+		err          error
+	)
+	if pathParams, err = bindStructT[PP](ctx.ShouldBindUri); err != nil {
+		return pathParams, queryParams, headerParams, cookieParams, err
+	}
+	if queryParams, err = bindStructT[QP](shouldBindQueryWithDefaults(ctx)); err != nil {
+		return pathParams, queryParams, headerParams, cookieParams, err
+	}
+	if headerParams, err = bindStructT[HP](ctx.ShouldBindHeader); err != nil {
+		return pathParams, queryParams, headerParams, cookieParams, err
+	}
+	cookieParams, err = bindCookieStructT[CP](ctx)
+	return pathParams, queryParams, headerParams, cookieParams, err
+}
+
+// shouldBindQueryWithDefaults is ctx.ShouldBindQuery with `default` tag values filled in for absent
+// or empty query params, so `binding:"required"` and other rules see the defaulted value.
+func shouldBindQueryWithDefaults(ctx *gin.Context) func(any) error {
+	return func(v any) error {
+		return ctx.ShouldBindWith(v, queryDefaultsBinding{})
+	}
+}
+
+// queryDefaultsBinding is gin's query binding with `default` tags applied before mapping and validation.
+type queryDefaultsBinding struct{}
+
+func (queryDefaultsBinding) Name() string {
+	return "query"
+}
+
+func (queryDefaultsBinding) Bind(req *http.Request, obj any) error {
+	values := req.URL.Query()
+	applyDefaultTagValues(reflect.TypeOf(obj), values)
+	if err := binding.MapFormWithTag(obj, values, "form"); err != nil {
+		return err
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// applyDefaultTagValues sets the `default` tag value of each field of struct t whose `form` key is
+// missing or empty in values. Untagged embedded structs are walked like gin does.
+func applyDefaultTagValues(t reflect.Type, values url.Values) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		formTag := f.Tag.Get("form")
+		if formTag == "-" {
+			continue
+		}
+		if f.Anonymous && formTag == "" {
+			applyDefaultTagValues(f.Type, values)
+			continue
+		}
+		def, ok := f.Tag.Lookup("default")
+		if !ok || f.PkgPath != "" {
+			continue
+		}
+		key := strings.Split(formTag, ",")[0]
+		if key == "" {
+			key = f.Name
+		}
+		if values.Get(key) == "" {
+			values.Set(key, def)
+		}
+	}
+}
+
+// BindPath binds the `uri` path params of a request into T, reusing the Endpoint binding rules.
+// It lets a CustomEndpoint handler read typed values; a failure is an *APIError with status 400.
+// BindPath 按 Endpoint 的绑定规则将请求的 `uri` 路径参数绑定到 T，
+// 便于 CustomEndpoint 的 handler 读取强类型参数；失败时返回状态码为 400 的 *APIError。
+func BindPath[T any](ctx *gin.Context) (T, error) {
+	v, err := bindStructT[T](ctx.ShouldBindUri)
+	return v, bindAPIError("invalid_path_params", err)
+}
+
+// BindQuery binds the `form` query params of a request into T; see BindPath.
+// BindQuery 将请求的 `form` query 参数绑定到 T；参见 BindPath。
+func BindQuery[T any](ctx *gin.Context) (T, error) {
+	v, err := bindStructT[T](shouldBindQueryWithDefaults(ctx))
+	return v, bindAPIError("invalid_query_params", err)
+}
+
+// BindHeader binds the `header` params of a request into T; see BindPath.
+// BindHeader 将请求的 `header` 参数绑定到 T；参见 BindPath。
+func BindHeader[T any](ctx *gin.Context) (T, error) {
+	v, err := bindStructT[T](ctx.ShouldBindHeader)
+	return v, bindAPIError("invalid_header_params", err)
+}
+
+// BindCookie binds the request cookies into T, matching field names or `mapstructure` tags; see BindPath.
+// BindCookie 将请求 cookie 按字段名或 `mapstructure` tag 绑定到 T；参见 BindPath。
+func BindCookie[T any](ctx *gin.Context) (T, error) {
+	v, err := bindCookieStructT[T](ctx)
+	return v, bindAPIError("invalid_cookie_params", err)
+}
+
+// BindJSON binds the JSON request body into T; see BindPath.
+// BindJSON 将 JSON 请求体绑定到 T；参见 BindPath。
+func BindJSON[T any](ctx *gin.Context) (T, error) {
+	v, err := bindJSONStructT[T](ctx)
+	return v, bindAPIError("invalid_json_body", err)
+}
+
+// bindAPIError wraps a binding error as a 400 *APIError with code; nil stays nil.
+func bindAPIError(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &APIError{Status: http.StatusBadRequest, Code: code, Message: err.Error()}
+}
+
 func bindJSONStructT[T any](ctx *gin.Context) (T, error) {
 	var v T
 	if isNoType(typeOf[T]()) {
@@ -57,6 +249,36 @@ func bindJSONStructT[T any](ctx *gin.Context) (T, error) {
 	return v, nil
 }
 
+// bindMultipartStructT binds multipart/form-data fields and file parts using `form` tags.
+func bindMultipartStructT[T any](ctx *gin.Context) (T, error) {
+	var v T
+	if err := ctx.ShouldBindWith(&v, binding.FormMultipart); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// hasMultipartFileFields reports whether struct t has *multipart.FileHeader or []*multipart.FileHeader fields.
+func hasMultipartFileFields(t reflect.Type) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	fileHeaderType := reflect.TypeOf(multipart.FileHeader{})
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i).Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if ft == fileHeaderType {
+			return true
+		}
+	}
+	return false
+}
+
 func bindCookieStructT[T any](ctx *gin.Context) (T, error) {
 	var v T
 	if isNoType(typeOf[T]()) {
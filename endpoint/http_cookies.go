@@ -0,0 +1,89 @@
+package endpoint
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseCookie declares a cookie an endpoint sets (e.g. a login session).
+// List it in Endpoint.SetCookies so generated docs show it, and call Set from the handler.
+// ResponseCookie 声明 endpoint 会设置的 cookie（如登录会话）；
+// 在 Endpoint.SetCookies 中列出以便生成文档展示，并在 handler 中调用 Set 写入。
+type ResponseCookie struct {
+	Name        string
+	Path        string
+	Domain      string
+	MaxAge      int
+	Secure      bool
+	HTTPOnly    bool
+	SameSite    http.SameSite
+	Description string
+}
+
+func (c ResponseCookie) httpCookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     c.Name,
+		Value:    value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		MaxAge:   c.MaxAge,
+		Secure:   c.Secure,
+		HttpOnly: c.HTTPOnly,
+		SameSite: c.SameSite,
+	}
+}
+
+// Set validates the cookie and writes it with the declared attributes.
+// Set 校验 cookie 并按声明的属性写入响应。
+func (c ResponseCookie) Set(ctx *gin.Context, value string) error {
+	cookie := c.httpCookie(value)
+	if err := cookie.Valid(); err != nil {
+		return fmt.Errorf("invalid cookie %q: %w", c.Name, err)
+	}
+	http.SetCookie(ctx.Writer, cookie)
+	return nil
+}
+
+// Clear expires the cookie on the client, keeping its Path and Domain.
+// Clear 使客户端上的该 cookie 失效（保留 Path 与 Domain）。
+func (c ResponseCookie) Clear(ctx *gin.Context) {
+	cookie := c.httpCookie("")
+	cookie.MaxAge = -1
+	http.SetCookie(ctx.Writer, cookie)
+}
+
+// summary renders the cookie for generated docs, e.g. `session (HttpOnly; Secure; SameSite=Lax): Login session`.
+func (c ResponseCookie) summary() string {
+	attrs := make([]string, 0, 5)
+	if c.HTTPOnly {
+		attrs = append(attrs, "HttpOnly")
+	}
+	if c.Secure {
+		attrs = append(attrs, "Secure")
+	}
+	switch c.SameSite {
+	case http.SameSiteLaxMode:
+		attrs = append(attrs, "SameSite=Lax")
+	case http.SameSiteStrictMode:
+		attrs = append(attrs, "SameSite=Strict")
+	case http.SameSiteNoneMode:
+		attrs = append(attrs, "SameSite=None")
+	}
+	if c.MaxAge > 0 {
+		attrs = append(attrs, fmt.Sprintf("Max-Age=%d", c.MaxAge))
+	}
+	if c.Path != "" {
+		attrs = append(attrs, "Path="+c.Path)
+	}
+	out := c.Name
+	if len(attrs) > 0 {
+		out += " (" + strings.Join(attrs, "; ") + ")"
+	}
+	if desc := strings.TrimSpace(c.Description); desc != "" {
+		out += ": " + desc
+	}
+	return out
+}
@@ -0,0 +1,263 @@
+package endpoint
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csvColumn is one CSV column derived from a json-tagged struct field.
+// Type is the TS cell type used by the generated parseCSVAs: string, number, bigint, boolean or json.
+type csvColumn struct {
+	Name     string
+	Field    int
+	Type     string
+	Optional bool
+}
+
+// csvColumnsOf walks the top-level json fields of a row struct, in declaration order.
+func csvColumnsOf(t reflect.Type) ([]csvColumn, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv row type %s is not a struct", t.String())
+	}
+	columns := make([]csvColumn, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, optional, ok := jsonFieldMeta(f)
+		if !ok {
+			continue
+		}
+		columns = append(columns, csvColumn{
+			Name:     name,
+			Field:    i,
+			Type:     csvCellType(f.Type),
+			Optional: optional || f.Type.Kind() == reflect.Ptr,
+		})
+	}
+	return columns, nil
+}
+
+func csvCellType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Int64, reflect.Uint64:
+		switch TSInt64MappingMode {
+		case TSInt64ModeString:
+			return "string"
+		case TSInt64ModeBigInt:
+			return "bigint"
+		}
+		return "number"
+	default:
+		return "json"
+	}
+}
+
+// WriteCSV writes rows as CSV. The header row holds the json field names of T,
+// so the generated parseCSVAs can map cells back to typed fields.
+// Scalars are written as text, time.Time as RFC3339, nil pointers as empty cells and other values as JSON.
+// A nil row (e.g. in []*T) is an error.
+// WriteCSV 将 rows 写为 CSV；表头为 T 的 json 字段名，便于生成的 parseCSVAs 还原类型化数据。
+// 标量按文本写出，time.Time 使用 RFC3339，nil 指针为空单元格，其余值写为 JSON；nil 行（如 []*T 中）会返回错误。
+func WriteCSV[T any](w io.Writer, rows []T) error {
+	columns, err := csvColumnsOf(typeOf[T]())
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(columns))
+	for r, row := range rows {
+		v := reflect.ValueOf(row)
+		for v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return fmt.Errorf("csv row %d is nil", r)
+		}
+		for i, c := range columns {
+			cell, err := formatCSVCell(v.Field(c.Field))
+			if err != nil {
+				return fmt.Errorf("csv column %s: %w", c.Name, err)
+			}
+			record[i] = cell
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ServeCSV writes rows as a text/csv response; a non-empty filename is sent as an attachment.
+// Use it from a CustomEndpoint whose ResponseKind is TSKindStream (Blob) or TSKindText (string).
+// ServeCSV 将 rows 作为 text/csv 响应写出；filename 非空时以附件形式下载。
+// 可在 ResponseKind 为 TSKindStream(Blob) 或 TSKindText(string) 的 CustomEndpoint 中使用。
+func ServeCSV[T any](ctx *gin.Context, status int, filename string, rows []T) error {
+	if filename != "" {
+		ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Status(status)
+	return WriteCSV(ctx.Writer, rows)
+}
+
+// ReadCSV parses CSV written by WriteCSV back into rows, matching cells by header name.
+// Unknown columns are ignored; empty cells of optional fields are left as zero values.
+// ReadCSV 将 WriteCSV 写出的 CSV 按表头名还原为 rows；未知列会被忽略，可选字段的空单元格保留零值。
+func ReadCSV[T any](r io.Reader) ([]T, error) {
+	columns, err := csvColumnsOf(typeOf[T]())
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]csvColumn, len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]T, 0)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var row T
+		v := reflect.ValueOf(&row).Elem()
+		for v.Kind() == reflect.Ptr {
+			v.Set(reflect.New(v.Type().Elem()))
+			v = v.Elem()
+		}
+		for i, name := range header {
+			c, ok := byName[name]
+			if !ok || i >= len(record) {
+				continue
+			}
+			if record[i] == "" && c.Optional {
+				continue
+			}
+			if err := parseCSVCell(v.Field(c.Field), record[i]); err != nil {
+				return nil, fmt.Errorf("csv column %s: %w", name, err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func formatCSVCell(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+func parseCSVCell(v reflect.Value, cell string) error {
+	for v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	if _, ok := v.Interface().(time.Time); ok {
+		t, err := time.Parse(time.RFC3339Nano, cell)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(cell)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(cell, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(cell, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return json.Unmarshal([]byte(cell), v.Addr().Interface())
+	}
+	return nil
+}
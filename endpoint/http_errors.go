@@ -0,0 +1,50 @@
+package endpoint
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the JSON body written for bind, timeout and handler errors.
+// Generated TS always includes it with validateErrorResponse / ensureErrorResponse.
+// ErrorResponse 是参数绑定、超时与 handler 错误时返回的 JSON 响应体；生成的 TS 始终包含它及其 validate/ensure 函数。
+type ErrorResponse struct {
+	Error   string `json:"error" tsdoc:"错误描述 / Error message"`
+	Code    string `json:"code,omitempty" tsdoc:"业务错误码 / Application error code"`
+	Details any    `json:"details,omitempty" tsdoc:"错误详情 / Extra error details"`
+}
+
+// APIError lets a handler choose the status, code and details of its ErrorResponse.
+// Return it (or wrap it) as the handler error; Status 0 keeps the Response status.
+// APIError 允许 handler 指定错误响应的状态码、错误码与详情；作为 handler 的 error 返回（可被包装）即可，Status 为 0 时沿用 Response 的状态码。
+type APIError struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+}
+
+// Error implements error.
+// Error 实现 error 接口。
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// errorResponseOf builds the ErrorResponse for err, taking code/details from a wrapped *APIError.
+func errorResponseOf(status int, err error) (int, ErrorResponse) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.Status > 0 {
+			status = apiErr.Status
+		}
+		return status, ErrorResponse{Error: apiErr.Message, Code: apiErr.Code, Details: apiErr.Details}
+	}
+	return status, ErrorResponse{Error: err.Error()}
+}
+
+// writeErrorResponse writes err as an ErrorResponse.
+func writeErrorResponse(ctx *gin.Context, status int, err error) {
+	status, body := errorResponseOf(status, err)
+	ctx.JSON(status, body)
+}
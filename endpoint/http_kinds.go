@@ -1,5 +1,7 @@
 package endpoint
 
+import "time"
+
 // TSKind describes how request/response bodies should be handled in TS.
 // TSKind 描述在 TS 中如何处理请求/响应体。
 type TSKind string
@@ -11,6 +13,7 @@ const (
 	TSKindText           TSKind = "text"
 	TSKindBytes          TSKind = "bytes"
 	TSKindStream         TSKind = "stream"
+	TSKindEventStream    TSKind = "event_stream"
 )
 
 // EndpointTSHints provides extra metadata for TS generation.
@@ -18,8 +21,30 @@ const (
 type EndpointTSHints struct {
 	RequestKind  TSKind
 	ResponseKind TSKind
+	// QueryArrayFormat overrides TSQueryArrayFormatMode for this endpoint; empty keeps the global mode.
+	// QueryArrayFormat 覆盖该 endpoint 的 TSQueryArrayFormatMode；为空时使用全局设置。
+	QueryArrayFormat TSQueryArrayFormat
+	// Timeout is the generated axios request timeout; zero emits none (callers may still pass options.timeout).
+	// Timeout 为生成的 axios 请求超时时间；为 0 时不输出（调用方仍可通过 options.timeout 指定）。
+	Timeout time.Duration
 }
 
+// TSQueryArrayFormat selects how generated clients serialize slice query params.
+// TSQueryArrayFormat 选择生成的客户端如何序列化切片类型的查询参数。
+type TSQueryArrayFormat string
+
+const (
+	// TSQueryArrayRepeat repeats the key (`tags=a&tags=b`), which gin's ShouldBindQuery binds by default.
+	// TSQueryArrayRepeat 重复键名（`tags=a&tags=b`），gin 的 ShouldBindQuery 默认即可绑定。
+	TSQueryArrayRepeat TSQueryArrayFormat = "repeat"
+	// TSQueryArrayComma joins items (`tags=a,b`); bind it with `collection_format:"csv"` on the field.
+	// TSQueryArrayComma 以逗号拼接（`tags=a,b`）；服务端字段需加 `collection_format:"csv"` 绑定。
+	TSQueryArrayComma TSQueryArrayFormat = "comma"
+	// TSQueryArrayBrackets appends brackets to the key (`tags[]=a&tags[]=b`); the form tag must include `[]`.
+	// TSQueryArrayBrackets 在键名后追加方括号（`tags[]=a&tags[]=b`）；form 标签需包含 `[]`。
+	TSQueryArrayBrackets TSQueryArrayFormat = "brackets"
+)
+
 // EndpointTSHintsProvider allows endpoints to customize TS generation behavior.
 // EndpointTSHintsProvider 允许 endpoint 自定义 TS 生成行为。
 type EndpointTSHintsProvider interface {
@@ -34,6 +59,13 @@ type FormData struct{}
 // RawBytes 是用于原始二进制请求体的标记类型。
 type RawBytes []byte
 
+// StreamRequest is a marker type for request bodies streamed from the client without buffering.
+// The body is left unread so the handler can consume ctx.Request.Body as a stream.
+// Generated clients send it with fetch: the axios client's baseURL applies, its interceptors do not.
+// StreamRequest 是客户端流式上传（不在浏览器中缓冲）的请求体标记类型；服务端不会预先读取请求体，handler 可直接以流方式读取 ctx.Request.Body。
+// 生成的客户端使用 fetch 发送：会使用 axios 实例的 baseURL，但不会执行其拦截器。
+type StreamRequest struct{}
+
 // StreamResponse is a marker type used for streaming responses.
 // StreamResponse 是用于流式响应的标记类型。
 type StreamResponse struct{}
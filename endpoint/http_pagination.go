@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Paginated is the list envelope `{ items, total, page, pageSize }` shared by paged list endpoints.
+// TS generation names each instantiation after its item type, e.g. PaginatedPersonDetailResp,
+// with the same field shape for every T.
+// Paginated 是分页列表接口共用的 `{ items, total, page, pageSize }` 响应结构；
+// TS 生成时按元素类型命名各实例（如 PaginatedPersonDetailResp），字段结构保持一致。
+type Paginated[T any] struct {
+	Items    []T   `json:"items"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"pageSize"`
+}
+
+// NewPaginatedResponse builds a 200 response with a Paginated body; nil items are sent as [].
+// NewPaginatedResponse 构建 Paginated 响应体的 200 响应；items 为 nil 时输出 []。
+func NewPaginatedResponse[T any](items []T, total int64, page int, pageSize int) Response[Paginated[T]] {
+	if items == nil {
+		items = []T{}
+	}
+	return Response[Paginated[T]]{
+		StatusCode: http.StatusOK,
+		Body: Paginated[T]{
+			Items:    items,
+			Total:    total,
+			Page:     page,
+			PageSize: pageSize,
+		},
+	}
+}
+
+// NewPaginatedEndpoint builds an Endpoint whose response body is Paginated[T].
+// NewPaginatedEndpoint 构建响应体为 Paginated[T] 的 Endpoint。
+func NewPaginatedEndpoint[PP, QP, HP, CP, Req, T any](
+	name string,
+	method HTTPMethod,
+	path string,
+	handler func(pathParams PP, queryParams QP, headerParams HP, cookieParams CP, requestBody Req, ctx *gin.Context) (Response[Paginated[T]], error),
+) Endpoint[PP, QP, HP, CP, Req, Paginated[T]] {
+	return Endpoint[PP, QP, HP, CP, Req, Paginated[T]]{
+		Name:        name,
+		Method:      method,
+		Path:        path,
+		HandlerFunc: handler,
+	}
+}
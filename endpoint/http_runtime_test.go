@@ -0,0 +1,1123 @@
+package endpoint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreateAccountReq struct {
+	Email    string `json:"email" binding:"required" validate:"email"`
+	Nickname string `json:"nickname" validate:"min=2"`
+}
+
+type CreateAccountResp struct {
+	ID string `json:"id"`
+}
+
+func newTestRouter(t *testing.T, endpoints ...EndpointLike) *gin.Engine {
+	t.Helper()
+	oldMode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() {
+		gin.SetMode(oldMode)
+	})
+	router := gin.New()
+	if err := registerEndpointHandlers(router, endpoints); err != nil {
+		t.Fatalf("register endpoints failed: %v", err)
+	}
+	return router
+}
+
+func serveTestRequest(router http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func buildCreateAccountEndpoint(validate bool) Endpoint[NoParams, NoParams, NoParams, NoParams, CreateAccountReq, CreateAccountResp] {
+	return Endpoint[NoParams, NoParams, NoParams, NoParams, CreateAccountReq, CreateAccountResp]{
+		Name:            "CreateAccount",
+		Method:          HTTPMethodPost,
+		Path:            "/accounts",
+		ValidateRequest: validate,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, req CreateAccountReq, _ *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{StatusCode: http.StatusCreated, Body: CreateAccountResp{ID: "acc-" + req.Nickname}}, nil
+		},
+	}
+}
+
+// TestEndpointGinHandler_ValidateRequest
+// 这个测试验证请求体校验：
+// 1) 开启 ValidateRequest 后，binding/validate 标签同时生效，失败返回 422 与按 JSON 字段名列出的错误。
+// 2) 合法请求正常进入 HandlerFunc。
+// 3) 关闭时保持旧行为：validate 标签不生效，不会突然拒绝请求。
+// 4) 422 响应体会进入元数据，从而生成 ValidationErrorResponse 的 TS 类型。
+func TestEndpointGinHandler_ValidateRequest(t *testing.T) {
+	router := newTestRouter(t, buildCreateAccountEndpoint(true))
+
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"email":"bad","nickname":"a"}`)))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var verr ValidationErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &verr); err != nil {
+		t.Fatalf("unmarshal validation error failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, f := range verr.Fields {
+		got[f.Field] = f.Tag
+	}
+	if got["email"] != "email" || got["nickname"] != "min" {
+		t.Fatalf("expected per-field errors for email/nickname, got %+v", verr.Fields)
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"nickname":"ab"}`)))
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(rec.Body.String(), `"tag":"required"`) {
+		t.Fatalf("expected binding:required to be reported as 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"email":"a@b.co","nickname":"ab"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected valid payload to pass, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	legacy := newTestRouter(t, buildCreateAccountEndpoint(false))
+	rec = serveTestRequest(legacy, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"email":"bad","nickname":"a"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected validate tags to be ignored when ValidateRequest is off, got %d", rec.Code)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{buildCreateAccountEndpoint(true)})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface ValidationErrorResponse") || !strings.Contains(code, "export function ensureValidationErrorResponse(") {
+		t.Fatalf("expected ValidationErrorResponse to be emitted as a known error body")
+	}
+}
+
+// TestServerAPIBuildGinGroup_EndpointMiddlewares
+// 这个测试验证单个 Endpoint 的中间件：
+// 1) 执行顺序固定为：分组中间件 -> Endpoint 中间件 -> 类型化 handler。
+// 2) Endpoint 中间件调用 ctx.AbortWithStatus 短路后，类型化 handler 不再执行。
+func TestServerAPIBuildGinGroup_EndpointMiddlewares(t *testing.T) {
+	oldMode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() {
+		gin.SetMode(oldMode)
+	})
+
+	var order []string
+	handlerCalled := false
+	ep := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:   "Profile",
+		Method: HTTPMethodGet,
+		Path:   "/profile",
+		Middlewares: []gin.HandlerFunc{
+			func(ctx *gin.Context) {
+				order = append(order, "endpoint")
+				if ctx.GetHeader("Authorization") == "" {
+					ctx.AbortWithStatus(http.StatusUnauthorized)
+					return
+				}
+				ctx.Next()
+			},
+		},
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[CreateAccountResp], error) {
+			handlerCalled = true
+			order = append(order, "handler")
+			return Response[CreateAccountResp]{StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	engine := gin.New()
+	engine.Use(func(ctx *gin.Context) {
+		order = append(order, "group")
+		ctx.Next()
+	})
+	api := ServerAPI{BasePath: "/api", Endpoints: []EndpointLike{ep}}
+	if _, err := api.BuildGinGroup(engine); err != nil {
+		t.Fatalf("BuildGinGroup returned error: %v", err)
+	}
+
+	rec := serveTestRequest(engine, httptest.NewRequest(http.MethodGet, "/api/profile", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected middleware to short-circuit with 401, got %d", rec.Code)
+	}
+	if handlerCalled {
+		t.Fatalf("expected typed handler not to run after AbortWithStatus")
+	}
+
+	order = nil
+	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec = serveTestRequest(engine, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 when middleware passes, got %d", rec.Code)
+	}
+	if strings.Join(order, ",") != "group,endpoint,handler" {
+		t.Fatalf("expected group -> endpoint -> handler order, got %v", order)
+	}
+}
+
+// TestEndpointEnabled_SkipsRegistrationAndTS
+// 这个测试验证 Endpoint.Enabled 开关：
+// 1) Enabled 返回 false 的端点不会注册到 gin（请求返回 404）。
+// 2) 同一份定义列表生成的 TS 中也不包含被禁用的端点；未设置 Enabled 的端点默认启用。
+// 3) 跳过禁用端点后，错误信息中的下标仍对应调用方传入的切片位置。
+func TestEndpointEnabled_SkipsRegistrationAndTS(t *testing.T) {
+	disabled := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:    "BetaReport",
+		Method:  HTTPMethodGet,
+		Path:    "/beta/report",
+		Enabled: func() bool { return false },
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{StatusCode: http.StatusOK}, nil
+		},
+	}
+	enabled := buildCreateAccountEndpoint(false)
+	router := newTestRouter(t, disabled, enabled)
+
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/beta/report", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected disabled endpoint not to be registered, got %d", rec.Code)
+	}
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"email":"a@b.co","nickname":"ab"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected endpoint without Enabled to stay registered, got %d", rec.Code)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{disabled, enabled})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "BetaReportGet") || strings.Contains(code, "/beta/report") {
+		t.Fatalf("expected disabled endpoint to be omitted from generated TS")
+	}
+	if !strings.Contains(code, "export class CreateAccountPost") {
+		t.Fatalf("expected enabled endpoint in generated TS")
+	}
+
+	broken := buildCreateAccountEndpoint(false)
+	broken.Path = ""
+	_, err = generateAxiosFromEndpoints("/api", "", []EndpointLike{disabled, broken})
+	if err == nil || !strings.Contains(err.Error(), "endpoint[1]") {
+		t.Fatalf("expected error to keep the caller's endpoint index, got %v", err)
+	}
+}
+
+type UploadPhotosReq struct {
+	Album string                  `json:"album" form:"album"`
+	Files []*multipart.FileHeader `json:"files" form:"files"`
+}
+
+type UploadPhotosResp struct {
+	Names []string `json:"names"`
+}
+
+func buildUploadPhotosEndpoint() Endpoint[NoParams, NoParams, NoParams, NoParams, UploadPhotosReq, UploadPhotosResp] {
+	return Endpoint[NoParams, NoParams, NoParams, NoParams, UploadPhotosReq, UploadPhotosResp]{
+		Name:   "UploadPhotos",
+		Method: HTTPMethodPost,
+		Path:   "/photos",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, req UploadPhotosReq, _ *gin.Context) (Response[UploadPhotosResp], error) {
+			names := make([]string, 0, len(req.Files))
+			for _, f := range req.Files {
+				names = append(names, req.Album+"/"+f.Filename)
+			}
+			return Response[UploadPhotosResp]{StatusCode: http.StatusOK, Body: UploadPhotosResp{Names: names}}, nil
+		},
+	}
+}
+
+// TestEndpointGinHandler_MultipartFiles
+// 这个测试验证多文件上传：
+// 1) 请求体含 []*multipart.FileHeader 字段时按 multipart/form-data 绑定，同名字段的多个文件全部进入切片。
+// 2) 普通表单字段按 form 标签一起绑定。
+// 3) 生成的 TS 自动按 multipart 发送，文件字段为 Blob[]，并透传 onUploadProgress。
+func TestEndpointGinHandler_MultipartFiles(t *testing.T) {
+	router := newTestRouter(t, buildUploadPhotosEndpoint())
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("album", "trip")
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		part, err := w.CreateFormFile("files", name)
+		if err != nil {
+			t.Fatalf("create form file failed: %v", err)
+		}
+		_, _ = part.Write([]byte(name))
+	}
+	_ = w.Close()
+	req := httptest.NewRequest(http.MethodPost, "/photos", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	rec := serveTestRequest(router, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp UploadPhotosResp
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response failed: %v", err)
+	}
+	if strings.Join(resp.Names, ",") != "trip/a.jpg,trip/b.jpg" {
+		t.Fatalf("expected both files under the same field, got %v", resp.Names)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{buildUploadPhotosEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "files: Blob[];") {
+		t.Fatalf("expected file slice field to map to Blob[]")
+	}
+	if !strings.Contains(code, "const requestData = toFormData(serializedRequest);") || !strings.Contains(code, "for (const item of v) append(k, item);") {
+		t.Fatalf("expected files to be appended as repeated parts under one field name")
+	}
+	if !strings.Contains(code, "onUploadProgress: options.onUploadProgress") {
+		t.Fatalf("expected onUploadProgress to be forwarded for multipart uploads")
+	}
+}
+
+// TestEndpointGinHandler_MultipartValidateRequest
+// 这个测试验证 multipart 请求体与 ValidateRequest 的组合：
+// 1) 缺少 binding:"required" 的表单字段时返回 422 ValidationErrorResponse，而不是 gin 默认的 400。
+// 2) 字段齐全时文件与普通字段正常绑定。
+func TestEndpointGinHandler_MultipartValidateRequest(t *testing.T) {
+	type UploadAvatarReq struct {
+		Owner string                `json:"owner" form:"owner" binding:"required"`
+		File  *multipart.FileHeader `json:"file" form:"file" binding:"required"`
+	}
+	ep := Endpoint[NoParams, NoParams, NoParams, NoParams, UploadAvatarReq, string]{
+		Name:            "UploadAvatar",
+		Method:          HTTPMethodPost,
+		Path:            "/avatar",
+		ValidateRequest: true,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, req UploadAvatarReq, _ *gin.Context) (Response[string], error) {
+			return Response[string]{StatusCode: http.StatusOK, Body: req.Owner + "/" + req.File.Filename}, nil
+		},
+	}
+	router := newTestRouter(t, ep)
+
+	newRequest := func(owner string) *http.Request {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		if owner != "" {
+			_ = w.WriteField("owner", owner)
+		}
+		part, err := w.CreateFormFile("file", "me.png")
+		if err != nil {
+			t.Fatalf("create form file failed: %v", err)
+		}
+		_, _ = part.Write([]byte("png"))
+		_ = w.Close()
+		req := httptest.NewRequest(http.MethodPost, "/avatar", &body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		return req
+	}
+
+	rec := serveTestRequest(router, newRequest(""))
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var verr ValidationErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &verr); err != nil {
+		t.Fatalf("unmarshal validation error failed: %v", err)
+	}
+	if len(verr.Fields) != 1 || verr.Fields[0].Field != "owner" || verr.Fields[0].Tag != "required" {
+		t.Fatalf("expected owner required error, got %+v", verr)
+	}
+
+	rec = serveTestRequest(router, newRequest("ann"))
+	if rec.Code != http.StatusOK || rec.Body.String() != `"ann/me.png"` {
+		t.Fatalf("expected bound multipart body, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+type StreamUploadResp struct {
+	Size int64 `json:"size"`
+}
+
+// TestEndpointGinHandler_StreamRequest
+// 这个测试验证流式上传：
+// 1) 请求体为 StreamRequest 时不预先读取，HandlerFunc 可直接流式读取 ctx.Request.Body。
+// 2) 生成的 TS 对该端点改用 fetch 发送 ReadableStream/AsyncIterable，并带 duplex: 'half'，不生成 axios requestConfig。
+// 3) fetch 路径沿用 axios 实例的 baseURL，失败时抛出 AxiosError。
+// 4) 仅以 RequestKind 标记为 stream 的其他请求体（如 RawBytes）仍走 axios。
+func TestEndpointGinHandler_StreamRequest(t *testing.T) {
+	ep := Endpoint[NoParams, NoParams, NoParams, NoParams, StreamRequest, StreamUploadResp]{
+		Name:   "UploadLog",
+		Method: HTTPMethodPut,
+		Path:   "/logs",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ StreamRequest, ctx *gin.Context) (Response[StreamUploadResp], error) {
+			n, err := io.Copy(io.Discard, ctx.Request.Body)
+			if err != nil {
+				return Response[StreamUploadResp]{StatusCode: http.StatusBadRequest}, err
+			}
+			return Response[StreamUploadResp]{StatusCode: http.StatusOK, Body: StreamUploadResp{Size: n}}, nil
+		},
+	}
+	router := newTestRouter(t, ep)
+	payload := strings.Repeat("x", 1<<20)
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodPut, "/logs", strings.NewReader(payload)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"size":1048576`) {
+		t.Fatalf("expected handler to stream the whole body, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{ep})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export type StreamRequestBody = ReadableStream<Uint8Array> | AsyncIterable<Uint8Array>;") {
+		t.Fatalf("expected StreamRequestBody type")
+	}
+	if !strings.Contains(code, "requestBody: StreamRequestBody") {
+		t.Fatalf("expected stream request body parameter")
+	}
+	if !strings.Contains(code, "await sendStreamRequest(UploadLogPut.METHOD, url, requestBody, {") ||
+		!strings.Contains(code, "duplex: 'half'") || !strings.Contains(code, "await fetch(") {
+		t.Fatalf("expected stream endpoint to be sent with fetch and a streaming body")
+	}
+	if strings.Contains(code, "UploadLogPut.requestConfig(") || strings.Contains(code, "axiosClient.request<StreamUploadResp>") {
+		t.Fatalf("expected stream endpoint not to use axios")
+	}
+	if !strings.Contains(code, "const baseURL = axiosClient.defaults.baseURL;") || !strings.Contains(code, "throw new AxiosError(") {
+		t.Fatalf("expected fetch path to honour the axios baseURL and throw AxiosError")
+	}
+
+	hinted := CustomEndpoint[NoParams, NoParams, NoParams, NoParams, RawBytes, StreamUploadResp]{
+		Name:        "UploadChunk",
+		Method:      HTTPMethodPut,
+		Path:        "/chunks",
+		RequestKind: TSKindStream,
+		HandlerFunc: func(_ *gin.Context) {},
+	}
+	code, err = generateAxiosFromEndpoints("/api", "", []EndpointLike{hinted})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "sendStreamRequest") || strings.Contains(code, "AxiosError") {
+		t.Fatalf("expected stream-hinted byte bodies to keep the axios path")
+	}
+	if !strings.Contains(code, "UploadChunkPut.requestConfig(") {
+		t.Fatalf("expected axios requestConfig for stream-hinted byte body")
+	}
+}
+
+type ReportRow struct {
+	Name    string    `json:"name" tsdoc:"姓名 / Name"`
+	Score   int       `json:"score"`
+	Passed  bool      `json:"passed"`
+	Note    *string   `json:"note,omitempty"`
+	TakenAt time.Time `json:"takenAt"`
+	Tags    []string  `json:"tags"`
+}
+
+// TestServeCSV_RoundTrip
+// 这个测试验证 CSV 导出：
+// 1) ServeCSV 返回 text/csv，表头为行结构体的 json 字段名（按声明顺序）。
+// 2) ReadCSV 能把 WriteCSV 的输出还原为类型化的行（数字/布尔/时间/可选/JSON 列）。
+// 3) 返回 []Row 的 stream 端点在 TS 中仍返回 Blob，并额外生成 CSV_COLUMNS 与 parseCSV → parseCSVAs<Row>。
+// 4) []*Row 中的 nil 行返回错误而不是 panic。
+func TestServeCSV_RoundTrip(t *testing.T) {
+	note := "retake"
+	takenAt := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	rows := []ReportRow{
+		{Name: "Alice", Score: 95, Passed: true, TakenAt: takenAt, Tags: []string{"a", "b"}},
+		{Name: "Bob, Jr.", Score: 40, Passed: false, Note: &note, TakenAt: takenAt, Tags: []string{}},
+	}
+	ep := CustomEndpoint[NoParams, NoParams, NoParams, NoParams, NoBody, []ReportRow]{
+		Name:         "ExportReport",
+		Method:       HTTPMethodGet,
+		Path:         "/report.csv",
+		ResponseKind: TSKindStream,
+		HandlerFunc: func(ctx *gin.Context) {
+			if err := ServeCSV(ctx, http.StatusOK, "report.csv", rows); err != nil {
+				t.Errorf("ServeCSV returned error: %v", err)
+			}
+		},
+	}
+	router := newTestRouter(t, ep)
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/report.csv", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected 200 text/csv, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Header().Get("Content-Disposition"), "report.csv") {
+		t.Fatalf("expected attachment filename, got %q", rec.Header().Get("Content-Disposition"))
+	}
+	header, _, _ := strings.Cut(rec.Body.String(), "\n")
+	if header != "name,score,passed,note,takenAt,tags" {
+		t.Fatalf("expected header from json field names, got %q", header)
+	}
+
+	parsed, err := ReadCSV[ReportRow](strings.NewReader(rec.Body.String()))
+	if err != nil {
+		t.Fatalf("ReadCSV returned error: %v", err)
+	}
+	if len(parsed) != 2 || parsed[0].Name != "Alice" || parsed[0].Score != 95 || !parsed[0].Passed || parsed[0].Note != nil {
+		t.Fatalf("expected typed first row, got %+v", parsed)
+	}
+	if parsed[1].Name != "Bob, Jr." || parsed[1].Note == nil || *parsed[1].Note != "retake" || !parsed[1].TakenAt.Equal(takenAt) {
+		t.Fatalf("expected typed second row, got %+v", parsed[1])
+	}
+	if len(parsed[0].Tags) != 2 || parsed[0].Tags[1] != "b" {
+		t.Fatalf("expected json column to round-trip, got %+v", parsed[0].Tags)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, []*ReportRow{&rows[0], nil}); err == nil || !strings.Contains(err.Error(), "csv row 1 is nil") {
+		t.Fatalf("expected nil row error, got %v", err)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{ep})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "static async request(options?: AxiosConvertOptions<never, Blob>): Promise<Blob> {") {
+		t.Fatalf("expected csv endpoint to keep returning Blob")
+	}
+	if !strings.Contains(code, "export async function parseCSVAs<T>(csv: Blob | string, columns: readonly CSVColumnSpec[]): Promise<T[]> {") {
+		t.Fatalf("expected parseCSVAs helper")
+	}
+	if !strings.Contains(code, "    { key: 'score', type: 'number' },\n") ||
+		!strings.Contains(code, "    { key: 'note', type: 'string', optional: true },\n") ||
+		!strings.Contains(code, "    { key: 'tags', type: 'json' },\n") {
+		t.Fatalf("expected CSV_COLUMNS from the row struct")
+	}
+	if !strings.Contains(code, "static parseCSV(csv: Blob | string): Promise<ReportRow[]> {") ||
+		!strings.Contains(code, "return parseCSVAs<ReportRow>(csv, ExportReportGet.CSV_COLUMNS);") {
+		t.Fatalf("expected typed parseCSV on the endpoint class")
+	}
+	if !strings.Contains(code, "export interface ReportRow {") {
+		t.Fatalf("expected row interface to be generated")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "parseCSVAs") {
+		t.Fatalf("expected no csv helpers without csv endpoints")
+	}
+}
+
+// TestEndpoint_TimeoutReturns504
+// 这个测试验证 Endpoint.Timeout：
+// 1) handler 超过超时时间时返回 504。
+// 2) handler 通过 ctx.Request.Context() 观察到取消并提前结束，而不是睡满全程。
+// 3) 在超时内完成的请求正常返回。
+func TestEndpoint_TimeoutReturns504(t *testing.T) {
+	stopped := make(chan error, 1)
+	slow := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:    "Slow",
+		Method:  HTTPMethodGet,
+		Path:    "/slow",
+		Timeout: 20 * time.Millisecond,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, ctx *gin.Context) (Response[CreateAccountResp], error) {
+			select {
+			case <-time.After(5 * time.Second):
+				stopped <- nil
+				return Response[CreateAccountResp]{Body: CreateAccountResp{ID: "late"}}, nil
+			case <-ctx.Request.Context().Done():
+				stopped <- ctx.Request.Context().Err()
+				return Response[CreateAccountResp]{}, ctx.Request.Context().Err()
+			}
+		},
+	}
+	fast := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:    "Fast",
+		Method:  HTTPMethodGet,
+		Path:    "/fast",
+		Timeout: time.Second,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{Body: CreateAccountResp{ID: "ok"}}, nil
+		},
+	}
+	router := newTestRouter(t, slow, fast)
+
+	started := time.Now()
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected handler to stop at the timeout, took %s", elapsed)
+	}
+	if err := <-stopped; err != context.DeadlineExceeded {
+		t.Fatalf("expected handler to observe the deadline, got %v", err)
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"ok"`) {
+		t.Fatalf("expected 200 within the timeout, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestEndpoint_PreloadLinksAndEarlyHints
+// 这个测试验证 PreloadLinks 与 EarlyHints：
+// 1) 最终响应带有配置的 Link preload 头。
+// 2) 开启 EarlyHints 时，handler 执行前先发送带 Link 头的 103 响应。
+func TestEndpoint_PreloadLinksAndEarlyHints(t *testing.T) {
+	links := []string{"</_nuxt/entry.js>; rel=preload; as=script", "</_nuxt/entry.css>; rel=preload; as=style"}
+	ep := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:         "Page",
+		Method:       HTTPMethodGet,
+		Path:         "/page",
+		PreloadLinks: links,
+		EarlyHints:   true,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{Body: CreateAccountResp{ID: "page"}}, nil
+		},
+	}
+	server := httptest.NewServer(newTestRouter(t, ep))
+	defer server.Close()
+
+	var hints []http.Header
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, http.Header(header))
+			}
+			return nil
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL+"/page", nil)
+	if err != nil {
+		t.Fatalf("build request failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected final 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Values("Link"); len(got) != 2 || got[0] != links[0] || got[1] != links[1] {
+		t.Fatalf("expected Link preload headers on the final response, got %v", got)
+	}
+	if len(hints) != 1 || len(hints[0].Values("Link")) != 2 {
+		t.Fatalf("expected one 103 Early Hints response with Link headers, got %v", hints)
+	}
+}
+
+// TestEndpoint_ResponseHeaders
+// 这个测试验证 Response.Headers：
+// 1) 类型化 handler 返回的响应头会写入响应（如 ETag、Cache-Control）。
+// 2) 3xx 状态码配合 Location 头可用于重定向。
+// 3) 未设置 Headers 时响应不变。
+func TestEndpoint_ResponseHeaders(t *testing.T) {
+	cached := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:   "Cached",
+		Method: HTTPMethodGet,
+		Path:   "/cached",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{
+				Body:    CreateAccountResp{ID: "c1"},
+				Headers: map[string]string{"ETag": `"v1"`, "Cache-Control": "max-age=60"},
+			}, nil
+		},
+	}
+	moved := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, NoBody]{
+		Name:   "Moved",
+		Method: HTTPMethodGet,
+		Path:   "/moved",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[NoBody], error) {
+			return Response[NoBody]{StatusCode: http.StatusFound, Headers: map[string]string{"Location": "/cached"}}, nil
+		},
+	}
+	router := newTestRouter(t, cached, moved, buildCreateAccountEndpoint(false))
+
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/cached", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"v1"` || rec.Header().Get("Cache-Control") != "max-age=60" {
+		t.Fatalf("expected typed response headers, got %d %v", rec.Code, rec.Header())
+	}
+	if !strings.Contains(rec.Body.String(), `"id":"c1"`) {
+		t.Fatalf("expected body to be serialized after headers, got %s", rec.Body.String())
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/moved", nil))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/cached" {
+		t.Fatalf("expected 302 redirect to /cached, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(`{"email":"a@b.co","nickname":"ab"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = serveTestRequest(router, req)
+	if rec.Header().Get("ETag") != "" || rec.Header().Get("Location") != "" {
+		t.Fatalf("expected no extra headers without Response.Headers, got %v", rec.Header())
+	}
+}
+
+// TestEndpoint_SetCookies
+// 这个测试验证类型化的 Set-Cookie 声明：
+// 1) ResponseCookie.Set 按声明的属性写入 cookie，非法值返回错误。
+// 2) EndpointMeta 列出该 endpoint 设置的 cookie。
+// 3) 生成的 TS 注释中包含 @setCookie 说明。
+func TestEndpoint_SetCookies(t *testing.T) {
+	session := ResponseCookie{
+		Name:        "session",
+		Path:        "/",
+		MaxAge:      3600,
+		Secure:      true,
+		HTTPOnly:    true,
+		SameSite:    http.SameSiteLaxMode,
+		Description: "Login session",
+	}
+	ep := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name:       "Login",
+		Method:     HTTPMethodPost,
+		Path:       "/login",
+		SetCookies: []ResponseCookie{session},
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, ctx *gin.Context) (Response[CreateAccountResp], error) {
+			if err := session.Set(ctx, "bad;value"); err == nil {
+				t.Errorf("expected an invalid cookie value to be rejected")
+			}
+			if err := session.Set(ctx, "token-1"); err != nil {
+				return Response[CreateAccountResp]{}, err
+			}
+			return Response[CreateAccountResp]{Body: CreateAccountResp{ID: "u1"}}, nil
+		},
+	}
+	router := newTestRouter(t, ep)
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/login", nil))
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusOK || len(cookies) != 1 {
+		t.Fatalf("expected 200 with one cookie, got %d %v", rec.Code, cookies)
+	}
+	c := cookies[0]
+	if c.Name != "session" || c.Value != "token-1" || c.Path != "/" || c.MaxAge != 3600 || !c.Secure || !c.HttpOnly || c.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("expected cookie with declared attributes, got %+v", c)
+	}
+
+	if meta := ep.EndpointMeta(); len(meta.SetCookies) != 1 || meta.SetCookies[0].Name != "session" {
+		t.Fatalf("expected metadata to list the session cookie, got %+v", meta.SetCookies)
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{ep})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, " * @setCookie session (HttpOnly; Secure; SameSite=Lax; Max-Age=3600; Path=/): Login session\n") {
+		t.Fatalf("expected @setCookie doc line in generated TS")
+	}
+}
+
+// TestEndpoint_ErrorResponseEnvelope
+// 这个测试验证错误响应统一为 ErrorResponse：
+// 1) 普通 error 返回 { error }。
+// 2) *APIError（可被包装）可指定状态码、code 与 details。
+// 3) 参数绑定失败同样返回 ErrorResponse。
+func TestEndpoint_ErrorResponseEnvelope(t *testing.T) {
+	plain := NewEndpointNoParams[NoBody, CreateAccountResp]("Plain", HTTPMethodGet, "/plain", func(_ NoBody, _ *gin.Context) (CreateAccountResp, error) {
+		return CreateAccountResp{}, errors.New("boom")
+	})
+	typed := NewEndpointNoParams[NoBody, CreateAccountResp]("Typed", HTTPMethodGet, "/typed", func(_ NoBody, _ *gin.Context) (CreateAccountResp, error) {
+		return CreateAccountResp{}, fmt.Errorf("load account: %w", &APIError{
+			Status:  http.StatusConflict,
+			Code:    "account_exists",
+			Message: "account already exists",
+			Details: map[string]string{"email": "a@b.co"},
+		})
+	})
+	router := newTestRouter(t, plain, typed, buildCreateAccountEndpoint(false))
+
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"error":"boom"}` {
+		t.Fatalf("expected plain error envelope, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodGet, "/typed", nil))
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error response failed: %v", err)
+	}
+	details, _ := body.Details.(map[string]any)
+	if rec.Code != http.StatusConflict || body.Error != "account already exists" || body.Code != "account_exists" || details["email"] != "a@b.co" {
+		t.Fatalf("expected APIError status/code/details, got %d %+v", rec.Code, body)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader("{"))
+	req.Header.Set("Content-Type", "application/json")
+	rec = serveTestRequest(router, req)
+	body = ErrorResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusBadRequest || body.Error == "" {
+		t.Fatalf("expected 400 ErrorResponse for bind errors, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+type sseTickEvent struct {
+	Seq  int    `json:"seq"`
+	Note string `json:"note"`
+}
+
+// TestHybridEndpoint_TypedBindingRawResponse
+// 这个测试验证 HybridEndpoint：
+// 1) path/query 参数与 JSON 请求体按泛型绑定，并通过 HybridContext 交给 handler。
+// 2) handler 可直接使用内嵌的 gin.Context 写出原始响应（如文件下载）。
+// 3) 参数绑定失败返回 400；handler 返回错误且未写响应时返回 ErrorResponse（*APIError 可指定状态码）。
+func TestHybridEndpoint_TypedBindingRawResponse(t *testing.T) {
+	type ReportPath struct {
+		ID string `uri:"id" binding:"required"`
+	}
+	type ReportQuery struct {
+		Format string `form:"format" binding:"required"`
+		Limit  int    `form:"limit"`
+	}
+	type ReportFilter struct {
+		Tags []string `json:"tags"`
+	}
+	download := HybridEndpoint[ReportPath, ReportQuery, NoParams, NoParams, ReportFilter, string]{
+		Name:         "DownloadReport",
+		Method:       HTTPMethodPost,
+		Path:         "/reports/:id/download",
+		ResponseKind: TSKindStream,
+		HandlerFunc: func(ctx *HybridContext[ReportPath, ReportQuery, NoParams, NoParams, ReportFilter]) error {
+			if ctx.QueryParams.Format == "pdf" {
+				return &APIError{Status: http.StatusNotImplemented, Code: "format_unsupported", Message: "pdf is not supported"}
+			}
+			ctx.Header("Content-Disposition", `attachment; filename="report.`+ctx.QueryParams.Format+`"`)
+			body := fmt.Sprintf("%s|%d|%s", ctx.PathParams.ID, ctx.QueryParams.Limit, strings.Join(ctx.RequestBody.Tags, ","))
+			ctx.Data(http.StatusOK, "text/csv", []byte(body))
+			return nil
+		},
+	}
+	router := newTestRouter(t, download)
+
+	rec := serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/reports/r1/download?format=csv&limit=5", strings.NewReader(`{"tags":["a","b"]}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Body.String(); got != "r1|5|a,b" {
+		t.Fatalf("expected typed values in raw response, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="report.csv"` {
+		t.Fatalf("expected handler-written headers, got %q", got)
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/reports/r1/download", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing required query param, got %d", rec.Code)
+	}
+
+	rec = serveTestRequest(router, httptest.NewRequest(http.MethodPost, "/reports/r1/download?format=pdf", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected APIError status 501, got %d", rec.Code)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal error response failed: %v", err)
+	}
+	if body.Code != "format_unsupported" {
+		t.Fatalf("expected APIError code in envelope, got %+v", body)
+	}
+}
+
+// TestBindHelpers_CustomEndpoint
+// 这个测试验证导出的 Bind* 辅助函数：
+// 1) CustomEndpoint 的原始 handler 可用 BindPath/BindQuery/BindHeader/BindCookie/BindJSON 读取强类型参数。
+// 2) 每个辅助函数绑定失败时返回状态码 400 且带对应错误码的 *APIError（path/cookie 直接在测试上下文中调用）。
+func TestBindHelpers_CustomEndpoint(t *testing.T) {
+	type NotePath struct {
+		ID string `uri:"id" binding:"required"`
+	}
+	type NoteQuery struct {
+		Limit int `form:"limit" binding:"required"`
+	}
+	type NoteHeader struct {
+		Tenant string `header:"X-Tenant" binding:"required"`
+	}
+	type NoteCookie struct {
+		Session string
+	}
+	type NoteReq struct {
+		Text string `json:"text" binding:"required"`
+	}
+	writeBindError := func(ctx *gin.Context, err error) {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Errorf("expected *APIError, got %T", err)
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.JSON(apiErr.Status, ErrorResponse{Error: apiErr.Message, Code: apiErr.Code})
+	}
+	note := CustomEndpoint[NotePath, NoteQuery, NoteHeader, NoteCookie, NoteReq, string]{
+		Name:   "UpdateNote",
+		Method: HTTPMethodPut,
+		Path:   "/notes/:id",
+		HandlerFunc: func(ctx *gin.Context) {
+			path, err := BindPath[NotePath](ctx)
+			if err != nil {
+				writeBindError(ctx, err)
+				return
+			}
+			query, err := BindQuery[NoteQuery](ctx)
+			if err != nil {
+				writeBindError(ctx, err)
+				return
+			}
+			header, err := BindHeader[NoteHeader](ctx)
+			if err != nil {
+				writeBindError(ctx, err)
+				return
+			}
+			cookie, err := BindCookie[NoteCookie](ctx)
+			if err != nil {
+				writeBindError(ctx, err)
+				return
+			}
+			req, err := BindJSON[NoteReq](ctx)
+			if err != nil {
+				writeBindError(ctx, err)
+				return
+			}
+			ctx.String(http.StatusOK, "%s|%d|%s|%s|%s", path.ID, query.Limit, header.Tenant, cookie.Session, req.Text)
+		},
+	}
+	router := newTestRouter(t, note)
+
+	newRequest := func(target string, tenant string, session string, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if tenant != "" {
+			req.Header.Set("X-Tenant", tenant)
+		}
+		if session != "" {
+			req.AddCookie(&http.Cookie{Name: "Session", Value: session})
+		}
+		return req
+	}
+
+	rec := serveTestRequest(router, newRequest("/notes/n1?limit=3", "acme", "7", `{"text":"hi"}`))
+	if rec.Code != http.StatusOK || rec.Body.String() != "n1|3|acme|7|hi" {
+		t.Fatalf("expected typed values, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	cases := []struct {
+		name string
+		req  *http.Request
+		code string
+	}{
+		{"query", newRequest("/notes/n1", "acme", "7", `{"text":"hi"}`), "invalid_query_params"},
+		{"header", newRequest("/notes/n1?limit=3", "", "7", `{"text":"hi"}`), "invalid_header_params"},
+		{"json", newRequest("/notes/n1?limit=3", "acme", "7", `{}`), "invalid_json_body"},
+	}
+	for _, tc := range cases {
+		rec := serveTestRequest(router, tc.req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", tc.name, rec.Code, rec.Body.String())
+		}
+		var body ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: unmarshal error response failed: %v", tc.name, err)
+		}
+		if body.Code != tc.code || body.Error == "" {
+			t.Fatalf("%s: expected code %q, got %+v", tc.name, tc.code, body)
+		}
+	}
+
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = newRequest("/notes/", "acme", "abc", `{}`)
+	if _, err := BindPath[NotePath](ctx); err == nil || err.(*APIError).Code != "invalid_path_params" {
+		t.Fatalf("expected invalid_path_params error, got %v", err)
+	}
+	type NumericCookie struct {
+		Session int
+	}
+	if _, err := BindCookie[NumericCookie](ctx); err == nil || err.(*APIError).Code != "invalid_cookie_params" {
+		t.Fatalf("expected invalid_cookie_params error, got %v", err)
+	}
+}
+
+// TestEndpoint_QueryDefaultTags
+// 这个测试验证 query 参数的 default tag：
+// 1) 缺失或为空的 query 参数使用 default 值（int/string/bool），并在校验之前生效，满足 binding:"required"。
+// 2) 请求中携带的值会覆盖 default。
+// 3) 生成的 TS 中带 default tag 的 query 字段为可选。
+func TestEndpoint_QueryDefaultTags(t *testing.T) {
+	type ListQuery struct {
+		Page     int    `form:"page" default:"1" binding:"required"`
+		PageSize int    `form:"pageSize" default:"20"`
+		Sort     string `form:"sort" default:"name"`
+		Desc     bool   `form:"desc" default:"true"`
+		Keyword  string `form:"keyword"`
+	}
+	list := Endpoint[NoParams, ListQuery, NoParams, NoParams, NoBody, string]{
+		Name:   "ListItems",
+		Method: HTTPMethodGet,
+		Path:   "/items",
+		HandlerFunc: func(_ NoParams, query ListQuery, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[string], error) {
+			return Response[string]{StatusCode: http.StatusOK, Body: fmt.Sprintf("%d|%d|%s|%t|%s", query.Page, query.PageSize, query.Sort, query.Desc, query.Keyword)}, nil
+		},
+	}
+	router := newTestRouter(t, list)
+
+	cases := []struct {
+		target string
+		want   string
+	}{
+		{"/items", `"1|20|name|true|"`},
+		{"/items?page=&sort=", `"1|20|name|true|"`},
+		{"/items?page=3&pageSize=50&sort=age&desc=false&keyword=go", `"3|50|age|false|go"`},
+	}
+	for _, tc := range cases {
+		rec := serveTestRequest(router, httptest.NewRequest(http.MethodGet, tc.target, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != tc.want {
+			t.Fatalf("%s: expected %s, got %d: %s", tc.target, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{list})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  page?: number;") || !strings.Contains(code, "  desc?: boolean;") {
+		t.Fatalf("expected defaulted query fields to be optional")
+	}
+	if !strings.Contains(code, "  keyword: string;") {
+		t.Fatalf("expected query field without default to stay required")
+	}
+}
+
+// TestBuildGinGroup_DuplicateRoutes
+// 这个测试验证重复路由检测：
+// 1) 同一 method+path（仅占位符名不同）返回描述性错误而不是 gin panic。
+// 2) 被禁用的端点不参与冲突检测。
+// 3) websocket 端点的重复路径同样返回错误。
+func TestBuildGinGroup_DuplicateRoutes(t *testing.T) {
+	oldMode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() {
+		gin.SetMode(oldMode)
+	})
+
+	handler := func(PathByURIID, NoParams, NoParams, NoParams, NoBody, *gin.Context) (Response[CreateAccountResp], error) {
+		return Response[CreateAccountResp]{StatusCode: http.StatusOK}, nil
+	}
+	first := Endpoint[PathByURIID, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name: "GetPerson", Method: HTTPMethodGet, Path: "/persons/:id", HandlerFunc: handler,
+	}
+	second := Endpoint[PathByURIID, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name: "GetPersonAgain", Method: HTTPMethodGet, Path: "/persons/:personID", HandlerFunc: handler,
+	}
+	other := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, CreateAccountResp]{
+		Name: "Profile", Method: HTTPMethodGet, Path: "/profile",
+		HandlerFunc: func(NoParams, NoParams, NoParams, NoParams, NoBody, *gin.Context) (Response[CreateAccountResp], error) {
+			return Response[CreateAccountResp]{StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	api := ServerAPI{BasePath: "/api", GroupPath: "/v1", Endpoints: []EndpointLike{first, other, second}}
+	_, err := api.BuildGinGroup(gin.New())
+	if err == nil || err.Error() != "duplicate route GET /persons/:personID at endpoints[0] and [2]" {
+		t.Fatalf("expected duplicate route error, got %v", err)
+	}
+
+	second.Enabled = func() bool { return false }
+	api.Endpoints = []EndpointLike{first, other, second}
+	if _, err := api.BuildGinGroup(gin.New()); err != nil {
+		t.Fatalf("expected disabled endpoint to be skipped, got %v", err)
+	}
+
+	wsA := NewWebSocketEndpoint()
+	wsA.Name = "chat"
+	wsA.Path = "/chat"
+	wsB := NewWebSocketEndpoint()
+	wsB.Name = "chatAgain"
+	wsB.Path = "/chat"
+	wsAPI := WebSocketAPI{BasePath: "/ws", GroupPath: "/v1", Endpoints: []WebSocketEndpointLike{wsA, wsB}}
+	_, err = wsAPI.BuildGinGroup(gin.New())
+	if err == nil || err.Error() != "duplicate route GET /chat at endpoints[0] and [1]" {
+		t.Fatalf("expected duplicate websocket route error, got %v", err)
+	}
+}
+
+// TestSSEEndpoint_PublishAndDisconnect
+// 这个测试验证 SSE 端点的推送与断开检测：
+// 1) 响应头为 text/event-stream，事件以 JSON data 帧推送。
+// 2) SendTo 只推送给指定客户端，未知客户端返回错误。
+// 3) 客户端取消请求后，服务端通过 ctx.Request.Context().Done() 移除客户端并触发 OnDisconnect。
+func TestSSEEndpoint_PublishAndDisconnect(t *testing.T) {
+	connected := make(chan string, 1)
+	disconnected := make(chan string, 1)
+	sse := &SSEEndpoint[sseTickEvent]{
+		Name: "Ticks",
+		Path: "/ticks",
+		OnConnect: func(_ *gin.Context, clientID string) error {
+			connected <- clientID
+			return nil
+		},
+		OnDisconnect: func(clientID string) {
+			disconnected <- clientID
+		},
+	}
+	server := httptest.NewServer(newTestRouter(t, sse))
+	defer server.Close()
+
+	reqCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL+"/ticks", nil)
+	if err != nil {
+		t.Fatalf("build request failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected 200 text/event-stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	clientID := <-connected
+
+	if err := sse.Publish(sseTickEvent{Seq: 1, Note: "all"}); err != nil {
+		t.Fatalf("Publish returned error: %v", err)
+	}
+	if err := sse.SendTo(clientID, sseTickEvent{Seq: 2, Note: "direct"}); err != nil {
+		t.Fatalf("SendTo returned error: %v", err)
+	}
+	if err := sse.SendTo("missing", sseTickEvent{}); err == nil {
+		t.Fatalf("expected SendTo an unknown client to fail")
+	}
+	reader := bufio.NewReader(resp.Body)
+	for _, want := range []string{`data: {"seq":1,"note":"all"}`, "", `data: {"seq":2,"note":"direct"}`} {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event failed: %v", err)
+		}
+		if got := strings.TrimRight(line, "\n"); got != want {
+			t.Fatalf("expected frame line %q, got %q", want, got)
+		}
+	}
+
+	cancel()
+	select {
+	case id := <-disconnected:
+		if id != clientID {
+			t.Fatalf("expected OnDisconnect for %s, got %s", clientID, id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected OnDisconnect after the client went away")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for sse.ConnectedCount() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected disconnected client to be removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,261 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultSSEBufferSize = 16
+
+// SSEEndpoint is a Server-Sent Events endpoint streaming Event values as JSON data frames.
+// The generated TS client exposes a typed EventSource wrapper for it.
+// SSEEndpoint 是 Server-Sent Events 端点，以 JSON data 帧推送 Event；生成的 TS 客户端提供类型化的 EventSource 封装。
+type SSEEndpoint[Event any] struct {
+	Name        string
+	Path        string
+	Description string
+
+	// Optional pre-stream check (e.g. token auth). Returning an error rejects the request
+	// with 401 unless the hook already wrote a response.
+	// 可选的建立流之前的校验（如 token 鉴权）；返回错误时拒绝请求（若未写响应则返回 401）。
+	OnConnect    func(ctx *gin.Context, clientID string) error
+	OnDisconnect func(clientID string)
+
+	// Optional keepalive. When KeepAliveInterval > 0, a comment frame is sent at that interval
+	// so proxies do not close idle streams.
+	// 可选保活：KeepAliveInterval > 0 时按间隔发送注释帧，避免代理关闭空闲连接。
+	KeepAliveInterval time.Duration
+
+	// Pending events buffered per client (defaults to 16). A client whose buffer is full is disconnected.
+	// 每个客户端缓冲的待发送事件数（默认 16）；缓冲区满的客户端会被断开。
+	BufferSize int
+
+	Middlewares []gin.HandlerFunc
+	Enabled     func() bool
+
+	hub *sseHub
+}
+
+type sseClient struct {
+	id   string
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *sseClient) close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// deliver queues a frame without blocking; a full buffer disconnects the client.
+func (c *sseClient) deliver(data []byte) error {
+	select {
+	case <-c.done:
+		return fmt.Errorf("sse client %s is closed", c.id)
+	default:
+	}
+	select {
+	case c.send <- data:
+		return nil
+	default:
+		c.close()
+		return fmt.Errorf("sse client %s buffer is full", c.id)
+	}
+}
+
+type sseHub struct {
+	mu      sync.RWMutex
+	clients map[string]*sseClient
+}
+
+func newSSEHub() *sseHub {
+	return &sseHub{clients: map[string]*sseClient{}}
+}
+
+func (h *sseHub) add(bufferSize int) *sseClient {
+	client := &sseClient{id: uuid.NewString(), send: make(chan []byte, bufferSize), done: make(chan struct{})}
+	h.mu.Lock()
+	h.clients[client.id] = client
+	h.mu.Unlock()
+	return client
+}
+
+func (h *sseHub) remove(id string) {
+	h.mu.Lock()
+	if client, ok := h.clients[id]; ok {
+		client.close()
+	}
+	delete(h.clients, id)
+	h.mu.Unlock()
+}
+
+func (h *sseHub) get(id string) *sseClient {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.clients[id]
+}
+
+func (h *sseHub) snapshot() []*sseClient {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	clients := make([]*sseClient, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+func (h *sseHub) count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+func (s *SSEEndpoint[Event]) ensureHub() {
+	if s.hub == nil {
+		s.hub = newSSEHub()
+	}
+}
+
+// EndpointMeta exposes metadata for TS generation.
+// EndpointMeta 暴露 TS 生成所需的元数据。
+func (s *SSEEndpoint[Event]) EndpointMeta() EndpointMeta {
+	return EndpointMeta{
+		Name:             s.Name,
+		Method:           HTTPMethodGet,
+		Path:             s.Path,
+		Description:      s.Description,
+		PathParamsType:   typeOf[NoParams](),
+		QueryParamsType:  typeOf[NoParams](),
+		HeaderParamsType: typeOf[NoParams](),
+		CookieParamsType: typeOf[NoParams](),
+		RequestBodyType:  typeOf[NoBody](),
+		Responses: []ResponseMeta{{
+			StatusCode: http.StatusOK,
+			BodyType:   typeOf[Event](),
+		}},
+	}
+}
+
+// EndpointTSHints marks the response as an event stream.
+// EndpointTSHints 将响应标记为事件流。
+func (s *SSEEndpoint[Event]) EndpointTSHints() EndpointTSHints {
+	return EndpointTSHints{ResponseKind: TSKindEventStream}
+}
+
+// EndpointMiddlewares returns middleware registered before the stream handler.
+// EndpointMiddlewares 返回在事件流处理器之前注册的中间件。
+func (s *SSEEndpoint[Event]) EndpointMiddlewares() []gin.HandlerFunc {
+	return s.Middlewares
+}
+
+// EndpointEnabled reports whether this endpoint should be registered and exported.
+// EndpointEnabled 返回该 endpoint 是否应被注册与导出。
+func (s *SSEEndpoint[Event]) EndpointEnabled() bool {
+	return s.Enabled == nil || s.Enabled()
+}
+
+// GinHandler streams events to the client until it disconnects or is dropped.
+// GinHandler 持续向客户端推送事件，直到客户端断开或被移除。
+func (s *SSEEndpoint[Event]) GinHandler() gin.HandlerFunc {
+	s.ensureHub()
+	return func(ctx *gin.Context) {
+		bufferSize := s.BufferSize
+		if bufferSize <= 0 {
+			bufferSize = defaultSSEBufferSize
+		}
+		client := s.hub.add(bufferSize)
+		defer s.hub.remove(client.id)
+		if s.OnConnect != nil {
+			if err := s.OnConnect(ctx, client.id); err != nil {
+				if !ctx.Writer.Written() {
+					ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+					return
+				}
+				ctx.Abort()
+				return
+			}
+		}
+		if s.OnDisconnect != nil {
+			defer s.OnDisconnect(client.id)
+		}
+
+		header := ctx.Writer.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		header.Set("X-Accel-Buffering", "no")
+		ctx.Status(http.StatusOK)
+		ctx.Writer.Flush()
+
+		var keepAlive <-chan time.Time
+		if s.KeepAliveInterval > 0 {
+			ticker := time.NewTicker(s.KeepAliveInterval)
+			defer ticker.Stop()
+			keepAlive = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Request.Context().Done():
+				return
+			case <-client.done:
+				return
+			case data := <-client.send:
+				if _, err := fmt.Fprintf(ctx.Writer, "data: %s\n\n", data); err != nil {
+					return
+				}
+				ctx.Writer.Flush()
+			case <-keepAlive:
+				if _, err := ctx.Writer.WriteString(": keepalive\n\n"); err != nil {
+					return
+				}
+				ctx.Writer.Flush()
+			}
+		}
+	}
+}
+
+// Publish sends an event to every connected client. Clients with a full buffer are disconnected.
+// Publish 向所有已连接客户端推送事件；缓冲区已满的客户端会被断开。
+func (s *SSEEndpoint[Event]) Publish(event Event) error {
+	s.ensureHub()
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, client := range s.hub.snapshot() {
+		if err := client.deliver(data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// SendTo sends an event to a single client by ID.
+// SendTo 按客户端 ID 向单个客户端推送事件。
+func (s *SSEEndpoint[Event]) SendTo(clientID string, event Event) error {
+	s.ensureHub()
+	client := s.hub.get(clientID)
+	if client == nil {
+		return fmt.Errorf("sse client not found: %s", clientID)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return client.deliver(data)
+}
+
+// ConnectedCount returns the number of connected clients.
+// ConnectedCount 返回当前连接的客户端数量。
+func (s *SSEEndpoint[Event]) ConnectedCount() int {
+	s.ensureHub()
+	return s.hub.count()
+}
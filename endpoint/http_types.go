@@ -1,8 +1,11 @@
 package endpoint
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -60,6 +63,9 @@ type Response[T any] struct {
 	StatusCode  int    `json:"statusCode"`
 	Body        T      `json:"body,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Headers are written before the body, e.g. ETag, Cache-Control, or Location for 3xx redirects.
+	// Headers 在写入响应体之前设置，如 ETag、Cache-Control，或 3xx 重定向的 Location。
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // EndpointMeta is the metadata view used to generate TypeScript from Endpoint.
@@ -76,6 +82,15 @@ type EndpointMeta struct {
 	CookieParamsType   reflect.Type
 	RequestBodyType    reflect.Type
 	Responses          []ResponseMeta
+	// Batch marks a bulk endpoint whose body is []BatchItemResult[T, E]; see NewBatchResponse.
+	// Batch 标记批量接口，其响应体为 []BatchItemResult[T, E]；参见 NewBatchResponse。
+	Batch bool
+	// SetCookies lists the cookies the endpoint sets; they are documented in generated TS and OpenAPI.
+	// SetCookies 列出 endpoint 会设置的 cookie，会写入生成的 TS 与 OpenAPI 文档。
+	SetCookies []ResponseCookie
+	// Deprecated is the deprecation reason of the endpoint; see Endpoint.Deprecated.
+	// Deprecated 为 endpoint 的弃用原因；参见 Endpoint.Deprecated。
+	Deprecated string
 }
 
 // ResponseMeta is the response metadata used to generate TypeScript.
@@ -93,6 +108,18 @@ type EndpointLike interface {
 	GinHandler() gin.HandlerFunc
 }
 
+// EndpointEnabledProvider lets an endpoint opt out of registration and TS generation at runtime.
+// EndpointEnabledProvider 允许 endpoint 在运行时决定是否注册及是否参与 TS 生成。
+type EndpointEnabledProvider interface {
+	EndpointEnabled() bool
+}
+
+// EndpointMiddlewareProvider lets an endpoint attach gin middleware that runs before its handler.
+// EndpointMiddlewareProvider 允许 endpoint 挂载在其 handler 之前执行的 gin 中间件。
+type EndpointMiddlewareProvider interface {
+	EndpointMiddlewares() []gin.HandlerFunc
+}
+
 // Endpoint is a strongly-typed server API definition.
 // HandlerFunc receives typed params/body and returns a typed Response.
 // Endpoint 是强类型服务器端 API 定义，HandlerFunc 接收强类型参数并返回强类型 Response。
@@ -108,7 +135,52 @@ type Endpoint[PP, QP, HP, CP, Req, Resp any] struct {
 	CookieParams       CP
 	RequestBody        Req
 	Responses          []Response[Resp]
-	HandlerFunc        func(pathParams PP, queryParams QP, headerParams HP, cookieParams CP, requestBody Req, ctx *gin.Context) (Response[Resp], error)
+	// ValidateRequest runs `binding`/`validate` tags on the request body and answers 422 with per-field errors.
+	// ValidateRequest 开启后会按 `binding`/`validate` 标签校验请求体，失败时返回带字段错误的 422。
+	ValidateRequest bool
+	// Middlewares run after group middleware and before the typed handler.
+	// Middlewares 在分组中间件之后、类型化 handler 之前执行。
+	Middlewares []gin.HandlerFunc
+	// Enabled gates registration and TS generation; nil means always enabled.
+	// Enabled 控制是否注册与生成 TS；为 nil 时始终启用。
+	Enabled func() bool
+	// RequestTypeOverride / ResponseTypeOverride force the TS request/response types
+	// when the serialized shape differs from Req/Resp. Nil keeps generic inference.
+	// RequestTypeOverride / ResponseTypeOverride 在实际序列化结构与 Req/Resp 不一致时强制指定 TS 类型；为 nil 时按泛型推断。
+	RequestTypeOverride  reflect.Type
+	ResponseTypeOverride reflect.Type
+	// Batch declares a bulk endpoint with per-item results; Resp must be []BatchItemResult[T, E].
+	// TS generation emits the per-item result array type and the splitBatchResults helper.
+	// Batch 声明带逐条结果的批量接口，Resp 必须为 []BatchItemResult[T, E]；
+	// TS 生成会输出逐条结果数组类型与 splitBatchResults 辅助函数。
+	Batch bool
+	// Timeout bounds HandlerFunc: ctx.Request carries a context that is cancelled after Timeout, and
+	// the endpoint answers 504 if it expires. The handler must observe ctx.Request.Context() to stop early.
+	// Timeout 限制 HandlerFunc 的执行时间：ctx.Request 携带超时后取消的 context，超时则返回 504；
+	// handler 需监听 ctx.Request.Context() 才能提前结束。
+	Timeout time.Duration
+	// ClientTimeout is emitted as the generated axios request timeout (e.g. for slow report endpoints).
+	// It is independent of Timeout, which bounds the handler on the server. Zero emits no timeout.
+	// ClientTimeout 会作为生成的 axios 请求超时时间输出（如耗时较长的报表接口）；
+	// 与限制服务端 handler 的 Timeout 相互独立；为 0 时不输出。
+	ClientTimeout time.Duration
+	// PreloadLinks are Link header values (e.g. `</_nuxt/entry.js>; rel=preload; as=script`) added to the response.
+	// With EarlyHints, they are also sent as a 103 Early Hints response before the handler runs.
+	// PreloadLinks 为添加到响应中的 Link 头（如 `</_nuxt/entry.js>; rel=preload; as=script`）；
+	// 开启 EarlyHints 时，还会在 handler 执行前以 103 Early Hints 响应提前发送。
+	PreloadLinks []string
+	EarlyHints   bool
+	// Deprecated marks the endpoint as deprecated with a reason (e.g. "use GetUserV2 instead");
+	// the generated TS class and request function get `@deprecated <reason>` so editors warn on use.
+	// Deprecated 以原因（如 "use GetUserV2 instead"）标记 endpoint 已弃用；
+	// 生成的 TS 类与请求函数会带上 `@deprecated <reason>`，编辑器会给出警告。
+	Deprecated string
+	// SetCookies declares the cookies this endpoint sets (set them with ResponseCookie.Set).
+	// They are documented in generated TS and OpenAPI, since HttpOnly cookies are invisible to JS.
+	// SetCookies 声明该 endpoint 设置的 cookie（通过 ResponseCookie.Set 写入）；
+	// 由于 HttpOnly cookie 对 JS 不可见，会在生成的 TS 与 OpenAPI 中注明。
+	SetCookies  []ResponseCookie
+	HandlerFunc func(pathParams PP, queryParams QP, headerParams HP, cookieParams CP, requestBody Req, ctx *gin.Context) (Response[Resp], error)
 }
 
 // EndpointMeta exposes metadata for TS generation.
@@ -125,68 +197,148 @@ func (s Endpoint[PP, QP, HP, CP, Req, Resp]) EndpointMeta() EndpointMeta {
 		HeaderParamsType:   typeOf[HP](),
 		CookieParamsType:   typeOf[CP](),
 		RequestBodyType:    typeOf[Req](),
+		Batch:              s.Batch,
+		SetCookies:         s.SetCookies,
+		Deprecated:         s.Deprecated,
 	}
 	if len(s.Responses) == 0 {
 		meta.Responses = []ResponseMeta{{
 			StatusCode: 200,
 			BodyType:   typeOf[Resp](),
 		}}
-		return meta
+	} else {
+		meta.Responses = make([]ResponseMeta, 0, len(s.Responses))
+		for _, r := range s.Responses {
+			meta.Responses = append(meta.Responses, ResponseMeta{
+				StatusCode:  r.StatusCode,
+				BodyType:    typeOf[Resp](),
+				Description: r.Description,
+			})
+		}
 	}
-	meta.Responses = make([]ResponseMeta, 0, len(s.Responses))
-	for _, r := range s.Responses {
+	applyEndpointTypeOverrides(&meta, s.RequestTypeOverride, s.ResponseTypeOverride)
+	if s.ValidateRequest && !isNoType(meta.RequestBodyType) {
 		meta.Responses = append(meta.Responses, ResponseMeta{
-			StatusCode:  r.StatusCode,
-			BodyType:    typeOf[Resp](),
-			Description: r.Description,
+			StatusCode:  http.StatusUnprocessableEntity,
+			BodyType:    typeOf[ValidationErrorResponse](),
+			Description: "Request validation failed",
 		})
 	}
 	return meta
 }
 
+// applyEndpointTypeOverrides replaces the inferred request body type and every response body type.
+func applyEndpointTypeOverrides(meta *EndpointMeta, requestType reflect.Type, responseType reflect.Type) {
+	if requestType != nil {
+		meta.RequestBodyType = requestType
+	}
+	if responseType != nil {
+		for i := range meta.Responses {
+			meta.Responses[i].BodyType = responseType
+		}
+	}
+}
+
+// EndpointTSHints customizes TS generation.
+// EndpointTSHints 自定义 TS 生成。
+func (s Endpoint[PP, QP, HP, CP, Req, Resp]) EndpointTSHints() EndpointTSHints {
+	return EndpointTSHints{Timeout: s.ClientTimeout}
+}
+
+// EndpointMiddlewares returns middleware registered before the typed handler.
+// EndpointMiddlewares 返回在类型化 handler 之前注册的中间件。
+func (s Endpoint[PP, QP, HP, CP, Req, Resp]) EndpointMiddlewares() []gin.HandlerFunc {
+	return s.Middlewares
+}
+
+// EndpointEnabled reports whether this endpoint should be registered and exported.
+// EndpointEnabled 返回该 endpoint 是否应被注册与导出。
+func (s Endpoint[PP, QP, HP, CP, Req, Resp]) EndpointEnabled() bool {
+	return s.Enabled == nil || s.Enabled()
+}
+
 // GinHandler builds a gin.HandlerFunc that auto-binds params/body and calls HandlerFunc.
-// GinHandler 会自动绑定参数/请求体并调用 HandlerFunc。
+// Bodies with *multipart.FileHeader fields are bound from multipart/form-data via `form` tags;
+// a StreamRequest body is left unread so HandlerFunc can stream ctx.Request.Body.
+// GinHandler 会自动绑定参数/请求体并调用 HandlerFunc；含 *multipart.FileHeader 字段的请求体按 `form` 标签从 multipart/form-data 绑定；
+// StreamRequest 请求体不会被读取，HandlerFunc 可直接流式读取 ctx.Request.Body。
 func (s Endpoint[PP, QP, HP, CP, Req, Resp]) GinHandler() gin.HandlerFunc {
+	multipartBody := hasMultipartFileFields(typeOf[Req]())
+	streamBody := typeOf[Req]() == reflect.TypeOf(StreamRequest{})
 	return func(ctx *gin.Context) {
-		pathParams, err := bindStructT[PP](ctx.ShouldBindUri)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
+		if len(s.PreloadLinks) > 0 {
+			writePreloadLinks(ctx, s.PreloadLinks, s.EarlyHints)
 		}
-		queryParams, err := bindStructT[QP](ctx.ShouldBindQuery)
+		pathParams, queryParams, headerParams, cookieParams, err := bindRequestParamsT[PP, QP, HP, CP](ctx)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			writeErrorResponse(ctx, http.StatusBadRequest, err)
 			return
 		}
-		headerParams, err := bindStructT[HP](ctx.ShouldBindHeader)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
+		var requestBody Req
+		switch {
+		case streamBody:
+			// Leave ctx.Request.Body unread for the handler.
+		case multipartBody && s.ValidateRequest:
+			requestBody, err = bindMultipartStructNoValidateT[Req](ctx)
+		case multipartBody:
+			requestBody, err = bindMultipartStructT[Req](ctx)
+		case s.ValidateRequest:
+			requestBody, err = bindJSONStructNoValidateT[Req](ctx)
+		default:
+			requestBody, err = bindJSONStructT[Req](ctx)
 		}
-		cookieParams, err := bindCookieStructT[CP](ctx)
 		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			writeErrorResponse(ctx, http.StatusBadRequest, err)
 			return
 		}
-		requestBody, err := bindJSONStructT[Req](ctx)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-			return
+		if s.ValidateRequest {
+			if verr := validateRequestBody(requestBody); verr != nil {
+				ctx.JSON(http.StatusUnprocessableEntity, verr)
+				return
+			}
 		}
 
+		if s.Timeout > 0 {
+			timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), s.Timeout)
+			defer cancel()
+			ctx.Request = ctx.Request.WithContext(timeoutCtx)
+		}
 		resp, callErr := s.HandlerFunc(pathParams, queryParams, headerParams, cookieParams, requestBody, ctx)
+		if s.Timeout > 0 && errors.Is(ctx.Request.Context().Err(), context.DeadlineExceeded) {
+			if !ctx.Writer.Written() {
+				ctx.JSON(http.StatusGatewayTimeout, ErrorResponse{Error: "endpoint timed out"})
+			}
+			return
+		}
 		status := http.StatusOK
 		if resp.StatusCode > 0 {
 			status = resp.StatusCode
 		}
+		for k, v := range resp.Headers {
+			ctx.Header(k, v)
+		}
 		if callErr != nil {
-			ctx.JSON(status, gin.H{"error": callErr.Error()})
+			writeErrorResponse(ctx, status, callErr)
 			return
 		}
 		ctx.JSON(status, resp.Body)
 	}
 }
 
+// writePreloadLinks sets the Link headers and, with earlyHints, sends them in a 103 response.
+// gin's writer only tracks the final status, so the 103 is written to the underlying writer.
+func writePreloadLinks(ctx *gin.Context, links []string, earlyHints bool) {
+	for _, link := range links {
+		ctx.Writer.Header().Add("Link", link)
+	}
+	if !earlyHints {
+		return
+	}
+	if u, ok := ctx.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		u.Unwrap().WriteHeader(http.StatusEarlyHints)
+	}
+}
+
 func typeOf[T any]() reflect.Type {
 	var p *T
 	return reflect.TypeOf(p).Elem()
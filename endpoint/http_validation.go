@@ -0,0 +1,198 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationFieldError describes one failed field rule.
+// ValidationFieldError 描述单个字段未通过的校验规则。
+type ValidationFieldError struct {
+	Field   string `json:"field" tsdoc:"字段路径(JSON 名) / Field path using JSON names"`
+	Tag     string `json:"tag" tsdoc:"未通过的规则 / Failed rule tag"`
+	Param   string `json:"param,omitempty" tsdoc:"规则参数 / Rule parameter"`
+	Message string `json:"message" tsdoc:"错误描述 / Human readable message"`
+}
+
+// ValidationErrorResponse is the 422 body returned when request validation fails.
+// ValidationErrorResponse 是请求校验失败时返回的 422 响应体。
+type ValidationErrorResponse struct {
+	Error  string                 `json:"error" tsdoc:"错误摘要 / Error summary"`
+	Fields []ValidationFieldError `json:"fields" tsdoc:"字段错误列表 / Per-field errors"`
+}
+
+var (
+	requestValidatorsOnce sync.Once
+	requestValidators     []*validator.Validate
+)
+
+// requestBodyValidators returns validators for `binding` and `validate` tags.
+// Field names are reported by JSON name so they match the TS interfaces.
+func requestBodyValidators() []*validator.Validate {
+	requestValidatorsOnce.Do(func() {
+		for _, tag := range []string{"binding", "validate"} {
+			v := validator.New(validator.WithRequiredStructEnabled())
+			v.SetTagName(tag)
+			v.RegisterTagNameFunc(func(f reflect.StructField) string {
+				name, _, ok := jsonFieldMeta(f)
+				if !ok {
+					return f.Name
+				}
+				return name
+			})
+			requestValidators = append(requestValidators, v)
+		}
+	})
+	return requestValidators
+}
+
+// bindJSONStructNoValidateT decodes JSON without gin's implicit `binding` validation,
+// so validateRequestBody can report every failed rule in one response.
+func bindJSONStructNoValidateT[T any](ctx *gin.Context) (T, error) {
+	var v T
+	if isNoType(typeOf[T]()) {
+		return v, nil
+	}
+	if ctx.Request == nil || ctx.Request.Body == nil {
+		return v, errors.New("invalid request")
+	}
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&v); err != nil && !errors.Is(err, io.EOF) {
+		return v, err
+	}
+	return v, nil
+}
+
+// multipartMaxMemory matches gin's in-memory limit when parsing multipart/form-data.
+const multipartMaxMemory = 32 << 20
+
+// bindMultipartStructNoValidateT maps multipart/form-data fields and file parts like bindMultipartStructT,
+// but without gin's implicit `binding` validation; see bindJSONStructNoValidateT.
+func bindMultipartStructNoValidateT[T any](ctx *gin.Context) (T, error) {
+	var v T
+	if err := ctx.Request.ParseMultipartForm(multipartMaxMemory); err != nil {
+		return v, err
+	}
+	if err := binding.MapFormWithTag(&v, ctx.Request.MultipartForm.Value, "form"); err != nil {
+		return v, err
+	}
+	setMultipartFileFields(reflect.ValueOf(&v).Elem(), ctx.Request.MultipartForm.File)
+	return v, nil
+}
+
+// setMultipartFileFields assigns *multipart.FileHeader and []*multipart.FileHeader fields (or their
+// non-pointer forms) of struct v from files, keyed by `form` tag or field name.
+func setMultipartFileFields(v reflect.Value, files map[string][]*multipart.FileHeader) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	headerPtrType := reflect.TypeOf(&multipart.FileHeader{})
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		key := strings.Split(f.Tag.Get("form"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = f.Name
+		}
+		headers := files[key]
+		if len(headers) == 0 {
+			continue
+		}
+		field := v.Field(i)
+		switch {
+		case f.Type == headerPtrType:
+			field.Set(reflect.ValueOf(headers[0]))
+		case f.Type == headerPtrType.Elem():
+			field.Set(reflect.ValueOf(*headers[0]))
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem() == headerPtrType:
+			field.Set(reflect.ValueOf(headers))
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem() == headerPtrType.Elem():
+			items := reflect.MakeSlice(f.Type, len(headers), len(headers))
+			for j, h := range headers {
+				items.Index(j).Set(reflect.ValueOf(*h))
+			}
+			field.Set(items)
+		}
+	}
+}
+
+// validateRequestBody runs `binding` and `validate` tags on body.
+// It returns nil when body passes or is not a struct.
+func validateRequestBody(body any) *ValidationErrorResponse {
+	t := reflect.TypeOf(body)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || isNoType(t) {
+		return nil
+	}
+
+	fields := make([]ValidationFieldError, 0)
+	seen := map[string]struct{}{}
+	for _, v := range requestBodyValidators() {
+		err := v.Struct(body)
+		if err == nil {
+			continue
+		}
+		var verrs validator.ValidationErrors
+		if !errors.As(err, &verrs) {
+			return &ValidationErrorResponse{Error: err.Error(), Fields: fields}
+		}
+		for _, fe := range verrs {
+			item := toValidationFieldError(fe)
+			key := item.Field + "|" + item.Tag
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			fields = append(fields, item)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ValidationErrorResponse{
+		Error:  "request validation failed",
+		Fields: fields,
+	}
+}
+
+func toValidationFieldError(fe validator.FieldError) ValidationFieldError {
+	field := fe.Namespace()
+	if idx := strings.Index(field, "."); idx >= 0 {
+		field = field[idx+1:]
+	}
+	if field == "" {
+		field = fe.Field()
+	}
+	msg := fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag())
+	switch fe.Tag() {
+	case "required":
+		msg = field + " is required"
+	default:
+		if fe.Param() != "" {
+			msg = fmt.Sprintf("%s must satisfy %s=%s", field, fe.Tag(), fe.Param())
+		}
+	}
+	return ValidationFieldError{
+		Field:   field,
+		Tag:     fe.Tag(),
+		Param:   fe.Param(),
+		Message: msg,
+	}
+}
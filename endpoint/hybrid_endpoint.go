@@ -0,0 +1,161 @@
+package endpoint
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// HybridContext is the gin context of a HybridEndpoint request together with its typed, already bound values.
+// The embedded *gin.Context writes the raw response (files, streams, redirects).
+// HybridContext 是 HybridEndpoint 请求的 gin 上下文及其已绑定的强类型参数；
+// 通过内嵌的 *gin.Context 直接写出原始响应（文件、流、重定向等）。
+type HybridContext[PP, QP, HP, CP, Req any] struct {
+	*gin.Context
+	PathParams   PP
+	QueryParams  QP
+	HeaderParams HP
+	CookieParams CP
+	RequestBody  Req
+}
+
+// HybridEndpoint binds typed params and body like Endpoint, but lets HandlerFunc write the response itself
+// like CustomEndpoint, e.g. a file download driven by typed query params.
+// The body is bound by RequestKind: JSON (default), form-urlencoded or multipart; text, bytes and
+// stream bodies are left unread. TS generation infers all types from the generics, as for CustomEndpoint.
+// HybridEndpoint 像 Endpoint 一样绑定强类型参数与请求体，又像 CustomEndpoint 一样由 HandlerFunc 自行写出响应，
+// 适用于如按强类型 query 参数下载文件的场景；请求体按 RequestKind 绑定：JSON（默认）、form-urlencoded 或 multipart，
+// text、bytes 与 stream 请求体不会被读取；TS 生成与 CustomEndpoint 一样按泛型推断全部类型。
+type HybridEndpoint[PP, QP, HP, CP, Req, Resp any] struct {
+	Name               string
+	Method             HTTPMethod
+	Path               string
+	Description        string
+	RequestDescription string
+	Responses          []Response[Resp]
+	RequestKind        TSKind
+	ResponseKind       TSKind
+	QueryArrayFormat   TSQueryArrayFormat
+	Middlewares        []gin.HandlerFunc
+	Enabled            func() bool
+	// Deprecated adds `@deprecated <reason>` to the generated TS docs when non-empty.
+	// Deprecated 非空时在生成的 TS 文档中添加 `@deprecated <reason>`。
+	Deprecated string
+	// ClientTimeout is emitted as the generated axios request timeout; zero emits none.
+	// ClientTimeout 会作为生成的 axios 请求超时时间输出；为 0 时不输出。
+	ClientTimeout time.Duration
+	// RequestTypeOverride / ResponseTypeOverride force the TS request/response types; nil keeps generic inference.
+	// RequestTypeOverride / ResponseTypeOverride 强制指定 TS 请求/响应类型；为 nil 时按泛型推断。
+	RequestTypeOverride  reflect.Type
+	ResponseTypeOverride reflect.Type
+	// HandlerFunc writes the response. A returned error is written as an ErrorResponse (500 unless it
+	// is an *APIError with a status) when nothing has been written yet.
+	// HandlerFunc 负责写出响应；返回的错误在尚未写出响应时以 ErrorResponse 返回（默认 500，*APIError 可指定状态码）。
+	HandlerFunc func(ctx *HybridContext[PP, QP, HP, CP, Req]) error
+}
+
+// EndpointMeta exposes metadata for TS generation.
+// EndpointMeta 暴露 TS 生成所需的元数据。
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMeta() EndpointMeta {
+	return s.customEndpoint().EndpointMeta()
+}
+
+// EndpointTSHints customizes TS generation.
+// EndpointTSHints 自定义 TS 生成。
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointTSHints() EndpointTSHints {
+	return s.customEndpoint().EndpointTSHints()
+}
+
+// EndpointMiddlewares returns middleware registered before HandlerFunc.
+// EndpointMiddlewares 返回在 HandlerFunc 之前注册的中间件。
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointMiddlewares() []gin.HandlerFunc {
+	return s.Middlewares
+}
+
+// EndpointEnabled reports whether this endpoint should be registered and exported.
+// EndpointEnabled 返回该 endpoint 是否应被注册与导出。
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) EndpointEnabled() bool {
+	return s.Enabled == nil || s.Enabled()
+}
+
+// customEndpoint is the CustomEndpoint with the same metadata, so both generate identical TS.
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) customEndpoint() CustomEndpoint[PP, QP, HP, CP, Req, Resp] {
+	return CustomEndpoint[PP, QP, HP, CP, Req, Resp]{
+		Name:                 s.Name,
+		Method:               s.Method,
+		Path:                 s.Path,
+		Description:          s.Description,
+		RequestDescription:   s.RequestDescription,
+		Deprecated:           s.Deprecated,
+		Responses:            s.Responses,
+		RequestKind:          s.RequestKind,
+		ResponseKind:         s.ResponseKind,
+		QueryArrayFormat:     s.QueryArrayFormat,
+		ClientTimeout:        s.ClientTimeout,
+		RequestTypeOverride:  s.RequestTypeOverride,
+		ResponseTypeOverride: s.ResponseTypeOverride,
+	}
+}
+
+// GinHandler builds a gin.HandlerFunc that binds params/body and calls HandlerFunc with a HybridContext.
+// GinHandler 会绑定参数/请求体，并以 HybridContext 调用 HandlerFunc。
+func (s HybridEndpoint[PP, QP, HP, CP, Req, Resp]) GinHandler() gin.HandlerFunc {
+	if s.HandlerFunc == nil {
+		return func(ctx *gin.Context) {
+			ctx.JSON(http.StatusInternalServerError, ErrorResponse{Error: "hybrid endpoint handler is nil"})
+		}
+	}
+	requestKind, _ := resolveEndpointKinds(s)
+	return func(ctx *gin.Context) {
+		pathParams, queryParams, headerParams, cookieParams, err := bindRequestParamsT[PP, QP, HP, CP](ctx)
+		if err != nil {
+			writeErrorResponse(ctx, http.StatusBadRequest, err)
+			return
+		}
+		var requestBody Req
+		switch requestKind {
+		case TSKindJSON:
+			requestBody, err = bindJSONStructT[Req](ctx)
+		case TSKindFormURLEncoded:
+			requestBody, err = bindStructT[Req](func(v any) error { return ctx.ShouldBindWith(v, binding.Form) })
+		case TSKindMultipart:
+			requestBody, err = bindMultipartStructT[Req](ctx)
+		default:
+			// Text, bytes and stream bodies are read by the handler from ctx.Request.Body.
+		}
+		if err != nil {
+			writeErrorResponse(ctx, http.StatusBadRequest, err)
+			return
+		}
+		hybridCtx := &HybridContext[PP, QP, HP, CP, Req]{
+			Context:      ctx,
+			PathParams:   pathParams,
+			QueryParams:  queryParams,
+			HeaderParams: headerParams,
+			CookieParams: cookieParams,
+			RequestBody:  requestBody,
+		}
+		if err := s.HandlerFunc(hybridCtx); err != nil && !ctx.Writer.Written() {
+			writeErrorResponse(ctx, http.StatusInternalServerError, err)
+		}
+	}
+}
+
+// NewHybridEndpoint builds a HybridEndpoint with common fields.
+// NewHybridEndpoint 构建 HybridEndpoint，便于快速初始化。
+func NewHybridEndpoint[PP, QP, HP, CP, Req, Resp any](
+	name string,
+	method HTTPMethod,
+	path string,
+	handler func(ctx *HybridContext[PP, QP, HP, CP, Req]) error,
+) HybridEndpoint[PP, QP, HP, CP, Req, Resp] {
+	return HybridEndpoint[PP, QP, HP, CP, Req, Resp]{
+		Name:        name,
+		Method:      method,
+		Path:        path,
+		HandlerFunc: handler,
+	}
+}
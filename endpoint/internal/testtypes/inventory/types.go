@@ -0,0 +1,12 @@
+// Package inventory holds fixture types used by endpoint tests to exercise
+// same-name types declared in different packages.
+// inventory 包提供 endpoint 测试使用的夹具类型，用于验证不同包中的同名类型。
+package inventory
+
+// Item is a stock entry; it shares its Go name with orders.Item.
+// Item 是库存条目，与 orders.Item 同名。
+type Item struct {
+	SKU       string `json:"sku"`
+	Warehouse string `json:"warehouse"`
+	OnHand    int    `json:"onHand"`
+}
@@ -0,0 +1,11 @@
+// Package orders holds fixture types used by endpoint tests to exercise
+// same-name types declared in different packages.
+// orders 包提供 endpoint 测试使用的夹具类型，用于验证不同包中的同名类型。
+package orders
+
+// Item is an order line; it shares its Go name with inventory.Item.
+// Item 是订单行，与 inventory.Item 同名。
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
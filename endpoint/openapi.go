@@ -0,0 +1,421 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenAPIVersion is the OpenAPI version written by ExportOpenAPI.
+// OpenAPIVersion 是 ExportOpenAPI 输出的 OpenAPI 版本。
+const OpenAPIVersion = "3.1.0"
+
+// JSONSchemaDraft is the `$schema` written by GenerateJSONSchema.
+// JSONSchemaDraft 是 GenerateJSONSchema 输出的 `$schema`。
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchemaBuilder converts Go types to JSON Schema.
+// Named structs become components and are referenced by $ref; names come from
+// tsInterfaceRegistry, so components share names and dedupe rules with the generated TS.
+type jsonSchemaBuilder struct {
+	registry   *tsInterfaceRegistry
+	components map[string]any
+	// refPrefix locates components: OpenAPI components or standalone JSON Schema definitions.
+	refPrefix string
+}
+
+func newJSONSchemaBuilder() *jsonSchemaBuilder {
+	return &jsonSchemaBuilder{
+		registry:   newTSInterfaceRegistry(),
+		components: map[string]any{},
+		refPrefix:  "#/components/schemas/",
+	}
+}
+
+// GenerateJSONSchema builds a standalone JSON Schema (draft-07) for a single Go type, e.g. for form generation.
+// It follows the same rules as the TS and OpenAPI output: json names and omitempty, tsunion as enum,
+// time.Time as a date-time string and int64 per TSInt64MappingMode. Named structs are put in
+// `definitions` and referenced by $ref, including the root type itself.
+// GenerateJSONSchema 为单个 Go 类型构建独立的 JSON Schema（draft-07），如用于表单生成；
+// 规则与 TS、OpenAPI 输出一致：json 名称与 omitempty、tsunion 输出为 enum、time.Time 输出为 date-time 字符串、
+// int64 遵循 TSInt64MappingMode；具名结构体（包括根类型本身）放入 `definitions` 并通过 $ref 引用。
+func GenerateJSONSchema(t reflect.Type) ([]byte, error) {
+	if !isValidType(t) {
+		return nil, fmt.Errorf("json schema type is required")
+	}
+	builder := newJSONSchemaBuilder()
+	builder.refPrefix = "#/definitions/"
+	schema, err := builder.schemaFromType(t)
+	if err != nil {
+		return nil, err
+	}
+	schema = copySchema(schema)
+	schema["$schema"] = JSONSchemaDraft
+	if len(builder.components) > 0 {
+		schema["definitions"] = builder.components
+	}
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (s *jsonSchemaBuilder) schemaFromType(t reflect.Type) (map[string]any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	}
+	if t.PkgPath() == "mime/multipart" && t.Name() == "FileHeader" {
+		return map[string]any{"type": "string", "format": "binary"}, nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" {
+		switch t.Name() {
+		case "FormData":
+			return map[string]any{"type": "object"}, nil
+		case "RawBytes", "StreamResponse", "StreamRequest":
+			return map[string]any{"type": "string", "format": "binary"}, nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.Int64, reflect.Uint64:
+		if TSInt64MappingMode == TSInt64ModeString || TSInt64MappingMode == TSInt64ModeBigInt {
+			return map[string]any{"type": "string", "format": "int64"}, nil
+		}
+		return map[string]any{"type": "integer", "format": "int64"}, nil
+	case reflect.Struct:
+		if t.Name() != "" {
+			return s.namedStructRef(t)
+		}
+		return s.structSchema(t)
+	case reflect.Map:
+		if !isJSONMapKey(t.Key()) {
+			return map[string]any{"type": "object"}, nil
+		}
+		elem, err := s.schemaFromType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": elem}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := s.schemaFromType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	default:
+		return map[string]any{}, nil
+	}
+}
+
+func (s *jsonSchemaBuilder) namedStructRef(t reflect.Type) (map[string]any, error) {
+	name, err := s.registry.ensureNamedStructType(t)
+	if err != nil {
+		return nil, err
+	}
+	ref := map[string]any{"$ref": s.refPrefix + name}
+	if _, ok := s.components[name]; ok {
+		return ref, nil
+	}
+	// Reserve the name first so self-referencing structs resolve to the $ref.
+	s.components[name] = map[string]any{}
+	schema, err := s.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	s.components[name] = schema
+	return ref, nil
+}
+
+func (s *jsonSchemaBuilder) structSchema(t reflect.Type) (map[string]any, error) {
+	properties := map[string]any{}
+	required := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, optional, ok := jsonFieldMeta(f)
+		if !ok {
+			continue
+		}
+		prop, err := s.fieldSchema(f)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = prop
+		if !optional {
+			required = append(required, name)
+		}
+	}
+	out := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+	return out, nil
+}
+
+// fieldSchema adds tsunion values as enum and tsdoc as description to the field type schema.
+func (s *jsonSchemaBuilder) fieldSchema(f reflect.StructField) (map[string]any, error) {
+	schema, err := s.schemaFromType(f.Type)
+	if err != nil {
+		return nil, err
+	}
+	unionValues, ok, err := tsUnionValuesFromField(f)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		enum := make([]any, 0, len(unionValues))
+		for _, v := range unionValues {
+			switch v.Type {
+			case "string":
+				enum = append(enum, v.Value)
+			case "boolean":
+				enum = append(enum, v.Value == "true")
+			default:
+				enum = append(enum, json.Number(v.Value))
+			}
+		}
+		schema = copySchema(schema)
+		schema["enum"] = enum
+	}
+	if doc := strings.TrimSpace(f.Tag.Get("tsdoc")); doc != "" {
+		if _, isRef := schema["$ref"]; isRef {
+			// OpenAPI 3.1 allows siblings next to $ref.
+			schema = copySchema(schema)
+		}
+		schema["description"] = doc
+	}
+	return schema, nil
+}
+
+func copySchema(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		out[k] = v
+	}
+	return out
+}
+
+// BuildOpenAPI builds an OpenAPI 3.1 document for all enabled endpoints.
+// BuildOpenAPI 为全部启用的端点构建 OpenAPI 3.1 文档。
+func (s ServerAPI) BuildOpenAPI() (map[string]any, error) {
+	builder := newJSONSchemaBuilder()
+	fullPathPrefix := resolveAPIPath(normalizePathSegment(s.BasePath), normalizePathSegment(s.GroupPath))
+	paths := map[string]any{}
+
+	for i, e := range s.Endpoints {
+		if !isEndpointEnabled(e) {
+			continue
+		}
+		meta := e.EndpointMeta()
+		if err := validateEndpointMeta(meta); err != nil {
+			return nil, fmt.Errorf("endpoint[%d] validation failed: %w", i, err)
+		}
+		requestKind, responseKind := resolveEndpointKinds(e)
+
+		operation := map[string]any{
+			"operationId": toLowerCamel(schemaBaseName(meta, i)),
+		}
+		if desc := strings.TrimSpace(meta.Description); desc != "" {
+			operation["summary"] = desc
+		}
+
+		params := make([]any, 0)
+		for _, loc := range []struct {
+			in  string
+			tag string
+			typ reflect.Type
+		}{
+			{"path", "uri", meta.PathParamsType},
+			{"query", "form", meta.QueryParamsType},
+			{"header", "header", meta.HeaderParamsType},
+			{"cookie", "cookie", meta.CookieParamsType},
+		} {
+			items, err := builder.parameters(loc.typ, loc.in, loc.tag)
+			if err != nil {
+				return nil, fmt.Errorf("build %s params for endpoint[%d]: %w", loc.in, i, err)
+			}
+			params = append(params, items...)
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+
+		if isValidType(meta.RequestBodyType) {
+			schema, err := builder.schemaFromType(meta.RequestBodyType)
+			if err != nil {
+				return nil, fmt.Errorf("build request schema for endpoint[%d]: %w", i, err)
+			}
+			body := map[string]any{
+				"required": true,
+				"content": map[string]any{
+					openAPIContentType(requestKind): map[string]any{"schema": schema},
+				},
+			}
+			if desc := strings.TrimSpace(meta.RequestDescription); desc != "" {
+				body["description"] = desc
+			}
+			operation["requestBody"] = body
+		}
+
+		responses := map[string]any{}
+		for j, r := range meta.Responses {
+			desc := strings.TrimSpace(r.Description)
+			if desc == "" {
+				desc = http.StatusText(r.StatusCode)
+			}
+			resp := map[string]any{"description": desc}
+			if r.BodyType != nil && r.BodyType.Kind() != reflect.Invalid && !isNoType(r.BodyType) {
+				schema, err := builder.schemaFromType(r.BodyType)
+				if err != nil {
+					return nil, fmt.Errorf("build response[%d] schema for endpoint[%d]: %w", j, i, err)
+				}
+				resp["content"] = map[string]any{
+					openAPIContentType(responseKind): map[string]any{"schema": schema},
+				}
+			}
+			if len(meta.SetCookies) > 0 && r.StatusCode >= 200 && r.StatusCode < 300 {
+				cookies := make([]string, 0, len(meta.SetCookies))
+				for _, c := range meta.SetCookies {
+					cookies = append(cookies, c.summary())
+				}
+				resp["headers"] = map[string]any{
+					"Set-Cookie": map[string]any{
+						"description": strings.Join(cookies, "\n"),
+						"schema":      map[string]any{"type": "string"},
+					},
+				}
+			}
+			responses[fmt.Sprintf("%d", r.StatusCode)] = resp
+		}
+		operation["responses"] = responses
+
+		path := openAPIPath(joinURLPath(fullPathPrefix, meta.Path))
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[path] = item
+		}
+		item[strings.ToLower(string(meta.Method))] = operation
+	}
+
+	return map[string]any{
+		"openapi": OpenAPIVersion,
+		"info": map[string]any{
+			"title":   "Nuxt Gin API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": builder.components,
+		},
+	}, nil
+}
+
+// ExportOpenAPI writes the OpenAPI 3.1 document of all endpoints to a relative JSON file.
+// ExportOpenAPI 将全部端点的 OpenAPI 3.1 文档写入相对路径的 JSON 文件。
+func (s ServerAPI) ExportOpenAPI(relativePath string) error {
+	if !shouldExportTSInCurrentEnv() {
+		return nil
+	}
+	if strings.TrimSpace(relativePath) == "" {
+		return fmt.Errorf("relative json path is required")
+	}
+	if filepath.IsAbs(relativePath) {
+		return fmt.Errorf("json file path must be relative to cwd")
+	}
+	doc, err := s.BuildOpenAPI()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeRelativeTSFile(relativePath, string(data)+"\n")
+}
+
+func (s *jsonSchemaBuilder) parameters(t reflect.Type, in string, primaryTag string) ([]any, error) {
+	if !isValidType(t) {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	out := make([]any, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, ok := resolveParamFieldName(f, primaryTag)
+		if !ok {
+			continue
+		}
+		_, optional, _ := jsonFieldMeta(f)
+		schema, err := s.fieldSchema(f)
+		if err != nil {
+			return nil, err
+		}
+		param := map[string]any{
+			"name":     name,
+			"in":       in,
+			"required": in == "path" || !optional,
+			"schema":   schema,
+		}
+		if desc, ok := schema["description"]; ok {
+			param["description"] = desc
+		}
+		out = append(out, param)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].(map[string]any)["name"].(string) < out[j].(map[string]any)["name"].(string)
+	})
+	return out, nil
+}
+
+var openAPIPathParamRegexp = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// openAPIPath converts gin `:id` and `*path` segments to OpenAPI `{id}` / `{path}`.
+func openAPIPath(path string) string {
+	return openAPIPathParamRegexp.ReplaceAllString(path, "{$1}")
+}
+
+func openAPIContentType(kind TSKind) string {
+	switch kind {
+	case TSKindFormURLEncoded:
+		return "application/x-www-form-urlencoded"
+	case TSKindMultipart:
+		return "multipart/form-data"
+	case TSKindText:
+		return "text/plain"
+	case TSKindEventStream:
+		return "text/event-stream"
+	case TSKindBytes, TSKindStream:
+		return "application/octet-stream"
+	default:
+		return "application/json"
+	}
+}
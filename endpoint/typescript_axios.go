@@ -29,49 +29,240 @@ type axiosFuncMeta struct {
 	HasParams        bool
 	HasPath          bool
 	HasQuery         bool
+	QueryArrayFormat TSQueryArrayFormat
 	HasHeader        bool
 	HasCookie        bool
 	HasReqBody       bool
+	StreamBody       bool
 	RequestKind      TSKind
 	ResponseKind     TSKind
+	ResponseVariants []axiosResponseVariant
+	// CSVRowType / CSVColumns are set for text/stream endpoints whose body is []Row; see WriteCSV.
+	CSVRowType string
+	CSVColumns []csvColumn
+	// Batch is set for endpoints returning []BatchItemResult; see NewBatchResponse.
+	Batch bool
+	// BigIntPaths are the response paths revived to bigint in TSInt64ModeBigInt; see tsBigIntPaths.
+	BigIntPaths []string
+	// DatePaths are the response paths revived to Date when TSAutoDateParsing is on; see tsDatePaths.
+	DatePaths []string
+	// SetCookies are the documented summaries of cookies the endpoint sets; see ResponseCookie.
+	SetCookies []string
+	// EventValidator is the validator expression (over `value`) of an event stream's event type; see SSEEndpoint.
+	EventValidator string
+	// TimeoutMS is the default axios timeout in milliseconds; 0 emits none. See EndpointTSHints.Timeout.
+	TimeoutMS int64
+	// Deprecated is the `@deprecated` reason of the endpoint; see Endpoint.Deprecated.
+	Deprecated string
+}
+
+type axiosResponseVariant struct {
+	StatusCode int
+	Type       string
+	DatePaths  []string
+}
+
+// TSResponseUnionMode controls whether endpoints declaring multiple response status codes
+// return a discriminated union (`{ status; data }`) instead of the primary 2xx body only.
+// Default is false to keep existing call sites unchanged.
+var TSResponseUnionMode = false
+
+// SetTSResponseUnionMode enables or disables discriminated union return types for multi-status endpoints.
+func SetTSResponseUnionMode(enabled bool) {
+	TSResponseUnionMode = enabled
+}
+
+// TSStrictPathParams controls whether generated buildURL throws when a required path param is missing.
+// Default is true because a silently malformed URL (e.g. `/person/`) is harder to debug than an explicit error.
+var TSStrictPathParams = true
+
+// SetTSStrictPathParams enables or disables required path param checks in generated buildURL.
+func SetTSStrictPathParams(enabled bool) {
+	TSStrictPathParams = enabled
+}
+
+// TSDevDescribeHelpers controls whether request body interfaces get a `describeX()` helper
+// exposing field names, TS types, optionality and tsdoc at runtime.
+// Default is false to keep production bundles lean.
+var TSDevDescribeHelpers = false
+
+// SetTSDevDescribeHelpers enables or disables generated `describeX()` helpers for request bodies.
+func SetTSDevDescribeHelpers(enabled bool) {
+	TSDevDescribeHelpers = enabled
+}
+
+// TSFormatAPIErrorHelper controls whether the generated client exports `formatApiError(error, options?)`,
+// which turns a thrown axios error into a user facing message.
+// Default is false to keep the generated runtime minimal.
+var TSFormatAPIErrorHelper = false
+
+// SetTSFormatAPIErrorHelper enables or disables the generated formatApiError helper.
+func SetTSFormatAPIErrorHelper(enabled bool) {
+	TSFormatAPIErrorHelper = enabled
+}
+
+// TSResponseCacheHelpers controls whether the generated client exports a `cached` object with
+// in-memory TTL-cached wrappers for GET endpoints, plus `invalidateCachedResponses`.
+// Default is false because caching changes request semantics and should be opted into.
+var TSResponseCacheHelpers = false
+
+// SetTSResponseCacheHelpers enables or disables the generated response cache helpers.
+func SetTSResponseCacheHelpers(enabled bool) {
+	TSResponseCacheHelpers = enabled
+}
+
+// TSOfflineMutationQueue controls whether the generated client exports an `offlineQueue` that persists
+// mutations sent while offline to localStorage and replays them on reconnect. Each queued mutation keeps
+// its idempotency key, sent as the Idempotency-Key header, so the server can drop duplicate replays.
+// Default is false because queueing changes when mutations reach the server.
+var TSOfflineMutationQueue = false
+
+// SetTSOfflineMutationQueue enables or disables the generated offline mutation queue.
+func SetTSOfflineMutationQueue(enabled bool) {
+	TSOfflineMutationQueue = enabled
+}
+
+// TSQueryArrayFormatMode controls how generated axios requests serialize slice query params.
+// Default is repeat (`tags=a&tags=b`) because that is what gin's ShouldBindQuery binds; endpoints
+// can override it via EndpointTSHints.QueryArrayFormat.
+var TSQueryArrayFormatMode = TSQueryArrayRepeat
+
+// SetTSQueryArrayFormat changes the query array format for TypeScript generation.
+// Unsupported values fallback to TSQueryArrayRepeat.
+func SetTSQueryArrayFormat(format TSQueryArrayFormat) {
+	switch format {
+	case TSQueryArrayComma, TSQueryArrayBrackets:
+		TSQueryArrayFormatMode = format
+	default:
+		TSQueryArrayFormatMode = TSQueryArrayRepeat
+	}
+}
+
+// resolveQueryArrayFormat returns the endpoint's query array format, falling back to TSQueryArrayFormatMode.
+func resolveQueryArrayFormat(e EndpointLike) (TSQueryArrayFormat, error) {
+	if hintProvider, ok := e.(EndpointTSHintsProvider); ok {
+		switch format := hintProvider.EndpointTSHints().QueryArrayFormat; format {
+		case "":
+		case TSQueryArrayRepeat, TSQueryArrayComma, TSQueryArrayBrackets:
+			return format, nil
+		default:
+			return "", fmt.Errorf("unsupported query array format %q", format)
+		}
+	}
+	return TSQueryArrayFormatMode, nil
+}
+
+// resolveTimeoutMS returns the endpoint's client timeout in milliseconds, rounding sub-millisecond values up.
+func resolveTimeoutMS(e EndpointLike) int64 {
+	hintProvider, ok := e.(EndpointTSHintsProvider)
+	if !ok {
+		return 0
+	}
+	timeout := hintProvider.EndpointTSHints().Timeout
+	if timeout <= 0 {
+		return 0
+	}
+	return max(timeout.Milliseconds(), 1)
+}
+
+// axiosRenderOptions selects optional output sections of renderAxiosTS.
+type axiosRenderOptions struct {
+	VueComposables bool
+	TanstackQuery  bool
+	// TanstackQueryModule is the package the hooks import from; empty means @tanstack/vue-query.
+	TanstackQueryModule string
+	ZodSchemas          bool
+	Mocks               bool
+	// OmitValidators drops the validateX/ensureX functions and event stream validation.
+	OmitValidators bool
+	// OmitDateParsing drops the reviveDates conversion of response dates.
+	OmitDateParsing bool
+	// AbortAll tracks in-flight requests and exports abortAllRequests().
+	AbortAll bool
+}
+
+// applyAxiosRenderOptions strips the metadata of features turned off by opts.
+func applyAxiosRenderOptions(metas []axiosFuncMeta, opts axiosRenderOptions) []axiosFuncMeta {
+	if !opts.OmitValidators && !opts.OmitDateParsing {
+		return metas
+	}
+	out := make([]axiosFuncMeta, len(metas))
+	for i, m := range metas {
+		if opts.OmitValidators && m.EventValidator != "" {
+			m.EventValidator = "true"
+		}
+		if opts.OmitDateParsing {
+			m.DatePaths = nil
+			variants := make([]axiosResponseVariant, len(m.ResponseVariants))
+			for j, v := range m.ResponseVariants {
+				v.DatePaths = nil
+				variants[j] = v
+			}
+			if len(variants) > 0 {
+				m.ResponseVariants = variants
+			}
+		}
+		out[i] = m
+	}
+	return out
 }
 
 func generateAxiosFromEndpoints(basePath string, groupPath string, endpoints []EndpointLike) (string, error) {
+	return generateAxiosFromEndpointsWithOptions(basePath, groupPath, endpoints, axiosRenderOptions{})
+}
+
+func generateAxiosFromEndpointsWithOptions(basePath string, groupPath string, endpoints []EndpointLike, opts axiosRenderOptions) (string, error) {
+	registry, metas, err := collectAxiosFuncMetas(endpoints)
+	if err != nil {
+		return "", err
+	}
+	return renderAxiosTS(basePath, groupPath, registry, metas, opts)
+}
+
+// collectAxiosFuncMetas validates the enabled endpoints and builds their TS types and render metadata,
+// sorted by class name. It is shared by the axios and fetch clients.
+func collectAxiosFuncMetas(endpoints []EndpointLike) (*tsInterfaceRegistry, []axiosFuncMeta, error) {
 	registry := newTSInterfaceRegistry()
+	registry.inline = collectInlineStructTypes(endpoints, TSInlineStructMaxFields)
+	// ErrorResponse is always emitted: every endpoint can answer with it.
+	if _, err := registry.ensureNamedStructType(typeOf[ErrorResponse]()); err != nil {
+		return nil, nil, fmt.Errorf("build error response type: %w", err)
+	}
 	metas := make([]axiosFuncMeta, 0, len(endpoints))
 
 	for i, e := range endpoints {
+		// Disabled endpoints are skipped in place so indexes in errors and fallback names
+		// still match the caller's slice.
+		if !isEndpointEnabled(e) {
+			continue
+		}
 		meta := e.EndpointMeta()
 		if err := validateEndpointMeta(meta); err != nil {
-			return "", fmt.Errorf("endpoint[%d] validation failed: %w", i, err)
+			return nil, nil, fmt.Errorf("endpoint[%d] validation failed: %w", i, err)
 		}
 
-		requestKind := TSKindJSON
-		responseKind := TSKindJSON
-		if hintProvider, ok := e.(EndpointTSHintsProvider); ok {
-			hints := hintProvider.EndpointTSHints()
-			if hints.RequestKind != "" {
-				requestKind = hints.RequestKind
-			}
-			if hints.ResponseKind != "" {
-				responseKind = hints.ResponseKind
-			}
+		requestKind, responseKind := resolveEndpointKinds(e)
+		queryArrayFormat, err := resolveQueryArrayFormat(e)
+		if err != nil {
+			return nil, nil, fmt.Errorf("endpoint[%d]: %w", i, err)
 		}
 
 		base := schemaBaseName(meta, i)
 
 		paramsType, hasPath, hasQuery, hasHeader, hasCookie, err := buildParamsTypeFromTypes(registry, meta.PathParamsType, meta.QueryParamsType, meta.HeaderParamsType, meta.CookieParamsType)
 		if err != nil {
-			return "", fmt.Errorf("build params type for endpoint[%d]: %w", i, err)
+			return nil, nil, fmt.Errorf("build params type for endpoint[%d]: %w", i, err)
 		}
 		hasParams := hasPath || hasQuery || hasHeader || hasCookie
 
 		requestType := ""
 		hasReqBody := meta.RequestBodyType != nil && meta.RequestBodyType.Kind() != reflect.Invalid && !isNoType(meta.RequestBodyType)
+		// Only StreamRequest bodies use the fetch path; other stream-hinted bodies stay on axios.
+		streamBody := meta.RequestBodyType == reflect.TypeOf(StreamRequest{})
 		if hasReqBody {
 			requestType, _, err = tsTypeFromType(meta.RequestBodyType, registry)
 			if err != nil {
-				return "", fmt.Errorf("build request type for endpoint[%d]: %w", i, err)
+				return nil, nil, fmt.Errorf("build request type for endpoint[%d]: %w", i, err)
 			}
 		}
 
@@ -79,18 +270,30 @@ func generateAxiosFromEndpoints(basePath string, groupPath string, endpoints []E
 			if meta.Responses[j].BodyType == nil || meta.Responses[j].BodyType.Kind() == reflect.Invalid {
 				continue
 			}
+			if _, _, ok := batchItemTypesOf(meta.Responses[j].BodyType); ok && meta.Batch {
+				continue
+			}
 			if _, _, err := tsTypeFromType(meta.Responses[j].BodyType, registry); err != nil {
-				return "", fmt.Errorf("build response[%d] type for endpoint[%d]: %w", j, i, err)
+				return nil, nil, fmt.Errorf("build response[%d] type for endpoint[%d]: %w", j, i, err)
 			}
 		}
 
 		responseType := "void"
 		responseWireType := "void"
 		primaryResp := inferPrimaryResponseMeta(meta)
-		if primaryResp != nil && primaryResp.BodyType != nil && primaryResp.BodyType.Kind() != reflect.Invalid {
+		if meta.Batch {
+			if primaryResp == nil {
+				return nil, nil, fmt.Errorf("batch endpoint[%d] has no success response", i)
+			}
+			responseType, err = batchResponseTSType(primaryResp.BodyType, registry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("build batch response type for endpoint[%d]: %w", i, err)
+			}
+			responseWireType = responseType
+		} else if primaryResp != nil && primaryResp.BodyType != nil && primaryResp.BodyType.Kind() != reflect.Invalid {
 			responseType, _, err = tsTypeFromType(primaryResp.BodyType, registry)
 			if err != nil {
-				return "", fmt.Errorf("build response type for endpoint[%d]: %w", i, err)
+				return nil, nil, fmt.Errorf("build response type for endpoint[%d]: %w", i, err)
 			}
 			responseWireType = responseType
 		}
@@ -106,6 +309,31 @@ func generateAxiosFromEndpoints(basePath string, groupPath string, endpoints []E
 			responseWireType = "ArrayBuffer"
 		}
 
+		csvRowType := ""
+		var csvColumns []csvColumn
+		if (responseKind == TSKindStream || responseKind == TSKindText) && primaryResp != nil {
+			if rowType, ok := csvRowTypeOf(primaryResp.BodyType); ok {
+				csvRowType, _, err = tsTypeFromType(rowType, registry)
+				if err != nil {
+					return nil, nil, fmt.Errorf("build csv row type for endpoint[%d]: %w", i, err)
+				}
+				csvColumns, err = csvColumnsOf(rowType)
+				if err != nil {
+					return nil, nil, fmt.Errorf("build csv columns for endpoint[%d]: %w", i, err)
+				}
+			}
+		}
+
+		var responseVariants []axiosResponseVariant
+		if TSResponseUnionMode && responseKind == TSKindJSON && !streamBody && len(meta.Responses) > 1 {
+			responseVariants, err = buildAxiosResponseVariants(meta.Responses, registry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("build response union for endpoint[%d]: %w", i, err)
+			}
+			responseType = axiosClassName(toLowerCamel(base), string(meta.Method)) + "Response"
+			responseWireType = "unknown"
+		}
+
 		fnMeta := axiosFuncMeta{
 			FuncName:         toLowerCamel(base),
 			Method:           strings.ToUpper(string(meta.Method)),
@@ -123,11 +351,38 @@ func generateAxiosFromEndpoints(basePath string, groupPath string, endpoints []E
 			HasParams:        hasParams,
 			HasPath:          hasPath,
 			HasQuery:         hasQuery,
+			QueryArrayFormat: queryArrayFormat,
+			TimeoutMS:        resolveTimeoutMS(e),
 			HasHeader:        hasHeader,
 			HasCookie:        hasCookie,
 			HasReqBody:       hasReqBody,
+			StreamBody:       streamBody,
 			RequestKind:      requestKind,
 			ResponseKind:     responseKind,
+			ResponseVariants: responseVariants,
+			CSVRowType:       csvRowType,
+			CSVColumns:       csvColumns,
+			Batch:            meta.Batch,
+			Deprecated:       strings.TrimSpace(meta.Deprecated),
+		}
+		jsonResponse := responseKind == TSKindJSON || responseKind == TSKindEventStream
+		if TSInt64MappingMode == TSInt64ModeBigInt && jsonResponse && len(responseVariants) == 0 && primaryResp != nil {
+			fnMeta.BigIntPaths = tsBigIntPaths(primaryResp.BodyType)
+		}
+		if TSAutoDateParsing && jsonResponse && len(responseVariants) == 0 && primaryResp != nil {
+			fnMeta.DatePaths = tsDatePaths(primaryResp.BodyType)
+		}
+		if responseKind == TSKindEventStream {
+			if primaryResp == nil || !isValidType(primaryResp.BodyType) {
+				return nil, nil, fmt.Errorf("event stream endpoint[%d] has no event type", i)
+			}
+			fnMeta.EventValidator, err = tsValidatorExprFromType(primaryResp.BodyType, "value", registry, 0)
+			if err != nil {
+				return nil, nil, fmt.Errorf("build event validator for endpoint[%d]: %w", i, err)
+			}
+		}
+		for _, c := range meta.SetCookies {
+			fnMeta.SetCookies = append(fnMeta.SetCookies, c.summary())
 		}
 		if primaryResp != nil {
 			fnMeta.ResponseDesc = strings.TrimSpace(primaryResp.Description)
@@ -147,7 +402,7 @@ func generateAxiosFromEndpoints(basePath string, groupPath string, endpoints []E
 		return metas[i].Method < metas[j].Method
 	})
 
-	return renderAxiosTS(basePath, groupPath, registry, metas)
+	return registry, metas, nil
 }
 
 func exportAxiosFromEndpointsToTSFile(basePath string, groupPath string, endpoints []EndpointLike, relativeTSPath string) error {
@@ -174,149 +429,188 @@ func exportAxiosFromEndpointsToTSFile(basePath string, groupPath string, endpoin
 	return os.WriteFile(fullPath, []byte(code), 0o644)
 }
 
-func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegistry, metas []axiosFuncMeta) (string, error) {
+func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegistry, metas []axiosFuncMeta, opts axiosRenderOptions) (string, error) {
+	metas = applyAxiosRenderOptions(metas, opts)
+	needsStreamHelper := false
+	for _, m := range metas {
+		if m.StreamBody {
+			needsStreamHelper = true
+			break
+		}
+	}
 	var b strings.Builder
 	writeTSBanner(&b, "Nuxt Gin HTTP API Client (Axios)")
 	writeTSMarker(&b, "Imports")
-	b.WriteString("import axios, { type AxiosRequestConfig } from 'axios';\n\n")
+	if needsStreamHelper {
+		b.WriteString("import axios, { AxiosError, type AxiosInstance, type AxiosProgressEvent, type AxiosRequestConfig, type AxiosResponse } from 'axios';\n")
+	} else {
+		b.WriteString("import axios, { type AxiosInstance, type AxiosProgressEvent, type AxiosRequestConfig } from 'axios';\n")
+	}
+	if opts.VueComposables {
+		b.WriteString("import { ref, shallowRef, type Ref } from 'vue';\n")
+	}
+	if opts.TanstackQuery {
+		if names := tanstackImports(withoutEventStreams(metas)); len(names) > 0 {
+			module := opts.TanstackQueryModule
+			if strings.TrimSpace(module) == "" {
+				module = defaultTanstackQueryModule
+			}
+			b.WriteString("import { ")
+			b.WriteString(strings.Join(names, ", "))
+			b.WriteString(" } from '")
+			b.WriteString(module)
+			b.WriteString("';\n")
+		}
+	}
+	if opts.ZodSchemas && len(registry.defs) > 0 {
+		b.WriteString("import { z } from 'zod';\n")
+	}
+	b.WriteString("\n")
 	writeTSMarkerEnd(&b, "Imports")
 	writeTSMarker(&b, "Runtime Helpers")
-	b.WriteString("const axiosClient = axios.create();\n\n")
-	b.WriteString("const isPlainObject = (value: unknown): value is Record<string, unknown> =>\n")
-	b.WriteString("  Object.prototype.toString.call(value) === '[object Object]';\n\n")
-	b.WriteString("const isoDateLike = /^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}(?:\\.\\d{1,9})?(?:Z|[+\\-]\\d{2}:\\d{2})$/;\n\n")
-	b.WriteString("const normalizeRequestJSON = (value: unknown): unknown => {\n")
-	b.WriteString("  if (value instanceof Date) return value.toISOString();\n")
-	b.WriteString("  if (Array.isArray(value)) return value.map(normalizeRequestJSON);\n")
-	b.WriteString("  if (isPlainObject(value)) {\n")
-	b.WriteString("    const out: Record<string, unknown> = {};\n")
-	b.WriteString("    for (const [k, v] of Object.entries(value)) out[k] = normalizeRequestJSON(v);\n")
-	b.WriteString("    return out;\n")
+	b.WriteString("let axiosClient: AxiosInstance = axios.create();\n\n")
+	writeHTTPRequestNormalizers(&b)
+	b.WriteString("const resolveHttpBaseURL = (url: string): string => {\n")
+	b.WriteString("  if (url.startsWith('http://') || url.startsWith('https://')) return url;\n")
+	b.WriteString("  // An injected client with its own baseURL decides the origin; axios prefixes it to relative URLs.\n")
+	b.WriteString("  if (axiosClient.defaults.baseURL) return url;\n")
+	b.WriteString("  if (url.startsWith('/') && typeof window !== 'undefined' && isDevelopmentEnv()) {\n")
+	b.WriteString("    return `${window.location.protocol}//${window.location.hostname}:${resolveGinPort()}${url}`;\n")
 	b.WriteString("  }\n")
-	b.WriteString("  return value;\n")
+	b.WriteString("  return url;\n")
 	b.WriteString("};\n\n")
-	b.WriteString("const normalizeResponseJSON = (value: unknown): unknown => {\n")
-	b.WriteString("  if (Array.isArray(value)) return value.map(normalizeResponseJSON);\n")
-	b.WriteString("  if (typeof value === 'string' && isoDateLike.test(value)) {\n")
-	b.WriteString("    const date = new Date(value);\n")
-	b.WriteString("    if (!Number.isNaN(date.getTime())) return date;\n")
-	b.WriteString("  }\n")
-	b.WriteString("  if (isPlainObject(value)) {\n")
-	b.WriteString("    const out: Record<string, unknown> = {};\n")
-	b.WriteString("    for (const [k, v] of Object.entries(value)) out[k] = normalizeResponseJSON(v);\n")
-	b.WriteString("    return out;\n")
-	b.WriteString("  }\n")
-	b.WriteString("  return value;\n")
+	needsMultipartHelper := needsHTTPMultipartHelper(metas)
+	if needsMultipartHelper {
+		writeHTTPFormDataHelpers(&b)
+	}
+	b.WriteString("let defaultHeaders: Record<string, string> = {};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Headers added to every generated request, e.g. Accept-Language for the current locale.\n")
+	b.WriteString(" * Header params and per-call options.headers take precedence. Calling it again replaces the previous set.\n")
+	b.WriteString(" * 为所有生成的请求添加的默认请求头（如当前语言的 Accept-Language）；header 参数与单次调用的 options.headers 优先；再次调用会替换之前的设置。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const setDefaultHeaders = (headers: Record<string, string>): void => {\n")
+	b.WriteString("  defaultHeaders = { ...headers };\n")
 	b.WriteString("};\n\n")
-	b.WriteString("const toFormUrlEncoded = (value: unknown): URLSearchParams => {\n")
-	b.WriteString("  if (value instanceof URLSearchParams) return value;\n")
-	b.WriteString("  const params = new URLSearchParams();\n")
-	b.WriteString("  if (!isPlainObject(value)) return params;\n")
-	b.WriteString("  for (const [k, v] of Object.entries(value)) {\n")
-	b.WriteString("    if (v === undefined || v === null) continue;\n")
-	b.WriteString("    if (Array.isArray(v)) {\n")
-	b.WriteString("      for (const item of v) params.append(k, String(item));\n")
-	b.WriteString("      continue;\n")
+	b.WriteString("const normalizedClients = new WeakSet<AxiosInstance>();\n\n")
+	b.WriteString("const applyNormalizationInterceptors = (instance: AxiosInstance): void => {\n")
+	b.WriteString("  if (normalizedClients.has(instance)) return;\n")
+	b.WriteString("  normalizedClients.add(instance);\n")
+	b.WriteString("  instance.interceptors.request.use((config) => {\n")
+	if needsMultipartHelper {
+		b.WriteString("    if (config.data !== undefined && !isFormData(config.data)) config.data = normalizeRequestJSON(config.data);\n")
+	} else {
+		b.WriteString("    if (config.data !== undefined) config.data = normalizeRequestJSON(config.data);\n")
+	}
+	b.WriteString("    if (config.params !== undefined) config.params = normalizeRequestJSON(config.params);\n")
+	b.WriteString("    for (const [key, value] of Object.entries(defaultHeaders)) {\n")
+	b.WriteString("      if (!config.headers.has(key)) config.headers.set(key, value);\n")
 	b.WriteString("    }\n")
-	b.WriteString("    params.append(k, String(v));\n")
-	b.WriteString("  }\n")
-	b.WriteString("  return params;\n")
+	b.WriteString("    return config;\n")
+	b.WriteString("  });\n")
 	b.WriteString("};\n\n")
-	b.WriteString("axiosClient.interceptors.request.use((config) => {\n")
-	b.WriteString("  if (config.data !== undefined) config.data = normalizeRequestJSON(config.data);\n")
-	b.WriteString("  if (config.params !== undefined) config.params = normalizeRequestJSON(config.params);\n")
-	b.WriteString("  return config;\n")
-	b.WriteString("});\n\n")
-	b.WriteString("axiosClient.interceptors.response.use((response) => {\n")
-	b.WriteString("  const rt = response.config?.responseType;\n")
-	b.WriteString("  if (rt !== 'arraybuffer' && rt !== 'blob' && rt !== 'text') {\n")
-	b.WriteString("    response.data = normalizeResponseJSON(response.data);\n")
-	b.WriteString("  }\n")
-	b.WriteString("  return response;\n")
-	b.WriteString("});\n\n")
+	b.WriteString("applyNormalizationInterceptors(axiosClient);\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Use an app-configured axios instance (auth, base URL, retry) for all generated requests.\n")
+	b.WriteString(" * Date/JSON normalization interceptors are added to it once. Its baseURL, if set, replaces the dev-mode Gin port.\n")
+	b.WriteString(" * 使用应用自己配置的 axios 实例（鉴权、baseURL、重试等）发送所有生成的请求；会为其添加一次日期/JSON 归一化拦截器；设置了 baseURL 时取代开发模式的 Gin 端口地址。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const setAxiosClient = (instance: AxiosInstance): void => {\n")
+	b.WriteString("  applyNormalizationInterceptors(instance);\n")
+	b.WriteString("  axiosClient = instance;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * The axios instance currently used by generated requests.\n")
+	b.WriteString(" * 当前生成的请求所使用的 axios 实例。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const getAxiosClient = (): AxiosInstance => axiosClient;\n\n")
+	if opts.AbortAll {
+		writeAxiosAbortAllHelpers(&b)
+	}
 	b.WriteString("export interface AxiosConvertOptions<TRequest = unknown, TResponse = unknown> {\n")
 	b.WriteString("  serializeRequest?: (value: TRequest) => unknown;\n")
 	b.WriteString("  deserializeResponse?: (value: unknown) => TResponse;\n")
+	b.WriteString("  /** Cancels the request when aborted, e.g. on component unmount. 中止时取消请求（如组件卸载时）。 */\n")
+	b.WriteString("  signal?: AbortSignal;\n")
+	b.WriteString("  /** Upload progress, for endpoints with a request body. 上传进度回调（仅对有请求体的端点生效）。 */\n")
+	b.WriteString("  onUploadProgress?: (event: AxiosProgressEvent) => void;\n")
+	b.WriteString("  /** Download progress, e.g. for blob/arraybuffer responses. 下载进度回调（如 blob/arraybuffer 响应）。 */\n")
+	b.WriteString("  onDownloadProgress?: (event: AxiosProgressEvent) => void;\n")
+	b.WriteString("  /** Request timeout in ms, overriding the endpoint default; 0 disables it. 请求超时（毫秒），覆盖端点默认值；0 表示不限制。 */\n")
+	b.WriteString("  timeout?: number;\n")
+	b.WriteString("  /** GET only: send If-None-Match with the last ETag of this URL and reuse its cached body on 304. 仅 GET：携带该 URL 上次的 ETag 发送 If-None-Match，304 时复用缓存的响应体。 */\n")
+	b.WriteString("  useETagCache?: boolean;\n")
+	b.WriteString("  /** Ad-hoc headers (request ID, idempotency key) merged last, over header params and Content-Type. 临时附加的请求头（如请求 ID、幂等键），最后合并，覆盖 header 参数与 Content-Type。 */\n")
+	b.WriteString("  headers?: Record<string, string>;\n")
 	b.WriteString("}\n\n")
-	b.WriteString("const normalizeParamKeys = (\n")
-	b.WriteString("  params: Record<string, any>,\n")
-	b.WriteString("  maps: { query?: Record<string, string>; header?: Record<string, string>; cookie?: Record<string, string> }\n")
-	b.WriteString(") => {\n")
-	b.WriteString("  const out: Record<string, any> = {};\n")
-	b.WriteString("  for (const key of ['query', 'header', 'cookie']) {\n")
-	b.WriteString("    const group = (params as any)?.[key] ?? {};\n")
-	b.WriteString("    const map = (maps as any)?.[key] ?? {};\n")
-	b.WriteString("    const normalized: Record<string, any> = {};\n")
-	b.WriteString("    for (const [k, v] of Object.entries(group)) {\n")
-	b.WriteString("      const mapped = map[k.toLowerCase()] ?? k;\n")
-	b.WriteString("      normalized[mapped] = v;\n")
-	b.WriteString("    }\n")
-	b.WriteString("    out[key] = normalized;\n")
-	b.WriteString("  }\n")
-	b.WriteString("  return out;\n")
-	b.WriteString("};\n\n")
-	writeTSMarkerEnd(&b, "Runtime Helpers")
-
-	if len(registry.defs) > 0 {
-		writeTSMarker(&b, "Interfaces & Validators")
-		b.WriteString("// =====================================================\n")
-		b.WriteString("// INTERFACES & VALIDATORS\n")
-		b.WriteString("// Default: object schemas use interface.\n")
-		b.WriteString("// Fallback: use type only when interface cannot model the shape.\n")
-		b.WriteString("// 默认：对象结构使用 interface。\n")
-		b.WriteString("// 兜底：只有 interface 无法表达时才使用 type。\n")
-		b.WriteString("// =====================================================\n\n")
-	}
-	sortedDefs := append([]tsInterfaceDef(nil), registry.defs...)
-	sort.Slice(sortedDefs, func(i, j int) bool {
-		return sortedDefs[i].Name < sortedDefs[j].Name
-	})
-	for _, def := range sortedDefs {
-		b.WriteString("// -----------------------------------------------------\n")
-		b.WriteString("// TYPE: ")
-		b.WriteString(def.Name)
-		b.WriteString("\n")
-		b.WriteString("// -----------------------------------------------------\n")
-		b.WriteString("export interface ")
-		b.WriteString(def.Name)
-		b.WriteString(" {\n")
-		if def.Body != "" {
-			b.WriteString(def.Body)
+	for _, m := range metas {
+		if axiosUsesETagCache(m) {
+			writeAxiosETagCacheHelpers(&b)
+			break
 		}
+	}
+	if TSFormatAPIErrorHelper {
+		b.WriteString("export interface FormatApiErrorOptions {\n")
+		b.WriteString("  /** Field of the error body holding the message. 错误响应体中的消息字段名。 */\n")
+		b.WriteString("  messageField?: string;\n")
+		b.WriteString("  /** Message used for non-HTTP errors. 非 HTTP 错误的兜底消息。 */\n")
+		b.WriteString("  fallback?: string;\n")
 		b.WriteString("}\n\n")
-		if strings.TrimSpace(def.Validator) != "" {
-			b.WriteString(def.Validator)
-			b.WriteString("\n")
-			b.WriteString("/**\n")
-			b.WriteString(" * Ensure a typed ")
-			b.WriteString(def.Name)
-			b.WriteString(" after validation.\n")
-			b.WriteString(" * 先校验，再确保得到类型化的 ")
-			b.WriteString(def.Name)
-			b.WriteString("。\n")
-			b.WriteString(" */\n")
-			b.WriteString("export function ensure")
-			b.WriteString(def.Name)
-			b.WriteString("(value: unknown): ")
-			b.WriteString(def.Name)
-			b.WriteString(" {\n")
-			b.WriteString("  if (!validate")
-			b.WriteString(def.Name)
-			b.WriteString("(value)) {\n")
-			b.WriteString("    throw new Error('Invalid ")
-			b.WriteString(def.Name)
-			b.WriteString("');\n")
-			b.WriteString("  }\n")
-			b.WriteString("  return value;\n")
-			b.WriteString("}\n\n")
+		b.WriteString("/**\n")
+		b.WriteString(" * Turn a thrown API error into a human readable message, e.g. for toasts.\n")
+		b.WriteString(" * Uses the typed error body's message field, then falls back to the HTTP status text.\n")
+		b.WriteString(" * 将抛出的 API 错误转换为可读消息（如 toast）；优先取错误响应体的消息字段，其次使用 HTTP 状态文本。\n")
+		b.WriteString(" */\n")
+		b.WriteString("export const formatApiError = (error: unknown, options?: FormatApiErrorOptions): string => {\n")
+		b.WriteString("  const field = options?.messageField ?? 'message';\n")
+		b.WriteString("  const fallback = options?.fallback ?? 'Unknown error';\n")
+		b.WriteString("  if (axios.isAxiosError(error)) {\n")
+		b.WriteString("    const response = error.response;\n")
+		b.WriteString("    if (!response) return error.message || fallback;\n")
+		b.WriteString("    const body = response.data;\n")
+		b.WriteString("    if (isPlainObject(body)) {\n")
+		b.WriteString("      const message = body[field];\n")
+		b.WriteString("      if (typeof message === 'string' && message.trim() !== '') return message;\n")
+		b.WriteString("    }\n")
+		b.WriteString("    const statusText = response.statusText?.trim();\n")
+		b.WriteString("    return statusText ? `${response.status} ${statusText}` : `Request failed with status ${response.status}`;\n")
+		b.WriteString("  }\n")
+		b.WriteString("  if (error instanceof Error && error.message) return error.message;\n")
+		b.WriteString("  return fallback;\n")
+		b.WriteString("};\n\n")
+	}
+	writeHTTPNormalizeParamKeys(&b)
+	writeTSMarkerEnd(&b, "Runtime Helpers")
+
+	writeHTTPInterfaces(&b, registry, metas, !opts.OmitValidators)
+	if opts.ZodSchemas {
+		zodCode, err := renderZodSchemas(registry)
+		if err != nil {
+			return "", err
 		}
+		b.WriteString(zodCode)
 	}
-	if len(registry.defs) > 0 {
-		writeTSMarkerEnd(&b, "Interfaces & Validators")
+	if opts.Mocks {
+		mockCode, err := renderMockFactories(registry)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(mockCode)
 	}
 
 	writeTSMarker(&b, "Endpoint Classes")
 
+	needsQueryHelper := false
+	for _, m := range metas {
+		if m.HasQuery && !m.StreamBody && m.ResponseKind != TSKindEventStream {
+			needsQueryHelper = true
+			break
+		}
+	}
+	if needsQueryHelper {
+		writeHTTPQueryHelpers(&b)
+	}
+
 	needsCookieHelper := false
 	for _, m := range metas {
 		if m.HasCookie {
@@ -324,122 +618,63 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 			break
 		}
 	}
+	needsPathParamsHelper := false
+	if TSStrictPathParams {
+		for _, m := range metas {
+			if len(extractPathParams(m.Path)) > 0 {
+				needsPathParamsHelper = true
+				break
+			}
+		}
+	}
+	if needsPathParamsHelper {
+		writeHTTPPathParamsHelper(&b)
+	}
+	if needsStreamHelper {
+		writeAxiosStreamHelpers(&b)
+	}
+	for _, m := range metas {
+		if m.CSVRowType != "" {
+			writeAxiosCSVHelpers(&b)
+			break
+		}
+	}
+	for _, m := range metas {
+		if m.Batch {
+			writeAxiosBatchHelpers(&b)
+			break
+		}
+	}
+	for _, m := range metas {
+		if m.ResponseKind == TSKindEventStream {
+			writeEventSourceHelpers(&b, axiosEventSourceURLResolver)
+			break
+		}
+	}
+	writeAxiosReviveHelpers(&b, metas)
 	if needsCookieHelper {
-		b.WriteString("const buildCookieHeader = (cookie: Record<string, unknown>): string =>\n")
-		b.WriteString("  Object.entries(cookie)\n")
-		b.WriteString("    .map(([k, v]) => `${k}=${encodeURIComponent(String(v))}`)\n")
-		b.WriteString("    .join('; ');\n\n")
+		writeHTTPCookieHelpers(&b)
 	}
 
 	fullBasePath := normalizePathSegment(basePath)
 	fullGroupPath := normalizePathSegment(groupPath)
 	for _, m := range metas {
-		className := toUpperCamel(m.FuncName) + toUpperCamel(strings.ToLower(m.Method))
-		fullPathPrefix := resolveAPIPath(fullBasePath, fullGroupPath)
-		fullPath := joinURLPath(fullPathPrefix, m.Path)
-		hasPathPlaceholders := len(extractPathParams(m.Path)) > 0
-		pathParamNames := extractPathParams(m.Path)
-		mappedPathParamNames := make([]string, 0, len(pathParamNames))
-		for _, raw := range pathParamNames {
-			key := strings.ToLower(raw)
-			if mapped, ok := m.PathParamMap[key]; ok && strings.TrimSpace(mapped) != "" {
-				mappedPathParamNames = append(mappedPathParamNames, mapped)
-				continue
-			}
-			mappedPathParamNames = append(mappedPathParamNames, raw)
-		}
-		if m.APIDescription != "" || m.RequestDesc != "" || m.ResponseDesc != "" {
-			b.WriteString("/**\n")
-			if m.APIDescription != "" {
-				b.WriteString(" * ")
-				b.WriteString(escapeTSComment(m.APIDescription))
-				b.WriteString("\n")
-			}
-			if m.RequestDesc != "" {
-				b.WriteString(" * @request ")
-				b.WriteString(escapeTSComment(m.RequestDesc))
-				b.WriteString("\n")
-			}
-			if m.ResponseDesc != "" {
-				b.WriteString(" * @response")
-				if m.ResponseStatus > 0 {
-					b.WriteString(" ")
-					b.WriteString(fmt.Sprintf("%d", m.ResponseStatus))
-				}
-				b.WriteString(" ")
-				b.WriteString(escapeTSComment(m.ResponseDesc))
-				b.WriteString("\n")
-			}
-			b.WriteString(" */\n")
-		}
-		b.WriteString("export class ")
-		b.WriteString(className)
-		b.WriteString(" {\n")
-		b.WriteString("  static readonly NAME = '")
-		b.WriteString(strings.ReplaceAll(m.FuncName, "'", "\\'"))
-		b.WriteString("' as const;\n")
-		b.WriteString("  static readonly SUMMARY = '")
-		b.WriteString(strings.ReplaceAll(escapeTSComment(m.APIDescription), "'", "\\'"))
-		b.WriteString("' as const;\n")
-		b.WriteString("  static readonly METHOD = '")
-		b.WriteString(m.Method)
-		b.WriteString("' as const;\n")
-		b.WriteString("  static readonly PATHS = {\n")
-		b.WriteString("    base: '")
-		b.WriteString(strings.ReplaceAll(fullBasePath, "'", "\\'"))
-		b.WriteString("',\n")
-		b.WriteString("    group: '")
-		b.WriteString(strings.ReplaceAll(fullGroupPath, "'", "\\'"))
-		b.WriteString("',\n")
-		b.WriteString("    api: '")
-		b.WriteString(strings.ReplaceAll(m.Path, "'", "\\'"))
-		b.WriteString("',\n")
-		b.WriteString("  } as const;\n")
-		b.WriteString("  static readonly FULL_PATH = '")
-		b.WriteString(strings.ReplaceAll(fullPath, "'", "\\'"))
-		b.WriteString("' as const;\n\n")
-		args := make([]string, 0, 3)
-		if m.HasParams {
-			args = append(args, "params: "+m.ParamsType)
-		}
-		if m.HasReqBody {
-			args = append(args, "requestBody: "+m.RequestType)
-		}
-		b.WriteString("  static pathParamsShape(): readonly string[] {\n")
-		if len(mappedPathParamNames) == 0 {
-			b.WriteString("    return [] as const;\n")
-		} else {
-			b.WriteString("    return [")
-			for i, name := range mappedPathParamNames {
-				if i > 0 {
-					b.WriteString(", ")
-				}
-				b.WriteString("'")
-				b.WriteString(strings.ReplaceAll(name, "'", "\\'"))
-				b.WriteString("'")
-			}
-			b.WriteString("] as const;\n")
+		className, args, hasPathPlaceholders := writeHTTPClassHeader(&b, m, fullBasePath, fullGroupPath)
+		if m.StreamBody {
+			writeAxiosStreamRequest(&b, m, className, args, hasPathPlaceholders, opts.AbortAll)
+			continue
 		}
-		b.WriteString("  }\n\n")
-		b.WriteString("  static buildURL")
-		if hasPathPlaceholders {
-			b.WriteString("(params: ")
-			b.WriteString(m.ParamsType)
-			b.WriteString("): string {\n")
-			b.WriteString("    return ")
-			b.WriteString(buildTSURLExprWithBaseAndMap(fullPathPrefix, m.Path, m.PathParamMap))
-			b.WriteString(";\n")
-		} else {
-			b.WriteString("(): string {\n")
-			b.WriteString("    return ")
-			b.WriteString(className)
-			b.WriteString(".FULL_PATH;\n")
+		if m.ResponseKind == TSKindEventStream {
+			writeAxiosEventSourceConnect(&b, m, className, hasPathPlaceholders)
+			writeHTTPRouteFunc(&b, m, className)
+			continue
 		}
-		b.WriteString("  }\n\n")
 		requestConfigArgs := make([]string, 0, 3)
 		requestConfigArgs = append(requestConfigArgs, args...)
 		if m.HasReqBody {
-			requestConfigArgs = append(requestConfigArgs, "options?: AxiosConvertOptions<"+m.RequestType+", "+m.ResponseType+">")
+			requestConfigArgs = append(requestConfigArgs, "options?: AxiosConvertOptions<"+m.RequestType+", "+axiosOptionsResponseType(m)+">")
+		} else {
+			requestConfigArgs = append(requestConfigArgs, "options?: AxiosConvertOptions<never, "+axiosOptionsResponseType(m)+">")
 		}
 		b.WriteString("  static requestConfig")
 		b.WriteString("(")
@@ -455,71 +690,35 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 			b.WriteString(".buildURL();\n")
 		}
 		if m.HasReqBody {
-			if m.RequestKind == TSKindFormURLEncoded {
+			switch m.RequestKind {
+			case TSKindFormURLEncoded:
 				b.WriteString("    const serializedRequest = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
 				b.WriteString("    const requestData = toFormUrlEncoded(serializedRequest);\n")
-			} else {
+			case TSKindMultipart:
+				// No Content-Type header: the browser sets multipart/form-data with its boundary.
+				b.WriteString("    const serializedRequest = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
+				b.WriteString("    const requestData = toFormData(serializedRequest);\n")
+			default:
 				b.WriteString("    const requestData = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
 			}
 		}
-		needsNormalizedParams := m.HasQuery || m.HasHeader || m.HasCookie
-		if needsNormalizedParams {
-			b.WriteString("    const normalizedParams = normalizeParamKeys(params, {\n")
-			if m.HasQuery {
-				b.WriteString("      query: ")
-				b.WriteString(renderParamMapObject(m.QueryParamMap))
-				b.WriteString(",\n")
-			}
-			if m.HasHeader {
-				b.WriteString("      header: ")
-				b.WriteString(renderParamMapObject(m.HeaderParamMap))
-				b.WriteString(",\n")
-			}
-			if m.HasCookie {
-				b.WriteString("      cookie: ")
-				b.WriteString(renderParamMapObject(m.CookieParamMap))
-				b.WriteString(",\n")
-			}
-			b.WriteString("    });\n")
-		}
-		requestHeaderValue := ""
-		switch m.RequestKind {
-		case TSKindFormURLEncoded:
-			requestHeaderValue = "application/x-www-form-urlencoded"
-		case TSKindText:
-			requestHeaderValue = "text/plain; charset=utf-8"
-		case TSKindBytes:
-			requestHeaderValue = "application/octet-stream"
-		}
-		needsHeaders := m.HasHeader || m.HasCookie || requestHeaderValue != ""
-		if requestHeaderValue != "" {
-			b.WriteString("    const requestHeaders = { 'Content-Type': '")
-			b.WriteString(requestHeaderValue)
-			b.WriteString("' };\n")
-		}
-		if needsHeaders {
-			b.WriteString("    const headers = {\n")
-			if m.HasHeader {
-				b.WriteString("      ...(normalizedParams?.header ?? {}),\n")
-			}
-			if requestHeaderValue != "" {
-				b.WriteString("      ...requestHeaders,\n")
-			}
-			if m.HasCookie {
-				b.WriteString("      Cookie: buildCookieHeader((normalizedParams?.cookie ?? {}) as Record<string, unknown>),\n")
-			}
-			b.WriteString("    };\n")
-		}
+		writeHTTPNormalizedParams(&b, m)
+		needsHeaders := writeHTTPRequestHeaders(&b, m, httpRequestContentType(m.RequestKind))
 		b.WriteString("    return {\n")
 		b.WriteString("      method: ")
 		b.WriteString(className)
 		b.WriteString(".METHOD,\n")
-		b.WriteString("      url,\n")
+		b.WriteString("      url: resolveHttpBaseURL(url),\n")
 		if m.HasQuery {
 			b.WriteString("      params: normalizedParams.query,\n")
+			b.WriteString("      paramsSerializer: (query: Record<string, unknown>) => serializeQueryParams(query, '")
+			b.WriteString(string(m.QueryArrayFormat))
+			b.WriteString("'),\n")
 		}
 		if needsHeaders {
-			b.WriteString("      headers,\n")
+			b.WriteString("      headers: { ...headers, ...(options?.headers ?? {}) },\n")
+		} else {
+			b.WriteString("      ...(options?.headers ? { headers: options.headers } : {}),\n")
 		}
 		switch m.ResponseKind {
 		case TSKindStream:
@@ -532,6 +731,17 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 		if m.HasReqBody {
 			b.WriteString("      data: requestData,\n")
 		}
+		b.WriteString("      signal: options?.signal,\n")
+		if m.TimeoutMS > 0 {
+			b.WriteString(fmt.Sprintf("      timeout: options?.timeout ?? %d,\n", m.TimeoutMS))
+		} else {
+			b.WriteString("      ...(options?.timeout !== undefined ? { timeout: options.timeout } : {}),\n")
+		}
+		// Progress callbacks are spread in only when set, so the config stays minimal.
+		if m.HasReqBody {
+			b.WriteString("      ...(options?.onUploadProgress ? { onUploadProgress: options.onUploadProgress } : {}),\n")
+		}
+		b.WriteString("      ...(options?.onDownloadProgress ? { onDownloadProgress: options.onDownloadProgress } : {}),\n")
 		b.WriteString("    };\n")
 		b.WriteString("  }\n\n")
 		b.WriteString("  static async request")
@@ -547,7 +757,7 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 			b.WriteString("never")
 		}
 		b.WriteString(", ")
-		b.WriteString(m.ResponseType)
+		b.WriteString(axiosOptionsResponseType(m))
 		b.WriteString(">")
 		b.WriteString("): Promise<")
 		b.WriteString(m.ResponseType)
@@ -558,15 +768,55 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 		}
 		if m.HasReqBody {
 			callArgs = append(callArgs, "requestBody")
+		}
+		// With AbortAll the request runs on a tracked signal, registered only while it is awaited.
+		sendPrefix, sendSuffix := "", ""
+		if opts.AbortAll {
+			callArgs = append(callArgs, "{ ...options, signal }")
+			sendPrefix, sendSuffix = "trackRequest(options?.signal, (signal) => ", ")"
+		} else {
 			callArgs = append(callArgs, "options")
 		}
-		b.WriteString("    const response = await axiosClient.request<")
-		b.WriteString(m.ResponseWireType)
-		b.WriteString(">(")
-		b.WriteString(className)
-		b.WriteString(".requestConfig(")
-		b.WriteString(strings.Join(callArgs, ", "))
-		b.WriteString("));\n")
+		if len(m.ResponseVariants) > 0 {
+			b.WriteString("    const response = await ")
+			b.WriteString(sendPrefix)
+			b.WriteString("axiosClient.request<unknown>({\n")
+			b.WriteString("      ...")
+			b.WriteString(className)
+			b.WriteString(".requestConfig(")
+			b.WriteString(strings.Join(callArgs, ", "))
+			b.WriteString("),\n")
+			b.WriteString("      validateStatus: () => true,\n")
+			b.WriteString("    })")
+			b.WriteString(sendSuffix)
+			b.WriteString(";\n")
+			writeHTTPResponseVariantSwitch(&b, m, className)
+			b.WriteString("  }\n")
+			b.WriteString("}\n\n")
+			writeHTTPRequestWrapper(&b, m, className, args, "AxiosConvertOptions")
+			continue
+		}
+		b.WriteString("    const response = await ")
+		b.WriteString(sendPrefix)
+		if axiosUsesETagCache(m) {
+			b.WriteString("requestWithETagCache(")
+			b.WriteString(className)
+			b.WriteString(".requestConfig(")
+			b.WriteString(strings.Join(callArgs, ", "))
+			b.WriteString("), options?.useETagCache, (config) => axiosClient.request<")
+			b.WriteString(m.ResponseWireType)
+			b.WriteString(">(config))")
+		} else {
+			b.WriteString("axiosClient.request<")
+			b.WriteString(m.ResponseWireType)
+			b.WriteString(">(")
+			b.WriteString(className)
+			b.WriteString(".requestConfig(")
+			b.WriteString(strings.Join(callArgs, ", "))
+			b.WriteString("))")
+		}
+		b.WriteString(sendSuffix)
+		b.WriteString(";\n")
 		if m.ResponseType == "void" {
 			b.WriteString("    return;\n")
 		} else {
@@ -577,7 +827,13 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 				b.WriteString("    }\n")
 				b.WriteString("    return responseData;\n")
 			} else {
-				b.WriteString("    const responseData = response.data as unknown;\n")
+				if len(m.BigIntPaths) > 0 || len(m.DatePaths) > 0 {
+					b.WriteString("    const responseData = ")
+					b.WriteString(axiosReviveExpr("response.data", m.DatePaths, m.BigIntPaths))
+					b.WriteString(";\n")
+				} else {
+					b.WriteString("    const responseData = response.data as unknown;\n")
+				}
 				b.WriteString("    if (options?.deserializeResponse) {\n")
 				b.WriteString("      return options.deserializeResponse(responseData);\n")
 				b.WriteString("    }\n")
@@ -588,58 +844,1667 @@ func renderAxiosTS(basePath string, groupPath string, registry *tsInterfaceRegis
 		}
 		b.WriteString("  }\n")
 		b.WriteString("}\n\n")
-		b.WriteString("export async function request")
-		b.WriteString(className)
-		b.WriteString("(")
-		b.WriteString(strings.Join(args, ", "))
-		if len(args) > 0 {
-			b.WriteString(", ")
+		writeHTTPRequestWrapper(&b, m, className, args, "AxiosConvertOptions")
+	}
+	writeTSMarkerEnd(&b, "Endpoint Classes")
+	requestMetas := withoutEventStreams(metas)
+	if TSResponseCacheHelpers {
+		writeAxiosResponseCache(&b, requestMetas)
+	}
+	if TSOfflineMutationQueue {
+		writeAxiosOfflineQueue(&b, requestMetas)
+	}
+	if opts.VueComposables {
+		writeAxiosVueComposables(&b, requestMetas)
+	}
+	if opts.TanstackQuery {
+		b.WriteString(renderTanstackTS(requestMetas))
+	}
+
+	return finalizeTypeScriptCode(b.String()), nil
+}
+
+// writeHTTPRequestNormalizers renders the JSON/form request normalizers and the dev-mode Gin port lookup.
+func writeHTTPRequestNormalizers(b *strings.Builder) {
+	b.WriteString("const isPlainObject = (value: unknown): value is Record<string, unknown> =>\n")
+	b.WriteString("  Object.prototype.toString.call(value) === '[object Object]';\n\n")
+	if TSInt64MappingMode == TSInt64ModeString || TSInt64MappingMode == TSInt64ModeBigInt {
+		writeAxiosInt64Helpers(b)
+	}
+	b.WriteString("const normalizeRequestJSON = (value: unknown): unknown => {\n")
+	b.WriteString("  if (value instanceof Date) return value.toISOString();\n")
+	if TSInt64MappingMode == TSInt64ModeBigInt {
+		b.WriteString("  if (typeof value === 'bigint') return formatInt64(value);\n")
+	}
+	b.WriteString("  if (Array.isArray(value)) return value.map(normalizeRequestJSON);\n")
+	b.WriteString("  if (isPlainObject(value)) {\n")
+	b.WriteString("    const out: Record<string, unknown> = {};\n")
+	b.WriteString("    for (const [k, v] of Object.entries(value)) out[k] = normalizeRequestJSON(v);\n")
+	b.WriteString("    return out;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return value;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const toFormUrlEncoded = (value: unknown): URLSearchParams => {\n")
+	b.WriteString("  if (value instanceof URLSearchParams) return value;\n")
+	b.WriteString("  const params = new URLSearchParams();\n")
+	b.WriteString("  if (!isPlainObject(value)) return params;\n")
+	b.WriteString("  for (const [k, v] of Object.entries(value)) {\n")
+	b.WriteString("    if (v === undefined || v === null) continue;\n")
+	b.WriteString("    if (Array.isArray(v)) {\n")
+	b.WriteString("      for (const item of v) params.append(k, String(item));\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    params.append(k, String(v));\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return params;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const isDevelopmentEnv = (): boolean => {\n")
+	b.WriteString("  if (typeof import.meta !== 'undefined' && (import.meta as any)?.env) {\n")
+	b.WriteString("    const dev = (import.meta as any).env?.DEV;\n")
+	b.WriteString("    if (typeof dev === 'boolean') return dev;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return false;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const resolveGinPort = (): string => {\n")
+	b.WriteString("  if (typeof window !== 'undefined') {\n")
+	b.WriteString("    const ginPort = useRuntimeConfig().public.ginPort;\n")
+	b.WriteString("    if (ginPort !== undefined && ginPort !== null && String(ginPort).trim() !== '') {\n")
+	b.WriteString("      return String(ginPort);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    if (window.location?.port && window.location.port.trim() !== '') {\n")
+	b.WriteString("      return window.location.port;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    return window.location?.protocol === 'https:' ? '443' : '80';\n")
+	b.WriteString("  }\n")
+	b.WriteString("  if (typeof import.meta !== 'undefined' && (import.meta as any)?.env?.NUXT_GIN_PORT) {\n")
+	b.WriteString("    return String((import.meta as any).env.NUXT_GIN_PORT);\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return '80';\n")
+	b.WriteString("};\n\n")
+}
+
+func needsHTTPMultipartHelper(metas []axiosFuncMeta) bool {
+	for _, m := range metas {
+		if m.HasReqBody && m.RequestKind == TSKindMultipart {
+			return true
 		}
-		b.WriteString("options?: AxiosConvertOptions<")
-		if m.HasReqBody {
-			b.WriteString(m.RequestType)
-		} else {
-			b.WriteString("never")
+	}
+	return false
+}
+
+func writeHTTPFormDataHelpers(b *strings.Builder) {
+	b.WriteString("const isFormData = (value: unknown): value is FormData =>\n")
+	b.WriteString("  typeof FormData !== 'undefined' && value instanceof FormData;\n\n")
+	b.WriteString("const toFormData = (value: unknown): FormData => {\n")
+	b.WriteString("  if (isFormData(value)) return value;\n")
+	b.WriteString("  const form = new FormData();\n")
+	b.WriteString("  if (!isPlainObject(value)) return form;\n")
+	b.WriteString("  const append = (k: string, v: unknown) => {\n")
+	b.WriteString("    if (v === undefined || v === null) return;\n")
+	b.WriteString("    if (v instanceof Blob) form.append(k, v);\n")
+	b.WriteString("    else if (v instanceof Date) form.append(k, v.toISOString());\n")
+	b.WriteString("    else form.append(k, String(v));\n")
+	b.WriteString("  };\n")
+	b.WriteString("  for (const [k, v] of Object.entries(value)) {\n")
+	b.WriteString("    if (Array.isArray(v)) {\n")
+	b.WriteString("      for (const item of v) append(k, item);\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    append(k, v);\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return form;\n")
+	b.WriteString("};\n\n")
+}
+
+func writeHTTPNormalizeParamKeys(b *strings.Builder) {
+	b.WriteString("const normalizeParamKeys = (\n")
+	b.WriteString("  params: Record<string, any>,\n")
+	b.WriteString("  maps: { query?: Record<string, string>; header?: Record<string, string>; cookie?: Record<string, string> }\n")
+	b.WriteString(") => {\n")
+	b.WriteString("  const out: Record<string, any> = {};\n")
+	b.WriteString("  for (const key of ['query', 'header', 'cookie']) {\n")
+	b.WriteString("    const group = (params as any)?.[key] ?? {};\n")
+	b.WriteString("    const map = (maps as any)?.[key] ?? {};\n")
+	b.WriteString("    const normalized: Record<string, any> = {};\n")
+	b.WriteString("    for (const [k, v] of Object.entries(group)) {\n")
+	b.WriteString("      const mapped = map[k.toLowerCase()] ?? k;\n")
+	b.WriteString("      normalized[mapped] = normalizeRequestJSON(v);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    out[key] = normalized;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return out;\n")
+	b.WriteString("};\n\n")
+}
+
+// writeHTTPInterfaces renders the "Interfaces & Validators" region: named unions, interfaces,
+// validate/ensure functions (when validators is set) and the describeX() helpers of JSON request bodies.
+func writeHTTPInterfaces(b *strings.Builder, registry *tsInterfaceRegistry, metas []axiosFuncMeta, validators bool) {
+	describedTypes := map[string]struct{}{}
+	for _, m := range metas {
+		if m.HasReqBody && m.RequestKind == TSKindJSON {
+			describedTypes[m.RequestType] = struct{}{}
 		}
-		b.WriteString(", ")
-		b.WriteString(m.ResponseType)
-		b.WriteString(">")
-		b.WriteString("): Promise<")
-		b.WriteString(m.ResponseType)
-		b.WriteString("> {\n")
-		wrapperCallArgs := make([]string, 0, 3)
-		if m.HasParams {
-			wrapperCallArgs = append(wrapperCallArgs, "params")
+	}
+	hasDescribe := false
+	for _, def := range registry.defs {
+		if _, ok := describedTypes[def.Name]; ok && def.Describe != "" {
+			hasDescribe = true
+			break
 		}
-		if m.HasReqBody {
-			wrapperCallArgs = append(wrapperCallArgs, "requestBody")
+	}
+	if len(registry.defs) > 0 {
+		writeTSMarker(b, "Interfaces & Validators")
+		b.WriteString("// =====================================================\n")
+		b.WriteString("// INTERFACES & VALIDATORS\n")
+		b.WriteString("// Default: object schemas use interface.\n")
+		b.WriteString("// Fallback: use type only when interface cannot model the shape.\n")
+		b.WriteString("// 默认：对象结构使用 interface。\n")
+		b.WriteString("// 兜底：只有 interface 无法表达时才使用 type。\n")
+		b.WriteString("// =====================================================\n\n")
+	}
+	// TSFieldDescriptor lives with the describeX() helpers so unified export moves them together.
+	if hasDescribe {
+		b.WriteString("export interface TSFieldDescriptor {\n")
+		b.WriteString("  name: string;\n")
+		b.WriteString("  type: string;\n")
+		b.WriteString("  optional: boolean;\n")
+		b.WriteString("  description?: string;\n")
+		b.WriteString("}\n\n")
+	}
+	b.WriteString(renderNamedUnions(registry))
+	sortedDefs := append([]tsInterfaceDef(nil), registry.defs...)
+	sort.Slice(sortedDefs, func(i, j int) bool {
+		return sortedDefs[i].Name < sortedDefs[j].Name
+	})
+	for _, def := range sortedDefs {
+		b.WriteString("// -----------------------------------------------------\n")
+		b.WriteString("// TYPE: ")
+		b.WriteString(def.Name)
+		b.WriteString("\n")
+		b.WriteString("// -----------------------------------------------------\n")
+		b.WriteString(renderTSInterfaceDoc(def.Doc))
+		b.WriteString("export interface ")
+		b.WriteString(def.Name)
+		b.WriteString(" {\n")
+		if def.Body != "" {
+			b.WriteString(def.Body)
 		}
-		wrapperCallArgs = append(wrapperCallArgs, "options")
-		b.WriteString("  return ")
-		b.WriteString(className)
-		b.WriteString(".request(")
-		b.WriteString(strings.Join(wrapperCallArgs, ", "))
-		b.WriteString(");\n")
 		b.WriteString("}\n\n")
+		if validators && strings.TrimSpace(def.Validator) != "" {
+			b.WriteString(def.Validator)
+			b.WriteString("\n")
+			b.WriteString("/**\n")
+			b.WriteString(" * Ensure a typed ")
+			b.WriteString(def.Name)
+			b.WriteString(" after validation.\n")
+			b.WriteString(" * 先校验，再确保得到类型化的 ")
+			b.WriteString(def.Name)
+			b.WriteString("。\n")
+			b.WriteString(" */\n")
+			b.WriteString("export function ensure")
+			b.WriteString(def.Name)
+			b.WriteString("(value: unknown): ")
+			b.WriteString(def.Name)
+			b.WriteString(" {\n")
+			b.WriteString("  if (!validate")
+			b.WriteString(def.Name)
+			b.WriteString("(value)) {\n")
+			b.WriteString("    throw new Error('Invalid ")
+			b.WriteString(def.Name)
+			b.WriteString("');\n")
+			b.WriteString("  }\n")
+			b.WriteString("  return value;\n")
+			b.WriteString("}\n\n")
+		}
+		if _, ok := describedTypes[def.Name]; ok && def.Describe != "" {
+			b.WriteString(def.Describe)
+			b.WriteString("\n")
+		}
 	}
-	writeTSMarkerEnd(&b, "Endpoint Classes")
+	if len(registry.defs) > 0 {
+		writeTSMarkerEnd(b, "Interfaces & Validators")
+	}
+}
 
-	return finalizeTypeScriptCode(b.String()), nil
+func writeHTTPQueryHelpers(b *strings.Builder) {
+	b.WriteString("type QueryArrayFormat = 'repeat' | 'comma' | 'brackets';\n\n")
+	b.WriteString("const toQueryString = (value: unknown): string => {\n")
+	b.WriteString("  if (value instanceof Date) return value.toISOString();\n")
+	b.WriteString("  if (typeof value === 'object') return JSON.stringify(value);\n")
+	b.WriteString("  return String(value);\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Serialize query params, writing arrays as `k=a&k=b` (repeat), `k=a,b` (comma) or `k[]=a&k[]=b` (brackets).\n")
+	b.WriteString(" * 序列化查询参数：数组按 `k=a&k=b`（repeat）、`k=a,b`（comma）或 `k[]=a&k[]=b`（brackets）写出。\n")
+	b.WriteString(" */\n")
+	b.WriteString("const serializeQueryParams = (params: Record<string, unknown>, arrayFormat: QueryArrayFormat): string => {\n")
+	b.WriteString("  const search = new URLSearchParams();\n")
+	b.WriteString("  for (const [k, v] of Object.entries(params ?? {})) {\n")
+	b.WriteString("    if (v === undefined || v === null) continue;\n")
+	b.WriteString("    if (!Array.isArray(v)) {\n")
+	b.WriteString("      search.append(k, toQueryString(v));\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    const items = v.filter((item) => item !== undefined && item !== null).map(toQueryString);\n")
+	b.WriteString("    if (arrayFormat === 'comma') {\n")
+	b.WriteString("      if (items.length > 0) search.append(k, items.join(','));\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    const key = arrayFormat === 'brackets' ? `${k}[]` : k;\n")
+	b.WriteString("    for (const item of items) search.append(key, item);\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return search.toString();\n")
+	b.WriteString("};\n\n")
 }
 
-func validateEndpointMeta(meta EndpointMeta) error {
-	if strings.TrimSpace(string(meta.Method)) == "" {
-		return fmt.Errorf("method is required")
+func writeHTTPPathParamsHelper(b *strings.Builder) {
+	b.WriteString("const assertRequiredPathParams = (endpointName: string, path: unknown, names: readonly string[]): void => {\n")
+	b.WriteString("  const record: Record<string, unknown> = isPlainObject(path) ? path : {};\n")
+	b.WriteString("  for (const name of names) {\n")
+	b.WriteString("    const value = record[name];\n")
+	b.WriteString("    if (value === undefined || value === null || String(value) === '') {\n")
+	b.WriteString("      throw new Error(`Missing required path param \"${name}\" for ${endpointName}`);\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+}
+
+func writeHTTPCookieHelpers(b *strings.Builder) {
+	b.WriteString("/**\n")
+	b.WriteString(" * Build a Cookie header from cookie params, keeping pairs of an existing Cookie header value.\n")
+	b.WriteString(" * Cookie params win over existing pairs with the same name; null/undefined params are skipped.\n")
+	b.WriteString(" * 由 cookie 参数构建 Cookie 头，并保留已有 Cookie 头中的键值对；同名时以 cookie 参数为准，null/undefined 参数会被跳过。\n")
+	b.WriteString(" */\n")
+	b.WriteString("const buildCookieHeader = (cookie: Record<string, unknown>, existing?: unknown): string => {\n")
+	b.WriteString("  const pairs = Object.entries(cookie)\n")
+	b.WriteString("    .filter(([, v]) => v !== undefined && v !== null)\n")
+	b.WriteString("    .map(([k, v]) => [encodeURIComponent(k), encodeURIComponent(String(v))] as const);\n")
+	b.WriteString("  const names = new Set(pairs.map(([k]) => k));\n")
+	b.WriteString("  const kept = typeof existing === 'string'\n")
+	b.WriteString("    ? existing.split(';').map((part) => part.trim()).filter((part) => part !== '' && !names.has(part.split('=')[0].trim()))\n")
+	b.WriteString("    : [];\n")
+	b.WriteString("  return [...kept, ...pairs.map(([k, v]) => `${k}=${v}`)].join('; ');\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Replace any Cookie header (case-insensitive) with one merged with the cookie params.\n")
+	b.WriteString(" * 将任意大小写的 Cookie 头替换为与 cookie 参数合并后的值。\n")
+	b.WriteString(" */\n")
+	b.WriteString("const withCookieHeader = (headers: Record<string, unknown>, cookie: Record<string, unknown>): Record<string, any> => {\n")
+	b.WriteString("  const out: Record<string, any> = {};\n")
+	b.WriteString("  let existing: unknown;\n")
+	b.WriteString("  for (const [k, v] of Object.entries(headers)) {\n")
+	b.WriteString("    if (k.toLowerCase() === 'cookie') existing = v;\n")
+	b.WriteString("    else out[k] = v;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  const value = buildCookieHeader(cookie, existing);\n")
+	b.WriteString("  if (value !== '') out.Cookie = value;\n")
+	b.WriteString("  return out;\n")
+	b.WriteString("};\n\n")
+}
+
+// writeHTTPClassHeader renders an endpoint's doc comment, response union type and class members up
+// to buildURL. It returns the class name, the request argument list and whether the path has params.
+func writeHTTPClassHeader(b *strings.Builder, m axiosFuncMeta, fullBasePath string, fullGroupPath string) (string, []string, bool) {
+	className := axiosClassName(m.FuncName, m.Method)
+	fullPathPrefix := resolveAPIPath(fullBasePath, fullGroupPath)
+	fullPath := joinURLPath(fullPathPrefix, m.Path)
+	hasPathPlaceholders := len(extractPathParams(m.Path)) > 0
+	pathParamNames := extractPathParams(m.Path)
+	mappedPathParamNames := make([]string, 0, len(pathParamNames))
+	for _, raw := range pathParamNames {
+		key := strings.ToLower(raw)
+		if mapped, ok := m.PathParamMap[key]; ok && strings.TrimSpace(mapped) != "" {
+			mappedPathParamNames = append(mappedPathParamNames, mapped)
+			continue
+		}
+		mappedPathParamNames = append(mappedPathParamNames, raw)
 	}
-	if !meta.Method.IsValid() {
-		return fmt.Errorf("invalid http method")
+	if m.APIDescription != "" || m.RequestDesc != "" || m.ResponseDesc != "" || len(m.SetCookies) > 0 || m.Deprecated != "" {
+		b.WriteString("/**\n")
+		if m.APIDescription != "" {
+			b.WriteString(" * ")
+			b.WriteString(escapeTSComment(m.APIDescription))
+			b.WriteString("\n")
+		}
+		if m.RequestDesc != "" {
+			b.WriteString(" * @request ")
+			b.WriteString(escapeTSComment(m.RequestDesc))
+			b.WriteString("\n")
+		}
+		if m.ResponseDesc != "" {
+			b.WriteString(" * @response")
+			if m.ResponseStatus > 0 {
+				b.WriteString(" ")
+				b.WriteString(fmt.Sprintf("%d", m.ResponseStatus))
+			}
+			b.WriteString(" ")
+			b.WriteString(escapeTSComment(m.ResponseDesc))
+			b.WriteString("\n")
+		}
+		for _, cookie := range m.SetCookies {
+			b.WriteString(" * @setCookie ")
+			b.WriteString(escapeTSComment(cookie))
+			b.WriteString("\n")
+		}
+		if m.Deprecated != "" {
+			b.WriteString(" * @deprecated ")
+			b.WriteString(escapeTSComment(m.Deprecated))
+			b.WriteString("\n")
+		}
+		b.WriteString(" */\n")
 	}
-	if strings.TrimSpace(meta.Path) == "" {
-		return fmt.Errorf("path is required")
+	if len(m.ResponseVariants) > 0 {
+		b.WriteString("export type ")
+		b.WriteString(m.ResponseType)
+		b.WriteString(" =\n")
+		for i, v := range m.ResponseVariants {
+			b.WriteString("  | { status: ")
+			b.WriteString(fmt.Sprintf("%d", v.StatusCode))
+			b.WriteString("; data: ")
+			b.WriteString(v.Type)
+			b.WriteString(" }")
+			if i == len(m.ResponseVariants)-1 {
+				b.WriteString(";")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
 	}
-	pathParams := extractPathParams(meta.Path)
-	if len(pathParams) > 0 && isNoType(meta.PathParamsType) {
-		return fmt.Errorf("path params required but PathParams type is NoParams")
+	b.WriteString("export class ")
+	b.WriteString(className)
+	b.WriteString(" {\n")
+	b.WriteString("  static readonly NAME = '")
+	b.WriteString(strings.ReplaceAll(m.FuncName, "'", "\\'"))
+	b.WriteString("' as const;\n")
+	b.WriteString("  static readonly SUMMARY = '")
+	b.WriteString(strings.ReplaceAll(escapeTSComment(m.APIDescription), "'", "\\'"))
+	b.WriteString("' as const;\n")
+	b.WriteString("  static readonly METHOD = '")
+	b.WriteString(m.Method)
+	b.WriteString("' as const;\n")
+	b.WriteString("  static readonly PATHS = {\n")
+	b.WriteString("    base: '")
+	b.WriteString(strings.ReplaceAll(fullBasePath, "'", "\\'"))
+	b.WriteString("',\n")
+	b.WriteString("    group: '")
+	b.WriteString(strings.ReplaceAll(fullGroupPath, "'", "\\'"))
+	b.WriteString("',\n")
+	b.WriteString("    api: '")
+	b.WriteString(strings.ReplaceAll(m.Path, "'", "\\'"))
+	b.WriteString("',\n")
+	b.WriteString("  } as const;\n")
+	b.WriteString("  static readonly FULL_PATH = '")
+	b.WriteString(strings.ReplaceAll(fullPath, "'", "\\'"))
+	b.WriteString("' as const;\n\n")
+	if m.CSVRowType != "" {
+		writeAxiosCSVClassMembers(b, m, className)
+	}
+	args := make([]string, 0, 3)
+	if m.HasParams {
+		args = append(args, "params: "+m.ParamsType)
+	}
+	if m.HasReqBody {
+		args = append(args, "requestBody: "+m.RequestType)
+	}
+	b.WriteString("  static pathParamsShape(): readonly string[] {\n")
+	if len(mappedPathParamNames) == 0 {
+		b.WriteString("    return [] as const;\n")
+	} else {
+		b.WriteString("    return [")
+		for i, name := range mappedPathParamNames {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("'")
+			b.WriteString(strings.ReplaceAll(name, "'", "\\'"))
+			b.WriteString("'")
+		}
+		b.WriteString("] as const;\n")
+	}
+	b.WriteString("  }\n\n")
+	b.WriteString("  static buildURL")
+	if hasPathPlaceholders {
+		b.WriteString("(params: ")
+		b.WriteString(m.ParamsType)
+		b.WriteString("): string {\n")
+		if TSStrictPathParams {
+			b.WriteString("    assertRequiredPathParams(")
+			b.WriteString(className)
+			b.WriteString(".NAME, params.path, ")
+			b.WriteString(className)
+			b.WriteString(".pathParamsShape());\n")
+		}
+		b.WriteString("    return ")
+		b.WriteString(buildTSURLExprWithBaseAndMap(fullPathPrefix, m.Path, m.PathParamMap))
+		b.WriteString(";\n")
+	} else {
+		b.WriteString("(): string {\n")
+		b.WriteString("    return ")
+		b.WriteString(className)
+		b.WriteString(".FULL_PATH;\n")
+	}
+	b.WriteString("  }\n\n")
+	return className, args, hasPathPlaceholders
+}
+
+// writeHTTPNormalizedParams renders the normalizeParamKeys call for an endpoint's query/header/cookie params.
+func writeHTTPNormalizedParams(b *strings.Builder, m axiosFuncMeta) {
+	if !m.HasQuery && !m.HasHeader && !m.HasCookie {
+		return
+	}
+	b.WriteString("    const normalizedParams = normalizeParamKeys(params, {\n")
+	if m.HasQuery {
+		b.WriteString("      query: ")
+		b.WriteString(renderParamMapObject(m.QueryParamMap))
+		b.WriteString(",\n")
+	}
+	if m.HasHeader {
+		b.WriteString("      header: ")
+		b.WriteString(renderParamMapObject(m.HeaderParamMap))
+		b.WriteString(",\n")
+	}
+	if m.HasCookie {
+		b.WriteString("      cookie: ")
+		b.WriteString(renderParamMapObject(m.CookieParamMap))
+		b.WriteString(",\n")
+	}
+	b.WriteString("    });\n")
+}
+
+// httpRequestContentType is the Content-Type a request kind sets explicitly; JSON and multipart
+// bodies leave it to the HTTP client.
+func httpRequestContentType(kind TSKind) string {
+	switch kind {
+	case TSKindFormURLEncoded:
+		return "application/x-www-form-urlencoded"
+	case TSKindText:
+		return "text/plain; charset=utf-8"
+	case TSKindBytes:
+		return "application/octet-stream"
+	}
+	return ""
+}
+
+// writeHTTPRequestHeaders renders the `headers` const of a request (header params, contentType and
+// merged cookie params) and reports whether it was written.
+func writeHTTPRequestHeaders(b *strings.Builder, m axiosFuncMeta, contentType string) bool {
+	needsHeaders := m.HasHeader || m.HasCookie || contentType != ""
+	if contentType != "" {
+		b.WriteString("    const requestHeaders = { 'Content-Type': '")
+		b.WriteString(contentType)
+		b.WriteString("' };\n")
+	}
+	if needsHeaders {
+		if m.HasCookie {
+			b.WriteString("    const headers = withCookieHeader({\n")
+		} else {
+			b.WriteString("    const headers = {\n")
+		}
+		if m.HasHeader {
+			b.WriteString("      ...(normalizedParams?.header ?? {}),\n")
+		}
+		if contentType != "" {
+			b.WriteString("      ...requestHeaders,\n")
+		}
+		if m.HasCookie {
+			b.WriteString("    }, (normalizedParams?.cookie ?? {}) as Record<string, unknown>);\n")
+		} else {
+			b.WriteString("    };\n")
+		}
+	}
+	return needsHeaders
+}
+
+// writeHTTPResponseVariantSwitch maps `response.status` / `response.data` to the endpoint's response union.
+func writeHTTPResponseVariantSwitch(b *strings.Builder, m axiosFuncMeta, className string) {
+	b.WriteString("    let result: ")
+	b.WriteString(m.ResponseType)
+	b.WriteString(";\n")
+	b.WriteString("    switch (response.status) {\n")
+	for _, v := range m.ResponseVariants {
+		status := fmt.Sprintf("%d", v.StatusCode)
+		b.WriteString("      case ")
+		b.WriteString(status)
+		b.WriteString(":\n")
+		b.WriteString("        result = { status: ")
+		b.WriteString(status)
+		if v.Type == "void" {
+			b.WriteString(", data: undefined };\n")
+		} else {
+			data := axiosReviveExpr("response.data", v.DatePaths, nil)
+			b.WriteString(", data: (options?.deserializeResponse ? options.deserializeResponse(")
+			b.WriteString(data)
+			b.WriteString(") : ")
+			b.WriteString(data)
+			b.WriteString(") as ")
+			b.WriteString(v.Type)
+			b.WriteString(" };\n")
+		}
+		b.WriteString("        break;\n")
+	}
+	b.WriteString("      default:\n")
+	b.WriteString("        throw new Error(`Unexpected response status ${response.status} for ${")
+	b.WriteString(className)
+	b.WriteString(".NAME}`);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    return result;\n")
+}
+
+// writeHTTPRequestWrapper renders the requestX() function delegating to X.request; optionsType is
+// the client's convert options interface.
+func writeHTTPRequestWrapper(b *strings.Builder, m axiosFuncMeta, className string, args []string, optionsType string) {
+	if m.Deprecated != "" {
+		b.WriteString("/** @deprecated ")
+		b.WriteString(escapeTSComment(m.Deprecated))
+		b.WriteString(" */\n")
+	}
+	b.WriteString("export async function request")
+	b.WriteString(className)
+	b.WriteString("(")
+	b.WriteString(strings.Join(args, ", "))
+	if len(args) > 0 {
+		b.WriteString(", ")
+	}
+	b.WriteString("options?: ")
+	b.WriteString(optionsType)
+	b.WriteString("<")
+	if m.HasReqBody {
+		b.WriteString(m.RequestType)
+	} else {
+		b.WriteString("never")
+	}
+	b.WriteString(", ")
+	b.WriteString(axiosOptionsResponseType(m))
+	b.WriteString(">")
+	b.WriteString("): Promise<")
+	b.WriteString(m.ResponseType)
+	b.WriteString("> {\n")
+	wrapperCallArgs := make([]string, 0, 3)
+	if m.HasParams {
+		wrapperCallArgs = append(wrapperCallArgs, "params")
+	}
+	if m.HasReqBody {
+		wrapperCallArgs = append(wrapperCallArgs, "requestBody")
+	}
+	wrapperCallArgs = append(wrapperCallArgs, "options")
+	b.WriteString("  return ")
+	b.WriteString(className)
+	b.WriteString(".request(")
+	b.WriteString(strings.Join(wrapperCallArgs, ", "))
+	b.WriteString(");\n")
+	b.WriteString("}\n\n")
+	writeHTTPRouteFunc(b, m, className)
+}
+
+// writeHTTPRouteFunc renders the routeX() function returning the concrete URL of an endpoint via
+// X.buildURL, so links built outside of requests encode path params exactly like the request does.
+func writeHTTPRouteFunc(b *strings.Builder, m axiosFuncMeta, className string) {
+	b.WriteString("export function route")
+	b.WriteString(className)
+	if len(extractPathParams(m.Path)) > 0 {
+		b.WriteString("(params: ")
+		b.WriteString(m.ParamsType)
+		b.WriteString("): string {\n")
+		b.WriteString("  return ")
+		b.WriteString(className)
+		b.WriteString(".buildURL(params);\n")
+	} else {
+		b.WriteString("(): string {\n")
+		b.WriteString("  return ")
+		b.WriteString(className)
+		b.WriteString(".buildURL();\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+// writeStreamBodyHelpers renders StreamRequestBody and its conversion to a ReadableStream.
+func writeStreamBodyHelpers(b *strings.Builder) {
+	b.WriteString("export type StreamRequestBody = ReadableStream<Uint8Array> | AsyncIterable<Uint8Array>;\n\n")
+	b.WriteString("const toReadableStream = (body: StreamRequestBody): ReadableStream<Uint8Array> => {\n")
+	b.WriteString("  if (body instanceof ReadableStream) return body;\n")
+	b.WriteString("  const iterator = body[Symbol.asyncIterator]();\n")
+	b.WriteString("  return new ReadableStream<Uint8Array>({\n")
+	b.WriteString("    async pull(controller) {\n")
+	b.WriteString("      const { value, done } = await iterator.next();\n")
+	b.WriteString("      if (done) controller.close();\n")
+	b.WriteString("      else controller.enqueue(value);\n")
+	b.WriteString("    },\n")
+	b.WriteString("    async cancel(reason) {\n")
+	b.WriteString("      await iterator.return?.(reason);\n")
+	b.WriteString("    },\n")
+	b.WriteString("  });\n")
+	b.WriteString("};\n\n")
+}
+
+func writeAxiosStreamHelpers(b *strings.Builder) {
+	writeStreamBodyHelpers(b)
+	b.WriteString("/**\n")
+	b.WriteString(" * Send a streaming request body with fetch (axios cannot stream request bodies).\n")
+	b.WriteString(" * The axios client's baseURL and the setDefaultHeaders headers are applied, but its interceptors are not run.\n")
+	b.WriteString(" * Non-2xx responses are thrown as AxiosError, like other generated requests.\n")
+	b.WriteString(" * 使用 fetch 发送流式请求体（axios 不支持流式请求体）；会使用 axios 实例的 baseURL 并发送 setDefaultHeaders 设置的请求头，但不会执行拦截器；非 2xx 响应与其他请求一样抛出 AxiosError。\n")
+	b.WriteString(" */\n")
+	b.WriteString("const sendStreamRequest = async (\n")
+	b.WriteString("  method: string,\n")
+	b.WriteString("  url: string,\n")
+	b.WriteString("  body: StreamRequestBody,\n")
+	b.WriteString("  init: { query?: Record<string, unknown>; headers?: Record<string, string>; overrideHeaders?: Record<string, string>; signal?: AbortSignal }\n")
+	b.WriteString("): Promise<Response> => {\n")
+	b.WriteString("  const search = new URLSearchParams();\n")
+	b.WriteString("  for (const [k, v] of Object.entries(init.query ?? {})) {\n")
+	b.WriteString("    if (v === undefined || v === null) continue;\n")
+	b.WriteString("    if (Array.isArray(v)) {\n")
+	b.WriteString("      for (const item of v) search.append(k, String(item));\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    search.append(k, String(v));\n")
+	b.WriteString("  }\n")
+	b.WriteString("  const qs = search.toString();\n")
+	b.WriteString("  const baseURL = axiosClient.defaults.baseURL;\n")
+	b.WriteString("  const target = baseURL && !/^https?:\\/\\//i.test(url) ? `${baseURL.replace(/\\/+$/, '')}${url}` : url;\n")
+	b.WriteString("  const response = await fetch(qs ? `${target}${target.includes('?') ? '&' : '?'}${qs}` : target, {\n")
+	b.WriteString("    method,\n")
+	b.WriteString("    body: toReadableStream(body),\n")
+	b.WriteString("    headers: { 'Content-Type': 'application/octet-stream', ...defaultHeaders, ...(init.headers ?? {}), ...(init.overrideHeaders ?? {}) },\n")
+	b.WriteString("    signal: init.signal,\n")
+	b.WriteString("    duplex: 'half',\n")
+	b.WriteString("  } as RequestInit & { duplex: 'half' });\n")
+	b.WriteString("  if (!response.ok) {\n")
+	b.WriteString("    const text = await response.text().catch(() => '');\n")
+	b.WriteString("    let data: unknown = text;\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      data = text ? JSON.parse(text) : undefined;\n")
+	b.WriteString("    } catch {\n")
+	b.WriteString("      // Keep the raw text body.\n")
+	b.WriteString("    }\n")
+	b.WriteString("    const headers: Record<string, string> = {};\n")
+	b.WriteString("    response.headers.forEach((value, key) => {\n")
+	b.WriteString("      headers[key] = value;\n")
+	b.WriteString("    });\n")
+	b.WriteString("    throw new AxiosError(\n")
+	b.WriteString("      `Request failed with status code ${response.status}`,\n")
+	b.WriteString("      response.status >= 500 ? AxiosError.ERR_BAD_RESPONSE : AxiosError.ERR_BAD_REQUEST,\n")
+	b.WriteString("      undefined,\n")
+	b.WriteString("      undefined,\n")
+	b.WriteString("      { data, status: response.status, statusText: response.statusText, headers, config: {} } as unknown as AxiosResponse\n")
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return response;\n")
+	b.WriteString("};\n\n")
+}
+
+// csvRowTypeOf reports the row struct of a `[]Row` response body served as CSV.
+func csvRowTypeOf(t reflect.Type) (reflect.Type, bool) {
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return nil, false
+	}
+	elem := t.Elem()
+	for elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct || elem.Name() == "" || (elem.PkgPath() == "time" && elem.Name() == "Time") {
+		return nil, false
+	}
+	return elem, true
+}
+
+func writeAxiosCSVHelpers(b *strings.Builder) {
+	b.WriteString("export interface CSVColumnSpec {\n")
+	b.WriteString("  key: string;\n")
+	b.WriteString("  type: 'string' | 'number' | 'bigint' | 'boolean' | 'json';\n")
+	b.WriteString("  optional?: boolean;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("const splitCSVRecords = (text: string): string[][] => {\n")
+	b.WriteString("  const records: string[][] = [];\n")
+	b.WriteString("  let record: string[] = [];\n")
+	b.WriteString("  let field = '';\n")
+	b.WriteString("  let quoted = false;\n")
+	b.WriteString("  for (let i = 0; i < text.length; i++) {\n")
+	b.WriteString("    const ch = text[i];\n")
+	b.WriteString("    if (quoted) {\n")
+	b.WriteString("      if (ch !== '\"') field += ch;\n")
+	b.WriteString("      else if (text[i + 1] === '\"') {\n")
+	b.WriteString("        field += '\"';\n")
+	b.WriteString("        i++;\n")
+	b.WriteString("      } else quoted = false;\n")
+	b.WriteString("      continue;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    if (ch === '\"') quoted = true;\n")
+	b.WriteString("    else if (ch === ',') {\n")
+	b.WriteString("      record.push(field);\n")
+	b.WriteString("      field = '';\n")
+	b.WriteString("    } else if (ch === '\\n' || ch === '\\r') {\n")
+	b.WriteString("      if (ch === '\\r' && text[i + 1] === '\\n') i++;\n")
+	b.WriteString("      record.push(field);\n")
+	b.WriteString("      records.push(record);\n")
+	b.WriteString("      record = [];\n")
+	b.WriteString("      field = '';\n")
+	b.WriteString("    } else field += ch;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  if (field !== '' || record.length > 0) {\n")
+	b.WriteString("    record.push(field);\n")
+	b.WriteString("    records.push(record);\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return records;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Parse CSV written by the server's WriteCSV into typed rows. Cells are matched by header name\n")
+	b.WriteString(" * and converted by column type; unknown columns are ignored.\n")
+	b.WriteString(" * 将服务端 WriteCSV 写出的 CSV 解析为类型化行：按表头名匹配单元格并按列类型转换，未知列会被忽略。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export async function parseCSVAs<T>(csv: Blob | string, columns: readonly CSVColumnSpec[]): Promise<T[]> {\n")
+	b.WriteString("  const text = typeof csv === 'string' ? csv : await csv.text();\n")
+	b.WriteString("  const [header, ...records] = splitCSVRecords(text);\n")
+	b.WriteString("  if (!header) return [];\n")
+	b.WriteString("  const specs = new Map(columns.map((c) => [c.key, c]));\n")
+	b.WriteString("  return records.map((record) => {\n")
+	b.WriteString("    const row: Record<string, unknown> = {};\n")
+	b.WriteString("    header.forEach((key, i) => {\n")
+	b.WriteString("      const spec = specs.get(key);\n")
+	b.WriteString("      const cell = record[i] ?? '';\n")
+	b.WriteString("      if (!spec || (cell === '' && spec.optional)) return;\n")
+	b.WriteString("      switch (spec.type) {\n")
+	b.WriteString("        case 'bigint':\n")
+	b.WriteString("          row[key] = BigInt(cell);\n")
+	b.WriteString("          break;\n")
+	b.WriteString("        case 'number':\n")
+	b.WriteString("          row[key] = Number(cell);\n")
+	b.WriteString("          break;\n")
+	b.WriteString("        case 'boolean':\n")
+	b.WriteString("          row[key] = cell === 'true';\n")
+	b.WriteString("          break;\n")
+	b.WriteString("        case 'json':\n")
+	b.WriteString("          row[key] = cell === '' ? null : JSON.parse(cell);\n")
+	b.WriteString("          break;\n")
+	b.WriteString("        default:\n")
+	b.WriteString("          row[key] = cell;\n")
+	b.WriteString("      }\n")
+	b.WriteString("    });\n")
+	b.WriteString("    return row as T;\n")
+	b.WriteString("  });\n")
+	b.WriteString("}\n\n")
+}
+
+func writeAxiosCSVClassMembers(b *strings.Builder, m axiosFuncMeta, className string) {
+	b.WriteString("  static readonly CSV_COLUMNS: readonly CSVColumnSpec[] = [\n")
+	for _, c := range m.CSVColumns {
+		b.WriteString("    { key: '")
+		b.WriteString(strings.ReplaceAll(c.Name, "'", "\\'"))
+		b.WriteString("', type: '")
+		b.WriteString(c.Type)
+		b.WriteString("'")
+		if c.Optional {
+			b.WriteString(", optional: true")
+		}
+		b.WriteString(" },\n")
+	}
+	b.WriteString("  ];\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Parse the CSV response into typed ")
+	b.WriteString(m.CSVRowType)
+	b.WriteString(" rows.\n")
+	b.WriteString("   * 将 CSV 响应解析为类型化的 ")
+	b.WriteString(m.CSVRowType)
+	b.WriteString(" 行。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  static parseCSV(csv: Blob | string): Promise<")
+	b.WriteString(m.CSVRowType)
+	b.WriteString("[]> {\n")
+	b.WriteString("    return parseCSVAs<")
+	b.WriteString(m.CSVRowType)
+	b.WriteString(">(csv, ")
+	b.WriteString(className)
+	b.WriteString(".CSV_COLUMNS);\n")
+	b.WriteString("  }\n\n")
+}
+
+// batchResponseTSType renders the per-item result array of a []BatchItemResult[T, E] body.
+func batchResponseTSType(t reflect.Type, registry *tsInterfaceRegistry) (string, error) {
+	dataType, errorType, ok := batchItemTypesOf(t)
+	if !ok {
+		return "", fmt.Errorf("batch response body must be []BatchItemResult[T, E], got %v", t)
+	}
+	data, _, err := tsTypeFromType(dataType, registry)
+	if err != nil {
+		return "", err
+	}
+	failure, _, err := tsTypeFromType(errorType, registry)
+	if err != nil {
+		return "", err
+	}
+	return "Array<{ index: number; status: number; data?: " + data + "; error?: " + failure + " }>", nil
+}
+
+func writeAxiosBatchHelpers(b *strings.Builder) {
+	b.WriteString("/**\n")
+	b.WriteString(" * Split the per-item results of a batch endpoint into successes (2xx with data) and failures.\n")
+	b.WriteString(" * 将批量接口的逐条结果拆分为成功项（2xx 且带 data）与失败项。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export function splitBatchResults<T, E>(\n")
+	b.WriteString("  results: ReadonlyArray<{ index: number; status: number; data?: T; error?: E }>,\n")
+	b.WriteString("): {\n")
+	b.WriteString("  succeeded: Array<{ index: number; status: number; data: T }>;\n")
+	b.WriteString("  failed: Array<{ index: number; status: number; error?: E }>;\n")
+	b.WriteString("} {\n")
+	b.WriteString("  const succeeded: Array<{ index: number; status: number; data: T }> = [];\n")
+	b.WriteString("  const failed: Array<{ index: number; status: number; error?: E }> = [];\n")
+	b.WriteString("  for (const item of results) {\n")
+	b.WriteString("    if (item.status >= 200 && item.status < 300 && item.error === undefined && item.data !== undefined) {\n")
+	b.WriteString("      succeeded.push({ index: item.index, status: item.status, data: item.data });\n")
+	b.WriteString("    } else {\n")
+	b.WriteString("      failed.push({ index: item.index, status: item.status, error: item.error });\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return { succeeded, failed };\n")
+	b.WriteString("}\n\n")
+}
+
+// withoutEventStreams drops event stream endpoints, which have no request/response call to wrap.
+func withoutEventStreams(metas []axiosFuncMeta) []axiosFuncMeta {
+	out := make([]axiosFuncMeta, 0, len(metas))
+	for _, m := range metas {
+		if m.ResponseKind != TSKindEventStream {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+const axiosEventSourceURLResolver = "const resolveEventSourceURL = (url: string): string => {\n" +
+	"  const resolved = resolveHttpBaseURL(url);\n" +
+	"  const baseURL = axiosClient.defaults.baseURL;\n" +
+	"  return baseURL && !/^https?:\\/\\//i.test(resolved) ? `${baseURL.replace(/\\/+$/, '')}${resolved}` : resolved;\n" +
+	"};\n\n"
+
+// writeEventSourceHelpers renders TypedEventSource; resolveURL is the declaration of
+// resolveEventSourceURL, which differs between the axios and fetch clients.
+func writeEventSourceHelpers(b *strings.Builder, resolveURL string) {
+	b.WriteString("export interface EventSourceConnectOptions {\n")
+	b.WriteString("  /** Send cookies on cross-origin streams. 跨域时携带 cookie。 */\n")
+	b.WriteString("  withCredentials?: boolean;\n")
+	b.WriteString("  /** Validate each event with the generated validator (default true). 使用生成的校验函数校验每个事件（默认 true）。 */\n")
+	b.WriteString("  validate?: boolean;\n")
+	b.WriteString("}\n\n")
+	b.WriteString(resolveURL)
+	b.WriteString("/**\n")
+	b.WriteString(" * Typed EventSource wrapper: each JSON data frame is decoded before reaching onEvent handlers.\n")
+	b.WriteString(" * Connection errors and frames that fail to decode are reported to onError handlers.\n")
+	b.WriteString(" * 类型化的 EventSource 封装：每个 JSON data 帧解码后再交给 onEvent 处理器；连接错误与解码失败的帧会交给 onError 处理器。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export class TypedEventSource<T> {\n")
+	b.WriteString("  readonly source: EventSource;\n")
+	b.WriteString("  private readonly errorHandlers = new Set<(error: unknown) => void>();\n\n")
+	b.WriteString("  constructor(url: string, private readonly decode: (data: unknown) => T, init?: EventSourceInit) {\n")
+	b.WriteString("    this.source = new EventSource(url, init);\n")
+	b.WriteString("    this.source.addEventListener('error', (event) => this.emitError(event));\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  onEvent(handler: (event: T) => void): () => void {\n")
+	b.WriteString("    const listener = (event: MessageEvent<string>) => {\n")
+	b.WriteString("      let value: T;\n")
+	b.WriteString("      try {\n")
+	b.WriteString("        value = this.decode(JSON.parse(event.data));\n")
+	b.WriteString("      } catch (error) {\n")
+	b.WriteString("        this.emitError(error);\n")
+	b.WriteString("        return;\n")
+	b.WriteString("      }\n")
+	b.WriteString("      handler(value);\n")
+	b.WriteString("    };\n")
+	b.WriteString("    this.source.addEventListener('message', listener);\n")
+	b.WriteString("    return () => this.source.removeEventListener('message', listener);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  onError(handler: (error: unknown) => void): () => void {\n")
+	b.WriteString("    this.errorHandlers.add(handler);\n")
+	b.WriteString("    return () => {\n")
+	b.WriteString("      this.errorHandlers.delete(handler);\n")
+	b.WriteString("    };\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  close(): void {\n")
+	b.WriteString("    this.source.close();\n")
+	b.WriteString("    this.errorHandlers.clear();\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private emitError(error: unknown): void {\n")
+	b.WriteString("    for (const handler of this.errorHandlers) handler(error);\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+}
+
+// writeAxiosEventSourceConnect renders the event validator and connect() of an event stream class.
+// int64 values are revived before validation (validators expect bigint), dates after (validators expect strings).
+func writeAxiosEventSourceConnect(b *strings.Builder, m axiosFuncMeta, className string, hasPathPlaceholders bool) {
+	b.WriteString("  static validateEvent(value: unknown): value is ")
+	b.WriteString(m.ResponseType)
+	b.WriteString(" {\n")
+	b.WriteString("    return ")
+	b.WriteString(m.EventValidator)
+	b.WriteString(";\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  static connect(")
+	if hasPathPlaceholders || m.HasQuery {
+		b.WriteString("params: ")
+		b.WriteString(m.ParamsType)
+		b.WriteString(", ")
+	}
+	b.WriteString("options?: EventSourceConnectOptions): TypedEventSource<")
+	b.WriteString(m.ResponseType)
+	b.WriteString("> {\n")
+	if m.HasQuery {
+		b.WriteString("    let url = resolveEventSourceURL(")
+	} else {
+		b.WriteString("    const url = resolveEventSourceURL(")
+	}
+	b.WriteString(className)
+	if hasPathPlaceholders {
+		b.WriteString(".buildURL(params));\n")
+	} else {
+		b.WriteString(".buildURL());\n")
+	}
+	if m.HasQuery {
+		b.WriteString("    const normalizedParams = normalizeParamKeys(params, { query: ")
+		b.WriteString(renderParamMapObject(m.QueryParamMap))
+		b.WriteString(" });\n")
+		b.WriteString("    const search = new URLSearchParams();\n")
+		b.WriteString("    for (const [k, v] of Object.entries(normalizedParams.query ?? {})) {\n")
+		b.WriteString("      if (v !== undefined && v !== null) search.append(k, String(v));\n")
+		b.WriteString("    }\n")
+		b.WriteString("    if (search.toString()) url += `${url.includes('?') ? '&' : '?'}${search.toString()}`;\n")
+	}
+	b.WriteString("    return new TypedEventSource<")
+	b.WriteString(m.ResponseType)
+	b.WriteString(">(\n")
+	b.WriteString("      url,\n")
+	b.WriteString("      (data) => {\n")
+	wire := "data"
+	if len(m.BigIntPaths) > 0 {
+		b.WriteString("        const wire = ")
+		b.WriteString(axiosReviveExpr("data", nil, m.BigIntPaths))
+		b.WriteString(";\n")
+		wire = "wire"
+	}
+	if m.EventValidator != "true" {
+		b.WriteString("        if (options?.validate !== false && !")
+		b.WriteString(className)
+		b.WriteString(".validateEvent(")
+		b.WriteString(wire)
+		b.WriteString(")) {\n")
+		b.WriteString("          throw new Error(`Invalid event for ${")
+		b.WriteString(className)
+		b.WriteString(".NAME}`);\n")
+		b.WriteString("        }\n")
+	}
+	b.WriteString("        return ")
+	b.WriteString(axiosReviveExpr(wire, m.DatePaths, nil))
+	b.WriteString(" as ")
+	b.WriteString(m.ResponseType)
+	b.WriteString(";\n")
+	b.WriteString("      },\n")
+	b.WriteString("      { withCredentials: options?.withCredentials },\n")
+	b.WriteString("    );\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+}
+
+// writeAxiosInt64Helpers renders parseInt64 / formatInt64, the single place that converts
+// between int64 wire strings and bigint. Emitted in the string and bigint int64 modes.
+func writeAxiosInt64Helpers(b *strings.Builder) {
+	b.WriteString("const int64Pattern = /^-?\\d+$/;\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Parse an int64 wire string into a bigint without losing precision.\n")
+	b.WriteString(" * 将 int64 的字符串形式无损解析为 bigint。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export function parseInt64(s: string): bigint {\n")
+	b.WriteString("  if (!int64Pattern.test(s)) throw new Error(`Invalid int64 value \"${s}\"`);\n")
+	b.WriteString("  return BigInt(s);\n")
+	b.WriteString("}\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Format a bigint or safe integer as an int64 wire string.\n")
+	b.WriteString(" * 将 bigint 或安全整数格式化为 int64 的字符串形式。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export function formatInt64(n: bigint | number): string {\n")
+	b.WriteString("  if (typeof n === 'number' && !Number.isSafeInteger(n)) throw new Error(`Unsafe int64 number ${n}`);\n")
+	b.WriteString("  return n.toString();\n")
+	b.WriteString("}\n\n")
+}
+
+func tsPathsLiteral(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, p := range paths {
+		quoted[i] = "'" + strings.ReplaceAll(p, "'", "\\'") + "'"
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// axiosReviveExpr wraps a response data expression with reviveDates / reviveBigInts for the given paths.
+func axiosReviveExpr(data string, datePaths []string, bigIntPaths []string) string {
+	if len(datePaths) > 0 {
+		data = "reviveDates(" + data + ", " + tsPathsLiteral(datePaths) + ")"
+	}
+	if len(bigIntPaths) > 0 {
+		data = "reviveBigInts(" + data + ", " + tsPathsLiteral(bigIntPaths) + ")"
+	}
+	return data
+}
+
+// writeAxiosReviveHelpers renders reviveDates / reviveBigInts when any endpoint uses them.
+// Both only touch the known JSON paths of a response, so ISO-looking or numeric strings in
+// other fields are left untouched.
+func writeAxiosReviveHelpers(b *strings.Builder, metas []axiosFuncMeta) {
+	needsDates, needsBigInts := false, false
+	for _, m := range metas {
+		needsDates = needsDates || len(m.DatePaths) > 0
+		needsBigInts = needsBigInts || len(m.BigIntPaths) > 0
+		for _, v := range m.ResponseVariants {
+			needsDates = needsDates || len(v.DatePaths) > 0
+		}
+	}
+	if !needsDates && !needsBigInts {
+		return
+	}
+	b.WriteString("const reviveAt = (value: unknown, segments: readonly string[], leaf: (value: unknown) => unknown): unknown => {\n")
+	b.WriteString("  if (segments.length === 0) return leaf(value);\n")
+	b.WriteString("  const [head, ...rest] = segments;\n")
+	b.WriteString("  if (head === '*') {\n")
+	b.WriteString("    if (Array.isArray(value)) return value.map((v) => reviveAt(v, rest, leaf));\n")
+	b.WriteString("    if (isPlainObject(value)) {\n")
+	b.WriteString("      for (const k of Object.keys(value)) value[k] = reviveAt(value[k], rest, leaf);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    return value;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  if (isPlainObject(value) && head in value) value[head] = reviveAt(value[head], rest, leaf);\n")
+	b.WriteString("  return value;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const revivePaths = (value: unknown, paths: readonly string[], leaf: (value: unknown) => unknown): unknown =>\n")
+	b.WriteString("  paths.reduce((out, path) => reviveAt(out, path === '' ? [] : path.split('.'), leaf), value);\n\n")
+	if needsDates {
+		b.WriteString("const isoDateLike = /^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}(?:\\.\\d{1,9})?(?:Z|[+\\-]\\d{2}:\\d{2})$/;\n\n")
+		b.WriteString("const toDate = (value: unknown): unknown => {\n")
+		b.WriteString("  if (typeof value === 'string' && isoDateLike.test(value)) {\n")
+		b.WriteString("    const date = new Date(value);\n")
+		b.WriteString("    if (!Number.isNaN(date.getTime())) return date;\n")
+		b.WriteString("  }\n")
+		b.WriteString("  return value;\n")
+		b.WriteString("};\n\n")
+		b.WriteString("const reviveDates = (value: unknown, paths: readonly string[]): unknown => revivePaths(value, paths, toDate);\n\n")
+	}
+	if needsBigInts {
+		b.WriteString("const toBigInt = (value: unknown): unknown => {\n")
+		b.WriteString("  if (typeof value === 'number' && Number.isInteger(value)) return BigInt(value);\n")
+		b.WriteString("  if (typeof value === 'string' && int64Pattern.test(value)) return parseInt64(value);\n")
+		b.WriteString("  return value;\n")
+		b.WriteString("};\n\n")
+		b.WriteString("const reviveBigInts = (value: unknown, paths: readonly string[]): unknown => revivePaths(value, paths, toBigInt);\n\n")
+	}
+}
+
+// writeAxiosStreamRequest renders the request method for a streaming upload endpoint.
+// It uses fetch instead of axios, so there is no requestConfig.
+func writeAxiosStreamRequest(b *strings.Builder, m axiosFuncMeta, className string, args []string, hasPathPlaceholders bool, abortAll bool) {
+	b.WriteString("  static async request(")
+	b.WriteString(strings.Join(args, ", "))
+	b.WriteString(", options?: AxiosConvertOptions<never, ")
+	b.WriteString(m.ResponseType)
+	b.WriteString(">): Promise<")
+	b.WriteString(m.ResponseType)
+	b.WriteString("> {\n")
+	b.WriteString("    const url = resolveHttpBaseURL(")
+	b.WriteString(className)
+	if hasPathPlaceholders {
+		b.WriteString(".buildURL(params));\n")
+	} else {
+		b.WriteString(".buildURL());\n")
+	}
+	writeHTTPNormalizedParams(b, m)
+	if abortAll {
+		b.WriteString("    const response = await trackRequest(options?.signal, (signal) => sendStreamRequest(")
+	} else {
+		b.WriteString("    const response = await sendStreamRequest(")
+	}
+	b.WriteString(className)
+	b.WriteString(".METHOD, url, requestBody, {\n")
+	if m.HasQuery {
+		b.WriteString("      query: normalizedParams.query,\n")
+	}
+	switch {
+	case m.HasCookie:
+		b.WriteString("      headers: withCookieHeader(")
+		if m.HasHeader {
+			b.WriteString("{ ...(normalizedParams?.header ?? {}) }")
+		} else {
+			b.WriteString("{}")
+		}
+		b.WriteString(", (normalizedParams?.cookie ?? {}) as Record<string, unknown>),\n")
+	case m.HasHeader:
+		b.WriteString("      headers: {\n")
+		b.WriteString("        ...(normalizedParams?.header ?? {}),\n")
+		b.WriteString("      },\n")
+	}
+	b.WriteString("      overrideHeaders: options?.headers,\n")
+	if abortAll {
+		b.WriteString("      signal,\n")
+		b.WriteString("    }));\n")
+	} else {
+		b.WriteString("      signal: options?.signal,\n")
+		b.WriteString("    });\n")
+	}
+	if m.ResponseType == "void" {
+		b.WriteString("    return;\n")
+	} else {
+		switch m.ResponseKind {
+		case TSKindBytes:
+			b.WriteString("    const responseData = new Uint8Array(await response.arrayBuffer());\n")
+		case TSKindStream:
+			b.WriteString("    const responseData = await response.blob();\n")
+		case TSKindText:
+			b.WriteString("    const responseData = await response.text();\n")
+		default:
+			b.WriteString("    const responseData = ")
+			b.WriteString(axiosReviveExpr("(await response.json()) as unknown", m.DatePaths, m.BigIntPaths))
+			b.WriteString(";\n")
+		}
+		b.WriteString("    if (options?.deserializeResponse) {\n")
+		b.WriteString("      return options.deserializeResponse(responseData);\n")
+		b.WriteString("    }\n")
+		b.WriteString("    return responseData as ")
+		b.WriteString(m.ResponseType)
+		b.WriteString(";\n")
+	}
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+	writeHTTPRequestWrapper(b, m, className, args, "AxiosConvertOptions")
+}
+
+// axiosUsesETagCache reports whether the request method of m honours AxiosConvertOptions.useETagCache:
+// plain axios GETs, not streams, event streams or status unions.
+func axiosUsesETagCache(m axiosFuncMeta) bool {
+	return m.Method == "GET" && !m.StreamBody && m.ResponseKind != TSKindEventStream && len(m.ResponseVariants) == 0
+}
+
+// writeAxiosETagCacheHelpers renders the module-level ETag cache keyed by full request URL, clearETagCache and
+// requestWithETagCache, which sends If-None-Match and turns a 304 into the cached 200 response.
+func writeAxiosETagCacheHelpers(b *strings.Builder) {
+	b.WriteString("const etagCache = new Map<string, { etag: string; data: unknown }>();\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Drop every cached ETag and body, e.g. on logout.\n")
+	b.WriteString(" * 清空所有缓存的 ETag 与响应体（如退出登录时）。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const clearETagCache = (): void => etagCache.clear();\n\n")
+	b.WriteString("type ETagCacheResponse = { status: number; data: unknown; headers: Record<string, any> };\n\n")
+	b.WriteString("const requestWithETagCache = async <R extends ETagCacheResponse>(\n")
+	b.WriteString("  config: AxiosRequestConfig,\n")
+	b.WriteString("  enabled: boolean | undefined,\n")
+	b.WriteString("  send: (config: AxiosRequestConfig) => Promise<R>,\n")
+	b.WriteString("): Promise<R> => {\n")
+	b.WriteString("  if (!enabled) return send(config);\n")
+	b.WriteString("  const key = axiosClient.getUri(config);\n")
+	b.WriteString("  const cached = etagCache.get(key);\n")
+	b.WriteString("  const response = await send({\n")
+	b.WriteString("    ...config,\n")
+	b.WriteString("    headers: { ...(config.headers ?? {}), ...(cached ? { 'If-None-Match': cached.etag } : {}) },\n")
+	b.WriteString("    validateStatus: (status) => (status >= 200 && status < 300) || (status === 304 && cached !== undefined),\n")
+	b.WriteString("  });\n")
+	b.WriteString("  if (response.status === 304 && cached) return { ...response, status: 200, data: cached.data } as R;\n")
+	b.WriteString("  const etag = response.headers['etag'];\n")
+	b.WriteString("  if (typeof etag === 'string' && etag !== '') etagCache.set(key, { etag, data: response.data });\n")
+	b.WriteString("  return response;\n")
+	b.WriteString("};\n\n")
+}
+
+// writeAxiosAbortAllHelpers renders the registry of in-flight request controllers, abortAllRequests()
+// and trackRequest, which runs one request on a registered signal that also follows the caller's signal.
+func writeAxiosAbortAllHelpers(b *strings.Builder) {
+	b.WriteString("const activeRequestControllers = new Set<AbortController>();\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Abort every in-flight request of this module, e.g. on SPA route changes.\n")
+	b.WriteString(" * 中止本模块所有进行中的请求（如 SPA 路由切换时）。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const abortAllRequests = (reason?: unknown): void => {\n")
+	b.WriteString("  for (const controller of Array.from(activeRequestControllers)) controller.abort(reason);\n")
+	b.WriteString("  activeRequestControllers.clear();\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const trackRequest = async <T>(signal: AbortSignal | undefined, send: (signal: AbortSignal) => Promise<T>): Promise<T> => {\n")
+	b.WriteString("  const controller = new AbortController();\n")
+	b.WriteString("  const forwardAbort = () => controller.abort(signal?.reason);\n")
+	b.WriteString("  if (signal?.aborted) forwardAbort();\n")
+	b.WriteString("  else signal?.addEventListener('abort', forwardAbort, { once: true });\n")
+	b.WriteString("  activeRequestControllers.add(controller);\n")
+	b.WriteString("  try {\n")
+	b.WriteString("    return await send(controller.signal);\n")
+	b.WriteString("  } finally {\n")
+	b.WriteString("    activeRequestControllers.delete(controller);\n")
+	b.WriteString("    signal?.removeEventListener('abort', forwardAbort);\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+}
+
+// writeAxiosResponseCache renders TTL-cached wrappers for GET endpoints.
+// Cache keys are the endpoint NAME plus a key-order independent encoding of params.
+func writeAxiosResponseCache(b *strings.Builder, metas []axiosFuncMeta) {
+	cacheable := make([]axiosFuncMeta, 0, len(metas))
+	for _, m := range metas {
+		if m.Method == "GET" && !m.HasReqBody {
+			cacheable = append(cacheable, m)
+		}
+	}
+	if len(cacheable) == 0 {
+		return
+	}
+	writeTSMarker(b, "Response Cache")
+	b.WriteString("export interface CachedRequestOptions {\n")
+	b.WriteString("  /** Time to live in milliseconds. Default 60000. 缓存有效期（毫秒），默认 60000。 */\n")
+	b.WriteString("  ttlMs?: number;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("const responseCache = new Map<string, { expiresAt: number; value: Promise<unknown> }>();\n\n")
+	b.WriteString("const stableCacheKey = (value: unknown): string => {\n")
+	b.WriteString("  if (Array.isArray(value)) return `[${value.map(stableCacheKey).join(',')}]`;\n")
+	b.WriteString("  if (isPlainObject(value)) {\n")
+	b.WriteString("    const entries = Object.keys(value)\n")
+	b.WriteString("      .sort()\n")
+	b.WriteString("      .map((k) => `${JSON.stringify(k)}:${stableCacheKey(value[k])}`);\n")
+	b.WriteString("    return `{${entries.join(',')}}`;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return JSON.stringify(value) ?? 'undefined';\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const cachedRequest = <T>(\n")
+	b.WriteString("  name: string,\n")
+	b.WriteString("  params: unknown,\n")
+	b.WriteString("  options: CachedRequestOptions | undefined,\n")
+	b.WriteString("  load: () => Promise<T>\n")
+	b.WriteString("): Promise<T> => {\n")
+	b.WriteString("  const key = `${name}:${stableCacheKey(params)}`;\n")
+	b.WriteString("  const now = Date.now();\n")
+	b.WriteString("  const hit = responseCache.get(key);\n")
+	b.WriteString("  if (hit && hit.expiresAt > now) return hit.value as Promise<T>;\n")
+	b.WriteString("  const value = load();\n")
+	b.WriteString("  responseCache.set(key, { expiresAt: now + (options?.ttlMs ?? 60000), value });\n")
+	b.WriteString("  value.catch(() => {\n")
+	b.WriteString("    if (responseCache.get(key)?.value === value) responseCache.delete(key);\n")
+	b.WriteString("  });\n")
+	b.WriteString("  return value;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Drop cached responses. Without arguments clears everything; with an endpoint NAME\n")
+	b.WriteString(" * (e.g. `GetPersonByIDGet.NAME`) clears that endpoint, optionally only for the given params.\n")
+	b.WriteString(" * 清除缓存：无参数时全部清除；传入端点 NAME 时清除该端点，可再按 params 精确清除。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const invalidateCachedResponses = (name?: string, params?: unknown): void => {\n")
+	b.WriteString("  if (name === undefined) {\n")
+	b.WriteString("    responseCache.clear();\n")
+	b.WriteString("    return;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  if (params !== undefined) {\n")
+	b.WriteString("    responseCache.delete(`${name}:${stableCacheKey(params)}`);\n")
+	b.WriteString("    return;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  for (const key of responseCache.keys()) {\n")
+	b.WriteString("    if (key.startsWith(`${name}:`)) responseCache.delete(key);\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * TTL-cached GET requests. Calls within ttlMs share one response; later calls refetch.\n")
+	b.WriteString(" * 带 TTL 缓存的 GET 请求；ttlMs 内的调用复用同一响应，过期后重新请求。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const cached = {\n")
+	for _, m := range cacheable {
+		className := axiosClassName(m.FuncName, m.Method)
+		b.WriteString("  ")
+		b.WriteString(toLowerCamel(m.FuncName))
+		b.WriteString(": (")
+		if m.HasParams {
+			b.WriteString("params: ")
+			b.WriteString(m.ParamsType)
+			b.WriteString(", ")
+		}
+		b.WriteString("options?: CachedRequestOptions): Promise<")
+		b.WriteString(m.ResponseType)
+		b.WriteString("> =>\n")
+		b.WriteString("    cachedRequest(")
+		b.WriteString(className)
+		b.WriteString(".NAME, ")
+		if m.HasParams {
+			b.WriteString("params")
+		} else {
+			b.WriteString("undefined")
+		}
+		b.WriteString(", options, () => ")
+		b.WriteString(className)
+		b.WriteString(".request(")
+		if m.HasParams {
+			b.WriteString("params")
+		}
+		b.WriteString(")),\n")
+	}
+	b.WriteString("} as const;\n\n")
+	writeTSMarkerEnd(b, "Response Cache")
+}
+
+// writeAxiosOfflineQueue renders the offline mutation queue. Only mutations whose payload survives
+// JSON storage are queueable, so multipart, bytes and streamed request bodies are left out.
+func writeAxiosOfflineQueue(b *strings.Builder, metas []axiosFuncMeta) {
+	mutations := make([]axiosFuncMeta, 0, len(metas))
+	for _, m := range metas {
+		if m.Method == "GET" || m.Method == "HEAD" || m.Method == "OPTIONS" || m.StreamBody {
+			continue
+		}
+		if m.HasReqBody && m.RequestKind != TSKindJSON && m.RequestKind != TSKindFormURLEncoded && m.RequestKind != TSKindText {
+			continue
+		}
+		mutations = append(mutations, m)
+	}
+	if len(mutations) == 0 {
+		return
+	}
+	writeTSMarker(b, "Offline Queue")
+	b.WriteString("export interface OfflineMutationPayloads {\n")
+	for _, m := range mutations {
+		b.WriteString("  ")
+		b.WriteString(toLowerCamel(m.FuncName))
+		fields := make([]string, 0, 2)
+		if m.HasParams {
+			fields = append(fields, "params: "+m.ParamsType)
+		}
+		if m.HasReqBody {
+			fields = append(fields, "requestBody: "+m.RequestType)
+		}
+		if len(fields) == 0 {
+			b.WriteString(": Record<string, never>;\n")
+			continue
+		}
+		b.WriteString(": { ")
+		b.WriteString(strings.Join(fields, "; "))
+		b.WriteString(" };\n")
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("export type OfflineMutationName = keyof OfflineMutationPayloads;\n\n")
+	b.WriteString("export interface OfflineMutation<K extends OfflineMutationName = OfflineMutationName> {\n")
+	b.WriteString("  /** Idempotency key, sent as the Idempotency-Key header on every attempt. 幂等键，每次发送都作为 Idempotency-Key 请求头。 */\n")
+	b.WriteString("  id: string;\n")
+	b.WriteString("  name: K;\n")
+	b.WriteString("  payload: OfflineMutationPayloads[K];\n")
+	b.WriteString("  queuedAt: number;\n")
+	b.WriteString("  attempts: number;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("export type OfflineConflictResolution = 'retry' | 'drop';\n\n")
+	b.WriteString("export interface OfflineQueueHooks {\n")
+	b.WriteString("  /** Called when the server rejects a replay; 'retry' keeps the mutation queued, 'drop' (default) removes it. 服务端拒绝重放时调用；'retry' 保留，'drop'（默认）移除。 */\n")
+	b.WriteString("  onConflict?: (mutation: OfflineMutation, error: unknown) => OfflineConflictResolution | Promise<OfflineConflictResolution>;\n")
+	b.WriteString("  /** Called after a queued mutation was replayed successfully. 排队的请求重放成功后调用。 */\n")
+	b.WriteString("  onReplayed?: (mutation: OfflineMutation, data: unknown) => void;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("const OFFLINE_QUEUE_STORAGE_KEY = 'nuxt-gin:offline-mutations';\n\n")
+	b.WriteString("let offlineQueueHooks: OfflineQueueHooks = {};\n\n")
+	b.WriteString("const newIdempotencyKey = (): string =>\n")
+	b.WriteString("  globalThis.crypto?.randomUUID?.() ?? `${Date.now().toString(36)}-${Math.random().toString(36).slice(2)}`;\n\n")
+	b.WriteString("const readOfflineQueue = (): OfflineMutation[] => {\n")
+	b.WriteString("  if (typeof localStorage === 'undefined') return [];\n")
+	b.WriteString("  try {\n")
+	b.WriteString("    const raw = localStorage.getItem(OFFLINE_QUEUE_STORAGE_KEY);\n")
+	b.WriteString("    return raw ? (JSON.parse(raw) as OfflineMutation[]) : [];\n")
+	b.WriteString("  } catch {\n")
+	b.WriteString("    return [];\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const writeOfflineQueue = (queue: OfflineMutation[]): void => {\n")
+	b.WriteString("  if (typeof localStorage === 'undefined') return;\n")
+	b.WriteString("  localStorage.setItem(OFFLINE_QUEUE_STORAGE_KEY, JSON.stringify(queue));\n")
+	b.WriteString("};\n\n")
+	b.WriteString("const isOfflineError = (error: unknown): boolean =>\n")
+	b.WriteString("  (typeof navigator !== 'undefined' && navigator.onLine === false) || (axios.isAxiosError(error) && !error.response);\n\n")
+	b.WriteString("const offlineMutationSenders: {\n")
+	b.WriteString("  [K in OfflineMutationName]: (payload: OfflineMutationPayloads[K], idempotencyKey: string) => Promise<unknown>;\n")
+	b.WriteString("} = {\n")
+	for _, m := range mutations {
+		className := axiosClassName(m.FuncName, m.Method)
+		args := make([]string, 0, 2)
+		if m.HasParams {
+			args = append(args, "payload.params")
+		}
+		if m.HasReqBody {
+			args = append(args, "payload.requestBody")
+		}
+		payloadArg := "payload"
+		if len(args) == 0 {
+			payloadArg = "_payload"
+		}
+		b.WriteString("  ")
+		b.WriteString(toLowerCamel(m.FuncName))
+		b.WriteString(": async (")
+		b.WriteString(payloadArg)
+		b.WriteString(", idempotencyKey) => {\n")
+		b.WriteString("    const config = ")
+		b.WriteString(className)
+		b.WriteString(".requestConfig(")
+		b.WriteString(strings.Join(args, ", "))
+		b.WriteString(");\n")
+		b.WriteString("    const response = await axiosClient.request({ ...config, headers: { ...(config.headers ?? {}), 'Idempotency-Key': idempotencyKey } });\n")
+		b.WriteString("    return response.data;\n")
+		b.WriteString("  },\n")
+	}
+	b.WriteString("};\n\n")
+	b.WriteString("let offlineReplay: Promise<void> | undefined;\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Offline mutation queue. `send` queues the mutation when the network is unavailable; queued\n")
+	b.WriteString(" * mutations are persisted to localStorage and replayed in order when the browser comes back online.\n")
+	b.WriteString(" * 离线请求队列：网络不可用时 `send` 会将请求排队并持久化到 localStorage，浏览器恢复联网后按顺序重放。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const offlineQueue = {\n")
+	b.WriteString("  configure(hooks: OfflineQueueHooks): void {\n")
+	b.WriteString("    offlineQueueHooks = hooks;\n")
+	b.WriteString("  },\n\n")
+	b.WriteString("  list(): OfflineMutation[] {\n")
+	b.WriteString("    return readOfflineQueue();\n")
+	b.WriteString("  },\n\n")
+	b.WriteString("  enqueue<K extends OfflineMutationName>(name: K, payload: OfflineMutationPayloads[K]): OfflineMutation<K> {\n")
+	b.WriteString("    const mutation: OfflineMutation<K> = { id: newIdempotencyKey(), name, payload, queuedAt: Date.now(), attempts: 0 };\n")
+	b.WriteString("    writeOfflineQueue([...readOfflineQueue(), mutation as OfflineMutation]);\n")
+	b.WriteString("    return mutation;\n")
+	b.WriteString("  },\n\n")
+	b.WriteString("  /** Send now, or queue when offline. 立即发送；离线时排队。 */\n")
+	b.WriteString("  async send<K extends OfflineMutationName>(\n")
+	b.WriteString("    name: K,\n")
+	b.WriteString("    payload: OfflineMutationPayloads[K]\n")
+	b.WriteString("  ): Promise<{ queued: false; data: unknown } | { queued: true; mutation: OfflineMutation<K> }> {\n")
+	b.WriteString("    const idempotencyKey = newIdempotencyKey();\n")
+	b.WriteString("    if (typeof navigator === 'undefined' || navigator.onLine !== false) {\n")
+	b.WriteString("      try {\n")
+	b.WriteString("        return { queued: false, data: await offlineMutationSenders[name](payload, idempotencyKey) };\n")
+	b.WriteString("      } catch (error) {\n")
+	b.WriteString("        if (!isOfflineError(error)) throw error;\n")
+	b.WriteString("      }\n")
+	b.WriteString("    }\n")
+	b.WriteString("    const mutation: OfflineMutation<K> = { id: idempotencyKey, name, payload, queuedAt: Date.now(), attempts: 0 };\n")
+	b.WriteString("    writeOfflineQueue([...readOfflineQueue(), mutation as OfflineMutation]);\n")
+	b.WriteString("    return { queued: true, mutation };\n")
+	b.WriteString("  },\n\n")
+	b.WriteString("  /** Replay queued mutations in order; stops at the first network failure. 按顺序重放队列；遇到网络失败即停止。 */\n")
+	b.WriteString("  replay(): Promise<void> {\n")
+	b.WriteString("    offlineReplay ??= (async () => {\n")
+	b.WriteString("      for (const mutation of readOfflineQueue()) {\n")
+	b.WriteString("        const sender = offlineMutationSenders[mutation.name] as (payload: unknown, idempotencyKey: string) => Promise<unknown>;\n")
+	b.WriteString("        let keep = false;\n")
+	b.WriteString("        try {\n")
+	b.WriteString("          const data = await sender(mutation.payload, mutation.id);\n")
+	b.WriteString("          offlineQueueHooks.onReplayed?.(mutation, data);\n")
+	b.WriteString("        } catch (error) {\n")
+	b.WriteString("          if (isOfflineError(error)) break;\n")
+	b.WriteString("          keep = (await offlineQueueHooks.onConflict?.(mutation, error)) === 'retry';\n")
+	b.WriteString("        }\n")
+	b.WriteString("        writeOfflineQueue(\n")
+	b.WriteString("          readOfflineQueue().flatMap((m) => (m.id !== mutation.id ? [m] : keep ? [{ ...m, attempts: m.attempts + 1 }] : []))\n")
+	b.WriteString("        );\n")
+	b.WriteString("      }\n")
+	b.WriteString("    })().finally(() => {\n")
+	b.WriteString("      offlineReplay = undefined;\n")
+	b.WriteString("    });\n")
+	b.WriteString("    return offlineReplay;\n")
+	b.WriteString("  },\n\n")
+	b.WriteString("  clear(): void {\n")
+	b.WriteString("    writeOfflineQueue([]);\n")
+	b.WriteString("  },\n")
+	b.WriteString("} as const;\n\n")
+	b.WriteString("if (typeof window !== 'undefined') {\n")
+	b.WriteString("  window.addEventListener('online', () => {\n")
+	b.WriteString("    void offlineQueue.replay();\n")
+	b.WriteString("  });\n")
+	b.WriteString("}\n\n")
+	writeTSMarkerEnd(b, "Offline Queue")
+}
+
+// writeAxiosVueComposables renders one Vue 3 composable per endpoint.
+// Each composable wraps the class request, so params/body/options keep their generated types.
+func writeAxiosVueComposables(b *strings.Builder, metas []axiosFuncMeta) {
+	if len(metas) == 0 {
+		return
+	}
+	writeTSMarker(b, "Vue Composables")
+	b.WriteString("export interface UseRequestReturn<TArgs extends unknown[], T> {\n")
+	b.WriteString("  /** Last successful response. 最近一次成功的响应。 */\n")
+	b.WriteString("  data: Ref<T | undefined>;\n")
+	b.WriteString("  /** Last error, cleared on the next execute. 最近一次错误，下次 execute 时清空。 */\n")
+	b.WriteString("  error: Ref<unknown>;\n")
+	b.WriteString("  /** True while a request is in flight. 请求进行中为 true。 */\n")
+	b.WriteString("  loading: Ref<boolean>;\n")
+	b.WriteString("  /** Send the request with the endpoint's typed arguments. 以端点的强类型参数发起请求。 */\n")
+	b.WriteString("  execute: (...args: TArgs) => Promise<T>;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("const useRequest = <TArgs extends unknown[], T>(\n")
+	b.WriteString("  run: (...args: TArgs) => Promise<T>\n")
+	b.WriteString("): UseRequestReturn<TArgs, T> => {\n")
+	b.WriteString("  const data = shallowRef<T | undefined>();\n")
+	b.WriteString("  const error = shallowRef<unknown>();\n")
+	b.WriteString("  const loading = ref(false);\n")
+	b.WriteString("  const execute = async (...args: TArgs): Promise<T> => {\n")
+	b.WriteString("    loading.value = true;\n")
+	b.WriteString("    error.value = undefined;\n")
+	b.WriteString("    try {\n")
+	b.WriteString("      const result = await run(...args);\n")
+	b.WriteString("      data.value = result;\n")
+	b.WriteString("      return result;\n")
+	b.WriteString("    } catch (err) {\n")
+	b.WriteString("      error.value = err;\n")
+	b.WriteString("      throw err;\n")
+	b.WriteString("    } finally {\n")
+	b.WriteString("      loading.value = false;\n")
+	b.WriteString("    }\n")
+	b.WriteString("  };\n")
+	b.WriteString("  return { data, error, loading, execute };\n")
+	b.WriteString("};\n\n")
+	for _, m := range metas {
+		className := axiosClassName(m.FuncName, m.Method)
+		if m.Deprecated != "" {
+			b.WriteString(renderTSDocBlock(strings.TrimSpace(m.APIDescription+"\n@deprecated "+m.Deprecated), ""))
+		} else if m.APIDescription != "" {
+			b.WriteString("/** ")
+			b.WriteString(escapeTSComment(m.APIDescription))
+			b.WriteString(" */\n")
+		}
+		b.WriteString("export function use")
+		b.WriteString(toUpperCamel(m.FuncName))
+		b.WriteString("(): UseRequestReturn<Parameters<typeof ")
+		b.WriteString(className)
+		b.WriteString(".request>, ")
+		b.WriteString(m.ResponseType)
+		b.WriteString("> {\n")
+		b.WriteString("  return useRequest((...args: Parameters<typeof ")
+		b.WriteString(className)
+		b.WriteString(".request>) => ")
+		b.WriteString(className)
+		b.WriteString(".request(...args));\n")
+		b.WriteString("}\n\n")
+	}
+	writeTSMarkerEnd(b, "Vue Composables")
+}
+
+// axiosOptionsResponseType is the TResponse of AxiosConvertOptions for an endpoint.
+// Union-mode endpoints deserialize each variant's data, so it is the union of the data types.
+func axiosOptionsResponseType(m axiosFuncMeta) string {
+	if len(m.ResponseVariants) > 0 {
+		return m.ResponseType + "['data']"
+	}
+	return m.ResponseType
+}
+
+func axiosClassName(funcName string, method string) string {
+	return toUpperCamel(funcName) + toUpperCamel(strings.ToLower(method))
+}
+
+func buildAxiosResponseVariants(responses []ResponseMeta, registry *tsInterfaceRegistry) ([]axiosResponseVariant, error) {
+	variants := make([]axiosResponseVariant, 0, len(responses))
+	seen := make(map[int]struct{}, len(responses))
+	for _, r := range responses {
+		if r.StatusCode <= 0 {
+			continue
+		}
+		if _, ok := seen[r.StatusCode]; ok {
+			continue
+		}
+		seen[r.StatusCode] = struct{}{}
+		tsType := "void"
+		if isValidType(r.BodyType) {
+			var err error
+			tsType, _, err = tsTypeFromType(r.BodyType, registry)
+			if err != nil {
+				return nil, err
+			}
+		}
+		variant := axiosResponseVariant{StatusCode: r.StatusCode, Type: tsType}
+		if TSAutoDateParsing && isValidType(r.BodyType) {
+			variant.DatePaths = tsDatePaths(r.BodyType)
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+func validateEndpointMeta(meta EndpointMeta) error {
+	if strings.TrimSpace(string(meta.Method)) == "" {
+		return fmt.Errorf("method is required")
+	}
+	if !meta.Method.IsValid() {
+		return fmt.Errorf("invalid http method")
+	}
+	if strings.TrimSpace(meta.Path) == "" {
+		return fmt.Errorf("path is required")
+	}
+	pathParams := extractPathParams(meta.Path)
+	if len(pathParams) > 0 && isNoType(meta.PathParamsType) {
+		return fmt.Errorf("path params required but PathParams type is NoParams")
+	}
+	if len(pathParams) > 0 && meta.PathParamsType != nil {
+		// Every placeholder must map to a field, otherwise the generated URL always interpolates ''.
+		pathType := meta.PathParamsType
+		for pathType.Kind() == reflect.Ptr {
+			pathType = pathType.Elem()
+		}
+		if pathType.Kind() == reflect.Struct {
+			fieldMap := pathParamFieldMap(pathType)
+			for _, name := range pathParams {
+				if _, ok := fieldMap[strings.ToLower(name)]; !ok {
+					return fmt.Errorf("path param %q has no matching field in %s", name, meta.PathParamsType.String())
+				}
+			}
+		}
+	}
+	if err := validateBodyType(meta.RequestBodyType); err != nil {
+		return fmt.Errorf("invalid request body type: %w", err)
+	}
+	for _, r := range meta.Responses {
+		if err := validateBodyType(r.BodyType); err != nil {
+			return fmt.Errorf("invalid response body type for status %d: %w", r.StatusCode, err)
+		}
+	}
+	return nil
+}
+
+// validateBodyType rejects types that cannot be encoded as a JSON body (e.g. an override of func or chan).
+func validateBodyType(t reflect.Type) error {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return fmt.Errorf("%s cannot be serialized", t.String())
 	}
 	return nil
 }
@@ -652,6 +2517,7 @@ func buildParamsTypeFromTypes(registry *tsInterfaceRegistry, pathType, queryType
 
 	fields := make(map[string]string, 4)
 	if hasPath {
+		registry.setParamTag(pathType, "uri")
 		t, _, err := tsTypeFromType(pathType, registry)
 		if err != nil {
 			return "", false, false, false, false, err
@@ -659,6 +2525,7 @@ func buildParamsTypeFromTypes(registry *tsInterfaceRegistry, pathType, queryType
 		fields["path"] = t
 	}
 	if hasQuery {
+		registry.setParamTag(queryType, "form")
 		t, _, err := tsTypeFromType(queryType, registry)
 		if err != nil {
 			return "", false, false, false, false, err
@@ -696,8 +2563,10 @@ func buildInlineObjectType(fields map[string]string) string {
 	var b strings.Builder
 	b.WriteString("{\n")
 	for _, k := range keys {
+		// Keys are path/query/header/cookie, always valid identifiers.
+		prop, _ := tsPropName(k)
 		b.WriteString("  ")
-		b.WriteString(k)
+		b.WriteString(prop)
 		b.WriteString(": ")
 		b.WriteString(fields[k])
 		if isMultilineObjectType(fields[k]) {
@@ -805,7 +2674,7 @@ func pathParamFieldMap(t reflect.Type) map[string]string {
 		if externalName == "" {
 			externalName = f.Name
 		}
-		tsFieldName, _, tsOK := jsonFieldMeta(f)
+		tsFieldName, _, tsOK := fieldNameMeta(f, "uri")
 		if !tsOK {
 			continue
 		}
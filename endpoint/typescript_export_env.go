@@ -2,7 +2,19 @@ package endpoint
 
 import "github.com/gin-gonic/gin"
 
+// TSExportPolicy decides whether TS/metadata export runs in the current environment.
+// When nil, export runs only in gin debug mode.
+var TSExportPolicy func() bool
+
+// SetTSExportPolicy overrides the default gin-debug-mode export gate.
+// Pass nil to restore the default.
+func SetTSExportPolicy(policy func() bool) {
+	TSExportPolicy = policy
+}
+
 func shouldExportTSInCurrentEnv() bool {
+	if TSExportPolicy != nil {
+		return TSExportPolicy()
+	}
 	return gin.Mode() == gin.DebugMode
 }
-
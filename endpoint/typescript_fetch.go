@@ -0,0 +1,351 @@
+package endpoint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// fetchEventSourceURLResolver reuses resolveHttpBaseURL: the fetch client applies its base URL there.
+const fetchEventSourceURLResolver = "const resolveEventSourceURL = (url: string): string => resolveHttpBaseURL(url);\n\n"
+
+func generateFetchFromEndpoints(basePath string, groupPath string, endpoints []EndpointLike) (string, error) {
+	registry, metas, err := collectAxiosFuncMetas(endpoints)
+	if err != nil {
+		return "", err
+	}
+	return renderFetchTS(basePath, groupPath, registry, metas, axiosRenderOptions{}), nil
+}
+
+func exportFetchFromEndpointsToTSFile(basePath string, groupPath string, endpoints []EndpointLike, relativeTSPath string) error {
+	if strings.TrimSpace(relativeTSPath) == "" {
+		return fmt.Errorf("relative ts path is required")
+	}
+	if filepath.IsAbs(relativeTSPath) {
+		return fmt.Errorf("ts file path must be relative to cwd")
+	}
+	code, err := generateFetchFromEndpoints(basePath, groupPath, endpoints)
+	if err != nil {
+		return err
+	}
+	return writeRelativeTSFile(relativeTSPath, code)
+}
+
+// renderFetchTS renders the same endpoint classes as renderAxiosTS on top of the Fetch API, with
+// no runtime dependency. Axios-only extras (Vue composables, TanStack Query, zod, mocks, response
+// cache, offline queue and formatApiError) are not generated; of opts only the Omit* fields apply.
+func renderFetchTS(basePath string, groupPath string, registry *tsInterfaceRegistry, metas []axiosFuncMeta, opts axiosRenderOptions) string {
+	metas = applyAxiosRenderOptions(metas, opts)
+	var b strings.Builder
+	writeTSBanner(&b, "Nuxt Gin HTTP API Client (Fetch)")
+	writeTSMarker(&b, "Runtime Helpers")
+	b.WriteString("let fetchImpl: typeof fetch = (input, init) => fetch(input, init);\n")
+	b.WriteString("let fetchBaseURL = '';\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Use a custom fetch (auth headers, credentials, retry) for all generated requests.\n")
+	b.WriteString(" * 为所有生成的请求使用自定义的 fetch（鉴权头、credentials、重试等）。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const setFetchImplementation = (impl: typeof fetch): void => {\n")
+	b.WriteString("  fetchImpl = impl;\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Prefix relative request URLs with baseURL; when set it replaces the dev-mode Gin port.\n")
+	b.WriteString(" * 为相对请求地址加上 baseURL 前缀；设置后取代开发模式的 Gin 端口地址。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const setFetchBaseURL = (baseURL: string): void => {\n")
+	b.WriteString("  fetchBaseURL = baseURL;\n")
+	b.WriteString("};\n\n")
+	writeHTTPRequestNormalizers(&b)
+	b.WriteString("const resolveHttpBaseURL = (url: string): string => {\n")
+	b.WriteString("  if (url.startsWith('http://') || url.startsWith('https://')) return url;\n")
+	b.WriteString("  if (fetchBaseURL) return `${fetchBaseURL.replace(/\\/+$/, '')}${url}`;\n")
+	b.WriteString("  if (url.startsWith('/') && typeof window !== 'undefined' && isDevelopmentEnv()) {\n")
+	b.WriteString("    return `${window.location.protocol}//${window.location.hostname}:${resolveGinPort()}${url}`;\n")
+	b.WriteString("  }\n")
+	b.WriteString("  return url;\n")
+	b.WriteString("};\n\n")
+	if needsHTTPMultipartHelper(metas) {
+		writeHTTPFormDataHelpers(&b)
+	}
+	b.WriteString("/**\n")
+	b.WriteString(" * Thrown for responses with an unexpected status; data is the JSON body, or its raw text.\n")
+	b.WriteString(" * 响应状态不符合预期时抛出；data 为 JSON 响应体（无法解析时为原始文本）。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export class FetchError extends Error {\n")
+	b.WriteString("  readonly status: number;\n")
+	b.WriteString("  readonly statusText: string;\n")
+	b.WriteString("  readonly data: unknown;\n")
+	b.WriteString("  readonly response: Response;\n\n")
+	b.WriteString("  constructor(response: Response, data: unknown) {\n")
+	b.WriteString("    super(`Request failed with status code ${response.status}`);\n")
+	b.WriteString("    this.name = 'FetchError';\n")
+	b.WriteString("    this.status = response.status;\n")
+	b.WriteString("    this.statusText = response.statusText;\n")
+	b.WriteString("    this.data = data;\n")
+	b.WriteString("    this.response = response;\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+	b.WriteString("export interface FetchConvertOptions<TRequest = unknown, TResponse = unknown> {\n")
+	b.WriteString("  serializeRequest?: (value: TRequest) => unknown;\n")
+	b.WriteString("  deserializeResponse?: (value: unknown) => TResponse;\n")
+	b.WriteString("  /** Cancels the request when aborted, e.g. on component unmount. 中止时取消请求（如组件卸载时）。 */\n")
+	b.WriteString("  signal?: AbortSignal;\n")
+	b.WriteString("  /** Request timeout in ms, overriding the endpoint default; 0 disables it. 请求超时（毫秒），覆盖端点默认值；0 表示不限制。 */\n")
+	b.WriteString("  timeout?: number;\n")
+	b.WriteString("  /** Ad-hoc headers (request ID, idempotency key) merged last, over header params and Content-Type. 临时附加的请求头（如请求 ID、幂等键），最后合并，覆盖 header 参数与 Content-Type。 */\n")
+	b.WriteString("  headers?: Record<string, string>;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("const readResponseBody = async (response: Response): Promise<unknown> => {\n")
+	b.WriteString("  const text = await response.text();\n")
+	b.WriteString("  if (text === '') return undefined;\n")
+	b.WriteString("  try {\n")
+	b.WriteString("    return JSON.parse(text);\n")
+	b.WriteString("  } catch {\n")
+	b.WriteString("    return text;\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Send a request with the configured fetch and decode it with read. The timeout (ms, 0 = none)\n")
+	b.WriteString(" * also covers reading the body. Statuses rejected by acceptStatus (default: 2xx) throw FetchError.\n")
+	b.WriteString(" * 使用配置的 fetch 发送请求并用 read 解码响应；超时（毫秒，0 表示不限制）同样覆盖读取响应体；acceptStatus（默认 2xx）拒绝的状态码会抛出 FetchError。\n")
+	b.WriteString(" */\n")
+	b.WriteString("const sendFetchRequest = async <T>(\n")
+	b.WriteString("  url: string,\n")
+	b.WriteString("  init: RequestInit,\n")
+	b.WriteString("  timeout: number | undefined,\n")
+	b.WriteString("  read: (response: Response) => Promise<T>,\n")
+	b.WriteString("  acceptStatus: (status: number) => boolean = (status) => status >= 200 && status < 300,\n")
+	b.WriteString("): Promise<T> => {\n")
+	b.WriteString("  const controller = new AbortController();\n")
+	b.WriteString("  const signal = init.signal;\n")
+	b.WriteString("  const abort = () => controller.abort(signal?.reason);\n")
+	b.WriteString("  if (signal?.aborted) abort();\n")
+	b.WriteString("  else signal?.addEventListener('abort', abort, { once: true });\n")
+	b.WriteString("  const timer = timeout ? setTimeout(() => controller.abort(new Error(`Request timed out after ${timeout}ms`)), timeout) : undefined;\n")
+	b.WriteString("  try {\n")
+	b.WriteString("    const response = await fetchImpl(url, { ...init, signal: controller.signal });\n")
+	b.WriteString("    if (!acceptStatus(response.status)) {\n")
+	b.WriteString("      throw new FetchError(response, await readResponseBody(response).catch(() => undefined));\n")
+	b.WriteString("    }\n")
+	b.WriteString("    return await read(response);\n")
+	b.WriteString("  } finally {\n")
+	b.WriteString("    clearTimeout(timer);\n")
+	b.WriteString("    signal?.removeEventListener('abort', abort);\n")
+	b.WriteString("  }\n")
+	b.WriteString("};\n\n")
+	writeHTTPNormalizeParamKeys(&b)
+	writeTSMarkerEnd(&b, "Runtime Helpers")
+
+	writeHTTPInterfaces(&b, registry, metas, !opts.OmitValidators)
+
+	writeTSMarker(&b, "Endpoint Classes")
+	needsQueryHelper, needsCookieHelper, needsPathParamsHelper, needsStreamHelper := false, false, false, false
+	for _, m := range metas {
+		needsQueryHelper = needsQueryHelper || (m.HasQuery && m.ResponseKind != TSKindEventStream)
+		needsCookieHelper = needsCookieHelper || m.HasCookie
+		needsPathParamsHelper = needsPathParamsHelper || (TSStrictPathParams && len(extractPathParams(m.Path)) > 0)
+		needsStreamHelper = needsStreamHelper || m.StreamBody
+	}
+	if needsQueryHelper {
+		writeHTTPQueryHelpers(&b)
+		b.WriteString("const withQueryString = (url: string, query: string): string =>\n")
+		b.WriteString("  query ? `${url}${url.includes('?') ? '&' : '?'}${query}` : url;\n\n")
+	}
+	if needsPathParamsHelper {
+		writeHTTPPathParamsHelper(&b)
+	}
+	if needsStreamHelper {
+		writeStreamBodyHelpers(&b)
+	}
+	for _, m := range metas {
+		if m.CSVRowType != "" {
+			writeAxiosCSVHelpers(&b)
+			break
+		}
+	}
+	for _, m := range metas {
+		if m.Batch {
+			writeAxiosBatchHelpers(&b)
+			break
+		}
+	}
+	for _, m := range metas {
+		if m.ResponseKind == TSKindEventStream {
+			writeEventSourceHelpers(&b, fetchEventSourceURLResolver)
+			break
+		}
+	}
+	writeAxiosReviveHelpers(&b, metas)
+	if needsCookieHelper {
+		writeHTTPCookieHelpers(&b)
+	}
+
+	fullBasePath := normalizePathSegment(basePath)
+	fullGroupPath := normalizePathSegment(groupPath)
+	for _, m := range metas {
+		className, args, hasPathPlaceholders := writeHTTPClassHeader(&b, m, fullBasePath, fullGroupPath)
+		if m.ResponseKind == TSKindEventStream {
+			writeAxiosEventSourceConnect(&b, m, className, hasPathPlaceholders)
+			writeHTTPRouteFunc(&b, m, className)
+			continue
+		}
+		writeFetchRequestInit(&b, m, className, args, hasPathPlaceholders)
+		writeFetchRequest(&b, m, className, args)
+		writeHTTPRequestWrapper(&b, m, className, args, "FetchConvertOptions")
+	}
+	writeTSMarkerEnd(&b, "Endpoint Classes")
+	return finalizeTypeScriptCode(b.String())
+}
+
+func fetchOptionsType(m axiosFuncMeta) string {
+	requestType := "never"
+	if m.HasReqBody {
+		requestType = m.RequestType
+	}
+	return "FetchConvertOptions<" + requestType + ", " + axiosOptionsResponseType(m) + ">"
+}
+
+// writeFetchRequestInit renders requestInit(), the fetch counterpart of the axios requestConfig():
+// the resolved URL with its query string plus the RequestInit (method, headers, body, signal).
+func writeFetchRequestInit(b *strings.Builder, m axiosFuncMeta, className string, args []string, hasPathPlaceholders bool) {
+	initArgs := append(append([]string{}, args...), "options?: "+fetchOptionsType(m))
+	b.WriteString("  static requestInit(")
+	b.WriteString(strings.Join(initArgs, ", "))
+	b.WriteString("): { url: string; init: RequestInit } {\n")
+	b.WriteString("    const url = ")
+	b.WriteString(className)
+	if hasPathPlaceholders {
+		b.WriteString(".buildURL(params);\n")
+	} else {
+		b.WriteString(".buildURL();\n")
+	}
+	body := ""
+	contentType := httpRequestContentType(m.RequestKind)
+	switch {
+	case m.StreamBody:
+		body = "toReadableStream(requestBody)"
+		contentType = "application/octet-stream"
+	case m.HasReqBody:
+		switch m.RequestKind {
+		case TSKindFormURLEncoded:
+			b.WriteString("    const serializedRequest = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
+			b.WriteString("    const requestData = toFormUrlEncoded(serializedRequest);\n")
+			body = "requestData"
+		case TSKindMultipart:
+			// No Content-Type header: the browser sets multipart/form-data with its boundary.
+			b.WriteString("    const serializedRequest = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
+			b.WriteString("    const requestData = toFormData(serializedRequest);\n")
+			body = "requestData"
+		case TSKindJSON:
+			b.WriteString("    const requestData = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
+			body = "JSON.stringify(normalizeRequestJSON(requestData))"
+			contentType = "application/json"
+		default:
+			b.WriteString("    const requestData = options?.serializeRequest ? options.serializeRequest(requestBody) : requestBody;\n")
+			body = "requestData as BodyInit"
+		}
+	}
+	writeHTTPNormalizedParams(b, m)
+	needsHeaders := writeHTTPRequestHeaders(b, m, contentType)
+	b.WriteString("    return {\n")
+	if m.HasQuery {
+		b.WriteString("      url: withQueryString(resolveHttpBaseURL(url), serializeQueryParams(normalizedParams.query, '")
+		b.WriteString(string(m.QueryArrayFormat))
+		b.WriteString("')),\n")
+	} else {
+		b.WriteString("      url: resolveHttpBaseURL(url),\n")
+	}
+	b.WriteString("      init: {\n")
+	b.WriteString("        method: ")
+	b.WriteString(className)
+	b.WriteString(".METHOD,\n")
+	if needsHeaders {
+		b.WriteString("        headers: { ...headers, ...(options?.headers ?? {}) },\n")
+	} else {
+		b.WriteString("        ...(options?.headers ? { headers: options.headers } : {}),\n")
+	}
+	if body != "" {
+		b.WriteString("        body: ")
+		b.WriteString(body)
+		b.WriteString(",\n")
+	}
+	b.WriteString("        signal: options?.signal,\n")
+	if m.StreamBody {
+		// Streaming request bodies require half-duplex mode.
+		b.WriteString("        duplex: 'half',\n")
+		b.WriteString("      } as RequestInit & { duplex: 'half' },\n")
+	} else {
+		b.WriteString("      },\n")
+	}
+	b.WriteString("    };\n")
+	b.WriteString("  }\n\n")
+}
+
+// writeFetchRequest renders request(), decoding the body by response kind.
+func writeFetchRequest(b *strings.Builder, m axiosFuncMeta, className string, args []string) {
+	callArgs := make([]string, 0, 3)
+	if m.HasParams {
+		callArgs = append(callArgs, "params")
+	}
+	if m.HasReqBody {
+		callArgs = append(callArgs, "requestBody")
+	}
+	callArgs = append(callArgs, "options")
+	requestArgs := append(append([]string{}, args...), "options?: "+fetchOptionsType(m))
+	timeout := "options?.timeout"
+	if m.TimeoutMS > 0 {
+		timeout = fmt.Sprintf("options?.timeout ?? %d", m.TimeoutMS)
+	}
+	b.WriteString("  static async request(")
+	b.WriteString(strings.Join(requestArgs, ", "))
+	b.WriteString("): Promise<")
+	b.WriteString(m.ResponseType)
+	b.WriteString("> {\n")
+	b.WriteString("    const { url, init } = ")
+	b.WriteString(className)
+	b.WriteString(".requestInit(")
+	b.WriteString(strings.Join(callArgs, ", "))
+	b.WriteString(");\n")
+	switch {
+	case len(m.ResponseVariants) > 0:
+		b.WriteString("    const response = await sendFetchRequest(\n")
+		b.WriteString("      url,\n")
+		b.WriteString("      init,\n")
+		b.WriteString("      ")
+		b.WriteString(timeout)
+		b.WriteString(",\n")
+		b.WriteString("      async (res) => ({ status: res.status, data: await readResponseBody(res) }),\n")
+		b.WriteString("      () => true,\n")
+		b.WriteString("    );\n")
+		writeHTTPResponseVariantSwitch(b, m, className)
+	case m.ResponseType == "void":
+		b.WriteString("    await sendFetchRequest(url, init, ")
+		b.WriteString(timeout)
+		b.WriteString(", async () => undefined);\n")
+	default:
+		b.WriteString("    return sendFetchRequest(url, init, ")
+		b.WriteString(timeout)
+		b.WriteString(", async (response) => {\n")
+		switch m.ResponseKind {
+		case TSKindBytes:
+			b.WriteString("      const responseData = new Uint8Array(await response.arrayBuffer());\n")
+		case TSKindStream:
+			b.WriteString("      const responseData = await response.blob();\n")
+		case TSKindText:
+			b.WriteString("      const responseData = await response.text();\n")
+		default:
+			b.WriteString("      const responseData = ")
+			b.WriteString(axiosReviveExpr("await readResponseBody(response)", m.DatePaths, m.BigIntPaths))
+			b.WriteString(";\n")
+		}
+		b.WriteString("      if (options?.deserializeResponse) {\n")
+		b.WriteString("        return options.deserializeResponse(responseData);\n")
+		b.WriteString("      }\n")
+		b.WriteString("      return responseData as ")
+		b.WriteString(m.ResponseType)
+		b.WriteString(";\n")
+		b.WriteString("    });\n")
+	}
+	b.WriteString("  }\n")
+	b.WriteString("}\n\n")
+}
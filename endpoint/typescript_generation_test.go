@@ -1,13 +1,17 @@
 package endpoint
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/RapboyGao/nuxtGin/endpoint/internal/testtypes/inventory"
+	"github.com/RapboyGao/nuxtGin/endpoint/internal/testtypes/orders"
 	"github.com/gin-gonic/gin"
 )
 
@@ -213,8 +217,8 @@ func TestGenerateAxiosFromEndpoints(t *testing.T) {
 	if !strings.Contains(code, "export class GetPersonByURIPathGet {") {
 		t.Fatalf("expected class generation for uri-tag path placeholder endpoint")
 	}
-	if !strings.Contains(code, "PersonByURI") || !strings.Contains(code, "params.path?.ID ?? \"\"") {
-		t.Fatalf("expected uri-tag endpoint to interpolate path param with original casing (ID)")
+	if !strings.Contains(code, "PersonByURI") || !strings.Contains(code, "params.path?.id ?? ''") {
+		t.Fatalf("expected uri-tag endpoint to interpolate path param with its uri name (id)")
 	}
 	if !strings.Contains(code, "normalizeParamKeys") {
 		t.Fatalf("expected param key normalization helper")
@@ -303,355 +307,3524 @@ func TestGenerateAxiosFromEndpoints_Int64AsStringMode(t *testing.T) {
 	}
 }
 
-// TestGenerateAxiosFromEndpoints_ValidationError
-// 这个测试验证生成前的元数据校验逻辑：
-// 当路由 path 中声明了路径参数（如 :id），但 Endpoint 的 PathParams 使用了 NoParams 时，
-// 生成器必须返回明确错误，而不是继续生成无效 TS。
-func TestGenerateAxiosFromEndpoints_ValidationError(t *testing.T) {
-	apis := []EndpointLike{
-		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
-			Name:   "invalid_path_params",
-			Method: HTTPMethodGet,
-			Path:   "/person/:id",
-			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
-				return Response[PersonDetailResp]{StatusCode: 200}, nil
-			},
-		},
-	}
+// TestGenerateAxiosFromEndpoints_Int64ValidatorFollowsMode
+// 这个测试验证 int64 的 validator 与 interface 类型保持一致：
+// number 模式下 validator 检查 typeof === 'number'，string 模式下检查 'string'，
+// 避免 ensureX 在任一模式下拒绝合法数据。
+func TestGenerateAxiosFromEndpoints_Int64ValidatorFollowsMode(t *testing.T) {
+	oldMode := TSInt64MappingMode
+	t.Cleanup(func() {
+		SetTSInt64MappingMode(oldMode)
+	})
 
-	_, err := generateAxiosFromEndpoints("/api", "/v1", apis)
-	if err == nil {
-		t.Fatalf("expected validation error for missing path params type")
+	tests := []struct {
+		name          string
+		mode          TSInt64Mode
+		wantField     string
+		wantValidator string
+		notValidator  string
+	}{
+		{
+			name:          "number",
+			mode:          TSInt64ModeNumber,
+			wantField:     "salary: number;",
+			wantValidator: `if (!(typeof obj["salary"] === 'number')) return false;`,
+			notValidator:  `typeof obj["salary"] === 'string'`,
+		},
+		{
+			name:          "string",
+			mode:          TSInt64ModeString,
+			wantField:     "salary: string;",
+			wantValidator: `if (!(typeof obj["salary"] === 'string')) return false;`,
+			notValidator:  `typeof obj["salary"] === 'number'`,
+		},
 	}
-	if !strings.Contains(err.Error(), "path params required") {
-		t.Fatalf("expected validation error message, got: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetTSInt64MappingMode(tt.mode)
+			code, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+			if err != nil {
+				t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+			}
+			if !strings.Contains(code, tt.wantField) {
+				t.Fatalf("expected %q in interface", tt.wantField)
+			}
+			if !strings.Contains(code, tt.wantValidator) || strings.Contains(code, tt.notValidator) {
+				t.Fatalf("expected int64 validator to follow %s mode", tt.mode)
+			}
+		})
 	}
 }
 
-// TestGenerateAxiosFromEndpoints_CustomEndpoint_ExportTSFile
-// 这个测试验证 CustomEndpoint 路径：
-// 1) CustomEndpoint 能与普通 Endpoint 一样参与 TS 生成并写入文件。
-// 2) RequestKind/ResponseKind 的自定义行为能体现在 TS（如 form-urlencoded 与 text 响应）。
-// 3) 自定义路径参数（含 uri/json tag）插值时字段名映射正确。
-func TestGenerateAxiosFromEndpoints_CustomEndpoint_ExportTSFile(t *testing.T) {
-	type CustomPathParams struct {
-		OrderID string `uri:"orderID" json:"orderID" tsdoc:"订单ID / Order identifier"`
+// TestGenerateAxiosFromEndpoints_Int64AsBigIntMode
+// 这个测试验证 TSInt64ModeBigInt：
+// 1) int64 字段生成为 bigint，validator 检查 typeof === 'bigint'。
+// 2) normalizeRequestJSON 将 bigint 序列化为字符串。
+// 3) 响应中已知的 int64 字段按路径通过 reviveBigInts 还原为 bigint，数组元素用 * 表示。
+// 4) 默认 number 模式下不生成 reviveBigInts。
+func TestGenerateAxiosFromEndpoints_Int64AsBigIntMode(t *testing.T) {
+	oldMode := TSInt64MappingMode
+	t.Cleanup(func() {
+		SetTSInt64MappingMode(oldMode)
+	})
+
+	SetTSInt64MappingMode(TSInt64ModeBigInt)
+	code, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
 	}
-	type CustomReq struct {
-		Format string `json:"format" tsunion:"json,text" tsdoc:"返回格式 / Response format"`
+	if !strings.Contains(code, "salary: bigint;") || !strings.Contains(code, `if (!(typeof obj["salary"] === 'bigint')) return false;`) {
+		t.Fatalf("expected int64 to map to bigint in interface and validator")
 	}
-	type CustomResp struct {
-		Result string `json:"result" tsdoc:"结果文本 / Result text"`
+	if !strings.Contains(code, "  if (typeof value === 'bigint') return formatInt64(value);\n") {
+		t.Fatalf("expected bigint request values to serialize as strings")
+	}
+	if !strings.Contains(code, "const reviveBigInts = (value: unknown, paths: readonly string[]): unknown =>") ||
+		!strings.Contains(code, "const responseData = reviveBigInts(reviveDates(response.data, ['resumes.*.startDate', 'resumes.*.endDate']), ['salary']);") {
+		t.Fatalf("expected response int64 fields to be revived to bigint")
 	}
 
-	cwd, err := os.Getwd()
+	SetTSInt64MappingMode(TSInt64ModeNumber)
+	plain, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
 	if err != nil {
-		t.Fatalf("getwd failed: %v", err)
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "reviveBigInts") || strings.Contains(plain, "bigint") {
+		t.Fatalf("expected no bigint handling in number mode")
 	}
+}
 
-	moduleRoot := cwd
-	for {
-		if _, statErr := os.Stat(filepath.Join(moduleRoot, "go.mod")); statErr == nil {
-			break
-		}
-		next := filepath.Dir(moduleRoot)
-		if next == moduleRoot {
-			t.Fatalf("go.mod not found from cwd: %s", cwd)
-		}
-		moduleRoot = next
+// TestGenerateAxiosFromEndpoints_Int64WireHelpers
+// 这个测试验证 int64 线上格式转换辅助函数：
+// 1) string / bigint 模式下生成一次 parseInt64(s): bigint 与 formatInt64(n: bigint | number): string。
+// 2) bigint 模式的请求序列化与响应还原分别使用 formatInt64 / parseInt64。
+// 3) 默认 number 模式下不生成这些辅助函数。
+func TestGenerateAxiosFromEndpoints_Int64WireHelpers(t *testing.T) {
+	oldMode := TSInt64MappingMode
+	t.Cleanup(func() {
+		SetTSInt64MappingMode(oldMode)
+	})
+
+	SetTSInt64MappingMode(TSInt64ModeString)
+	code, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Count(code, "export function parseInt64(s: string): bigint {") != 1 ||
+		strings.Count(code, "export function formatInt64(n: bigint | number): string {") != 1 {
+		t.Fatalf("expected int64 helpers to be emitted once in string mode")
 	}
 
-	t.Cleanup(func() { _ = os.Chdir(cwd) })
-	if err := os.Chdir(moduleRoot); err != nil {
-		t.Fatalf("chdir failed: %v", err)
+	SetTSInt64MappingMode(TSInt64ModeBigInt)
+	code, err = generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export function parseInt64(s: string): bigint {") {
+		t.Fatalf("expected int64 helpers in bigint mode")
+	}
+	if !strings.Contains(code, "  if (typeof value === 'bigint') return formatInt64(value);\n") ||
+		!strings.Contains(code, "  if (typeof value === 'string' && int64Pattern.test(value)) return parseInt64(value);\n") {
+		t.Fatalf("expected request/response transforms to use formatInt64/parseInt64")
 	}
 
-	custom := CustomEndpoint[CustomPathParams, NoParams, NoParams, NoParams, CustomReq, CustomResp]{
-		Name:         "submit_order_custom",
-		Method:       HTTPMethodPost,
-		Path:         "/custom/order/:orderID",
-		Description:  "Submit order with custom endpoint.",
-		RequestKind:  TSKindFormURLEncoded,
-		ResponseKind: TSKindText,
-		Responses: []Response[CustomResp]{
-			{StatusCode: 200, Description: "ok"},
-		},
-		HandlerFunc: func(ctx *gin.Context) {
-			ctx.String(200, "ok")
+	SetTSInt64MappingMode(TSInt64ModeNumber)
+	plain, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "parseInt64") || strings.Contains(plain, "formatInt64") {
+		t.Fatalf("expected no int64 helpers in number mode")
+	}
+}
+
+type KeyQuotingResp struct {
+	PersonID  string `json:"personID"`
+	TraceID   string `json:"trace-id"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// TestGenerateAxiosFromEndpoints_KeyQuoting
+// 这个测试验证属性名加引号策略：
+// 1) 默认 auto 只为非法标识符加引号。
+// 2) always 为所有属性名加引号，validator 仍按 obj["key"] 查找。
+// 3) never 遇到非法标识符时返回错误。
+func TestGenerateAxiosFromEndpoints_KeyQuoting(t *testing.T) {
+	apis := []EndpointLike{
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, KeyQuotingResp]{
+			Name:   "GetKeys",
+			Method: HTTPMethodGet,
+			Path:   "/keys",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[KeyQuotingResp], error) {
+				return Response[KeyQuotingResp]{StatusCode: 200}, nil
+			},
 		},
 	}
+	oldMode := TSKeyQuotingMode
+	t.Cleanup(func() {
+		SetTSKeyQuoting(oldMode)
+	})
 
-	outPath := filepath.Join(".generated", "schema", "custom", "custom_endpoint_api.ts")
-	customAPI := ServerAPI{
-		BasePath:  "/api",
-		GroupPath: "/v2",
-		Endpoints: []EndpointLike{custom},
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
 	}
-	if err := customAPI.ExportTS(outPath); err != nil {
-		t.Fatalf("ServerAPI.ExportTS returned error: %v", err)
+	if !strings.Contains(code, "  personID: string;") || !strings.Contains(code, `  "trace-id": string;`) {
+		t.Fatalf("expected auto mode to quote only invalid identifiers")
 	}
 
-	data, err := os.ReadFile(filepath.Join(moduleRoot, outPath))
+	SetTSKeyQuoting(TSKeyQuotingAlways)
+	code, err = generateAxiosFromEndpoints("/api", "", apis)
 	if err != nil {
-		t.Fatalf("read generated ts file failed: %v", err)
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	for _, want := range []string{`  "personID": string;`, `  "trace-id": string;`, `  "created_at"?: string;`} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected always mode to quote every key, missing %q", want)
+		}
+	}
+	if !strings.Contains(code, `obj["personID"]`) {
+		t.Fatalf("expected validator key lookups to stay bracketed")
 	}
-	code := string(data)
 
-	if !strings.Contains(code, "export class SubmitOrderCustomPost {") {
-		t.Fatalf("expected class generation for custom endpoint")
+	SetTSKeyQuoting(TSKeyQuotingNever)
+	if _, err := generateAxiosFromEndpoints("/api", "", apis); err == nil || !strings.Contains(err.Error(), "trace-id") {
+		t.Fatalf("expected never mode to reject invalid identifier keys, got %v", err)
 	}
-	if !strings.Contains(code, "export async function requestSubmitOrderCustomPost(") || !strings.Contains(code, "return SubmitOrderCustomPost.request(") {
-		t.Fatalf("expected generated convenience request function for custom endpoint class")
+}
+
+// TestGenerateAxiosFromEndpoints_StrictPathParams
+// 这个测试验证必填路径参数的客户端校验：
+// 1) 默认开启时，带路径占位符的 buildURL 会在拼接 URL 前调用 assertRequiredPathParams，缺参直接抛错而不是发出错误请求。
+// 2) 关闭 TSStrictPathParams 后不再生成校验逻辑，保持旧的宽松行为。
+func TestGenerateAxiosFromEndpoints_StrictPathParams(t *testing.T) {
+	apis := buildCommonHTTPTestAPIs()
+
+	code, err := generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "toFormUrlEncoded") {
-		t.Fatalf("expected form-urlencoded helper usage for custom endpoint")
+	if !strings.Contains(code, "const assertRequiredPathParams = (") {
+		t.Fatalf("expected required path param helper generation")
 	}
-	if !strings.Contains(code, `responseType: "text"`) {
-		t.Fatalf("expected text response type for custom endpoint")
+	if !strings.Contains(code, "Missing required path param") {
+		t.Fatalf("expected descriptive error message for missing path params")
 	}
-	if !strings.Contains(code, "params.path?.orderID") {
-		t.Fatalf("expected path param interpolation to use orderID casing")
+	if !strings.Contains(code, "assertRequiredPathParams(") || !strings.Contains(code, "GetPersonByIDGet.pathParamsShape()") {
+		t.Fatalf("expected buildURL to assert path params before building url")
 	}
-}
 
-type wsClientEnvelope struct {
-	Type    string          `json:"type" tsdoc:"消息类型 / Message type"`
-	Payload wsClientPayload `json:"payload" tsdoc:"消息载荷 / Message payload"`
-}
+	oldStrict := TSStrictPathParams
+	SetTSStrictPathParams(false)
+	t.Cleanup(func() {
+		SetTSStrictPathParams(oldStrict)
+	})
 
-type wsClientPayload struct {
-	JoinRoom *wsClientJoinRoomPayload `json:"joinRoom,omitempty" tsdoc:"加入房间消息 / Join room payload"`
-	ChatText *wsClientChatTextPayload `json:"chatText,omitempty" tsdoc:"聊天文本消息 / Chat text payload"`
+	code, err = generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "assertRequiredPathParams") {
+		t.Fatalf("expected no path param assertion when strict mode is disabled")
+	}
 }
 
-type wsClientJoinRoomPayload struct {
-	RoomID   string `json:"roomID" tsdoc:"房间ID / Room identifier"`
-	ClientID string `json:"clientID" tsdoc:"客户端ID / Client identifier"`
+type multiStatusTestEndpoint struct {
+	meta EndpointMeta
 }
 
-type wsClientChatTextPayload struct {
-	RoomID string `json:"roomID" tsdoc:"房间ID / Room identifier"`
-	Text   string `json:"text" tsdoc:"文本内容 / Message text"`
+func (e multiStatusTestEndpoint) EndpointMeta() EndpointMeta { return e.meta }
+
+func (e multiStatusTestEndpoint) GinHandler() gin.HandlerFunc { return func(*gin.Context) {} }
+
+type PersonNotFoundResp struct {
+	Message string `json:"message" tsdoc:"错误信息 / Error message"`
 }
 
-type wsClientNoPayload struct{}
+// TestGenerateAxiosFromEndpoints_ResponseUnionMode
+// 这个测试验证多状态码响应的判别联合返回类型：
+// 1) 默认关闭时，仍只取主 2xx 响应体作为返回类型。
+// 2) 开启 TSResponseUnionMode 后，生成 { status; data } 联合类型，函数按 response.status 分支返回。
+// 3) 每个响应体的 validator/ensure 仍然生成；NoBody 响应映射为 { status: 204; data: void }。
+func TestGenerateAxiosFromEndpoints_ResponseUnionMode(t *testing.T) {
+	apis := []EndpointLike{
+		multiStatusTestEndpoint{meta: EndpointMeta{
+			Name:           "FindPerson",
+			Method:         HTTPMethodGet,
+			Path:           "/person/:id",
+			PathParamsType: reflect.TypeOf(PathByID{}),
+			Responses: []ResponseMeta{
+				{StatusCode: 200, BodyType: reflect.TypeOf(PersonDetailResp{})},
+				{StatusCode: 204, BodyType: reflect.TypeOf(NoBody{})},
+				{StatusCode: 404, BodyType: reflect.TypeOf(PersonNotFoundResp{})},
+			},
+		}},
+	}
 
-type wsServerEnvelope struct {
-	Type    string          `json:"type" tsdoc:"服务端消息类型 / Server message type"`
-	Payload wsServerPayload `json:"payload" tsdoc:"服务端消息载荷 / Server message payload"`
+	code, err := generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "FindPersonGetResponse") {
+		t.Fatalf("expected no response union when union mode is disabled")
+	}
+	if !strings.Contains(code, "Promise<PersonDetailResp>") {
+		t.Fatalf("expected primary 2xx body as return type by default")
+	}
+
+	oldUnion := TSResponseUnionMode
+	SetTSResponseUnionMode(true)
+	t.Cleanup(func() {
+		SetTSResponseUnionMode(oldUnion)
+	})
+
+	code, err = generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export type FindPersonGetResponse =") {
+		t.Fatalf("expected discriminated union response type")
+	}
+	if !strings.Contains(code, "{ status: 200; data: PersonDetailResp }") ||
+		!strings.Contains(code, "{ status: 204; data: void }") ||
+		!strings.Contains(code, "{ status: 404; data: PersonNotFoundResp }") {
+		t.Fatalf("expected union members for every declared status")
+	}
+	if !strings.Contains(code, "Promise<FindPersonGetResponse>") {
+		t.Fatalf("expected union as request return type")
+	}
+	if !strings.Contains(code, "validateStatus: () => true") || !strings.Contains(code, "switch (response.status)") {
+		t.Fatalf("expected request to inspect response.status")
+	}
+	if !strings.Contains(code, "export function validatePersonNotFoundResp(") || !strings.Contains(code, "export function ensurePersonNotFoundResp(") {
+		t.Fatalf("expected validator/ensure for error response body")
+	}
 }
 
-type wsServerPayload struct {
-	Ack       *wsServerAckPayload       `json:"ack,omitempty" tsdoc:"确认消息 / Acknowledgement payload"`
-	Broadcast *wsServerBroadcastPayload `json:"broadcast,omitempty" tsdoc:"广播消息 / Broadcast payload"`
+// TestGenerateAxiosFromEndpoints_ResponseUnionModeDeserialize
+// 这个测试验证联合响应模式与 int64 映射组合时的反序列化：
+// 1) deserializeResponse 作用于每个分支的 data，而不是 { status, data } 整体。
+// 2) options 的响应类型为联合类型的 data 部分，int64 字段按 string 映射输出。
+func TestGenerateAxiosFromEndpoints_ResponseUnionModeDeserialize(t *testing.T) {
+	oldUnion := TSResponseUnionMode
+	oldInt64 := TSInt64MappingMode
+	SetTSResponseUnionMode(true)
+	SetTSInt64MappingMode(TSInt64ModeString)
+	t.Cleanup(func() {
+		SetTSResponseUnionMode(oldUnion)
+		SetTSInt64MappingMode(oldInt64)
+	})
+
+	apis := []EndpointLike{
+		multiStatusTestEndpoint{meta: EndpointMeta{
+			Name:           "FindPerson",
+			Method:         HTTPMethodGet,
+			Path:           "/person/:id",
+			PathParamsType: reflect.TypeOf(PathByID{}),
+			Responses: []ResponseMeta{
+				{StatusCode: 200, BodyType: reflect.TypeOf(PersonDetailResp{})},
+				{StatusCode: 404, BodyType: reflect.TypeOf(PersonNotFoundResp{})},
+			},
+		}},
+	}
+	code, err := generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "{ status: 200; data: PersonDetailResp }") || !strings.Contains(code, "salary: string;") {
+		t.Fatalf("expected union member with int64 mapped to string")
+	}
+	if !strings.Contains(code, "result = { status: 200, data: (options?.deserializeResponse ? options.deserializeResponse(reviveDates(response.data, ['resumes.*.startDate', 'resumes.*.endDate'])) : reviveDates(response.data, ['resumes.*.startDate', 'resumes.*.endDate'])) as PersonDetailResp };") {
+		t.Fatalf("expected deserializeResponse to run on the variant data")
+	}
+	if strings.Contains(code, "options.deserializeResponse(result)") {
+		t.Fatalf("expected union object not to be passed to deserializeResponse")
+	}
+	if !strings.Contains(code, "AxiosConvertOptions<never, FindPersonGetResponse['data']>") {
+		t.Fatalf("expected options typed with the union data types")
+	}
 }
 
-type wsServerAckPayload struct {
-	Accepted bool   `json:"accepted" tsdoc:"是否接受 / Whether accepted"`
-	Reason   string `json:"reason,omitempty" tsdoc:"原因说明 / Reason detail"`
+type EventQueryParams struct {
+	Since time.Time `form:"since" tsdoc:"起始时间 / Lower bound time"`
+	Level string    `form:"level" tsunion:"info,warn" tsdoc:"事件等级 / Event level"`
 }
 
-type wsServerBroadcastPayload struct {
-	FromClientID string `json:"fromClientID" tsdoc:"发送者客户端ID / Sender client identifier"`
-	RoomID       string `json:"roomID" tsdoc:"房间ID / Room identifier"`
-	Level        string `json:"level" tsunion:"warning,success,error" tsdoc:"消息等级 / Message level"`
-	Priority     int    `json:"priority" tsunion:"1,2,3" tsdoc:"优先级 / Priority"`
-	Text         string `json:"text" tsdoc:"广播文本 / Broadcast text"`
+// TestGenerateAxiosFromEndpoints_QueryDateAndEnumSerialization
+// 这个测试验证 query 参数中的 time.Time 与 tsunion 枚举：
+// 1) time.Time 字段在 TS 中为 string，枚举字段生成字面量联合；无 json tag 时属性名取 form 名。
+// 2) normalizeParamKeys 对 query/header/cookie 的每个值执行 normalizeRequestJSON，Date 会变成 ISO 字符串而不是 [object Object]。
+// 3) requestConfig 使用 normalizeParamKeys 的结果作为 params，并按 form 名映射 since/level。
+func TestGenerateAxiosFromEndpoints_QueryDateAndEnumSerialization(t *testing.T) {
+	apis := []EndpointLike{
+		Endpoint[NoParams, EventQueryParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "ListEvents",
+			Method: HTTPMethodGet,
+			Path:   "/events",
+			HandlerFunc: func(_ NoParams, _ EventQueryParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "since: string;") || !strings.Contains(code, "info") || !strings.Contains(code, "warn") {
+		t.Fatalf("expected time.Time query field as string and enum literal union")
+	}
+	if !strings.Contains(code, "normalized[mapped] = normalizeRequestJSON(v);") {
+		t.Fatalf("expected query/header/cookie values to be normalized before serialization")
+	}
+	if !strings.Contains(code, "if (value instanceof Date) return value.toISOString();") {
+		t.Fatalf("expected Date values to serialize to ISO strings")
+	}
+	if !strings.Contains(code, "since") || !strings.Contains(code, "params: normalizedParams.query,") {
+		t.Fatalf("expected query params to use normalized form names")
+	}
 }
 
-func buildCommonWSTestEndpoint() *WebSocketEndpoint {
-	endpoint := &WebSocketEndpoint{
-		Name:              "chat_events",
-		Path:              "/chat/events",
-		ClientMessageType: reflect.TypeOf(wsClientEnvelope{}),
-		ServerMessageType: reflect.TypeOf(wsServerEnvelope{}),
-		MessageTypes:      []string{"room:join", "chat:text", "system:ack"},
+// TestGenerateAxiosFromEndpoints_ParamTagPropertyNames
+// 这个测试验证参数结构体的 TS 属性名解析顺序：
+// 1) 仅有 uri tag 的路径参数结构体使用 uri 名作为属性名，validator 与 buildURL 插值一致。
+// 2) 仅有 form tag 的 query 参数结构体使用 form 名作为属性名。
+// 3) json tag 优先于 uri/form，未打 tag 的字段回退到 Go 字段名。
+func TestGenerateAxiosFromEndpoints_ParamTagPropertyNames(t *testing.T) {
+	type OrderPathParams struct {
+		OrderID string `uri:"order_id"`
+	}
+	type OrderQueryParams struct {
+		PageSize int    `form:"page_size"`
+		Keyword  string `json:"keyword" form:"q"`
+		Verbose  bool
+	}
+	apis := []EndpointLike{
+		Endpoint[OrderPathParams, OrderQueryParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "GetOrder",
+			Method: HTTPMethodGet,
+			Path:   "/orders/:order_id",
+			HandlerFunc: func(_ OrderPathParams, _ OrderQueryParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  order_id: string;") || strings.Contains(code, "OrderID: string;") {
+		t.Fatalf("expected uri-only path field to use its uri name")
+	}
+	if !strings.Contains(code, `obj["order_id"]`) {
+		t.Fatalf("expected validator to check the uri name")
+	}
+	if !strings.Contains(code, "params.path?.order_id ?? ''") {
+		t.Fatalf("expected buildURL to interpolate the uri name")
+	}
+	if !strings.Contains(code, "  page_size: number;") || !strings.Contains(code, `obj["page_size"]`) {
+		t.Fatalf("expected form-only query field to use its form name")
+	}
+	if !strings.Contains(code, "  keyword: string;") || strings.Contains(code, "  q: string;") {
+		t.Fatalf("expected json tag to take precedence over form")
+	}
+	if !strings.Contains(code, "  Verbose: boolean;") {
+		t.Fatalf("expected untagged field to fall back to the Go field name")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_RouteFunctions
+// 这个测试验证独立的 route 函数：
+// 1) 带路径参数的 endpoint 生成 routeX(params)，通过 buildURL 返回包含 basePath 与编码后参数的 URL。
+// 2) 无路径参数的 endpoint 生成无参的 routeX()，返回 FULL_PATH。
+// 3) fetch 客户端同样生成 route 函数。
+func TestGenerateAxiosFromEndpoints_RouteFunctions(t *testing.T) {
+	type OrderPathParams struct {
+		OrderID string `uri:"order_id"`
+	}
+	apis := []EndpointLike{
+		Endpoint[OrderPathParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "GetOrder",
+			Method: HTTPMethodGet,
+			Path:   "/orders/:order_id",
+			HandlerFunc: func(_ OrderPathParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "ListOrders",
+			Method: HTTPMethodGet,
+			Path:   "/orders",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export function routeGetOrderGet(params: {") || !strings.Contains(code, "  return GetOrderGet.buildURL(params);\n}") {
+		t.Fatalf("expected routeGetOrderGet to delegate to buildURL")
+	}
+	if !strings.Contains(code, "return `/api/orders/${encodeURIComponent(String(params.path?.order_id ?? ''))}`;") {
+		t.Fatalf("expected buildURL to encode the path param")
+	}
+	if !strings.Contains(code, "export function routeListOrdersGet(): string {\n  return ListOrdersGet.buildURL();\n}") {
+		t.Fatalf("expected param-less routeListOrdersGet")
+	}
+
+	fetchCode, err := generateFetchFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("generateFetchFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(fetchCode, "export function routeGetOrderGet(params: {") {
+		t.Fatalf("expected fetch client to generate route functions")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_CallerHeaders
+// 这个测试验证调用方临时请求头：
+// 1) AxiosConvertOptions / FetchConvertOptions 包含 headers?: Record<string, string>。
+// 2) 已声明 header 参数的请求在 header 参数（及 Content-Type）之后合并 options.headers。
+// 3) 未声明 header 的请求仅在传入 options.headers 时设置 headers。
+func TestGenerateAxiosFromEndpoints_CallerHeaders(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  headers?: Record<string, string>;\n}") {
+		t.Fatalf("expected AxiosConvertOptions to declare caller headers")
+	}
+	if !strings.Contains(code, "      headers: { ...headers, ...(options?.headers ?? {}) },\n") {
+		t.Fatalf("expected caller headers to be merged after header params and Content-Type")
+	}
+	if !strings.Contains(code, "      ...(options?.headers ? { headers: options.headers } : {}),\n") {
+		t.Fatalf("expected endpoints without header params to accept caller headers")
+	}
+
+	fetchCode, err := generateFetchFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateFetchFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(fetchCode, "  headers?: Record<string, string>;\n}") || !strings.Contains(fetchCode, "        headers: { ...headers, ...(options?.headers ?? {}) },\n") {
+		t.Fatalf("expected fetch client to merge caller headers")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_DefaultHeaders
+// 这个测试验证全局默认请求头：
+// 1) 生成 setDefaultHeaders(headers)，用于一次性设置 Accept-Language 等请求头。
+// 2) 归一化请求拦截器只在请求未设置该请求头时写入默认值，header 参数与 options.headers 优先。
+func TestGenerateAxiosFromEndpoints_DefaultHeaders(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export const setDefaultHeaders = (headers: Record<string, string>): void => {\n  defaultHeaders = { ...headers };\n};") {
+		t.Fatalf("expected setDefaultHeaders to be exported")
+	}
+	interceptor := "  instance.interceptors.request.use((config) => {\n"
+	idx := strings.Index(code, interceptor)
+	if idx < 0 {
+		t.Fatalf("expected request interceptor")
+	}
+	if !strings.Contains(code[idx:], "    for (const [key, value] of Object.entries(defaultHeaders)) {\n      if (!config.headers.has(key)) config.headers.set(key, value);\n    }\n    return config;\n") {
+		t.Fatalf("expected interceptor to apply default headers without overriding request headers")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_DevDescribeHelpers
+// 这个测试验证开发用的请求体描述函数：
+// 1) 默认关闭，不生成 describeX，避免增大生产包体积。
+// 2) 开启后为请求体类型生成 describeGetPersonReq，包含字段名、TS 类型、可选性与 tsdoc 描述。
+// 3) 仅用于响应体的类型不生成 describe 函数。
+func TestGenerateAxiosFromEndpoints_DevDescribeHelpers(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "describeGetPersonReq") || strings.Contains(code, "TSFieldDescriptor") {
+		t.Fatalf("expected describe helpers to be off by default")
+	}
+
+	oldDescribe := TSDevDescribeHelpers
+	SetTSDevDescribeHelpers(true)
+	t.Cleanup(func() {
+		SetTSDevDescribeHelpers(oldDescribe)
+	})
+
+	code, err = generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface TSFieldDescriptor") {
+		t.Fatalf("expected TSFieldDescriptor runtime type")
+	}
+	if !strings.Contains(code, "export function describeGetPersonReq(): TSFieldDescriptor[]") {
+		t.Fatalf("expected describe helper for request body type")
+	}
+	idx := strings.Index(code, "export function describeGetPersonReq(")
+	describe := code[idx:]
+	if end := strings.Index(describe, "\n}\n"); end >= 0 {
+		describe = describe[:end]
+	}
+	for _, want := range []string{"personID", "retryAfter", "traceID", "optional: false", "optional: true", "Person identifier"} {
+		if !strings.Contains(describe, want) {
+			t.Fatalf("expected describe helper to contain %q, got:\n%s", want, describe)
+		}
+	}
+	if strings.Contains(code, "describePersonDetailResp") {
+		t.Fatalf("expected no describe helper for response-only types")
+	}
+}
+
+type NestedCollectionsResp struct {
+	Counts []map[string]int              `json:"counts" tsdoc:"按维度计数 / Counts per dimension"`
+	Groups map[string][]PersonDetailResp `json:"groups" tsdoc:"分组人员 / People by group"`
+	Extras []map[string]any              `json:"extras"`
+}
+
+// TestTSValidatorExprFromType_NestedCollections
+// 这个测试验证嵌套集合的校验表达式：
+// 1) []map[string]int：数组的每个元素都按 string 键记录校验，记录的每个值再校验为 number。
+// 2) map[string][]PersonDetailResp：记录的每个值都是数组，数组元素调用 validatePersonDetailResp。
+// 3) 每层使用不同的变量名（v1/v2），不会发生遮蔽；元素无法校验时不输出多余的 every(() => true)。
+func TestTSValidatorExprFromType_NestedCollections(t *testing.T) {
+	registry := newTSInterfaceRegistry()
+
+	expr, err := tsValidatorExprFromType(reflect.TypeOf([]map[string]int{}), "obj[\"counts\"]", registry, 0)
+	if err != nil {
+		t.Fatalf("tsValidatorExprFromType returned error: %v", err)
+	}
+	want := "Array.isArray(obj[\"counts\"]) && obj[\"counts\"].every((v1) => isPlainObject(v1) && Object.values(v1).every((v2) => typeof v2 === 'number'))"
+	if expr != want {
+		t.Fatalf("unexpected validator for []map[string]int:\n got: %s\nwant: %s", expr, want)
+	}
+
+	expr, err = tsValidatorExprFromType(reflect.TypeOf(map[string][]PersonDetailResp{}), "obj[\"groups\"]", registry, 0)
+	if err != nil {
+		t.Fatalf("tsValidatorExprFromType returned error: %v", err)
+	}
+	want = "isPlainObject(obj[\"groups\"]) && Object.values(obj[\"groups\"]).every((v1) => Array.isArray(v1) && v1.every((v2) => validatePersonDetailResp(v2)))"
+	if expr != want {
+		t.Fatalf("unexpected validator for map[string][]PersonDetailResp:\n got: %s\nwant: %s", expr, want)
+	}
+
+	expr, err = tsValidatorExprFromType(reflect.TypeOf([]map[string]any{}), "obj[\"extras\"]", registry, 0)
+	if err != nil {
+		t.Fatalf("tsValidatorExprFromType returned error: %v", err)
+	}
+	want = "Array.isArray(obj[\"extras\"]) && obj[\"extras\"].every((v1) => isPlainObject(v1))"
+	if expr != want {
+		t.Fatalf("unexpected validator for []map[string]any:\n got: %s\nwant: %s", expr, want)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, NestedCollectionsResp]{
+			Name:   "GetNestedCollections",
+			Method: HTTPMethodGet,
+			Path:   "/nested",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[NestedCollectionsResp], error) {
+				return Response[NestedCollectionsResp]{StatusCode: 200}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "Record<string, number>[]") || !strings.Contains(code, "Record<string, PersonDetailResp[]>") {
+		t.Fatalf("expected nested collection interface fields")
+	}
+	if !strings.Contains(code, "export function validateNestedCollectionsResp(") || !strings.Contains(code, "validatePersonDetailResp(v2)") {
+		t.Fatalf("expected nested collection validator to reach element validators")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_TypeOverrides
+// 这个测试验证显式类型覆盖：
+// 1) CustomEndpoint 的 ResponseTypeOverride 会替换泛型推断出的响应类型，生成对应 interface 与返回类型。
+// 2) RequestTypeOverride 同样作用于请求体类型。
+// 3) 无法序列化的覆盖类型（如 func）在生成前被校验拒绝。
+func TestGenerateAxiosFromEndpoints_TypeOverrides(t *testing.T) {
+	custom := NewCustomEndpoint[NoParams, NoParams, NoParams, NoParams, any, any](
+		"RawPerson",
+		HTTPMethodPost,
+		"/raw/person",
+		func(ctx *gin.Context) {},
+	)
+	custom.RequestTypeOverride = reflect.TypeOf(GetPersonReq{})
+	custom.ResponseTypeOverride = reflect.TypeOf(PersonDetailResp{})
+
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{custom})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface PersonDetailResp") || !strings.Contains(code, "Promise<PersonDetailResp>") {
+		t.Fatalf("expected response override to drive generated response type")
+	}
+	if !strings.Contains(code, "export interface GetPersonReq") || !strings.Contains(code, "requestBody: GetPersonReq") {
+		t.Fatalf("expected request override to drive generated request type")
+	}
+
+	typed := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, map[string]any]{
+		Name:                 "TypedOverride",
+		Method:               HTTPMethodGet,
+		Path:                 "/typed/override",
+		ResponseTypeOverride: reflect.TypeOf(PersonNotFoundResp{}),
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[map[string]any], error) {
+			return Response[map[string]any]{StatusCode: 200}, nil
+		},
+	}
+	code, err = generateAxiosFromEndpoints("/api", "", []EndpointLike{typed})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "Promise<PersonNotFoundResp>") {
+		t.Fatalf("expected response override on Endpoint")
+	}
+
+	typed.ResponseTypeOverride = reflect.TypeOf(func() {})
+	if _, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{typed}); err == nil {
+		t.Fatalf("expected unusable override type to be rejected")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_FormatAPIErrorHelper
+// 这个测试验证错误消息格式化函数：
+// 1) 默认关闭，不生成 formatApiError。
+// 2) 开启后优先从错误响应体读取消息字段（默认 message，可通过 messageField 配置）。
+// 3) 响应体没有消息时回退为 HTTP 状态文本，非 HTTP 错误回退为 Error.message 或兜底文案。
+func TestGenerateAxiosFromEndpoints_FormatAPIErrorHelper(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "formatApiError") {
+		t.Fatalf("expected formatApiError to be off by default")
+	}
+
+	oldHelper := TSFormatAPIErrorHelper
+	SetTSFormatAPIErrorHelper(true)
+	t.Cleanup(func() {
+		SetTSFormatAPIErrorHelper(oldHelper)
+	})
+
+	code, err = generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export const formatApiError = (error: unknown, options?: FormatApiErrorOptions): string =>") {
+		t.Fatalf("expected formatApiError helper")
+	}
+	if !strings.Contains(code, "messageField?: string;") || !strings.Contains(code, "const message = body[field];") {
+		t.Fatalf("expected message to be read from a configurable error body field")
+	}
+	if !strings.Contains(code, "axios.isAxiosError(error)") || !strings.Contains(code, "response.statusText") {
+		t.Fatalf("expected fallback to HTTP status text")
+	}
+	if !strings.Contains(code, "if (error instanceof Error && error.message) return error.message;") || !strings.Contains(code, "return fallback;") {
+		t.Fatalf("expected fallback for unknown errors")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ResolveHttpBaseURL
+// 这个测试验证 HTTP 请求地址解析：
+// 1) 与 WS 客户端一致，浏览器开发模式下读取 import.meta.env.DEV 与 runtimeConfig.public.ginPort 拼接 Gin 地址。
+// 2) 已是 http(s):// 的绝对地址保持不变，服务端回退为编译期路径。
+// 3) 每个 requestConfig 的 url 都经过 resolveHttpBaseURL。
+// 4) 通过 setAxiosClient 注入且带 baseURL 的实例优先，开发模式不再改写地址。
+func TestGenerateAxiosFromEndpoints_ResolveHttpBaseURL(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "const resolveHttpBaseURL = (url: string): string =>") {
+		t.Fatalf("expected resolveHttpBaseURL helper")
+	}
+	if !strings.Contains(code, "useRuntimeConfig().public.ginPort") || !strings.Contains(code, "(import.meta as any).env?.DEV") {
+		t.Fatalf("expected dev-mode ginPort resolution")
+	}
+	if !strings.Contains(code, "window.location.hostname}:${resolveGinPort()}${url}") {
+		t.Fatalf("expected dev-mode host with ginPort")
+	}
+	if !strings.Contains(code, "url.startsWith('http://') || url.startsWith('https://')") {
+		t.Fatalf("expected absolute URLs to be kept as-is")
+	}
+	baseIdx := strings.Index(code, "if (axiosClient.defaults.baseURL) return url;")
+	devIdx := strings.Index(code, "window.location.hostname}:${resolveGinPort()}${url}")
+	if baseIdx < 0 || baseIdx > devIdx {
+		t.Fatalf("expected an injected client's baseURL to skip the dev rewrite")
+	}
+	if !strings.Contains(code, "url: resolveHttpBaseURL(url),") || strings.Contains(code, "      url,\n") {
+		t.Fatalf("expected every requestConfig url to go through resolveHttpBaseURL")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_AbortSignal
+// 这个测试验证请求取消：
+// 1) AxiosConvertOptions 暴露 signal?: AbortSignal。
+// 2) requestConfig 无论是否有请求体都接收 options，并把 signal 透传给 axios。
+// 3) request 静态方法与导出函数都把 options 传给 requestConfig。
+func TestGenerateAxiosFromEndpoints_AbortSignal(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "signal?: AbortSignal;") {
+		t.Fatalf("expected signal option on AxiosConvertOptions")
+	}
+	if !strings.Contains(code, "signal: options?.signal,") {
+		t.Fatalf("expected signal to be passed to axios request config")
+	}
+	if !strings.Contains(code, "options?: AxiosConvertOptions<never, PersonDetailResp>): AxiosRequestConfig {") {
+		t.Fatalf("expected requestConfig without body to accept options")
+	}
+	if !strings.Contains(code, "GetPersonByIDGet.requestConfig(params, options)") {
+		t.Fatalf("expected request to pass options to requestConfig")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ResponseCache
+// 这个测试验证响应缓存：
+// 1) 默认关闭，不生成 cached。
+// 2) 开启后为 GET 端点生成 cached.xxx(params, { ttlMs })，以端点 NAME 与参数生成缓存键。
+// 3) TTL 内直接返回缓存，不再调用 request；失败的请求不会被缓存。
+// 4) 提供 invalidateCachedResponses 清除全部、单个端点或单个参数的缓存。
+func TestGenerateAxiosFromEndpoints_ResponseCache(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "export const cached = {") {
+		t.Fatalf("expected response cache to be off by default")
+	}
+
+	oldCache := TSResponseCacheHelpers
+	SetTSResponseCacheHelpers(true)
+	t.Cleanup(func() {
+		SetTSResponseCacheHelpers(oldCache)
+	})
+
+	code, err = generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "getPersonByID: (params:") || !strings.Contains(code, "options?: CachedRequestOptions): Promise<PersonDetailResp> =>") {
+		t.Fatalf("expected cached wrapper for GET endpoint")
+	}
+	if !strings.Contains(code, "cachedRequest(GetPersonByIDGet.NAME, params, options, () => GetPersonByIDGet.request(params))") {
+		t.Fatalf("expected cached wrapper keyed by endpoint NAME and params")
+	}
+	if strings.Contains(code, "cachedRequest(GetPersonDetailPost.NAME") {
+		t.Fatalf("expected non-GET endpoints not to be cached")
+	}
+	if !strings.Contains(code, "if (hit && hit.expiresAt > now) return hit.value as Promise<T>;") {
+		t.Fatalf("expected cached value to be returned within TTL")
+	}
+	if !strings.Contains(code, "options?.ttlMs ?? 60000") || !strings.Contains(code, "responseCache.delete(key)") {
+		t.Fatalf("expected TTL default and failed requests to be evicted")
+	}
+	if !strings.Contains(code, "export const invalidateCachedResponses = (name?: string, params?: unknown): void =>") ||
+		!strings.Contains(code, "responseCache.clear();") {
+		t.Fatalf("expected cache invalidation helper")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_OfflineMutationQueue
+// 这个测试验证离线请求队列（需显式开启）：
+// 1) 默认不生成 offlineQueue。
+// 2) 只为非 GET 的请求生成带类型的 payload 与发送函数。
+// 3) 离线时 send 将请求连同幂等键写入 localStorage 队列。
+// 4) 恢复联网（online 事件）时按顺序重放，并携带原幂等键作为 Idempotency-Key 请求头。
+// 5) 提供 onConflict / onReplayed 钩子。
+func TestGenerateAxiosFromEndpoints_OfflineMutationQueue(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "offlineQueue") {
+		t.Fatalf("expected offline queue to be off by default")
+	}
+
+	old := TSOfflineMutationQueue
+	SetTSOfflineMutationQueue(true)
+	t.Cleanup(func() {
+		SetTSOfflineMutationQueue(old)
+	})
+	code, err = generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface OfflineMutationPayloads {\n  getPersonDetail: { requestBody: GetPersonReq };\n}") {
+		t.Fatalf("expected typed payloads for mutations only")
+	}
+	if !strings.Contains(code, "    const config = GetPersonDetailPost.requestConfig(payload.requestBody);\n") ||
+		!strings.Contains(code, "headers: { ...(config.headers ?? {}), 'Idempotency-Key': idempotencyKey }") {
+		t.Fatalf("expected senders to reuse requestConfig with the idempotency key header")
+	}
+	if !strings.Contains(code, "        if (!isOfflineError(error)) throw error;\n") ||
+		!strings.Contains(code, "    const mutation: OfflineMutation<K> = { id: idempotencyKey, name, payload, queuedAt: Date.now(), attempts: 0 };\n") ||
+		!strings.Contains(code, "localStorage.setItem(OFFLINE_QUEUE_STORAGE_KEY, JSON.stringify(queue));") {
+		t.Fatalf("expected offline sends to be persisted with their idempotency key")
+	}
+	if !strings.Contains(code, "  window.addEventListener('online', () => {\n    void offlineQueue.replay();\n") ||
+		!strings.Contains(code, "          const data = await sender(mutation.payload, mutation.id);\n") {
+		t.Fatalf("expected queued mutations to be replayed with their idempotency key when back online")
+	}
+	if !strings.Contains(code, "onConflict?: (mutation: OfflineMutation, error: unknown) =>") || !strings.Contains(code, "offlineQueueHooks.onReplayed?.(mutation, data);") {
+		t.Fatalf("expected conflict and replay hooks")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_MultipartUpload
+// 这个测试验证 multipart/form-data 上传：
+// 1) 请求体为 FormData 标记类型时自动按 TSKindMultipart 生成，无需显式 RequestKind。
+// 2) requestBody 经 toFormData 原样作为 FormData 发送，不设置 Content-Type，由浏览器补充 boundary。
+// 3) 请求拦截器对 FormData 跳过 normalizeRequestJSON。
+// 4) 没有 multipart 端点时不输出 isFormData/toFormData。
+func TestGenerateAxiosFromEndpoints_MultipartUpload(t *testing.T) {
+	upload := CustomEndpoint[NoParams, NoParams, NoParams, NoParams, FormData, PersonDetailResp]{
+		Name:        "UploadAvatar",
+		Method:      HTTPMethodPost,
+		Path:        "/avatar",
+		HandlerFunc: func(_ *gin.Context) {},
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{upload})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "requestBody: FormData") {
+		t.Fatalf("expected FormData request body type")
+	}
+	if !strings.Contains(code, "const requestData = toFormData(serializedRequest);") {
+		t.Fatalf("expected request body to be sent as FormData")
+	}
+	if strings.Contains(code, "multipart/form-data") || strings.Contains(code, "const requestHeaders") {
+		t.Fatalf("expected Content-Type to be left to the browser for multipart uploads")
+	}
+	if !strings.Contains(code, "!isFormData(config.data)") {
+		t.Fatalf("expected normalizeRequestJSON to be skipped for FormData")
+	}
+	if kind, _ := resolveEndpointKinds(upload); kind != TSKindMultipart {
+		t.Fatalf("expected FormData body to resolve to multipart, got %q", kind)
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "isFormData") || strings.Contains(plain, "toFormData") {
+		t.Fatalf("expected no FormData helpers without multipart endpoints")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ProgressCallbacks
+// 这个测试验证进度回调：
+// 1) AxiosConvertOptions 暴露可选的 onUploadProgress / onDownloadProgress。
+// 2) 仅在调用方传入时才展开到 axios 配置中；onUploadProgress 只对有请求体的端点生成。
+func TestGenerateAxiosFromEndpoints_ProgressCallbacks(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "onUploadProgress?: (event: AxiosProgressEvent) => void;") ||
+		!strings.Contains(code, "onDownloadProgress?: (event: AxiosProgressEvent) => void;") {
+		t.Fatalf("expected progress callbacks on AxiosConvertOptions")
+	}
+	if !strings.Contains(code, "type AxiosProgressEvent") {
+		t.Fatalf("expected AxiosProgressEvent type import")
+	}
+	if !strings.Contains(code, "...(options?.onDownloadProgress ? { onDownloadProgress: options.onDownloadProgress } : {}),") {
+		t.Fatalf("expected download progress to be spread only when provided")
+	}
+	if strings.Count(code, "onUploadProgress: options.onUploadProgress") != 1 {
+		t.Fatalf("expected upload progress only for the endpoint with a request body")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_SetAxiosClient
+// 这个测试验证注入共享 axios 实例：
+// 1) 导出 setAxiosClient / getAxiosClient。
+// 2) axiosClient 为可变绑定，生成的请求在调用时读取当前实例；默认仍为内部 axios.create()。
+// 3) 归一化拦截器会应用到注入的实例上，且同一实例只添加一次。
+func TestGenerateAxiosFromEndpoints_SetAxiosClient(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export const setAxiosClient = (instance: AxiosInstance): void =>") ||
+		!strings.Contains(code, "export const getAxiosClient = (): AxiosInstance => axiosClient;") {
+		t.Fatalf("expected setAxiosClient/getAxiosClient exports")
+	}
+	if !strings.Contains(code, "let axiosClient: AxiosInstance = axios.create();") || strings.Contains(code, "const axiosClient =") {
+		t.Fatalf("expected a mutable default axios client")
+	}
+	if !strings.Contains(code, "await axiosClient.request<") {
+		t.Fatalf("expected generated requests to reference the mutable client")
+	}
+	if !strings.Contains(code, "applyNormalizationInterceptors(instance);") || !strings.Contains(code, "if (normalizedClients.has(instance)) return;") {
+		t.Fatalf("expected normalization interceptors to be applied once to injected instances")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_VueComposables
+// 这个测试验证 Vue 组合式函数输出：
+// 1) 开启后每个端点生成 useXxx()，返回 { data, error, loading, execute } 并复用类的 request 签名。
+// 2) 仅在开启时导入 vue 的 ref/shallowRef；默认输出不包含 vue 导入与组合式函数。
+func TestGenerateAxiosFromEndpoints_VueComposables(t *testing.T) {
+	code, err := generateAxiosFromEndpointsWithOptions("/api/v1", "", buildCommonHTTPTestAPIs(), axiosRenderOptions{VueComposables: true})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "import { ref, shallowRef, type Ref } from 'vue';") {
+		t.Fatalf("expected vue import when composables are emitted")
+	}
+	if !strings.Contains(code, "export function useGetPersonByID(): UseRequestReturn<Parameters<typeof GetPersonByIDGet.request>,") {
+		t.Fatalf("expected typed useGetPersonByID composable")
+	}
+	if !strings.Contains(code, "return useRequest((...args: Parameters<typeof GetPersonDetailPost.request>) => GetPersonDetailPost.request(...args));") {
+		t.Fatalf("expected composable to wrap the endpoint class request")
+	}
+	if !strings.Contains(code, "return { data, error, loading, execute };") {
+		t.Fatalf("expected composable state shape")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api/v1", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "from 'vue'") || strings.Contains(plain, "useRequest") {
+		t.Fatalf("expected no vue output by default")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_TanstackQuery
+// 这个测试验证 TanStack Query hooks 输出：
+// 1) GET 端点生成 useXxxQuery，queryKey 由导出的 apiQueryKey(NAME, params) 生成，并透传 signal。
+// 2) 非 GET 端点生成 useXxxMutation，mutationFn 调用端点类的 request。
+// 3) 可指定 hooks 所在的包；默认不输出任何 TanStack 代码。
+func TestGenerateAxiosFromEndpoints_TanstackQuery(t *testing.T) {
+	code, err := generateAxiosFromEndpointsWithOptions("/api/v1", "", buildCommonHTTPTestAPIs(), axiosRenderOptions{TanstackQuery: true})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "import { useMutation, useQuery } from '@tanstack/vue-query';") {
+		t.Fatalf("expected vue-query import by default")
+	}
+	if !strings.Contains(code, "export const apiQueryKey = (name: string, params?: unknown): readonly unknown[] =>") {
+		t.Fatalf("expected exported query key function")
+	}
+	if !strings.Contains(code, "export function useGetPersonByIDQuery(params: ") ||
+		!strings.Contains(code, "queryKey: apiQueryKey(GetPersonByIDGet.NAME, params),") ||
+		!strings.Contains(code, "GetPersonByIDGet.request(params, { signal })") {
+		t.Fatalf("expected GET endpoint query hook keyed by NAME + params")
+	}
+	if !strings.Contains(code, "export function useGetPersonDetailMutation() {") ||
+		!strings.Contains(code, "mutationFn: (requestBody: GetPersonReq) => GetPersonDetailPost.request(requestBody),") {
+		t.Fatalf("expected POST endpoint mutation hook")
+	}
+
+	code, err = generateAxiosFromEndpointsWithOptions("/api/v1", "", buildCommonHTTPTestAPIs(), axiosRenderOptions{
+		TanstackQuery:       true,
+		TanstackQueryModule: "@tanstack/react-query",
+	})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "from '@tanstack/react-query';") {
+		t.Fatalf("expected configurable hooks module")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api/v1", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "@tanstack") || strings.Contains(plain, "apiQueryKey") {
+		t.Fatalf("expected no TanStack output by default")
+	}
+}
+
+type ZodTreeNode struct {
+	Name     string        `json:"name"`
+	Children []ZodTreeNode `json:"children"`
+}
+
+// TestGenerateAxiosFromEndpoints_ZodSchemas
+// 这个测试验证 Zod schema 生成：
+// 1) 开启 ZodSchemas 后导入 zod，并为每个注册的接口生成 XSchema 常量。
+// 2) tsunion 字段生成 z.literal/z.union，omitempty 字段追加 .optional()。
+// 3) 嵌套具名类型先于父类型输出并直接引用；递归类型使用 z.lazy 并带上类型标注。
+// 4) 默认不输出任何 zod 代码。
+func TestGenerateAxiosFromEndpoints_ZodSchemas(t *testing.T) {
+	endpoints := append(buildCommonHTTPTestAPIs(), Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, ZodTreeNode]{
+		Name:   "GetTree",
+		Method: HTTPMethodGet,
+		Path:   "/tree",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[ZodTreeNode], error) {
+			return Response[ZodTreeNode]{StatusCode: 200}, nil
+		},
+	})
+	code, err := generateAxiosFromEndpointsWithOptions("/api/v1", "", endpoints, axiosRenderOptions{ZodSchemas: true})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "import { z } from 'zod';") {
+		t.Fatalf("expected zod import")
+	}
+	if !strings.Contains(code, "level: z.union([z.literal('warning'), z.literal('success'), z.literal('error')]),") ||
+		!strings.Contains(code, "retryAfter: z.union([z.literal(0), z.literal(5), z.literal(30)]),") {
+		t.Fatalf("expected tsunion fields as zod literals")
+	}
+	if !strings.Contains(code, "traceID: z.string().optional(),") {
+		t.Fatalf("expected omitempty field as optional")
+	}
+	resumeIdx := strings.Index(code, "export const ResumeItemSchema = z.object({")
+	personIdx := strings.Index(code, "export const PersonDetailRespSchema = z.object({")
+	if resumeIdx < 0 || personIdx < 0 || resumeIdx > personIdx {
+		t.Fatalf("expected nested schema before its parent")
+	}
+	if !strings.Contains(code, "resumes: z.array(ResumeItemSchema),") || !strings.Contains(code, "salary: z.number(),") {
+		t.Fatalf("expected nested named schema reference and int64 number")
+	}
+	if !strings.Contains(code, "export const ZodTreeNodeSchema: z.ZodType<ZodTreeNode> = z.object({") ||
+		!strings.Contains(code, "children: z.array(z.lazy(() => ZodTreeNodeSchema)),") {
+		t.Fatalf("expected recursive schema via z.lazy with annotation")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api/v1", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "from 'zod'") || strings.Contains(plain, "Schema = z.object(") {
+		t.Fatalf("expected no zod output by default")
+	}
+}
+
+type InlineTinyTag struct {
+	Label string `json:"label"`
+	Color string `json:"color,omitempty"`
+}
+
+type InlineSharedMeta struct {
+	Source string `json:"source"`
+}
+
+type InlineOrderResp struct {
+	ID   string           `json:"id"`
+	Tag  InlineTinyTag    `json:"tag"`
+	Meta InlineSharedMeta `json:"meta"`
+}
+
+type InlineInvoiceResp struct {
+	ID   string           `json:"id"`
+	Meta InlineSharedMeta `json:"meta"`
+}
+
+// TestGenerateAxiosFromEndpoints_InlineSmallStructs
+// 这个测试验证小结构体内联：
+// 1) 仅被一个端点使用、字段数不超过上限的嵌套结构体内联为匿名对象类型，不再生成顶层 interface。
+// 2) 被多个端点共用的结构体、端点顶层响应类型仍保持具名 interface。
+// 3) 父类型的 validator 对内联字段逐字段校验，而不是调用不存在的 validateX。
+// 4) 默认（上限为 0）不内联。
+func TestGenerateAxiosFromEndpoints_InlineSmallStructs(t *testing.T) {
+	old := TSInlineStructMaxFields
+	t.Cleanup(func() { SetTSInlineStructMaxFields(old) })
+
+	endpoints := []EndpointLike{
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, InlineOrderResp]{
+			Name:   "GetOrder",
+			Method: HTTPMethodGet,
+			Path:   "/order",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[InlineOrderResp], error) {
+				return Response[InlineOrderResp]{StatusCode: 200}, nil
+			},
+		},
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, InlineInvoiceResp]{
+			Name:   "GetInvoice",
+			Method: HTTPMethodGet,
+			Path:   "/invoice",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[InlineInvoiceResp], error) {
+				return Response[InlineInvoiceResp]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	SetTSInlineStructMaxFields(2)
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "export interface InlineTinyTag {") || strings.Contains(code, "validateInlineTinyTag(") {
+		t.Fatalf("expected single-use small struct to be inlined")
+	}
+	if !strings.Contains(code, "  tag: {\n  label: string;\n  color?: string;\n},\n") {
+		t.Fatalf("expected inlined object type for tag")
+	}
+	if !strings.Contains(code, "export interface InlineSharedMeta {") || !strings.Contains(code, "validateInlineSharedMeta(obj[\"meta\"])") {
+		t.Fatalf("expected shared struct to stay named")
+	}
+	if !strings.Contains(code, "export interface InlineOrderResp {") {
+		t.Fatalf("expected top-level response type to stay named")
+	}
+	if !strings.Contains(code, `"label" in (obj["tag"] as Record<string, unknown>) && (typeof (obj["tag"] as Record<string, unknown>)["label"] === 'string')`) ||
+		!strings.Contains(code, `((obj["tag"] as Record<string, unknown>)["color"] === undefined || (typeof (obj["tag"] as Record<string, unknown>)["color"] === 'string'))`) {
+		t.Fatalf("expected inline validation expression for inlined struct")
+	}
+
+	SetTSInlineStructMaxFields(0)
+	code, err = generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface InlineTinyTag {") {
+		t.Fatalf("expected no inlining by default")
+	}
+}
+
+type MockLinkedNode struct {
+	Value string          `json:"value"`
+	Next  *MockLinkedNode `json:"next,omitempty"`
+	Tags  map[string]int  `json:"tags"`
+}
+
+// TestGenerateAxiosFromEndpoints_Mocks
+// 这个测试验证 mock 工厂函数生成：
+// 1) 开启 Mocks 后为每个接口生成 mockX(overrides?: Partial<X>): X，并以 ...overrides 覆盖默认值。
+// 2) 默认值是确定的：空字符串 / 0 / false / [] / {}，时间字段为固定的 ISO 字符串，tsunion 取第一个字面量，omitempty 字段也会填充。
+// 3) 嵌套具名类型调用对应的 mockY()；会回到自身的字段不再递归调用，避免运行时无限递归。
+// 4) 默认不生成 mock。
+func TestGenerateAxiosFromEndpoints_Mocks(t *testing.T) {
+	endpoints := append(buildCommonHTTPTestAPIs(), Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, MockLinkedNode]{
+		Name:   "GetNode",
+		Method: HTTPMethodGet,
+		Path:   "/node",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[MockLinkedNode], error) {
+			return Response[MockLinkedNode]{StatusCode: 200}, nil
+		},
+	})
+	code, err := generateAxiosFromEndpointsWithOptions("/api/v1", "", endpoints, axiosRenderOptions{Mocks: true})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "export function mockPersonDetailResp(overrides?: Partial<PersonDetailResp>): PersonDetailResp {") ||
+		!strings.Contains(code, "    personID: '',\n    salary: 0,\n    resumes: [],\n    ...overrides,\n") {
+		t.Fatalf("expected deterministic PersonDetailResp mock")
+	}
+	if !strings.Contains(code, "    startDate: '1970-01-01T00:00:00.000Z',\n") {
+		t.Fatalf("expected fixed timestamp for time fields")
+	}
+	if !strings.Contains(code, "    level: 'warning',\n") || !strings.Contains(code, "    retryAfter: 0,\n") ||
+		!strings.Contains(code, "    canFallback: true,\n") || !strings.Contains(code, "    traceID: '',\n") {
+		t.Fatalf("expected first tsunion literal and populated omitempty fields")
+	}
+	if !strings.Contains(code, "    next: {} as MockLinkedNode,\n") || !strings.Contains(code, "    tags: {},\n") {
+		t.Fatalf("expected self reference not to recurse and maps to be empty objects")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api/v1", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "export function mock") {
+		t.Fatalf("expected no mocks by default")
+	}
+}
+
+type BatchUpsertItem struct {
+	SKU   string `json:"sku"`
+	Count int    `json:"count"`
+}
+
+type BatchUpsertError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// TestGenerateAxiosFromEndpoints_BatchResults
+// 这个测试验证批量接口（207 风格）的生成：
+// 1) Batch: true 的 endpoint 响应类型为 Array<{ index; status; data?: T; error?: E }>，不生成泛型实例化的接口名。
+// 2) 生成 splitBatchResults 辅助函数，用于拆分成功项与失败项；无批量接口时不生成。
+// 3) Batch 声明的响应体不是 []BatchItemResult 时返回错误。
+func TestGenerateAxiosFromEndpoints_BatchResults(t *testing.T) {
+	batch := Endpoint[NoParams, NoParams, NoParams, NoParams, []BatchUpsertItem, []BatchItemResult[BatchUpsertItem, BatchUpsertError]]{
+		Name:   "BatchUpsert",
+		Method: HTTPMethodPost,
+		Path:   "/items/batch",
+		Batch:  true,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, items []BatchUpsertItem, _ *gin.Context) (Response[[]BatchItemResult[BatchUpsertItem, BatchUpsertError]], error) {
+			results := make([]BatchItemResult[BatchUpsertItem, BatchUpsertError], 0, len(items))
+			for i, item := range items {
+				results = append(results, BatchItemOK[BatchUpsertItem, BatchUpsertError](i, 200, item))
+			}
+			return NewBatchResponse(results), nil
+		},
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{batch})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "Promise<Array<{ index: number; status: number; data?: BatchUpsertItem; error?: BatchUpsertError }>>") {
+		t.Fatalf("expected per-item batch result type")
+	}
+	if !strings.Contains(code, "export interface BatchUpsertError {") || strings.Contains(code, "interface BatchItemResult") {
+		t.Fatalf("expected data/error interfaces without a generic BatchItemResult interface")
+	}
+	if !strings.Contains(code, "export function splitBatchResults<T, E>(") {
+		t.Fatalf("expected splitBatchResults helper")
+	}
+
+	plain, err := generateAxiosFromEndpoints("/api", "", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(plain, "splitBatchResults") {
+		t.Fatalf("expected no batch helper without batch endpoints")
+	}
+
+	invalid := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, []BatchUpsertItem]{
+		Name:   "BadBatch",
+		Method: HTTPMethodPost,
+		Path:   "/items/bad-batch",
+		Batch:  true,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[[]BatchUpsertItem], error) {
+			return Response[[]BatchUpsertItem]{StatusCode: 200}, nil
+		},
+	}
+	if _, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{invalid}); err == nil {
+		t.Fatalf("expected error for batch endpoint without []BatchItemResult body")
+	}
+}
+
+type ReleaseResp struct {
+	Version     string    `json:"version"`
+	ReleasedAt  time.Time `json:"releasedAt"`
+	RawBuiltAt  time.Time `json:"rawBuiltAt" tsnodate:"true"`
+	Changelog   []string  `json:"changelog"`
+	PreviousTag *string   `json:"previousTag,omitempty"`
+}
+
+// TestGenerateAxiosFromEndpoints_DateParsingAllowlist
+// 这个测试验证响应中的日期转换只作用于 time.Time 字段路径：
+// 1) 只生成 releasedAt 的日期路径，version 等看起来像 ISO 时间的普通字符串保持原样。
+// 2) 带 tsnodate:"true" 的 time.Time 字段不转换。
+// 3) 不再对所有字符串做 isoDateLike 匹配（没有全局 normalizeResponseJSON）。
+// 4) SetTSAutoDateParsing(false) 后完全不生成日期转换。
+func TestGenerateAxiosFromEndpoints_DateParsingAllowlist(t *testing.T) {
+	old := TSAutoDateParsing
+	t.Cleanup(func() {
+		SetTSAutoDateParsing(old)
+	})
+	endpoints := []EndpointLike{Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, ReleaseResp]{
+		Name:   "GetRelease",
+		Method: HTTPMethodGet,
+		Path:   "/release",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[ReleaseResp], error) {
+			return Response[ReleaseResp]{StatusCode: 200, Body: ReleaseResp{Version: "2024-01-02T03:04:05Z"}}, nil
+		},
+	}}
+
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "const responseData = reviveDates(response.data, ['releasedAt']);") {
+		t.Fatalf("expected only releasedAt to be revived to Date")
+	}
+	if strings.Contains(code, "normalizeResponseJSON") {
+		t.Fatalf("expected no blanket ISO string matching on responses")
+	}
+
+	SetTSAutoDateParsing(false)
+	code, err = generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "reviveDates") || strings.Contains(code, "isoDateLike") {
+		t.Fatalf("expected no date parsing when TSAutoDateParsing is disabled")
+	}
+}
+
+type PriceTickEvent struct {
+	Symbol string    `json:"symbol"`
+	Price  float64   `json:"price"`
+	At     time.Time `json:"at"`
+}
+
+// TestGenerateAxiosFromEndpoints_SSEEventSource
+// 这个测试验证 SSE 端点生成类型化的 EventSource 客户端：
+// 1) 生成 TypedEventSource 辅助类与 onEvent 订阅方法。
+// 2) 事件类型复用 interface/validator 注册表，并在解码时校验、转换日期。
+// 3) SSE 端点不生成 request 包装函数，也不参与 TanStack Query hooks。
+func TestGenerateAxiosFromEndpoints_SSEEventSource(t *testing.T) {
+	endpoints := []EndpointLike{&SSEEndpoint[PriceTickEvent]{
+		Name:        "PriceTicks",
+		Path:        "/prices/stream",
+		Description: "Live price ticks",
+	}}
+
+	code, err := generateAxiosFromEndpointsWithOptions("/api", "", endpoints, axiosRenderOptions{TanstackQuery: true})
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpointsWithOptions returned error: %v", err)
+	}
+	if !strings.Contains(code, "export class TypedEventSource<T> {") ||
+		!strings.Contains(code, "  onEvent(handler: (event: T) => void): () => void {") {
+		t.Fatalf("expected TypedEventSource helper with onEvent")
+	}
+	if !strings.Contains(code, "export interface PriceTickEvent {") || !strings.Contains(code, "export function validatePriceTickEvent(") {
+		t.Fatalf("expected event interface and validator from the registry")
+	}
+	if !strings.Contains(code, "  static connect(options?: EventSourceConnectOptions): TypedEventSource<PriceTickEvent> {") ||
+		!strings.Contains(code, "    const url = resolveEventSourceURL(PriceTicksGet.buildURL());") {
+		t.Fatalf("expected typed connect on the endpoint class")
+	}
+	if !strings.Contains(code, "    return validatePriceTickEvent(value);") ||
+		!strings.Contains(code, "        if (options?.validate !== false && !PriceTicksGet.validateEvent(data)) {") ||
+		!strings.Contains(code, "        return reviveDates(data, ['at']) as PriceTickEvent;") {
+		t.Fatalf("expected events to be validated and date-revived")
+	}
+	if strings.Contains(code, "requestPriceTicksGet") || strings.Contains(code, "usePriceTicksGet") || strings.Contains(code, "PriceTicksGet.request(") {
+		t.Fatalf("expected no request wrapper or query hooks for the event stream")
+	}
+}
+
+type Item2 struct {
+	Label string `json:"label"`
+}
+
+type StockReconciliation struct {
+	Ordered []orders.Item    `json:"ordered"`
+	Stocked []inventory.Item `json:"stocked"`
+	Note    Item2            `json:"note"`
+}
+
+// TestGenerateAxiosFromEndpoints_SameNameTypesAcrossPackages
+// 这个测试验证不同包中的同名类型不会被合并：
+// 1) orders.Item 与 inventory.Item 生成两个 interface 与各自的 validator。
+// 2) 引用处与 validator 调用使用各自的名称。
+// 3) 编号名称不会与真实存在的同名 Go 类型（Item2）冲突。
+// 4) 多次生成的名称保持稳定。
+func TestGenerateAxiosFromEndpoints_SameNameTypesAcrossPackages(t *testing.T) {
+	endpoints := []EndpointLike{Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, StockReconciliation]{
+		Name:   "ReconcileStock",
+		Method: HTTPMethodGet,
+		Path:   "/stock/reconcile",
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[StockReconciliation], error) {
+			return Response[StockReconciliation]{StatusCode: 200}, nil
+		},
+	}}
+
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	for _, want := range []string{
+		"export interface Item {",
+		"export interface Item2 {",
+		"export interface Item22 {",
+		"export function validateItem(",
+		"export function validateItem2(",
+		"export function validateItem22(",
+		"  ordered: Item[];",
+		"  stocked: Item2[];",
+		"  note: Item22;",
+		"validateItem2(v1)",
+		"validateItem22(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected generated code to contain %q", want)
+		}
+	}
+	if strings.Count(code, "export interface Item2 {") != 1 {
+		t.Fatalf("expected a single Item2 interface")
+	}
+	if !strings.Contains(code[strings.Index(code, "export interface Item {"):], "  quantity: number;") ||
+		!strings.Contains(code[strings.Index(code, "export interface Item2 {"):], "  warehouse: string;") {
+		t.Fatalf("expected each interface to keep its own package's fields")
+	}
+
+	again, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if again != code {
+		t.Fatalf("expected stable names across generations")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ErrorResponseAlwaysPresent
+// 这个测试验证统一错误响应类型始终输出：
+// 1) 即使端点没有任何结构体类型，也会生成 ErrorResponse interface。
+// 2) 同时生成 validateErrorResponse 与 ensureErrorResponse。
+// 3) code / details 为可选字段，details 为 unknown。
+func TestGenerateAxiosFromEndpoints_ErrorResponseAlwaysPresent(t *testing.T) {
+	endpoints := []EndpointLike{NewEndpointNoParams[NoBody, string]("Ping", HTTPMethodGet, "/ping", func(_ NoBody, _ *gin.Context) (string, error) {
+		return "pong", nil
+	})}
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface ErrorResponse {") ||
+		!strings.Contains(code, "export function validateErrorResponse(") ||
+		!strings.Contains(code, "export function ensureErrorResponse(value: unknown): ErrorResponse {") {
+		t.Fatalf("expected ErrorResponse with validator and ensure helper")
+	}
+	if !strings.Contains(code, "  error: string;") || !strings.Contains(code, "  code?: string;") || !strings.Contains(code, "  details?: unknown;") {
+		t.Fatalf("expected ErrorResponse fields error/code?/details?")
+	}
+}
+
+type intKeyNested struct {
+	Label string `json:"label"`
+}
+
+type intKeyMapsResp struct {
+	Names   map[int]string         `json:"names"`
+	Nested  map[int64]intKeyNested `json:"nested"`
+	Unknown map[[2]int]string      `json:"unknown"`
+}
+
+// TestGenerateAxiosFromEndpoints_IntKeyMaps
+// 这个测试验证非字符串键的 map：
+// 1) map[int]string / map[int64]Nested 输出为保留值类型的 Record<string, T>。
+// 2) 校验函数对每个值调用对应的校验表达式（如 validateIntKeyNested）。
+// 3) encoding/json 不支持的键类型（如数组）仍回退为 Record<string, unknown>。
+func TestGenerateAxiosFromEndpoints_IntKeyMaps(t *testing.T) {
+	endpoints := []EndpointLike{NewEndpointNoParams[NoBody, intKeyMapsResp]("GetIntKeyMaps", HTTPMethodGet, "/int-key-maps", func(_ NoBody, _ *gin.Context) (intKeyMapsResp, error) {
+		return intKeyMapsResp{}, nil
+	})}
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	for _, want := range []string{
+		"  names: Record<string, string>;",
+		"  nested: Record<string, IntKeyNested>;",
+		"  unknown: Record<string, unknown>;",
+		`Object.values(obj["names"]).every((v1) => typeof v1 === 'string')`,
+		`Object.values(obj["nested"]).every((v1) => validateIntKeyNested(v1))`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected generated code to contain %q", want)
+		}
+	}
+}
+
+type tsDocProfile struct {
+	Name  string       `json:"name"`
+	Owner tsDocPerson  `json:"owner"`
+	Plain tsDocPlainID `json:"plain"`
+}
+
+type tsDocPerson struct {
+	Email string `json:"email"`
+}
+
+type tsDocPlainID struct {
+	ID string `json:"id"`
+}
+
+// TestGenerateAxiosFromEndpoints_RegisterTSDoc
+// 这个测试验证类型级 JSDoc：
+// 1) 单行文档输出为 /** ... */，位于 export interface 之前。
+// 2) 多行（中英双语）文档逐行输出，指针类型注册同样生效。
+// 3) 未注册文档的类型不输出额外注释。
+func TestGenerateAxiosFromEndpoints_RegisterTSDoc(t *testing.T) {
+	RegisterTSDoc(reflect.TypeOf(tsDocProfile{}), "Represents a person's full profile")
+	RegisterTSDoc(reflect.TypeOf(&tsDocPerson{}), "Profile owner\n档案所有者")
+	t.Cleanup(func() {
+		RegisterTSDoc(reflect.TypeOf(tsDocProfile{}), "")
+		RegisterTSDoc(reflect.TypeOf(tsDocPerson{}), "")
+	})
+
+	endpoints := []EndpointLike{NewEndpointNoParams[NoBody, tsDocProfile]("GetProfile", HTTPMethodGet, "/profile", func(_ NoBody, _ *gin.Context) (tsDocProfile, error) {
+		return tsDocProfile{}, nil
+	})}
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "/** Represents a person's full profile */\nexport interface TsDocProfile {") {
+		t.Fatalf("expected single-line interface doc before TsDocProfile")
+	}
+	if !strings.Contains(code, "/**\n * Profile owner\n * 档案所有者\n */\nexport interface TsDocPerson {") {
+		t.Fatalf("expected multi-line interface doc before TsDocPerson")
+	}
+	if !strings.Contains(code, "// -----------------------------------------------------\nexport interface TsDocPlainID {") {
+		t.Fatalf("expected no doc block before TsDocPlainID")
+	}
+}
+
+type unionNameToast struct {
+	Level string `json:"level" tsunion:"warning,success,error" tsunionname:"MessageLevel"`
+}
+
+type unionNameLog struct {
+	Level  string           `json:"level" tsunion:"warning,success,error" tsunionname:"MessageLevel"`
+	Toasts []unionNameToast `json:"toasts"`
+}
+
+type unionNameConflict struct {
+	Level string       `json:"level" tsunion:"info,debug" tsunionname:"MessageLevel"`
+	Log   unionNameLog `json:"log"`
+}
+
+// TestGenerateAxiosFromEndpoints_TSUnionName
+// 这个测试验证具名字面量联合类型：
+// 1) 两个结构体使用相同 tsunionname 时只输出一个 export type 与 isMessageLevel 守卫。
+// 2) 字段类型渲染为 MessageLevel，校验函数调用 isMessageLevel。
+// 3) 同名但字面量不同时返回错误。
+func TestGenerateAxiosFromEndpoints_TSUnionName(t *testing.T) {
+	endpoints := []EndpointLike{NewEndpointNoParams[NoBody, unionNameLog]("GetLog", HTTPMethodGet, "/log", func(_ NoBody, _ *gin.Context) (unionNameLog, error) {
+		return unionNameLog{}, nil
+	})}
+	code, err := generateAxiosFromEndpoints("/api", "", endpoints)
+	if err != nil {
+		t.Fatalf("generateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Count(code, "export type MessageLevel = 'warning' | 'success' | 'error';") != 1 {
+		t.Fatalf("expected exactly one MessageLevel alias")
+	}
+	if strings.Count(code, "export function isMessageLevel(value: unknown): value is MessageLevel {") != 1 {
+		t.Fatalf("expected exactly one isMessageLevel guard")
+	}
+	if strings.Count(code, "  level: MessageLevel;") != 2 {
+		t.Fatalf("expected both structs to reference MessageLevel")
+	}
+	if strings.Count(code, `isMessageLevel(obj["level"])`) != 2 {
+		t.Fatalf("expected both validators to call isMessageLevel")
+	}
+	if strings.Contains(code, "level: 'warning' | 'success' | 'error'") {
+		t.Fatalf("expected no inline union for named tsunion fields")
+	}
+
+	conflict := []EndpointLike{NewEndpointNoParams[NoBody, unionNameConflict]("GetConflict", HTTPMethodGet, "/conflict", func(_ NoBody, _ *gin.Context) (unionNameConflict, error) {
+		return unionNameConflict{}, nil
+	})}
+	if _, err := generateAxiosFromEndpoints("/api", "", conflict); err == nil || !strings.Contains(err.Error(), "tsunionname MessageLevel") {
+		t.Fatalf("expected tsunionname conflict error, got %v", err)
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_QueryArrayFormat
+// 这个测试验证查询参数数组的序列化方式：
+// 1) 默认使用 repeat（`tags=a&tags=b`），与 gin 的 ShouldBindQuery 一致。
+// 2) 端点可通过 QueryArrayFormat 单独指定 comma / brackets，且 serializeQueryParams 按各模式编码。
+// 3) SetTSQueryArrayFormat 修改全局默认值；无效的端点配置返回错误。
+func TestGenerateAxiosFromEndpoints_QueryArrayFormat(t *testing.T) {
+	type TagQuery struct {
+		Tags []string `form:"tags"`
+	}
+	newSearch := func(name string, format TSQueryArrayFormat) CustomEndpoint[NoParams, TagQuery, NoParams, NoParams, NoBody, []string] {
+		return CustomEndpoint[NoParams, TagQuery, NoParams, NoParams, NoBody, []string]{
+			Name:             name,
+			Method:           HTTPMethodGet,
+			Path:             "/" + name,
+			QueryArrayFormat: format,
+			HandlerFunc:      func(_ *gin.Context) {},
+		}
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{
+		newSearch("SearchRepeat", ""),
+		newSearch("SearchComma", TSQueryArrayComma),
+		newSearch("SearchBrackets", TSQueryArrayBrackets),
+	})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	for name, format := range map[string]string{"SearchRepeatGet": "repeat", "SearchCommaGet": "comma", "SearchBracketsGet": "brackets"} {
+		classIdx := strings.Index(code, "export class "+name+" {")
+		if classIdx < 0 || !strings.Contains(code[classIdx:], "      paramsSerializer: (query: Record<string, unknown>) => serializeQueryParams(query, '"+format+"'),\n") {
+			t.Fatalf("expected %s to serialize query arrays as %s", name, format)
+		}
+	}
+	for _, want := range []string{
+		"    if (arrayFormat === 'comma') {\n      if (items.length > 0) search.append(k, items.join(','));\n",
+		"    const key = arrayFormat === 'brackets' ? `${k}[]` : k;\n    for (const item of items) search.append(key, item);\n",
+		"    if (!Array.isArray(v)) {\n      search.append(k, toQueryString(v));\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected serializeQueryParams to contain %q", want)
+		}
+	}
+
+	old := TSQueryArrayFormatMode
+	SetTSQueryArrayFormat(TSQueryArrayComma)
+	t.Cleanup(func() { SetTSQueryArrayFormat(old) })
+	code, err = generateAxiosFromEndpoints("/api", "", []EndpointLike{newSearch("SearchRepeat", "")})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "serializeQueryParams(query, 'comma')") {
+		t.Fatalf("expected global query array format to apply")
+	}
+	if _, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{newSearch("SearchBad", "pipes")}); err == nil {
+		t.Fatalf("expected unsupported query array format to fail")
+	}
+	plain, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{CustomEndpoint[NoParams, NoParams, NoParams, NoParams, NoBody, []string]{
+		Name:        "ListAll",
+		Method:      HTTPMethodGet,
+		Path:        "/all",
+		HandlerFunc: func(_ *gin.Context) {},
+	}})
+	if err != nil || strings.Contains(plain, "serializeQueryParams") {
+		t.Fatalf("expected no query helper without query params: %v", err)
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_CookieHeaderMerge
+// 这个测试验证 Cookie 头与 cookie 参数的合并：
+// 1) header 参数中的 Cookie（任意大小写）不会被 cookie 参数覆盖，而是与其合并。
+// 2) 同名 cookie 以 cookie 参数为准；名称与值都经过 URL 编码；null/undefined 的 cookie 参数被跳过。
+// 3) 流式请求体端点同样合并 Cookie 头。
+func TestGenerateAxiosFromEndpoints_CookieHeaderMerge(t *testing.T) {
+	type SessionHeader struct {
+		Cookie string `header:"Cookie"`
+		Token  string `header:"X-Token"`
+	}
+	type SessionCookie struct {
+		Session string `cookie:"session"`
+		Theme   string `cookie:"ui theme"`
+	}
+	profile := CustomEndpoint[NoParams, NoParams, SessionHeader, SessionCookie, NoBody, string]{
+		Name:        "Profile",
+		Method:      HTTPMethodGet,
+		Path:        "/profile",
+		HandlerFunc: func(_ *gin.Context) {},
+	}
+	upload := CustomEndpoint[NoParams, NoParams, SessionHeader, SessionCookie, StreamRequest, string]{
+		Name:        "Upload",
+		Method:      HTTPMethodPost,
+		Path:        "/upload",
+		HandlerFunc: func(_ *gin.Context) {},
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{profile, upload})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if strings.Contains(code, "Cookie: buildCookieHeader(") {
+		t.Fatalf("expected Cookie header not to be overwritten by cookie params")
+	}
+	if !strings.Contains(code, "    const headers = withCookieHeader({\n      ...(normalizedParams?.header ?? {}),\n    }, (normalizedParams?.cookie ?? {}) as Record<string, unknown>);\n") {
+		t.Fatalf("expected request headers to merge the Cookie header with cookie params")
+	}
+	if !strings.Contains(code, "      headers: withCookieHeader({ ...(normalizedParams?.header ?? {}) }, (normalizedParams?.cookie ?? {}) as Record<string, unknown>),\n") {
+		t.Fatalf("expected stream request headers to merge the Cookie header with cookie params")
+	}
+	for _, want := range []string{
+		"    if (k.toLowerCase() === 'cookie') existing = v;\n",
+		"    .filter(([, v]) => v !== undefined && v !== null)\n",
+		"    .map(([k, v]) => [encodeURIComponent(k), encodeURIComponent(String(v))] as const);\n",
+		"filter((part) => part !== '' && !names.has(part.split('=')[0].trim()))",
+		"  return [...kept, ...pairs.map(([k, v]) => `${k}=${v}`)].join('; ');\n",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected cookie helpers to contain %q", want)
+		}
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ClientTimeout
+// 这个测试验证端点级的请求超时：
+// 1) Endpoint/CustomEndpoint 设置 ClientTimeout 后，requestConfig 以毫秒输出 timeout，且可被 options.timeout 覆盖。
+// 2) 未设置时不输出固定 timeout，仅在调用方传入 options.timeout 时生效。
+// 3) AxiosConvertOptions 暴露 timeout 选项。
+func TestGenerateAxiosFromEndpoints_ClientTimeout(t *testing.T) {
+	report := Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, string]{
+		Name:          "BuildReport",
+		Method:        HTTPMethodGet,
+		Path:          "/report",
+		ClientTimeout: 2 * time.Minute,
+		HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[string], error) {
+			return Response[string]{StatusCode: 200, Body: "ok"}, nil
+		},
+	}
+	export := CustomEndpoint[NoParams, NoParams, NoParams, NoParams, NoBody, string]{
+		Name:          "ExportReport",
+		Method:        HTTPMethodGet,
+		Path:          "/export",
+		ClientTimeout: 1500 * time.Millisecond,
+		HandlerFunc:   func(_ *gin.Context) {},
+	}
+	quick := CustomEndpoint[NoParams, NoParams, NoParams, NoParams, NoBody, string]{
+		Name:        "Ping",
+		Method:      HTTPMethodGet,
+		Path:        "/ping",
+		HandlerFunc: func(_ *gin.Context) {},
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{report, export, quick})
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  timeout?: number;\n") {
+		t.Fatalf("expected AxiosConvertOptions.timeout")
+	}
+	for name, want := range map[string]string{
+		"BuildReportGet":  "      timeout: options?.timeout ?? 120000,\n",
+		"ExportReportGet": "      timeout: options?.timeout ?? 1500,\n",
+		"PingGet":         "      ...(options?.timeout !== undefined ? { timeout: options.timeout } : {}),\n",
+	} {
+		classIdx := strings.Index(code, "export class "+name+" {")
+		if classIdx < 0 {
+			t.Fatalf("expected class %s", name)
+		}
+		classCode := code[classIdx:]
+		if next := strings.Index(classCode[1:], "export class "); next >= 0 {
+			classCode = classCode[:next+1]
+		}
+		if !strings.Contains(classCode, want) {
+			t.Fatalf("expected %s request config to contain %q", name, want)
+		}
+	}
+}
+
+// TestGenerateFetchFromEndpoints
+// 这个测试验证基于 fetch 的 HTTP 客户端生成：
+// 1) ServerAPI.ExportFetchTS 写出文件，生成与 axios 相同的 class（NAME/PATHS/FULL_PATH/buildURL）与 request 便捷函数。
+// 2) 不依赖 axios：以 requestInit 构建 URL 与 RequestInit，通过 sendFetchRequest 发送，非 2xx 抛出 FetchError。
+// 3) query 参数序列化进 URL，header/cookie 参数写入 headers，JSON 请求体序列化并带 Content-Type。
+// 4) interface/validator 与参数归一化辅助函数与 axios 客户端共用。
+func TestGenerateFetchFromEndpoints(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	httpAPI := ServerAPI{
+		BasePath:  "/api",
+		GroupPath: "/v1",
+		Endpoints: buildCommonHTTPTestAPIs(),
+	}
+	outPath := filepath.Join("schema", "server_fetch.ts")
+	if err := httpAPI.ExportFetchTS(outPath); err != nil {
+		t.Fatalf("ServerAPI.ExportFetchTS returned error: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read generated ts file failed: %v", err)
+	}
+	code := string(data)
+
+	if strings.Contains(code, "axios") || strings.Contains(code, "Axios") {
+		t.Fatalf("expected fetch client not to depend on axios")
+	}
+	for _, want := range []string{
+		"export class GetPersonByIDGet {",
+		"static readonly NAME = 'getPersonByID' as const;",
+		"static readonly FULL_PATH = '/api/v1/Person/:ID' as const;",
+		"static buildURL(",
+		"static requestInit(",
+		"const { url, init } = GetPersonByIDGet.requestInit(params, options);",
+		"return sendFetchRequest(url, init, options?.timeout, async (response) => {",
+		"export async function requestGetPersonByIDGet(",
+		"return GetPersonByIDGet.request(params, options);",
+		"export class FetchError extends Error {",
+		"export const setFetchImplementation = (impl: typeof fetch): void => {",
+		"export const setFetchBaseURL = (baseURL: string): void => {",
+		"export interface FetchConvertOptions<TRequest = unknown, TResponse = unknown> {",
+		"export function validatePersonDetailResp(",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected fetch client to contain %q", want)
+		}
+	}
+	if !strings.Contains(code, "      url: withQueryString(resolveHttpBaseURL(url), serializeQueryParams(normalizedParams.query, 'repeat')),\n") {
+		t.Fatalf("expected query params to be serialized into the request URL")
+	}
+	if !strings.Contains(code, "    const headers = withCookieHeader({\n      ...(normalizedParams?.header ?? {}),\n    }, (normalizedParams?.cookie ?? {}) as Record<string, unknown>);\n") {
+		t.Fatalf("expected header and cookie params to be written to headers")
+	}
+	if !strings.Contains(code, "const requestHeaders = { 'Content-Type': 'application/json' };") || !strings.Contains(code, "        body: JSON.stringify(normalizeRequestJSON(requestData)),\n") {
+		t.Fatalf("expected JSON request bodies to be serialized with a JSON content type")
+	}
+	if !strings.Contains(code, "      const responseData = reviveDates(await readResponseBody(response), ['resumes.*.startDate', 'resumes.*.endDate']);\n") {
+		t.Fatalf("expected JSON responses to be parsed from the response body with dates revived")
+	}
+	if !strings.Contains(code, "throw new FetchError(response, await readResponseBody(response).catch(() => undefined));") {
+		t.Fatalf("expected non-2xx responses to throw FetchError")
+	}
+}
+
+// TestGenerateFetchFromEndpoints_ResponseKinds
+// 这个测试验证 fetch 客户端对 TSKind 的处理：
+// 1) text/bytes/stream 响应分别通过 response.text()/arrayBuffer()/blob() 读取。
+// 2) form-urlencoded 请求体转换为 URLSearchParams，text 请求体原样发送并带 Content-Type。
+// 3) StreamRequest 请求体以 ReadableStream + duplex: 'half' 发送。
+// 4) SSE 端点复用 TypedEventSource，地址经 resolveHttpBaseURL 解析。
+func TestGenerateFetchFromEndpoints_ResponseKinds(t *testing.T) {
+	type FormReq struct {
+		Name string `json:"name"`
+	}
+	apis := []EndpointLike{
+		CustomEndpoint[NoParams, NoParams, NoParams, NoParams, FormReq, string]{
+			Name:         "SubmitForm",
+			Method:       HTTPMethodPost,
+			Path:         "/form",
+			RequestKind:  TSKindFormURLEncoded,
+			ResponseKind: TSKindText,
+			HandlerFunc:  func(_ *gin.Context) {},
+		},
+		CustomEndpoint[NoParams, NoParams, NoParams, NoParams, string, RawBytes]{
+			Name:         "Echo",
+			Method:       HTTPMethodPut,
+			Path:         "/echo",
+			RequestKind:  TSKindText,
+			ResponseKind: TSKindBytes,
+			HandlerFunc:  func(_ *gin.Context) {},
+		},
+		CustomEndpoint[NoParams, NoParams, NoParams, NoParams, NoBody, string]{
+			Name:          "Download",
+			Method:        HTTPMethodGet,
+			Path:          "/download",
+			ResponseKind:  TSKindStream,
+			ClientTimeout: 2 * time.Second,
+			HandlerFunc:   func(_ *gin.Context) {},
+		},
+		CustomEndpoint[NoParams, NoParams, NoParams, NoParams, StreamRequest, string]{
+			Name:        "Upload",
+			Method:      HTTPMethodPost,
+			Path:        "/upload",
+			HandlerFunc: func(_ *gin.Context) {},
+		},
+		&SSEEndpoint[PriceTickEvent]{Name: "PriceTicks", Path: "/prices/stream"},
+	}
+	code, err := GenerateFetchFromEndpoints("/api", apis)
+	if err != nil {
+		t.Fatalf("GenerateFetchFromEndpoints returned error: %v", err)
+	}
+	for _, want := range []string{
+		"      const responseData = await response.text();\n",
+		"      const responseData = new Uint8Array(await response.arrayBuffer());\n",
+		"      const responseData = await response.blob();\n",
+		"    const requestData = toFormUrlEncoded(serializedRequest);\n",
+		"const requestHeaders = { 'Content-Type': 'application/x-www-form-urlencoded' };",
+		"const requestHeaders = { 'Content-Type': 'text/plain; charset=utf-8' };",
+		"        body: requestData as BodyInit,\n",
+		"return sendFetchRequest(url, init, options?.timeout ?? 2000, async (response) => {",
+		"        body: toReadableStream(requestBody),\n",
+		"      } as RequestInit & { duplex: 'half' },\n",
+		"const resolveEventSourceURL = (url: string): string => resolveHttpBaseURL(url);",
+		"static connect(options?: EventSourceConnectOptions): TypedEventSource<PriceTickEvent> {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected fetch client to contain %q", want)
+		}
+	}
+	if strings.Contains(code, "sendStreamRequest") {
+		t.Fatalf("expected stream request bodies to use the regular fetch request path")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_HybridEndpoint
+// 这个测试验证 HybridEndpoint 的 TS 生成：
+// 1) path/query 参数类型与请求体类型按泛型推断。
+// 2) ResponseKind 生效（stream 响应生成 Blob 返回类型）。
+// 3) 与同样声明的 CustomEndpoint 生成完全相同的 TS。
+func TestGenerateAxiosFromEndpoints_HybridEndpoint(t *testing.T) {
+	type ExportPath struct {
+		ID string `uri:"id"`
+	}
+	type ExportQuery struct {
+		From time.Time `form:"from"`
+	}
+	type ExportFilter struct {
+		Status string `json:"status"`
+	}
+	hybrid := NewHybridEndpoint[ExportPath, ExportQuery, NoParams, NoParams, ExportFilter, []byte]("ExportOrders", HTTPMethodPost, "/orders/:id/export",
+		func(_ *HybridContext[ExportPath, ExportQuery, NoParams, NoParams, ExportFilter]) error { return nil })
+	hybrid.ResponseKind = TSKindStream
+	code, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{hybrid})
+	if err != nil {
+		t.Fatalf("generate ts failed: %v", err)
+	}
+	for _, want := range []string{
+		"export class ExportOrdersPost {",
+		"path: ExportPath;",
+		"query: ExportQuery;",
+		"requestBody: ExportFilter",
+		"Promise<Blob>",
+		"responseType: 'blob',",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected hybrid endpoint TS to contain %q", want)
+		}
+	}
+	custom := NewCustomEndpoint[ExportPath, ExportQuery, NoParams, NoParams, ExportFilter, []byte]("ExportOrders", HTTPMethodPost, "/orders/:id/export", func(_ *gin.Context) {})
+	custom.ResponseKind = TSKindStream
+	customCode, err := generateAxiosFromEndpoints("/api", "", []EndpointLike{custom})
+	if err != nil {
+		t.Fatalf("generate ts failed: %v", err)
+	}
+	if code != customCode {
+		t.Fatalf("expected hybrid endpoint TS to match the equivalent CustomEndpoint")
+	}
+}
+
+// TestServerAPIExportTSWithOptions
+// 这个测试验证 ExportTSWithOptions：
+// 1) 零值与 DefaultTSExportOptions 的输出与 ExportTS 完全一致。
+// 2) 开启 OmitValidators 后不再生成 validateX/ensureX，SSE 事件也不再校验。
+// 3) 开启 OmitDateParsing 后不再生成 reviveDates。
+// 4) Client 为 fetch 时生成 fetch 客户端，仍保留校验函数与日期转换；fetch 客户端不支持 Vue 组合式函数等 axios 专属功能。
+// 5) 统一导出使用同一组选项，OmitValidators 同样生效。
+func TestServerAPIExportTSWithOptions(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	api := ServerAPI{
+		BasePath:  "/api",
+		GroupPath: "/v1",
+		Endpoints: append(buildCommonHTTPTestAPIs(), &SSEEndpoint[PriceTickEvent]{Name: "PriceTicks", Path: "/prices/stream"}),
+	}
+	export := func(path string, opts TSExportOptions) string {
+		t.Helper()
+		if err := api.ExportTSWithOptions(path, opts); err != nil {
+			t.Fatalf("ExportTSWithOptions returned error: %v", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read generated ts file failed: %v", err)
+		}
+		return string(data)
+	}
+
+	if err := api.ExportTS("default.ts"); err != nil {
+		t.Fatalf("ExportTS returned error: %v", err)
+	}
+	want, err := os.ReadFile("default.ts")
+	if err != nil {
+		t.Fatalf("read generated ts file failed: %v", err)
+	}
+	if got := export("options.ts", DefaultTSExportOptions()); got != string(want) {
+		t.Fatalf("expected DefaultTSExportOptions to match ExportTS output")
+	}
+	if got := export("zero.ts", TSExportOptions{}); got != string(want) {
+		t.Fatalf("expected zero TSExportOptions to match ExportTS output")
+	}
+
+	opts := DefaultTSExportOptions()
+	opts.OmitValidators = true
+	code := export("no_validators.ts", opts)
+	if strings.Contains(code, "export function validatePersonDetailResp(") || strings.Contains(code, "export function ensurePersonDetailResp(") {
+		t.Fatalf("expected validators to be omitted")
+	}
+	if !strings.Contains(code, "export interface PersonDetailResp {") {
+		t.Fatalf("expected interfaces to be kept without validators")
+	}
+	if strings.Contains(code, ".validateEvent(wire)") || strings.Contains(code, ".validateEvent(data)") {
+		t.Fatalf("expected event stream validation to be omitted")
+	}
+	if !strings.Contains(code, "reviveDates(") {
+		t.Fatalf("expected date normalization to be kept")
+	}
+
+	opts = DefaultTSExportOptions()
+	opts.OmitDateParsing = true
+	code = export("no_dates.ts", opts)
+	if strings.Contains(code, "reviveDates") || strings.Contains(code, "isoDateLike") {
+		t.Fatalf("expected date normalization to be omitted")
+	}
+	if !strings.Contains(code, "export function validatePersonDetailResp(") {
+		t.Fatalf("expected validators to be kept")
+	}
+
+	opts = TSExportOptions{Client: ClientKindFetch}
+	code = export("fetch.ts", opts)
+	if !strings.Contains(code, "sendFetchRequest(") || strings.Contains(code, "axios") {
+		t.Fatalf("expected fetch client output")
+	}
+	if !strings.Contains(code, "export function validatePersonDetailResp(") || !strings.Contains(code, "reviveDates(") {
+		t.Fatalf("expected fetch client to keep validators and date normalization by default")
+	}
+	opts.EmitVueComposables = true
+	if err := api.ExportTSWithOptions("fetch_vue.ts", opts); err == nil {
+		t.Fatalf("expected vue composables to be rejected for the fetch client")
+	}
+	if err := api.ExportTSWithOptions("fetch_zod.ts", TSExportOptions{Client: ClientKindFetch, EmitZodSchemas: true}); err == nil {
+		t.Fatalf("expected zod schemas to be rejected for the fetch client")
+	}
+	if err := api.ExportTSWithOptions("unknown.ts", TSExportOptions{Client: "superagent"}); err == nil {
+		t.Fatalf("expected unsupported client kind error")
+	}
+
+	unified := UnifiedTSExportOptions{
+		ServerTSPath:    "unified_server.ts",
+		WebSocketTSPath: "unified_ws.ts",
+		SchemaTSPath:    "unified_schema.ts",
+		TSExportOptions: TSExportOptions{OmitValidators: true},
+	}
+	if err := ExportUnifiedAPIsToTSFiles(api, WebSocketAPI{BasePath: "/ws", Endpoints: []WebSocketEndpointLike{buildCommonWSTestEndpoint()}}, unified); err != nil {
+		t.Fatalf("ExportUnifiedAPIsToTSFiles returned error: %v", err)
+	}
+	schema, err := os.ReadFile(unified.SchemaTSPath)
+	if err != nil {
+		t.Fatalf("read unified schema file failed: %v", err)
+	}
+	if strings.Contains(string(schema), "export function validatePersonDetailResp(") || !strings.Contains(string(schema), "export interface PersonDetailResp {") {
+		t.Fatalf("expected unified export to honor OmitValidators")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_Deprecated
+// 这个测试验证弃用标记：
+// 1) 带 tsdeprecated tag 的字段在 JSDoc 中输出 `@deprecated <reason>`，空原因输出裸 `@deprecated`。
+// 2) Endpoint.Deprecated 会出现在生成类与 requestX 函数的文档注释中。
+// 3) 未弃用的 endpoint 不输出 @deprecated。
+func TestGenerateAxiosFromEndpoints_Deprecated(t *testing.T) {
+	type LegacyUser struct {
+		Name     string `json:"name" tsdoc:"用户名 / User name" tsdeprecated:"use displayName instead"`
+		Nickname string `json:"nickname" tsdeprecated:""`
+		Display  string `json:"displayName"`
+	}
+	apis := []EndpointLike{
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, LegacyUser]{
+			Name:        "GetLegacyUser",
+			Method:      HTTPMethodGet,
+			Path:        "/legacy-user",
+			Description: "Get the legacy user.",
+			Deprecated:  "use GetUserV2 instead",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[LegacyUser], error) {
+				return Response[LegacyUser]{StatusCode: 200}, nil
+			},
+		},
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, LegacyUser]{
+			Name:   "GetUserV2",
+			Method: HTTPMethodGet,
+			Path:   "/user-v2",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[LegacyUser], error) {
+				return Response[LegacyUser]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  /**\n   * 用户名 / User name\n   * @deprecated use displayName instead\n   */\n  name: string;") {
+		t.Fatalf("expected deprecated field JSDoc with reason")
+	}
+	if !strings.Contains(code, "  /** @deprecated */\n  nickname: string;") {
+		t.Fatalf("expected bare @deprecated for an empty reason")
+	}
+	if !strings.Contains(code, " * Get the legacy user.\n * @deprecated use GetUserV2 instead\n */\nexport class GetLegacyUserGet {") {
+		t.Fatalf("expected deprecated endpoint class doc comment")
+	}
+	if !strings.Contains(code, "/** @deprecated use GetUserV2 instead */\nexport async function requestGetLegacyUserGet(") {
+		t.Fatalf("expected deprecated request function doc comment")
+	}
+	if strings.Count(code, "@deprecated use GetUserV2 instead") != 2 {
+		t.Fatalf("expected only the deprecated endpoint to be marked")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_PaginatedEndpoint
+// 这个测试验证分页响应结构：
+// 1) NewPaginatedEndpoint 生成以元素类型命名的 PaginatedPersonDetailResp 接口，字段为 items/total/page/pageSize。
+// 2) int64 的 total 遵循 TSInt64MappingMode（string 模式下为 string）。
+// 3) 响应日期按 items.* 路径还原；NewPaginatedResponse 将 nil items 输出为空数组。
+func TestGenerateAxiosFromEndpoints_PaginatedEndpoint(t *testing.T) {
+	oldInt64 := TSInt64MappingMode
+	SetTSInt64MappingMode(TSInt64ModeString)
+	t.Cleanup(func() {
+		SetTSInt64MappingMode(oldInt64)
+	})
+
+	apis := []EndpointLike{
+		NewPaginatedEndpoint(
+			"ListPersons",
+			HTTPMethodGet,
+			"/persons",
+			func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[Paginated[PersonDetailResp]], error) {
+				return NewPaginatedResponse[PersonDetailResp](nil, 0, 1, 20), nil
+			},
+		),
+	}
+	code, err := generateAxiosFromEndpoints("/api", "", apis)
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface PaginatedPersonDetailResp {\n  items: PersonDetailResp[];\n  total: string;\n  page: number;\n  pageSize: number;\n}") {
+		t.Fatalf("expected PaginatedPersonDetailResp interface with string total")
+	}
+	if !strings.Contains(code, "axiosClient.request<PaginatedPersonDetailResp>(") {
+		t.Fatalf("expected request typed with the paginated interface")
+	}
+	if !strings.Contains(code, "'items.*.resumes.*.startDate'") {
+		t.Fatalf("expected dates inside items to be revived")
+	}
+
+	resp := NewPaginatedResponse[PersonDetailResp](nil, 0, 1, 20)
+	data, err := json.Marshal(resp.Body)
+	if err != nil {
+		t.Fatalf("marshal paginated body failed: %v", err)
+	}
+	if string(data) != `{"items":[],"total":0,"page":1,"pageSize":20}` {
+		t.Fatalf("unexpected paginated body: %s", data)
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ETagCache
+// 这个测试验证 ETag 缓存往返：
+// 1) AxiosConvertOptions 声明 useETagCache，GET 请求经 requestWithETagCache 发送，POST 不参与。
+// 2) 开启时携带上次的 If-None-Match，304 被视为成功并以状态 200 返回缓存的响应体。
+// 3) 新响应的 ETag 与响应体按完整 URL 写入模块级缓存，可通过 clearETagCache 清空。
+func TestGenerateAxiosFromEndpoints_ETagCache(t *testing.T) {
+	code, err := generateAxiosFromEndpoints("/api", "/v1", buildCommonHTTPTestAPIs())
+	if err != nil {
+		t.Fatalf("GenerateAxiosFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "  useETagCache?: boolean;\n") {
+		t.Fatalf("expected useETagCache option")
+	}
+	if !strings.Contains(code, "const response = await requestWithETagCache(GetPersonByIDGet.requestConfig(params, options), options?.useETagCache, (config) => axiosClient.request<PersonDetailResp>(config));") {
+		t.Fatalf("expected GET request to go through the ETag cache")
+	}
+	if !strings.Contains(code, "const response = await axiosClient.request<PersonDetailResp>(GetPersonDetailPost.requestConfig(requestBody, options));") {
+		t.Fatalf("expected POST request to bypass the ETag cache")
+	}
+	if !strings.Contains(code, "const key = axiosClient.getUri(config);") {
+		t.Fatalf("expected cache keyed by full request URL")
+	}
+	if !strings.Contains(code, "...(cached ? { 'If-None-Match': cached.etag } : {})") {
+		t.Fatalf("expected If-None-Match from the cached ETag")
+	}
+	if !strings.Contains(code, "(status === 304 && cached !== undefined)") {
+		t.Fatalf("expected 304 to be accepted only with a cached body")
+	}
+	if !strings.Contains(code, "if (response.status === 304 && cached) return { ...response, status: 200, data: cached.data } as R;") {
+		t.Fatalf("expected 304 to return the cached body")
+	}
+	if !strings.Contains(code, "etagCache.set(key, { etag, data: response.data });") || !strings.Contains(code, "export const clearETagCache = (): void => etagCache.clear();") {
+		t.Fatalf("expected ETag cache writes and clearETagCache")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_ValidationError
+// 这个测试验证生成前的元数据校验逻辑：
+// 当路由 path 中声明了路径参数（如 :id），但 Endpoint 的 PathParams 使用了 NoParams 时，
+// 生成器必须返回明确错误，而不是继续生成无效 TS。
+func TestGenerateAxiosFromEndpoints_ValidationError(t *testing.T) {
+	apis := []EndpointLike{
+		Endpoint[NoParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "invalid_path_params",
+			Method: HTTPMethodGet,
+			Path:   "/person/:id",
+			HandlerFunc: func(_ NoParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+	}
+
+	_, err := generateAxiosFromEndpoints("/api", "/v1", apis)
+	if err == nil {
+		t.Fatalf("expected validation error for missing path params type")
+	}
+	if !strings.Contains(err.Error(), "path params required") {
+		t.Fatalf("expected validation error message, got: %v", err)
+	}
+}
+
+// TestServerAPIExportTSWithOptions_AbortAll
+// 这个测试验证 EmitAbortAll 选项：
+// 1) 默认不生成 abortAllRequests，避免简单场景的额外开销。
+// 2) 开启后导出 abortAllRequests，并让每个请求在 await 期间以 trackRequest 注册的 signal 发送。
+// 3) fetch 客户端不支持该选项，返回错误。
+func TestServerAPIExportTSWithOptions_AbortAll(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	api := ServerAPI{BasePath: "/api", GroupPath: "/v1", Endpoints: buildCommonHTTPTestAPIs()}
+	if err := api.ExportTSWithOptions("default.ts", DefaultTSExportOptions()); err != nil {
+		t.Fatalf("ExportTSWithOptions returned error: %v", err)
+	}
+	data, err := os.ReadFile("default.ts")
+	if err != nil {
+		t.Fatalf("read generated ts file failed: %v", err)
+	}
+	if strings.Contains(string(data), "abortAllRequests") || strings.Contains(string(data), "trackRequest") {
+		t.Fatalf("expected abortAllRequests to be opt-in")
+	}
+
+	opts := DefaultTSExportOptions()
+	opts.EmitAbortAll = true
+	if err := api.ExportTSWithOptions("abort_all.ts", opts); err != nil {
+		t.Fatalf("ExportTSWithOptions returned error: %v", err)
+	}
+	data, err = os.ReadFile("abort_all.ts")
+	if err != nil {
+		t.Fatalf("read generated ts file failed: %v", err)
+	}
+	code := string(data)
+	if !strings.Contains(code, "export const abortAllRequests = (reason?: unknown): void => {") {
+		t.Fatalf("expected exported abortAllRequests")
+	}
+	if !strings.Contains(code, "activeRequestControllers.add(controller);") || !strings.Contains(code, "activeRequestControllers.delete(controller);") {
+		t.Fatalf("expected controllers to be registered and deregistered")
+	}
+	if !strings.Contains(code, "const response = await trackRequest(options?.signal, (signal) => axiosClient.request<PersonDetailResp>(GetPersonDetailPost.requestConfig(requestBody, { ...options, signal })));") {
+		t.Fatalf("expected requests to run on the tracked signal")
+	}
+
+	opts.Client = ClientKindFetch
+	if err := api.ExportTSWithOptions("fetch.ts", opts); err == nil {
+		t.Fatalf("expected abortAllRequests to be rejected for the fetch client")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_MissingPathParamField
+// 这个测试验证路径参数与结构体字段的交叉校验：
+// 1) path 中的占位符在 PathParams 结构体中找不到对应字段时返回包含参数名的错误。
+// 2) 字段匹配大小写不敏感，与 TS 插值的映射规则一致。
+func TestGenerateAxiosFromEndpoints_MissingPathParamField(t *testing.T) {
+	type UserPathParams struct {
+		ID string `uri:"id"`
+	}
+	type UserPostPathParams struct {
+		ID     string `uri:"id"`
+		PostID string `uri:"postid"`
+	}
+	handler := func(_ UserPathParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+		return Response[PersonDetailResp]{StatusCode: 200}, nil
+	}
+
+	_, err := generateAxiosFromEndpoints("/api", "/v1", []EndpointLike{
+		Endpoint[UserPathParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:        "GetUserPost",
+			Method:      HTTPMethodGet,
+			Path:        "/user/:id/post/:postID",
+			HandlerFunc: handler,
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), `path param "postID" has no matching field`) {
+		t.Fatalf("expected missing path param field error, got: %v", err)
+	}
+
+	code, err := generateAxiosFromEndpoints("/api", "/v1", []EndpointLike{
+		Endpoint[UserPostPathParams, NoParams, NoParams, NoParams, NoBody, PersonDetailResp]{
+			Name:   "GetUserPost",
+			Method: HTTPMethodGet,
+			Path:   "/user/:id/post/:postID",
+			HandlerFunc: func(_ UserPostPathParams, _ NoParams, _ NoParams, _ NoParams, _ NoBody, _ *gin.Context) (Response[PersonDetailResp], error) {
+				return Response[PersonDetailResp]{StatusCode: 200}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected case-insensitive path param match, got: %v", err)
+	}
+	if !strings.Contains(code, "params.path?.postid") {
+		t.Fatalf("expected postID placeholder to map to the postid field")
+	}
+}
+
+// TestGenerateAxiosFromEndpoints_CustomEndpoint_ExportTSFile
+// 这个测试验证 CustomEndpoint 路径：
+// 1) CustomEndpoint 能与普通 Endpoint 一样参与 TS 生成并写入文件。
+// 2) RequestKind/ResponseKind 的自定义行为能体现在 TS（如 form-urlencoded 与 text 响应）。
+// 3) 自定义路径参数（含 uri/json tag）插值时字段名映射正确。
+func TestGenerateAxiosFromEndpoints_CustomEndpoint_ExportTSFile(t *testing.T) {
+	type CustomPathParams struct {
+		OrderID string `uri:"orderID" json:"orderID" tsdoc:"订单ID / Order identifier"`
+	}
+	type CustomReq struct {
+		Format string `json:"format" tsunion:"json,text" tsdoc:"返回格式 / Response format"`
+	}
+	type CustomResp struct {
+		Result string `json:"result" tsdoc:"结果文本 / Result text"`
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+
+	moduleRoot := cwd
+	for {
+		if _, statErr := os.Stat(filepath.Join(moduleRoot, "go.mod")); statErr == nil {
+			break
+		}
+		next := filepath.Dir(moduleRoot)
+		if next == moduleRoot {
+			t.Fatalf("go.mod not found from cwd: %s", cwd)
+		}
+		moduleRoot = next
+	}
+
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(moduleRoot); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	custom := CustomEndpoint[CustomPathParams, NoParams, NoParams, NoParams, CustomReq, CustomResp]{
+		Name:         "submit_order_custom",
+		Method:       HTTPMethodPost,
+		Path:         "/custom/order/:orderID",
+		Description:  "Submit order with custom endpoint.",
+		RequestKind:  TSKindFormURLEncoded,
+		ResponseKind: TSKindText,
+		Responses: []Response[CustomResp]{
+			{StatusCode: 200, Description: "ok"},
+		},
+		HandlerFunc: func(ctx *gin.Context) {
+			ctx.String(200, "ok")
+		},
+	}
+
+	outPath := filepath.Join(".generated", "schema", "custom", "custom_endpoint_api.ts")
+	customAPI := ServerAPI{
+		BasePath:  "/api",
+		GroupPath: "/v2",
+		Endpoints: []EndpointLike{custom},
+	}
+	if err := customAPI.ExportTS(outPath); err != nil {
+		t.Fatalf("ServerAPI.ExportTS returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(moduleRoot, outPath))
+	if err != nil {
+		t.Fatalf("read generated ts file failed: %v", err)
+	}
+	code := string(data)
+
+	if !strings.Contains(code, "export class SubmitOrderCustomPost {") {
+		t.Fatalf("expected class generation for custom endpoint")
+	}
+	if !strings.Contains(code, "export async function requestSubmitOrderCustomPost(") || !strings.Contains(code, "return SubmitOrderCustomPost.request(") {
+		t.Fatalf("expected generated convenience request function for custom endpoint class")
+	}
+	if !strings.Contains(code, "toFormUrlEncoded") {
+		t.Fatalf("expected form-urlencoded helper usage for custom endpoint")
+	}
+	if !strings.Contains(code, `responseType: "text"`) {
+		t.Fatalf("expected text response type for custom endpoint")
+	}
+	if !strings.Contains(code, "params.path?.orderID") {
+		t.Fatalf("expected path param interpolation to use orderID casing")
+	}
+}
+
+type wsClientEnvelope struct {
+	Type    string          `json:"type" tsdoc:"消息类型 / Message type"`
+	Payload wsClientPayload `json:"payload" tsdoc:"消息载荷 / Message payload"`
+}
+
+type wsClientPayload struct {
+	JoinRoom *wsClientJoinRoomPayload `json:"joinRoom,omitempty" tsdoc:"加入房间消息 / Join room payload"`
+	ChatText *wsClientChatTextPayload `json:"chatText,omitempty" tsdoc:"聊天文本消息 / Chat text payload"`
+}
+
+type wsClientJoinRoomPayload struct {
+	RoomID   string `json:"roomID" tsdoc:"房间ID / Room identifier"`
+	ClientID string `json:"clientID" tsdoc:"客户端ID / Client identifier"`
+}
+
+type wsClientChatTextPayload struct {
+	RoomID string `json:"roomID" tsdoc:"房间ID / Room identifier"`
+	Text   string `json:"text" tsdoc:"文本内容 / Message text"`
+}
+
+type wsClientNoPayload struct{}
+
+type wsServerEnvelope struct {
+	Type    string          `json:"type" tsdoc:"服务端消息类型 / Server message type"`
+	Payload wsServerPayload `json:"payload" tsdoc:"服务端消息载荷 / Server message payload"`
+}
+
+type wsServerPayload struct {
+	Ack       *wsServerAckPayload       `json:"ack,omitempty" tsdoc:"确认消息 / Acknowledgement payload"`
+	Broadcast *wsServerBroadcastPayload `json:"broadcast,omitempty" tsdoc:"广播消息 / Broadcast payload"`
+}
+
+type wsServerAckPayload struct {
+	Accepted bool   `json:"accepted" tsdoc:"是否接受 / Whether accepted"`
+	Reason   string `json:"reason,omitempty" tsdoc:"原因说明 / Reason detail"`
+}
+
+type wsServerBroadcastPayload struct {
+	FromClientID string `json:"fromClientID" tsdoc:"发送者客户端ID / Sender client identifier"`
+	RoomID       string `json:"roomID" tsdoc:"房间ID / Room identifier"`
+	Level        string `json:"level" tsunion:"warning,success,error" tsdoc:"消息等级 / Message level"`
+	Priority     int    `json:"priority" tsunion:"1,2,3" tsdoc:"优先级 / Priority"`
+	Text         string `json:"text" tsdoc:"广播文本 / Broadcast text"`
+}
+
+// TestServerAPIExportMetadataJSON
+// 这个测试验证端点元数据的 JSON 批量导出：
+// 1) ExportMetadataJSON 会把 CollectAPIModel 的结果写入相对路径文件，内容可被 json 反序列化。
+// 2) 端点条目包含 method、拼接后的 fullPath，以及与 TS 一致的请求/响应类型名。
+// 3) path 参数会带上字段名与 tsdoc 描述，便于外部工具直接消费。
+func TestServerAPIExportMetadataJSON(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+
+	moduleRoot := cwd
+	for {
+		if _, statErr := os.Stat(filepath.Join(moduleRoot, "go.mod")); statErr == nil {
+			break
+		}
+		next := filepath.Dir(moduleRoot)
+		if next == moduleRoot {
+			t.Fatalf("go.mod not found from cwd: %s", cwd)
+		}
+		moduleRoot = next
+	}
+
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(moduleRoot); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	api := ServerAPI{
+		BasePath:  "/api",
+		GroupPath: "/v1",
+		Endpoints: buildCommonHTTPTestAPIs(),
+	}
+	outPath := filepath.Join(".generated", "schema", "server", "api_metadata.json")
+	if err := api.ExportMetadataJSON(outPath); err != nil {
+		t.Fatalf("ServerAPI.ExportMetadataJSON returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(moduleRoot, outPath))
+	if err != nil {
+		t.Fatalf("read generated json file failed: %v", err)
+	}
+	var model APIModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		t.Fatalf("unmarshal metadata json failed: %v", err)
+	}
+	if model.BasePath != "/api" || model.GroupPath != "/v1" {
+		t.Fatalf("expected base/group path in metadata, got %q %q", model.BasePath, model.GroupPath)
+	}
+
+	var found *APIEndpointModel
+	for i := range model.Endpoints {
+		if model.Endpoints[i].Name == "GetPersonByID" {
+			found = &model.Endpoints[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected GetPersonByID entry in metadata json")
+	}
+	if found.Method != HTTPMethodGet || found.FullPath != "/api/v1/Person/:ID" {
+		t.Fatalf("expected method and full path in metadata, got %s %s", found.Method, found.FullPath)
+	}
+	if len(found.PathParams) != 1 || found.PathParams[0].Name != "id" || found.PathParams[0].Description == "" {
+		t.Fatalf("expected path param shape in metadata, got %+v", found.PathParams)
+	}
+	if len(found.Responses) == 0 || found.Responses[0].Type != "PersonDetailResp" {
+		t.Fatalf("expected response type name in metadata, got %+v", found.Responses)
+	}
+}
+
+// TestServerAPIExportOpenAPI
+// 这个测试验证 OpenAPI 3.1 导出：
+// 1) 输出文件可被 json 反序列化，openapi 版本为 3.1.0。
+// 2) gin 的 `:ID` 路径参数被转换为 `{ID}`，query/header/cookie 参数带上正确的 in。
+// 3) 多个端点共用的具名结构体只生成一个 component，并通过 $ref 引用。
+// 4) int64 字段遵循 TSInt64MappingMode（string 模式下输出 string/int64）。
+func TestServerAPIExportOpenAPI(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	oldInt64Mode := TSInt64MappingMode
+	t.Cleanup(func() { SetTSInt64MappingMode(oldInt64Mode) })
+	SetTSInt64MappingMode(TSInt64ModeString)
+
+	api := ServerAPI{
+		BasePath:  "/api",
+		GroupPath: "/v1",
+		Endpoints: buildCommonHTTPTestAPIs(),
+	}
+	if err := api.ExportOpenAPI("openapi.json"); err != nil {
+		t.Fatalf("ServerAPI.ExportOpenAPI returned error: %v", err)
+	}
+	data, err := os.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatalf("read generated openapi file failed: %v", err)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+			RequestBody *struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal openapi json failed: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Fatalf("expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+
+	byID, ok := doc.Paths["/api/v1/Person/{ID}"]["get"]
+	if !ok {
+		t.Fatalf("expected converted path /api/v1/Person/{ID}, got paths %v", doc.Paths)
+	}
+	if byID.OperationID != "getPersonByID" {
+		t.Fatalf("expected operationId getPersonByID, got %q", byID.OperationID)
+	}
+	if len(byID.Parameters) != 1 || byID.Parameters[0].Name != "id" || byID.Parameters[0].In != "path" || !byID.Parameters[0].Required {
+		t.Fatalf("expected required path param id, got %+v", byID.Parameters)
+	}
+	if ref := byID.Responses["200"].Content["application/json"].Schema["$ref"]; ref != "#/components/schemas/PersonDetailResp" {
+		t.Fatalf("expected response $ref to PersonDetailResp, got %v", ref)
+	}
+
+	list, ok := doc.Paths["/api/v1/people"]["get"]
+	if !ok {
+		t.Fatalf("expected /api/v1/people path")
+	}
+	in := map[string]string{}
+	for _, p := range list.Parameters {
+		in[p.Name] = p.In
+	}
+	if in["page"] != "query" || in["pageSize"] != "query" || in["ClientID"] != "header" || in["sessionID"] != "cookie" {
+		t.Fatalf("expected query/header/cookie params with correct in, got %v", in)
+	}
+
+	detail, ok := doc.Paths["/api/v1/person/detail"]["post"]
+	if !ok || detail.RequestBody == nil {
+		t.Fatalf("expected POST /api/v1/person/detail with request body")
+	}
+	if _, ok := detail.RequestBody.Content["application/json"]; !ok {
+		t.Fatalf("expected json request body content, got %v", detail.RequestBody.Content)
+	}
+
+	if len(doc.Components.Schemas) != 3 {
+		t.Fatalf("expected PersonDetailResp/ResumeItem/GetPersonReq components only, got %d", len(doc.Components.Schemas))
+	}
+	salary := doc.Components.Schemas["PersonDetailResp"].Properties["salary"]
+	if salary["type"] != "string" || salary["format"] != "int64" {
+		t.Fatalf("expected int64 salary as string/int64 in string mode, got %v", salary)
+	}
+	if items, _ := doc.Components.Schemas["PersonDetailResp"].Properties["resumes"]["items"].(map[string]any); items["$ref"] != "#/components/schemas/ResumeItem" {
+		t.Fatalf("expected nested struct $ref in array items, got %v", doc.Components.Schemas["PersonDetailResp"].Properties["resumes"])
+	}
+}
+
+func buildCommonWSTestEndpoint() *WebSocketEndpoint {
+	endpoint := &WebSocketEndpoint{
+		Name:              "chat_events",
+		Path:              "/chat/events",
+		ClientMessageType: reflect.TypeOf(wsClientEnvelope{}),
+		ServerMessageType: reflect.TypeOf(wsServerEnvelope{}),
+		MessageTypes:      []string{"room:join", "chat:text", "system:ack"},
+	}
+	RegisterWebSocketTypedHandler(endpoint, "room:join", func(payload wsClientJoinRoomPayload, _ *WebSocketContext) (any, error) { return nil, nil })
+	RegisterWebSocketTypedHandler(endpoint, "chat:text", func(payload wsClientChatTextPayload, _ *WebSocketContext) (any, error) { return nil, nil })
+	RegisterWebSocketTypedHandler(endpoint, "system:ack", func(payload wsClientNoPayload, _ *WebSocketContext) (any, error) { return nil, nil })
+	RegisterWebSocketServerPayloadType[wsServerAckPayload](endpoint, "system:ack")
+	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "chat:text")
+	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "room:join")
+	return endpoint
+}
+
+func buildNotifyWSTestEndpoint() *WebSocketEndpoint {
+	endpoint := &WebSocketEndpoint{
+		Name:              "notify_events",
+		Path:              "/notify/events",
+		ClientMessageType: reflect.TypeOf(wsClientEnvelope{}),
+		ServerMessageType: reflect.TypeOf(wsServerEnvelope{}),
+		MessageTypes:      []string{"notify:new", "notify:read"},
+	}
+	RegisterWebSocketTypedHandler(endpoint, "notify:new", func(payload wsClientChatTextPayload, _ *WebSocketContext) (any, error) { return nil, nil })
+	RegisterWebSocketTypedHandler(endpoint, "notify:read", func(payload wsClientNoPayload, _ *WebSocketContext) (any, error) { return nil, nil })
+	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "notify:new")
+	RegisterWebSocketServerPayloadType[wsServerAckPayload](endpoint, "notify:read")
+	return endpoint
+}
+
+// TestGenerateJSONSchema
+// 这个测试验证单个类型的 JSON Schema 导出：
+// 1) 根类型通过 $ref 引用 definitions，输出 draft-07 的 $schema。
+// 2) required 只包含未标记 omitempty 的 json 字段，json:"-" 字段被忽略。
+// 3) tsunion 输出为 enum，time.Time 为 date-time 字符串，int64 遵循 TSInt64MappingMode。
+// 4) 具名嵌套结构体放入 definitions 并通过 $ref 引用。
+func TestGenerateJSONSchema(t *testing.T) {
+	type SchemaAddress struct {
+		City string `json:"city"`
+	}
+	type SchemaSignupForm struct {
+		Name      string          `json:"name" tsdoc:"Display name"`
+		Role      string          `json:"role" tsunion:"admin,member"`
+		Level     int             `json:"level,omitempty" tsunion:"1,2,3"`
+		BirthDate time.Time       `json:"birthDate"`
+		Balance   int64           `json:"balance"`
+		Address   *SchemaAddress  `json:"address,omitempty"`
+		Others    []SchemaAddress `json:"others"`
+		Secret    string          `json:"-"`
+	}
+	oldInt64Mode := TSInt64MappingMode
+	t.Cleanup(func() { SetTSInt64MappingMode(oldInt64Mode) })
+	SetTSInt64MappingMode(TSInt64ModeString)
+
+	data, err := GenerateJSONSchema(reflect.TypeOf(SchemaSignupForm{}))
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema returned error: %v", err)
+	}
+	var schema struct {
+		Schema      string `json:"$schema"`
+		Ref         string `json:"$ref"`
+		Definitions map[string]struct {
+			Required   []string                  `json:"required"`
+			Properties map[string]map[string]any `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("unmarshal json schema failed: %v", err)
+	}
+	if schema.Schema != JSONSchemaDraft || schema.Ref != "#/definitions/SchemaSignupForm" {
+		t.Fatalf("unexpected root schema: %s", data)
+	}
+	form, ok := schema.Definitions["SchemaSignupForm"]
+	if !ok {
+		t.Fatalf("expected root type in definitions, got %s", data)
+	}
+	if !reflect.DeepEqual(form.Required, []string{"name", "role", "birthDate", "balance", "others"}) {
+		t.Fatalf("unexpected required fields: %v", form.Required)
+	}
+	if _, ok := form.Properties["Secret"]; ok {
+		t.Fatalf("expected json:\"-\" field to be omitted")
+	}
+	if got := form.Properties["role"]["enum"]; !reflect.DeepEqual(got, []any{"admin", "member"}) {
+		t.Fatalf("expected string enum, got %v", got)
+	}
+	if got := form.Properties["level"]["enum"]; !reflect.DeepEqual(got, []any{float64(1), float64(2), float64(3)}) {
+		t.Fatalf("expected numeric enum, got %v", got)
+	}
+	if form.Properties["name"]["description"] != "Display name" {
+		t.Fatalf("expected tsdoc as description, got %v", form.Properties["name"])
+	}
+	if form.Properties["birthDate"]["type"] != "string" || form.Properties["birthDate"]["format"] != "date-time" {
+		t.Fatalf("expected time.Time as date-time string, got %v", form.Properties["birthDate"])
+	}
+	if form.Properties["balance"]["type"] != "string" || form.Properties["balance"]["format"] != "int64" {
+		t.Fatalf("expected int64 to follow TSInt64MappingMode, got %v", form.Properties["balance"])
+	}
+	if form.Properties["address"]["$ref"] != "#/definitions/SchemaAddress" {
+		t.Fatalf("expected nested struct $ref, got %v", form.Properties["address"])
+	}
+	if items, _ := form.Properties["others"]["items"].(map[string]any); items["$ref"] != "#/definitions/SchemaAddress" {
+		t.Fatalf("expected array items to reuse the nested definition, got %v", form.Properties["others"])
+	}
+	if address := schema.Definitions["SchemaAddress"]; !reflect.DeepEqual(address.Required, []string{"city"}) {
+		t.Fatalf("expected nested definition with required city, got %+v", address)
+	}
+
+	if _, err := GenerateJSONSchema(nil); err == nil {
+		t.Fatalf("expected error for nil type")
+	}
+}
+
+// TestSetTSExportPolicy
+// 这个测试验证自定义导出策略：
+// 1) 策略返回 false 时，即使处于 gin debug 模式，ExportTS 也不会写文件。
+// 2) 策略返回 true 时，即使处于 release 模式也会导出。
+// 3) 传入 nil 恢复默认行为（仅 debug 模式导出）。
+func TestSetTSExportPolicy(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	oldMode := gin.Mode()
+	oldPolicy := TSExportPolicy
+	t.Cleanup(func() {
+		gin.SetMode(oldMode)
+		SetTSExportPolicy(oldPolicy)
+	})
+
+	api := ServerAPI{BasePath: "/api", Endpoints: buildCommonHTTPTestAPIs()}
+
+	gin.SetMode(gin.DebugMode)
+	SetTSExportPolicy(func() bool { return false })
+	if err := api.ExportTS("api.ts"); err != nil {
+		t.Fatalf("ServerAPI.ExportTS returned error: %v", err)
+	}
+	if _, err := os.Stat("api.ts"); !os.IsNotExist(err) {
+		t.Fatalf("expected policy returning false to suppress export in debug mode")
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	SetTSExportPolicy(func() bool { return true })
+	if err := api.ExportMetadataJSON("api.json"); err != nil {
+		t.Fatalf("ServerAPI.ExportMetadataJSON returned error: %v", err)
+	}
+	if _, err := os.Stat("api.json"); err != nil {
+		t.Fatalf("expected policy returning true to export in release mode: %v", err)
+	}
+
+	SetTSExportPolicy(nil)
+	if shouldExportTSInCurrentEnv() {
+		t.Fatalf("expected nil policy to restore the gin debug mode default")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_ClassAndTypedHandlers
+// 这个测试验证 WebSocket TS 生成的核心能力，覆盖面最大：
+// 1) 基础 TypedWebSocketClient 是否生成（状态字段、计数器、生命周期订阅等）。
+// 2) URL 解析策略是否同时覆盖开发环境/生产环境分支。
+// 3) validator + ensure 是否为 WS 相关 interface 正确生成。
+// 4) onType/onTyped 及其 options 默认校验逻辑是否按设计输出。
+// 5) 每个 WS Endpoint 专属 class（如 ChatEvents）及 message type union、onXxx helper 是否完整。
+func TestGenerateWebSocketClientFromEndpoints_ClassAndTypedHandlers(t *testing.T) {
+	ws := buildCommonWSTestEndpoint()
+
+	code, err := generateWebSocketClientFromEndpoints("/ws", "/v1", []WebSocketEndpointLike{ws})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+
+	if !strings.Contains(code, "export class TypedWebSocketClient<") {
+		t.Fatalf("expected class based websocket client generation")
+	}
+	if !strings.Contains(code, "public status:") || !strings.Contains(code, "connecting") || !strings.Contains(code, "closing") || !strings.Contains(code, "closed") {
+		t.Fatalf("expected websocket client status member generation")
+	}
+	if !strings.Contains(code, "public readonly url: string;") {
+		t.Fatalf("expected websocket client url member generation")
+	}
+	if !strings.Contains(code, "public messagesSent = 0;") || !strings.Contains(code, "public messagesReceived = 0;") {
+		t.Fatalf("expected websocket client message counters generation")
+	}
+	if !strings.Contains(code, "window.location.hostname}:${resolveGinPort()}") {
+		t.Fatalf("expected websocket dev-mode host with ginPort generation")
+	}
+	if !strings.Contains(code, "useRuntimeConfig().public.ginPort") {
+		t.Fatalf("expected websocket ginPort to read from nuxt runtimeConfig public")
+	}
+	if !strings.Contains(code, "(import.meta as any).env?.DEV") {
+		t.Fatalf("expected websocket env mode to read from import.meta env")
+	}
+	if !strings.Contains(code, "`${protocol}://${window.location.host}${url}`") {
+		t.Fatalf("expected websocket prod-mode URL to follow browser host")
+	}
+	if !strings.Contains(code, "get readyState(): number {") || !strings.Contains(code, "get isOpen(): boolean {") {
+		t.Fatalf("expected websocket client ready-state getters generation")
+	}
+	if !strings.Contains(code, "this.messagesSent += 1;") || !strings.Contains(code, "this.messagesReceived += 1;") {
+		t.Fatalf("expected websocket client counters update logic")
+	}
+	if !strings.Contains(code, "export function validateWsClientEnvelope(") || !strings.Contains(code, "value is WsClientEnvelope") {
+		t.Fatalf("expected websocket interface validator generation")
+	}
+	if !strings.Contains(code, "/** 消息类型 / Message type */") {
+		t.Fatalf("expected websocket tsdoc comment generation")
+	}
+	if !strings.Contains(code, "export function validateWsClientJoinRoomPayload(") || !strings.Contains(code, "value is WsClientJoinRoomPayload") {
+		t.Fatalf("expected websocket validator generation for nested client payload")
+	}
+	if !strings.Contains(code, "export function validateWsServerAckPayload(") || !strings.Contains(code, "value is WsServerAckPayload") {
+		t.Fatalf("expected websocket validator generation for nested server payload")
+	}
+	if !strings.Contains(code, "level:") || !strings.Contains(code, "warning") || !strings.Contains(code, "success") || !strings.Contains(code, "error") {
+		t.Fatalf("expected websocket tsunion field to generate string literal union")
+	}
+	if !strings.Contains(code, "typeof obj[\"level\"] ===") || !strings.Contains(code, "obj[\"level\"] ===") {
+		t.Fatalf("expected websocket tsunion validator generation")
+	}
+	if !strings.Contains(code, "priority: 1 | 2 | 3;") {
+		t.Fatalf("expected websocket numeric tsunion field generation")
+	}
+	if !strings.Contains(code, "typeof obj[\"priority\"] ===") || !strings.Contains(code, "obj[\"priority\"] === 3") {
+		t.Fatalf("expected websocket numeric tsunion validator generation")
+	}
+	if !strings.Contains(code, "export function ensureWsClientEnvelope(") || !strings.Contains(code, "): WsClientEnvelope") {
+		t.Fatalf("expected websocket interface ensure function generation")
+	}
+	if !strings.Contains(code, "if (!validateWsClientEnvelope(value)) {") {
+		t.Fatalf("expected websocket interface ensure function to validate first")
+	}
+	if !strings.Contains(code, "onType(") || !strings.Contains(code, "type: TType") || !strings.Contains(code, "TypeHandlerOptions<TReceive>") {
+		t.Fatalf("expected onType typed handler registration")
+	}
+	if !strings.Contains(code, "onTyped<TPayload>(") {
+		t.Fatalf("expected onTyped generic payload handler registration")
+	}
+	if !strings.Contains(code, "onTypedMessage<TType extends ChatEventsMessageType>(") {
+		t.Fatalf("expected discriminated receive union helper generation")
+	}
+	if !strings.Contains(code, "sendTypedMessage(message: ChatEventsSendUnion): void {") {
+		t.Fatalf("expected discriminated send union helper generation")
+	}
+	if !strings.Contains(code, "validate?: (message: TReceive) => boolean;") {
+		t.Fatalf("expected onType validator options generation")
+	}
+	if !strings.Contains(code, "validate?: (payload: unknown, message: TReceive) => boolean;") {
+		t.Fatalf("expected onTyped validator options generation")
+	}
+	if !strings.Contains(code, "if (options?.validate && !options.validate(rawPayload, message)) return;") {
+		t.Fatalf("expected onTyped validator usage")
+	}
+	if !strings.Contains(code, "options?: TypeHandlerOptions<TReceive>") || !strings.Contains(code, "options?: TypedHandlerOptions<TReceive, TPayload>") {
+		t.Fatalf("expected base on handlers to keep optional options signature")
+	}
+	if !strings.Contains(code, "options?: TypeHandlerOptions<WsServerEnvelope>") {
+		t.Fatalf("expected endpoint type handlers to keep optional options signature")
+	}
+	if !strings.Contains(code, "if (options === undefined)") || !strings.Contains(code, "options = { validate: validateWsServerEnvelope };") {
+		t.Fatalf("expected endpoint type handlers to provide default message validator")
+	}
+	if !strings.Contains(code, "function defaultValidatePayload(") || !strings.Contains(code, "return validateWsServerEnvelope(message);") || !strings.Contains(code, "options = { validate: defaultValidatePayload };") {
+		t.Fatalf("expected endpoint payload handlers to provide default message validator")
+	}
+	if !strings.Contains(code, "// ignore single listener errors and continue dispatch") {
+		t.Fatalf("expected listener dispatch fallback behavior")
+	}
+	if !strings.Contains(code, "export class ChatEvents<") || !strings.Contains(code, "extends TypedWebSocketClient<") || !strings.Contains(code, "ChatEventsMessageType") {
+		t.Fatalf("expected endpoint-specific class extending TypedWebSocketClient")
+	}
+	if !strings.Contains(code, "export type ChatEventsMessageType =") || !strings.Contains(code, "chat:text") || !strings.Contains(code, "room:join") || !strings.Contains(code, "system:ack") {
+		t.Fatalf("expected message type union alias generation")
+	}
+	if strings.Contains(code, "export function chatEvents<TSend =") {
+		t.Fatalf("expected legacy endpoint factory function to be removed")
+	}
+	if !strings.Contains(code, "export function createChatEvents<TSend =") || !strings.Contains(code, "return new ChatEvents<TSend>(options);") {
+		t.Fatalf("expected generated convenience create function for endpoint class")
+	}
+	if !strings.Contains(code, "onRoomJoinType(") || !strings.Contains(code, "onChatTextType(") || !strings.Contains(code, "onSystemAckType(") {
+		t.Fatalf("expected endpoint-specific on<Type>Type helpers")
+	}
+	if !strings.Contains(code, "onRoomJoinPayload(") || strings.Contains(code, "onRoomJoinPayload<TPayload>(") {
+		t.Fatalf("expected typed payload helper without generic when payload map is available")
+	}
+	if !strings.Contains(code, "options?: TypedHandlerOptions<WsServerEnvelope, WsServerBroadcastPayload>") {
+		t.Fatalf("expected endpoint payload handlers to use mapped payload type options")
+	}
+	if !strings.Contains(code, "options?: TypedHandlerOptions<WsServerEnvelope, WsServerAckPayload>") {
+		t.Fatalf("expected endpoint payload handlers to use mapped ack payload type options")
+	}
+	if !strings.Contains(code, "sendRoomJoinPayload(payload: WsClientJoinRoomPayload): void {") {
+		t.Fatalf("expected typed send helper generation for room:join payload")
+	}
+	if !strings.Contains(code, "sendChatTextPayload(payload: WsClientChatTextPayload): void {") {
+		t.Fatalf("expected typed send helper generation for chat:text payload")
+	}
+	if !strings.Contains(code, "export interface ChatEventsServerPayloadByType") || !strings.Contains(code, `"system:ack": WsServerAckPayload;`) {
+		t.Fatalf("expected server payload map generation for message types")
+	}
+	if !strings.Contains(code, "export interface ChatEventsClientPayloadByType") || !strings.Contains(code, `"chat:text": WsClientChatTextPayload;`) {
+		t.Fatalf("expected client payload map generation for message types")
+	}
+	if !strings.Contains(code, "export type ChatEventsReceiveUnion =") || !strings.Contains(code, `{ type: "chat:text"; payload: WsServerBroadcastPayload }`) {
+		t.Fatalf("expected receive discriminated union generation")
+	}
+	if !strings.Contains(code, "export type ChatEventsSendUnion =") || !strings.Contains(code, `{ type: "room:join"; payload: WsClientJoinRoomPayload }`) {
+		t.Fatalf("expected send discriminated union generation")
+	}
+	if !strings.Contains(code, "static readonly MESSAGE_TYPES = [") {
+		t.Fatalf("expected endpoint-specific message type metadata")
+	}
+	if !strings.Contains(code, "options: WebSocketConvertOptions<TSend, WsServerEnvelope>") {
+		t.Fatalf("expected required options in endpoint class constructor")
+	}
+	if !strings.Contains(code, "options: WebSocketConvertOptions<TSend, TReceive>") {
+		t.Fatalf("expected required options in websocket client constructor")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_ReconnectBackoff
+// 这个测试验证重连退避策略的默认实现：
+// 1) 生成 computeReconnectDelay，并暴露 backoffMs/maxDelayMs/jitter 三个配置项与默认值。
+// 2) 延迟按 2^attempt 指数增长，并始终被 maxDelayMs 截断。
+// 3) jitter 开启时采用 full-jitter（在 [0, 上限] 内随机），避免服务端抖动后集中重连。
+func TestGenerateWebSocketClientFromEndpoints_ReconnectBackoff(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface WebSocketReconnectOptions") ||
+		!strings.Contains(code, "backoffMs?: number;") ||
+		!strings.Contains(code, "maxDelayMs?: number;") ||
+		!strings.Contains(code, "jitter?: boolean;") {
+		t.Fatalf("expected reconnect backoff options generation")
+	}
+	if !strings.Contains(code, "reconnect?: WebSocketReconnectOptions;") {
+		t.Fatalf("expected reconnect options on WebSocketConvertOptions")
+	}
+	if !strings.Contains(code, "export const computeReconnectDelay = (") {
+		t.Fatalf("expected default backoff implementation")
+	}
+	if !strings.Contains(code, "backoffMs * 2 ** exponent") || !strings.Contains(code, "Math.min(maxDelayMs, ") {
+		t.Fatalf("expected exponential growth capped at maxDelayMs")
+	}
+	if !strings.Contains(code, "Math.floor(random() * capped)") {
+		t.Fatalf("expected full-jitter delay when jitter is enabled")
+	}
+	if !strings.Contains(code, "maxDelayMs: 30000") || !strings.Contains(code, "backoffMs: 500") {
+		t.Fatalf("expected sane backoff defaults")
+	}
+	if !strings.Contains(code, "nextReconnectDelay(): number {") {
+		t.Fatalf("expected client helper that derives delay from reconnectCount")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_AutoReconnect
+// 这个测试验证自动重连：
+// 1) reconnect 配置新增 enabled/maxRetries/baseDelayMs。
+// 2) 非主动关闭时按退避延迟重新 connect，且每次尝试 reconnectCount 自增，open 成功后归零。
+// 3) 主动 close() 会清除待执行的重连定时器并禁止后续重连。
+// 4) 监听器保存在客户端上，旧 socket 的迟到事件会被忽略。
+func TestGenerateWebSocketClientFromEndpoints_AutoReconnect(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	for _, field := range []string{"enabled?: boolean;", "maxRetries?: number;", "baseDelayMs?: number;"} {
+		if !strings.Contains(code, field) {
+			t.Fatalf("expected reconnect option %q", field)
+		}
+	}
+	if !strings.Contains(code, "private connect(): void {") || !strings.Contains(code, "this.connect();") {
+		t.Fatalf("expected socket creation to be extracted into connect()")
+	}
+	if !strings.Contains(code, "private scheduleReconnect(): void {") ||
+		!strings.Contains(code, "this.reconnectCount += 1;") ||
+		!strings.Contains(code, "this.reconnectCount = 0;") {
+		t.Fatalf("expected reconnect scheduling with reconnectCount bookkeeping")
+	}
+	if !strings.Contains(code, "this.reconnectCount >= options.maxRetries") {
+		t.Fatalf("expected maxRetries to stop reconnecting")
+	}
+	if !strings.Contains(code, "this.manuallyClosed = true;") || !strings.Contains(code, "clearTimeout(this.reconnectTimer);") {
+		t.Fatalf("expected manual close() to disable further reconnects")
+	}
+	if !strings.Contains(code, "if (socket !== this.socket) return;") {
+		t.Fatalf("expected stale socket events to be ignored")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_Heartbeat
+// 这个测试验证客户端心跳：
+// 1) WebSocketConvertOptions 暴露 heartbeat 配置（intervalMs/message）。
+// 2) open 时启动定时心跳，默认发送 { type: 'ping' }；close 时与主动 close() 时停止定时器。
+func TestGenerateWebSocketClientFromEndpoints_Heartbeat(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface WebSocketHeartbeatOptions") ||
+		!strings.Contains(code, "intervalMs: number;") ||
+		!strings.Contains(code, "heartbeat?: WebSocketHeartbeatOptions;") {
+		t.Fatalf("expected heartbeat options generation")
+	}
+	if !strings.Contains(code, "private startHeartbeat(): void {") || !strings.Contains(code, "this.startHeartbeat();") {
+		t.Fatalf("expected heartbeat to start on open")
+	}
+	if !strings.Contains(code, "type: 'ping'") && !strings.Contains(code, `type: "ping"`) {
+		t.Fatalf("expected default ping heartbeat message")
+	}
+	if strings.Count(code, "this.stopHeartbeat();") < 3 || !strings.Contains(code, "clearInterval(this.heartbeatTimer);") {
+		t.Fatalf("expected heartbeat timer to be cleared on close")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_WaitOpen
+// 这个测试验证 waitOpen：
+// 1) 已打开时直接 resolve，否则在 open 事件后 resolve。
+// 2) 打开前发生 error/close 时 reject，并支持 timeoutMs 与 AbortSignal。
+// 3) 结束时清理 open/error/close 监听器与定时器，避免泄漏。
+func TestGenerateWebSocketClientFromEndpoints_WaitOpen(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export interface WebSocketWaitOpenOptions") ||
+		!strings.Contains(code, "signal?: AbortSignal;") ||
+		!strings.Contains(code, "timeoutMs?: number;") {
+		t.Fatalf("expected waitOpen options generation")
+	}
+	if !strings.Contains(code, "waitOpen(options?: WebSocketWaitOpenOptions): Promise<void> {") ||
+		!strings.Contains(code, "if (this.isOpen) return Promise.resolve();") {
+		t.Fatalf("expected waitOpen method that resolves immediately when already open")
+	}
+	if !strings.Contains(code, "const offOpen = this.onOpen(") || !strings.Contains(code, "resolve();") {
+		t.Fatalf("expected waitOpen to resolve on open")
+	}
+	if !strings.Contains(code, "const offError = this.onError(") || !strings.Contains(code, "WebSocket error before open") {
+		t.Fatalf("expected waitOpen to reject on error before open")
+	}
+	if !strings.Contains(code, "const offClose = this.onClose(") || !strings.Contains(code, "WebSocket closed before open") {
+		t.Fatalf("expected waitOpen to reject on close before open")
+	}
+	if !strings.Contains(code, "signal.addEventListener(") || !strings.Contains(code, "WebSocket waitOpen aborted") {
+		t.Fatalf("expected waitOpen to honor AbortSignal")
+	}
+	if !strings.Contains(code, "WebSocket did not open within") || !strings.Contains(code, "if (timer !== undefined) clearTimeout(timer);") {
+		t.Fatalf("expected waitOpen timeout with cleanup")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_BinaryFrames
+// 这个测试验证客户端二进制帧支持：
+// 1) socket 使用 arraybuffer 接收二进制，二进制帧交给 onBinary 订阅者（Uint8Array）。
+// 2) 二进制帧在 JSON 解析与按 type 分发之前返回，不会进入 defaultMessageType。
+// 3) 生成 sendBinary(data: ArrayBuffer | Uint8Array)。
+func TestGenerateWebSocketClientFromEndpoints_BinaryFrames(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "socket.binaryType = ") || !strings.Contains(code, "arraybuffer") {
+		t.Fatalf("expected arraybuffer binaryType")
+	}
+	if !strings.Contains(code, "sendBinary(data: ArrayBuffer | Uint8Array): void {") {
+		t.Fatalf("expected sendBinary method")
+	}
+	if !strings.Contains(code, "onBinary(handler: (data: Uint8Array) => void): () => void {") {
+		t.Fatalf("expected onBinary subscription")
+	}
+	binaryIdx := strings.Index(code, "if (event.data instanceof ArrayBuffer) {")
+	parseIdx := strings.Index(code, "payload = JSON.parse(payload);")
+	if binaryIdx < 0 || parseIdx < 0 || binaryIdx > parseIdx {
+		t.Fatalf("expected binary frames to be dispatched before JSON parsing")
+	}
+	if !strings.Contains(code, "this.emitBinary(new Uint8Array(event.data));") {
+		t.Fatalf("expected binary frames to be emitted as Uint8Array")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_QueryOption
+// 这个测试验证连接参数：
+// 1) WebSocketConvertOptions 暴露 query 配置，用于携带 token 等连接时参数。
+// 2) resolveWebSocketURL 在解析出的地址后追加查询串，已有 ? 时用 & 连接。
+func TestGenerateWebSocketClientFromEndpoints_QueryOption(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "query?: Record<string, string>;") {
+		t.Fatalf("expected query option on WebSocketConvertOptions")
+	}
+	if !strings.Contains(code, "const resolveWebSocketURL = (url: string, query?: Record<string, string>): string => {") ||
+		!strings.Contains(code, "new URLSearchParams()") {
+		t.Fatalf("expected resolveWebSocketURL to append query params")
+	}
+	if !strings.Contains(code, "this.url = resolveWebSocketURL(url, options?.query);") {
+		t.Fatalf("expected client constructor to pass query option")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_AutoAck
+// 这个测试验证消息确认：
+// 1) 收到带 ackId 的消息时，客户端在分发前自动回传 { type: 'ack', ackId }。
+// 2) 仅在连接打开时发送确认。
+func TestGenerateWebSocketClientFromEndpoints_AutoAck(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "typeof payload.ackId === 'string'") || !strings.Contains(code, "this.sendAck(payload.ackId);") {
+		t.Fatalf("expected messages carrying ackId to be acknowledged")
+	}
+	if !strings.Contains(code, "private sendAck(ackId: string): void {") || !strings.Contains(code, "type: 'ack', ackId") {
+		t.Fatalf("expected sendAck to echo the ack id")
+	}
+	ackIdx := strings.Index(code, "this.sendAck(payload.ackId);")
+	emitIdx := strings.Index(code, "this.emitMessage(message);")
+	if ackIdx < 0 || emitIdx < 0 || ackIdx > emitIdx {
+		t.Fatalf("expected ack to be sent before dispatching the message")
 	}
-	RegisterWebSocketTypedHandler(endpoint, "room:join", func(payload wsClientJoinRoomPayload, _ *WebSocketContext) (any, error) { return nil, nil })
-	RegisterWebSocketTypedHandler(endpoint, "chat:text", func(payload wsClientChatTextPayload, _ *WebSocketContext) (any, error) { return nil, nil })
-	RegisterWebSocketTypedHandler(endpoint, "system:ack", func(payload wsClientNoPayload, _ *WebSocketContext) (any, error) { return nil, nil })
-	RegisterWebSocketServerPayloadType[wsServerAckPayload](endpoint, "system:ack")
-	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "chat:text")
-	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "room:join")
-	return endpoint
 }
 
-func buildNotifyWSTestEndpoint() *WebSocketEndpoint {
-	endpoint := &WebSocketEndpoint{
-		Name:              "notify_events",
-		Path:              "/notify/events",
-		ClientMessageType: reflect.TypeOf(wsClientEnvelope{}),
-		ServerMessageType: reflect.TypeOf(wsServerEnvelope{}),
-		MessageTypes:      []string{"notify:new", "notify:read"},
-	}
-	RegisterWebSocketTypedHandler(endpoint, "notify:new", func(payload wsClientChatTextPayload, _ *WebSocketContext) (any, error) { return nil, nil })
-	RegisterWebSocketTypedHandler(endpoint, "notify:read", func(payload wsClientNoPayload, _ *WebSocketContext) (any, error) { return nil, nil })
-	RegisterWebSocketServerPayloadType[wsServerBroadcastPayload](endpoint, "notify:new")
-	RegisterWebSocketServerPayloadType[wsServerAckPayload](endpoint, "notify:read")
-	return endpoint
+type rpcAddParams struct {
+	A int `json:"a"`
+	B int `json:"b"`
 }
 
-// TestGenerateWebSocketClientFromEndpoints_ClassAndTypedHandlers
-// 这个测试验证 WebSocket TS 生成的核心能力，覆盖面最大：
-// 1) 基础 TypedWebSocketClient 是否生成（状态字段、计数器、生命周期订阅等）。
-// 2) URL 解析策略是否同时覆盖开发环境/生产环境分支。
-// 3) validator + ensure 是否为 WS 相关 interface 正确生成。
-// 4) onType/onTyped 及其 options 默认校验逻辑是否按设计输出。
-// 5) 每个 WS Endpoint 专属 class（如 ChatEvents）及 message type union、onXxx helper 是否完整。
-func TestGenerateWebSocketClientFromEndpoints_ClassAndTypedHandlers(t *testing.T) {
+type rpcAddResult struct {
+	Sum int `json:"sum"`
+}
+
+// TestGenerateWebSocketClientFromEndpoints_JSONRPC
+// 这个测试验证 JSON-RPC 2.0 客户端生成：
+// 1) 默认关闭，不生成 call/rpcXxx。
+// 2) 开启后 call 发送 { jsonrpc: '2.0', id, method, params } 信封，并按响应 id 匹配 resolve/reject。
+// 3) 每个通过 RegisterWebSocketRPCMethod 注册的方法生成强类型 rpcXxx(params, options)。
+// 4) 连接关闭时 reject 所有未完成的调用。
+func TestGenerateWebSocketClientFromEndpoints_JSONRPC(t *testing.T) {
 	ws := buildCommonWSTestEndpoint()
+	RegisterWebSocketRPCMethod(ws, "math.add", func(p rpcAddParams, _ *WebSocketContext) (rpcAddResult, error) {
+		return rpcAddResult{Sum: p.A + p.B}, nil
+	})
 
-	code, err := generateWebSocketClientFromEndpoints("/ws", "/v1", []WebSocketEndpointLike{ws})
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws})
 	if err != nil {
 		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
 	}
+	if strings.Contains(code, "rpcMathAdd(") || strings.Contains(code, "JsonRpcError") {
+		t.Fatalf("expected JSON-RPC generation to be off by default")
+	}
 
-	if !strings.Contains(code, "export class TypedWebSocketClient<") {
-		t.Fatalf("expected class based websocket client generation")
+	oldRPC := TSWebSocketJSONRPC
+	SetTSWebSocketJSONRPC(true)
+	t.Cleanup(func() {
+		SetTSWebSocketJSONRPC(oldRPC)
+	})
+
+	code, err = generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "public status:") || !strings.Contains(code, "connecting") || !strings.Contains(code, "closing") || !strings.Contains(code, "closed") {
-		t.Fatalf("expected websocket client status member generation")
+	if !strings.Contains(code, "call<TResult = unknown>(method: string, params?: unknown, options?: WebSocketRPCCallOptions): Promise<TResult> {") {
+		t.Fatalf("expected generic call method")
 	}
-	if !strings.Contains(code, "public readonly url: string;") {
-		t.Fatalf("expected websocket client url member generation")
+	if !strings.Contains(code, "{ jsonrpc: '2.0', id, method }") || !strings.Contains(code, "request.params = normalizeWsRequestJSON(params);") {
+		t.Fatalf("expected JSON-RPC request envelope")
 	}
-	if !strings.Contains(code, "public messagesSent = 0;") || !strings.Contains(code, "public messagesReceived = 0;") {
-		t.Fatalf("expected websocket client message counters generation")
+	if !strings.Contains(code, "payload.jsonrpc === '2.0' && typeof payload.id === 'number'") || !strings.Contains(code, "this.rpcPending.get(payload.id)") {
+		t.Fatalf("expected responses to be matched by id")
 	}
-	if !strings.Contains(code, "window.location.hostname}:${resolveGinPort()}") {
-		t.Fatalf("expected websocket dev-mode host with ginPort generation")
+	if !strings.Contains(code, "pending.reject(new JsonRpcError(") || !strings.Contains(code, "pending.resolve(normalizeWsResponseJSON(payload.result));") {
+		t.Fatalf("expected result/error handling for JSON-RPC responses")
 	}
-	if !strings.Contains(code, "useRuntimeConfig().public.ginPort") {
-		t.Fatalf("expected websocket ginPort to read from nuxt runtimeConfig public")
+	if !strings.Contains(code, "rpcMathAdd(params: RpcAddParams, options?: WebSocketRPCCallOptions): Promise<RpcAddResult> {") ||
+		!strings.Contains(code, "return this.call<RpcAddResult>(\"math.add\", params, options);") {
+		t.Fatalf("expected typed rpc method per registered method")
 	}
-	if !strings.Contains(code, "(import.meta as any).env?.DEV") {
-		t.Fatalf("expected websocket env mode to read from import.meta env")
+	if !strings.Contains(code, "this.rejectPendingCalls(") {
+		t.Fatalf("expected pending calls to be rejected on close")
 	}
-	if !strings.Contains(code, "`${protocol}://${window.location.host}${url}`") {
-		t.Fatalf("expected websocket prod-mode URL to follow browser host")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_MeasureLatency
+// 这个测试验证延迟测量：
+// 1) measureLatency 发送 { type: 'ping', ackId }，收到相同 ackId 的 ack 回显后以往返毫秒数 resolve。
+// 2) 结果记录到 lastLatencyMs；回显不会分发给消息监听器，也不会再被自动确认。
+// 3) 超时未回显时 reject。
+func TestGenerateWebSocketClientFromEndpoints_MeasureLatency(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "get readyState(): number {") || !strings.Contains(code, "get isOpen(): boolean {") {
-		t.Fatalf("expected websocket client ready-state getters generation")
+	if !strings.Contains(code, "measureLatency(options?: WebSocketLatencyOptions): Promise<number> {") {
+		t.Fatalf("expected measureLatency method")
 	}
-	if !strings.Contains(code, "this.messagesSent += 1;") || !strings.Contains(code, "this.messagesReceived += 1;") {
-		t.Fatalf("expected websocket client counters update logic")
+	if !strings.Contains(code, "public lastLatencyMs?: number;") || !strings.Contains(code, "this.lastLatencyMs = latency;") {
+		t.Fatalf("expected lastLatencyMs tracking")
 	}
-	if !strings.Contains(code, "export function validateWsClientEnvelope(") || !strings.Contains(code, "value is WsClientEnvelope") {
-		t.Fatalf("expected websocket interface validator generation")
+	if !strings.Contains(code, "this.socket.send(JSON.stringify({ type: 'ping', ackId }));") {
+		t.Fatalf("expected latency ping with ack id")
 	}
-	if !strings.Contains(code, "/** 消息类型 / Message type */") {
-		t.Fatalf("expected websocket tsdoc comment generation")
+	if !strings.Contains(code, "const settle = this.latencyPending.get(payload.ackId);") || !strings.Contains(code, "settle(nowMs());") {
+		t.Fatalf("expected echo to settle the pending latency measurement")
 	}
-	if !strings.Contains(code, "export function validateWsClientJoinRoomPayload(") || !strings.Contains(code, "value is WsClientJoinRoomPayload") {
-		t.Fatalf("expected websocket validator generation for nested client payload")
+	settleIdx := strings.Index(code, "settle(nowMs());")
+	emitIdx := strings.Index(code, "this.emitMessage(message);")
+	if settleIdx < 0 || emitIdx < 0 || settleIdx > emitIdx {
+		t.Fatalf("expected latency echo to be consumed before message dispatch")
 	}
-	if !strings.Contains(code, "export function validateWsServerAckPayload(") || !strings.Contains(code, "value is WsServerAckPayload") {
-		t.Fatalf("expected websocket validator generation for nested server payload")
+	if !strings.Contains(code, "payload.type !== 'ack' && typeof payload.ackId === 'string'") {
+		t.Fatalf("expected ack frames not to be acknowledged again")
 	}
-	if !strings.Contains(code, "level:") || !strings.Contains(code, "warning") || !strings.Contains(code, "success") || !strings.Contains(code, "error") {
-		t.Fatalf("expected websocket tsunion field to generate string literal union")
+	if !strings.Contains(code, "WebSocket latency ping timed out") {
+		t.Fatalf("expected latency timeout")
 	}
-	if !strings.Contains(code, "typeof obj[\"level\"] ===") || !strings.Contains(code, "obj[\"level\"] ===") {
-		t.Fatalf("expected websocket tsunion validator generation")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_RetryAfterReconnect
+// 这个测试验证重连期间的发送缓冲：
+// 1) retryAfterReconnect 启用时，未打开期间 send 的消息进入缓冲而不是直接写 socket。
+// 2) 每次 open（包括重连后的 open）都会按顺序 flush 缓冲，并累计 messagesFlushed。
+// 3) 超过 ttlMs 的消息在 flush 时被丢弃并计入 messagesDropped；超出 maxSize 时丢弃最旧的消息。
+// 4) 主动 close 会清空缓冲，避免下一次连接发送过期消息。
+func TestGenerateWebSocketClientFromEndpoints_RetryAfterReconnect(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("GenerateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "priority: 1 | 2 | 3;") {
-		t.Fatalf("expected websocket numeric tsunion field generation")
+	if !strings.Contains(code, "export interface WebSocketSendBufferOptions {") ||
+		!strings.Contains(code, "  retryAfterReconnect?: WebSocketSendBufferOptions;\n") {
+		t.Fatalf("expected retryAfterReconnect option")
 	}
-	if !strings.Contains(code, "typeof obj[\"priority\"] ===") || !strings.Contains(code, "obj[\"priority\"] === 3") {
-		t.Fatalf("expected websocket numeric tsunion validator generation")
+	if !strings.Contains(code, "    if (this.isOpen || this.manuallyClosed) return false;\n    return this.queueWhileConnecting || this.sendBufferOptions?.enabled === true;\n") ||
+		!strings.Contains(code, "this.sendBuffer.push({ data, queuedAt: Date.now() });") {
+		t.Fatalf("expected sends during reconnect to be buffered")
 	}
-	if !strings.Contains(code, "export function ensureWsClientEnvelope(") || !strings.Contains(code, "): WsClientEnvelope") {
-		t.Fatalf("expected websocket interface ensure function generation")
+	openIdx := strings.Index(code, "socket.addEventListener('open', (event) => {")
+	flushIdx := strings.Index(code, "      this.flushSendBuffer();\n")
+	closeIdx := strings.Index(code, "socket.addEventListener('close', (event) => {")
+	if openIdx < 0 || flushIdx < openIdx || flushIdx > closeIdx {
+		t.Fatalf("expected buffer to flush in the open handler of every connection")
 	}
-	if !strings.Contains(code, "if (!validateWsClientEnvelope(value)) {") {
-		t.Fatalf("expected websocket interface ensure function to validate first")
+	if !strings.Contains(code, "const item = this.sendBuffer.shift()!;") ||
+		!strings.Contains(code, "this.messagesFlushed += 1;") {
+		t.Fatalf("expected in-order flush with flushed counter")
 	}
-	if !strings.Contains(code, "onType(") || !strings.Contains(code, "type: TType") || !strings.Contains(code, "TypeHandlerOptions<TReceive>") {
-		t.Fatalf("expected onType typed handler registration")
+	if !strings.Contains(code, "if (ttlMs !== undefined && now - item.queuedAt > ttlMs) {") ||
+		!strings.Contains(code, "public messagesDropped = 0;") {
+		t.Fatalf("expected ttl-expired messages to be dropped and counted")
 	}
-	if !strings.Contains(code, "onTyped<TPayload>(") {
-		t.Fatalf("expected onTyped generic payload handler registration")
+	if !strings.Contains(code, "this.messagesDropped += this.sendBuffer.length;\n    this.sendBuffer.length = 0;") {
+		t.Fatalf("expected manual close to drop the buffer")
 	}
-	if !strings.Contains(code, "onTypedMessage<TType extends ChatEventsMessageType>(") {
-		t.Fatalf("expected discriminated receive union helper generation")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_CloseCodes
+// 这个测试验证关闭码支持：
+// 1) 运行时帮助代码中生成 WebSocketCloseCode 常量与同名类型（1000/1001/1008 等）。
+// 2) close(code?, reason?) 把参数透传给 socket.close，并标记为主动关闭以停止重连。
+func TestGenerateWebSocketClientFromEndpoints_CloseCodes(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "sendTypedMessage(message: ChatEventsSendUnion): void {") {
-		t.Fatalf("expected discriminated send union helper generation")
+	for _, want := range []string{
+		"export const WebSocketCloseCode = {\n  Normal: 1000,\n  GoingAway: 1001,\n",
+		"  PolicyViolation: 1008,\n",
+		"export type WebSocketCloseCode = (typeof WebSocketCloseCode)[keyof typeof WebSocketCloseCode];",
+	} {
+		if !strings.Contains(code, want) {
+			t.Fatalf("expected close code constants, missing %q", want)
+		}
 	}
-	if !strings.Contains(code, "validate?: (message: TReceive) => boolean;") {
-		t.Fatalf("expected onType validator options generation")
+	closeIdx := strings.Index(code, "  close(code?: number, reason?: string): void {\n    this.manuallyClosed = true;\n")
+	passIdx := strings.Index(code, "    this.socket.close(code, reason);\n")
+	if closeIdx < 0 || passIdx < closeIdx {
+		t.Fatalf("expected close(code, reason) to disable reconnect and pass through to socket.close")
 	}
-	if !strings.Contains(code, "validate?: (payload: unknown, message: TReceive) => boolean;") {
-		t.Fatalf("expected onTyped validator options generation")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_ReconnectEvents
+// 这个测试验证重连生命周期事件：
+// 1) 生成 onReconnecting(attempt) 与 onReconnected() 订阅方法及其监听集合。
+// 2) scheduleReconnect 在重连被禁用时提前返回，只有安排了重连才触发 onReconnecting。
+// 3) 重连后的 open 事件触发 onReconnected，主动 close() 清除重连状态。
+func TestGenerateWebSocketClientFromEndpoints_ReconnectEvents(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "if (options?.validate && !options.validate(rawPayload, message)) return;") {
-		t.Fatalf("expected onTyped validator usage")
+	if !strings.Contains(code, "  onReconnecting(handler: (attempt: number) => void): () => void {\n    this.reconnectingListeners.add(handler);\n") ||
+		!strings.Contains(code, "  onReconnected(handler: () => void): () => void {\n    this.reconnectedListeners.add(handler);\n") {
+		t.Fatalf("expected onReconnecting/onReconnected subscription methods")
 	}
-	if !strings.Contains(code, "options?: TypeHandlerOptions<TReceive>") || !strings.Contains(code, "options?: TypedHandlerOptions<TReceive, TPayload>") {
-		t.Fatalf("expected base on handlers to keep optional options signature")
+	scheduleIdx := strings.Index(code, "  private scheduleReconnect(): void {")
+	disabledIdx := strings.Index(code[scheduleIdx:], "    if (this.manuallyClosed || !options?.enabled) return;\n")
+	fireIdx := strings.Index(code[scheduleIdx:], "    for (const listener of this.reconnectingListeners) listener(this.reconnectCount);\n")
+	if scheduleIdx < 0 || disabledIdx < 0 || fireIdx < disabledIdx {
+		t.Fatalf("expected onReconnecting to fire only after a reconnect is scheduled")
 	}
-	if !strings.Contains(code, "options?: TypeHandlerOptions<WsServerEnvelope>") {
-		t.Fatalf("expected endpoint type handlers to keep optional options signature")
+	if !strings.Contains(code, "      if (this.reconnecting) {\n        this.reconnecting = false;\n        for (const listener of this.reconnectedListeners) listener();\n") {
+		t.Fatalf("expected onReconnected to fire on a reconnected open")
 	}
-	if !strings.Contains(code, "if (options === undefined)") || !strings.Contains(code, "options = { validate: validateWsServerEnvelope };") {
-		t.Fatalf("expected endpoint type handlers to provide default message validator")
+	if !strings.Contains(code, "    this.manuallyClosed = true;\n    this.reconnecting = false;\n") {
+		t.Fatalf("expected close() to reset reconnecting state")
 	}
-	if !strings.Contains(code, "function defaultValidatePayload(") || !strings.Contains(code, "return validateWsServerEnvelope(message);") || !strings.Contains(code, "options = { validate: defaultValidatePayload };") {
-		t.Fatalf("expected endpoint payload handlers to provide default message validator")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_QueueWhileConnecting
+// 这个测试验证连接建立前的发送队列：
+// 1) 生成 queueWhileConnecting 选项及其缓冲语义的 JSDoc。
+// 2) 未打开时 send() 入队而不写 socket，messagesSent 只在真正写出时累加。
+// 3) 关闭后没有安排重连时丢弃队列，主动 close() 同样丢弃。
+func TestGenerateWebSocketClientFromEndpoints_QueueWhileConnecting(t *testing.T) {
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "// ignore single listener errors and continue dispatch") {
-		t.Fatalf("expected listener dispatch fallback behavior")
+	if !strings.Contains(code, "  queueWhileConnecting?: boolean;\n") ||
+		!strings.Contains(code, "   * Queue send() calls made before the socket is open (status !== 'open') and flush them in order on open.\n") {
+		t.Fatalf("expected documented queueWhileConnecting option")
 	}
-	if !strings.Contains(code, "export class ChatEvents<") || !strings.Contains(code, "extends TypedWebSocketClient<") || !strings.Contains(code, "ChatEventsMessageType") {
-		t.Fatalf("expected endpoint-specific class extending TypedWebSocketClient")
+	if !strings.Contains(code, "    this.queueWhileConnecting = options?.queueWhileConnecting ?? false;\n") {
+		t.Fatalf("expected queueWhileConnecting to default to false")
 	}
-	if !strings.Contains(code, "export type ChatEventsMessageType =") || !strings.Contains(code, "chat:text") || !strings.Contains(code, "room:join") || !strings.Contains(code, "system:ack") {
-		t.Fatalf("expected message type union alias generation")
+	sendIdx := strings.Index(code, "  send(message: TSend): void {")
+	queueIdx := strings.Index(code[sendIdx:], "    if (this.shouldQueueSend()) {")
+	writeIdx := strings.Index(code[sendIdx:], "    this.socket.send(data);\n    this.messagesSent += 1;\n")
+	if sendIdx < 0 || queueIdx < 0 || writeIdx < queueIdx {
+		t.Fatalf("expected send() to queue before writing and count only written frames")
 	}
-	if strings.Contains(code, "export function chatEvents<TSend =") {
-		t.Fatalf("expected legacy endpoint factory function to be removed")
+	if !strings.Contains(code, "      this.scheduleReconnect();\n      if (this.reconnectTimer === undefined) this.dropSendBuffer();\n") {
+		t.Fatalf("expected queue to be dropped when no reconnect is scheduled")
 	}
-	if !strings.Contains(code, "export function createChatEvents<TSend =") || !strings.Contains(code, "return new ChatEvents<TSend>(options);") {
-		t.Fatalf("expected generated convenience create function for endpoint class")
+	if !strings.Contains(code, "    this.dropSendBuffer();\n    this.status = 'closing';\n") {
+		t.Fatalf("expected close() to drop queued messages")
 	}
-	if !strings.Contains(code, "onRoomJoinType(") || !strings.Contains(code, "onChatTextType(") || !strings.Contains(code, "onSystemAckType(") {
-		t.Fatalf("expected endpoint-specific on<Type>Type helpers")
+}
+
+type wsSequencePayload struct {
+	Seq    int64   `json:"seq"`
+	Shards []int64 `json:"shards"`
+}
+
+// TestGenerateWebSocketClientFromEndpoints_Int64AsBigIntMode
+// 这个测试验证 WebSocket 生成遵循 TSInt64ModeBigInt：
+// 1) int64 payload 字段生成为 bigint，validator 检查 typeof === 'bigint'。
+// 2) normalizeWsRequestJSON 将 bigint 序列化为字符串，避免 JSON.stringify 抛错。
+func TestGenerateWebSocketClientFromEndpoints_Int64AsBigIntMode(t *testing.T) {
+	oldMode := TSInt64MappingMode
+	t.Cleanup(func() {
+		SetTSInt64MappingMode(oldMode)
+	})
+	SetTSInt64MappingMode(TSInt64ModeBigInt)
+
+	ws := buildCommonWSTestEndpoint()
+	RegisterWebSocketServerPayloadType[wsSequencePayload](ws, "system:ack")
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "onRoomJoinPayload(") || strings.Contains(code, "onRoomJoinPayload<TPayload>(") {
-		t.Fatalf("expected typed payload helper without generic when payload map is available")
+	if !strings.Contains(code, "seq: bigint;") || !strings.Contains(code, "shards: bigint[];") {
+		t.Fatalf("expected int64 payload fields to map to bigint")
 	}
-	if !strings.Contains(code, "options?: TypedHandlerOptions<WsServerEnvelope, WsServerBroadcastPayload>") {
-		t.Fatalf("expected endpoint payload handlers to use mapped payload type options")
+	if !strings.Contains(code, `typeof obj["seq"] === 'bigint'`) {
+		t.Fatalf("expected bigint validator for int64 payload fields")
 	}
-	if !strings.Contains(code, "options?: TypedHandlerOptions<WsServerEnvelope, WsServerAckPayload>") {
-		t.Fatalf("expected endpoint payload handlers to use mapped ack payload type options")
+	if !strings.Contains(code, "const normalizeWsRequestJSON = (value: unknown): unknown => {\n  if (value instanceof Date) return value.toISOString();\n  if (typeof value === 'bigint') return value.toString();\n") {
+		t.Fatalf("expected bigint values to serialize as strings in WebSocket messages")
 	}
-	if !strings.Contains(code, "sendRoomJoinPayload(payload: WsClientJoinRoomPayload): void {") {
-		t.Fatalf("expected typed send helper generation for room:join payload")
+}
+
+// TestGenerateWebSocketClientFromEndpoints_StateMachineGuard
+// 这个测试验证 WebSocket 协议状态机守卫：
+// 1) 声明 StateMachine 后生成 STATE_MACHINE 常量与 protocolState 字段，连接打开时重置为初始状态。
+// 2) 收到 transitions 中的消息类型（如 system:ack）后切换状态。
+// 3) 生成的 send() 在当前状态不允许该消息类型时抛出异常（如 room:join 被 ack 之前发送 chat:text）。
+// 4) 状态机引用未知消息类型时返回校验错误；未声明时不生成守卫。
+func TestGenerateWebSocketClientFromEndpoints_StateMachineGuard(t *testing.T) {
+	ws := buildCommonWSTestEndpoint()
+	ws.StateMachine = &WebSocketStateMachine{
+		Initial: "connected",
+		Allowed: map[string][]string{
+			"connected": {"room:join"},
+			"joined":    {"room:join", "chat:text", "system:ack"},
+		},
+		Transitions: map[string]string{"system:ack": "joined"},
 	}
-	if !strings.Contains(code, "sendChatTextPayload(payload: WsClientChatTextPayload): void {") {
-		t.Fatalf("expected typed send helper generation for chat:text payload")
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "export interface ChatEventsServerPayloadByType") || !strings.Contains(code, `"system:ack": WsServerAckPayload;`) {
-		t.Fatalf("expected server payload map generation for message types")
+	if !strings.Contains(code, "export interface WebSocketStateMachine {") ||
+		!strings.Contains(code, "  static readonly STATE_MACHINE: WebSocketStateMachine = {\n    initial: 'connected',\n") {
+		t.Fatalf("expected state machine declaration")
 	}
-	if !strings.Contains(code, "export interface ChatEventsClientPayloadByType") || !strings.Contains(code, `"chat:text": WsClientChatTextPayload;`) {
-		t.Fatalf("expected client payload map generation for message types")
+	if !strings.Contains(code, "      'connected': ['room:join'],\n      'joined': ['room:join', 'chat:text', 'system:ack'],\n") ||
+		!strings.Contains(code, "      'system:ack': 'joined',\n") {
+		t.Fatalf("expected allowed types and transitions to be rendered")
 	}
-	if !strings.Contains(code, "export type ChatEventsReceiveUnion =") || !strings.Contains(code, `{ type: "chat:text"; payload: WsServerBroadcastPayload }`) {
-		t.Fatalf("expected receive discriminated union generation")
+	if !strings.Contains(code, "      this.protocolState = ChatEvents.STATE_MACHINE.initial;\n") ||
+		!strings.Contains(code, "        this.protocolState = next;\n") {
+		t.Fatalf("expected state reset on open and transitions on received messages")
 	}
-	if !strings.Contains(code, "export type ChatEventsSendUnion =") || !strings.Contains(code, `{ type: "room:join"; payload: WsClientJoinRoomPayload }`) {
-		t.Fatalf("expected send discriminated union generation")
+	if !strings.Contains(code, "    if (typeof type === 'string' && allowed !== undefined && !allowed.includes(type)) {\n      throw new Error(") ||
+		!strings.Contains(code, "    super.send(message);\n") {
+		t.Fatalf("expected send guard that throws in disallowed states")
 	}
-	if !strings.Contains(code, "static readonly MESSAGE_TYPES = [") {
-		t.Fatalf("expected endpoint-specific message type metadata")
+
+	ws.StateMachine = &WebSocketStateMachine{Initial: "connected", Allowed: map[string][]string{"connected": {"room:leave"}}}
+	if _, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws}); err == nil {
+		t.Fatalf("expected validation error for unknown message type in state machine")
 	}
-	if !strings.Contains(code, "options: WebSocketConvertOptions<TSend, WsServerEnvelope>") {
-		t.Fatalf("expected required options in endpoint class constructor")
+
+	plain, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
 	}
-	if !strings.Contains(code, "options: WebSocketConvertOptions<TSend, TReceive>") {
-		t.Fatalf("expected required options in websocket client constructor")
+	if strings.Contains(plain, "STATE_MACHINE") {
+		t.Fatalf("expected no state guard without StateMachine")
+	}
+}
+
+// TestGenerateWebSocketClientFromEndpoints_MessageKinds
+// 这个测试验证 websocket 消息帧格式提示：
+// 1) 默认 JSON 时子类直接调用 super(url, options)，基类默认 sendKind/receiveKind 为 'json'。
+// 2) ClientMessageKind=text / ServerMessageKind=bytes 时类泛型变为 string / Uint8Array，并把默认值传给基类。
+// 3) 基类 send() 按 sendKind 编码，接收 'binary' 时把 Uint8Array 交给 onMessage。
+// 4) 不支持的帧格式返回错误。
+func TestGenerateWebSocketClientFromEndpoints_MessageKinds(t *testing.T) {
+	plain, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{buildCommonWSTestEndpoint()})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(plain, "    super(url, options);\n") ||
+		!strings.Contains(plain, "    this.sendKind = options?.sendKind ?? 'json';\n") ||
+		!strings.Contains(plain, "export type WebSocketMessageKind = 'json' | 'text' | 'binary';") {
+		t.Fatalf("expected JSON defaults for endpoints without hints")
+	}
+
+	ws := &WebSocketEndpoint{
+		Name:              "frames",
+		Path:              "/frames",
+		ClientMessageType: reflect.TypeOf(""),
+		ServerMessageType: reflect.TypeOf([]byte(nil)),
+		ClientMessageKind: TSKindText,
+		ServerMessageKind: TSKindBytes,
+	}
+	code, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws})
+	if err != nil {
+		t.Fatalf("generateWebSocketClientFromEndpoints returned error: %v", err)
+	}
+	if !strings.Contains(code, "export class Frames<TSend = string> extends TypedWebSocketClient<Uint8Array, TSend, FramesMessageType> {") {
+		t.Fatalf("expected string send type and Uint8Array receive type")
+	}
+	if !strings.Contains(code, "    super(url, { sendKind: 'text', receiveKind: 'binary', ...options });\n") {
+		t.Fatalf("expected non-JSON kinds to be passed to the base client")
+	}
+	if !strings.Contains(code, "    if (this.sendKind === 'text') return String(value);\n") ||
+		!strings.Contains(code, "        if (this.receiveKind === 'binary') this.emitMessage(this.deserialize(new Uint8Array(event.data)));\n") {
+		t.Fatalf("expected kind-aware encode and binary message dispatch")
+	}
+	if strings.Contains(code, "export interface WebSocketMessage {") {
+		t.Fatalf("expected no JSON envelope interface for a text client message kind")
+	}
+
+	ws.ServerMessageKind = TSKindMultipart
+	if _, err := generateWebSocketClientFromEndpoints("/ws", "", []WebSocketEndpointLike{ws}); err == nil || !strings.Contains(err.Error(), "unsupported websocket message kind") {
+		t.Fatalf("expected unsupported kind error, got %v", err)
 	}
 }
 
@@ -982,3 +4155,145 @@ func TestExportUnifiedAPIsToTSFiles(t *testing.T) {
 		t.Fatalf("expected shared schema interface dedupe")
 	}
 }
+
+// TestExportUnifiedAPIsToTSFiles_Index
+// 这个测试验证统一导出的聚合入口文件：
+// 1) 设置 IndexTSPath 后生成 index 文件，并重新导出 shared/server/ws 三个文件以保持 tree-shaking。
+// 2) index 从 server 与 ws 文件导入端点类，apiClient 以类的 NAME 为键映射到静态 request 函数。
+// 3) websocket 端点映射为带类型的客户端工厂函数，所需类型从对应文件导入。
+func TestExportUnifiedAPIsToTSFiles_Index(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	server := ServerAPI{BasePath: "/api", Endpoints: buildCommonHTTPTestAPIs()}
+	ws := WebSocketAPI{BasePath: "/ws", Endpoints: []WebSocketEndpointLike{buildCommonWSTestEndpoint()}}
+	opts := UnifiedTSExportOptions{
+		ServerTSPath:    filepath.Join("api", "server.ts"),
+		WebSocketTSPath: filepath.Join("api", "ws.ts"),
+		SchemaTSPath:    filepath.Join("api", "schema.ts"),
+		IndexTSPath:     filepath.Join("api", "index.ts"),
+	}
+	if err := ExportUnifiedAPIsToTSFiles(server, ws, opts); err != nil {
+		t.Fatalf("ExportUnifiedAPIsToTSFiles returned error: %v", err)
+	}
+	indexBytes, err := os.ReadFile(opts.IndexTSPath)
+	if err != nil {
+		t.Fatalf("read index ts file failed: %v", err)
+	}
+	indexCode := string(indexBytes)
+	for _, want := range []string{"export * from './schema';\n", "export * from './server';\n", "export * from './ws';\n"} {
+		if !strings.Contains(indexCode, want) {
+			t.Fatalf("expected index to re-export %q", want)
+		}
+	}
+	if !regexp.MustCompile(`(?m)^import \{ [^}]*GetPersonByIDGet[^}]* \} from './server';$`).MatchString(indexCode) {
+		t.Fatalf("expected index to import endpoint classes from server file")
+	}
+	if !regexp.MustCompile(`(?m)^import \{ [^}]*ChatEvents[^}]* \} from './ws';$`).MatchString(indexCode) ||
+		!regexp.MustCompile(`(?m)^import type \{ [^}]*WebSocketConvertOptions[^}]* \} from './ws';$`).MatchString(indexCode) {
+		t.Fatalf("expected index to import websocket classes and option types from ws file")
+	}
+	if !strings.Contains(indexCode, "  [GetPersonByIDGet.NAME]: GetPersonByIDGet.request,\n") {
+		t.Fatalf("expected apiClient to map NAME to the request function")
+	}
+	if !strings.Contains(indexCode, ">(options: WebSocketConvertOptions<TSend, ") || !strings.Contains(indexCode, "<TSend>(options),\n") {
+		t.Fatalf("expected apiClient to expose a typed websocket client factory")
+	}
+	if !strings.Contains(indexCode, "export type APIClient = typeof apiClient;") {
+		t.Fatalf("expected APIClient type export")
+	}
+}
+
+// TestExportUnifiedAPIsToTSFiles_AbortAll
+// 这个测试验证统一导出的 EmitAbortAll 选项：
+// 1) 默认不生成 abortAllRequests。
+// 2) 开启后服务端文件导出 abortAllRequests，请求经 trackRequest 发送。
+func TestExportUnifiedAPIsToTSFiles_AbortAll(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+
+	server := ServerAPI{BasePath: "/api", Endpoints: buildCommonHTTPTestAPIs()}
+	ws := WebSocketAPI{BasePath: "/ws", Endpoints: []WebSocketEndpointLike{buildCommonWSTestEndpoint()}}
+	export := func(opts UnifiedTSExportOptions) string {
+		t.Helper()
+		opts.ServerTSPath = "server.ts"
+		opts.WebSocketTSPath = "ws.ts"
+		opts.SchemaTSPath = "schema.ts"
+		if err := ExportUnifiedAPIsToTSFiles(server, ws, opts); err != nil {
+			t.Fatalf("ExportUnifiedAPIsToTSFiles returned error: %v", err)
+		}
+		data, err := os.ReadFile(opts.ServerTSPath)
+		if err != nil {
+			t.Fatalf("read server ts file failed: %v", err)
+		}
+		return string(data)
+	}
+
+	if code := export(UnifiedTSExportOptions{}); strings.Contains(code, "abortAllRequests") {
+		t.Fatalf("expected abortAllRequests to be opt-in")
+	}
+	code := export(UnifiedTSExportOptions{TSExportOptions: TSExportOptions{EmitAbortAll: true}})
+	if !strings.Contains(code, "export const abortAllRequests = (reason?: unknown): void => {") || !strings.Contains(code, "await trackRequest(options?.signal, (signal) => ") {
+		t.Fatalf("expected unified server file to export abortAllRequests")
+	}
+}
+
+// TestExportUnifiedAPIsToTSFiles_DevDescribeHelpers
+// 这个测试验证统一导出与 describe 辅助函数组合：
+// 1) describeX() 被移入共享 schema 文件时，TSFieldDescriptor 也一并输出到该文件。
+// 2) 服务端文件不再保留 TSFieldDescriptor 的定义。
+func TestExportUnifiedAPIsToTSFiles_DevDescribeHelpers(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd failed: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir failed: %v", err)
+	}
+	oldDescribe := TSDevDescribeHelpers
+	SetTSDevDescribeHelpers(true)
+	t.Cleanup(func() {
+		SetTSDevDescribeHelpers(oldDescribe)
+	})
+
+	server := ServerAPI{BasePath: "/api", Endpoints: buildCommonHTTPTestAPIs()}
+	ws := WebSocketAPI{BasePath: "/ws", Endpoints: []WebSocketEndpointLike{buildCommonWSTestEndpoint()}}
+	opts := UnifiedTSExportOptions{
+		ServerTSPath:    "server.ts",
+		WebSocketTSPath: "ws.ts",
+		SchemaTSPath:    "schema.ts",
+	}
+	if err := ExportUnifiedAPIsToTSFiles(server, ws, opts); err != nil {
+		t.Fatalf("ExportUnifiedAPIsToTSFiles returned error: %v", err)
+	}
+	sharedCode, err := os.ReadFile(opts.SchemaTSPath)
+	if err != nil {
+		t.Fatalf("read shared ts file failed: %v", err)
+	}
+	serverCode, err := os.ReadFile(opts.ServerTSPath)
+	if err != nil {
+		t.Fatalf("read server ts file failed: %v", err)
+	}
+	if !strings.Contains(string(sharedCode), "export function describeGetPersonReq(): TSFieldDescriptor[]") {
+		t.Fatalf("expected describe helper in shared schema")
+	}
+	if !strings.Contains(string(sharedCode), "export interface TSFieldDescriptor") {
+		t.Fatalf("expected TSFieldDescriptor to be defined next to describe helpers")
+	}
+	if strings.Contains(string(serverCode), "export interface TSFieldDescriptor") {
+		t.Fatalf("expected TSFieldDescriptor to move out of the server file")
+	}
+}
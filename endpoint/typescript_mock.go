@@ -0,0 +1,195 @@
+package endpoint
+
+import (
+	"reflect"
+	"strings"
+)
+
+// tsMockTime is the fixed timestamp used for time.Time fields, which are typed as string in TS.
+// A constant keeps mocks deterministic across test runs.
+const tsMockTime = "1970-01-01T00:00:00.000Z"
+
+// renderMockFactories renders `export function mockX(overrides?: Partial<X>): X` for every registered interface.
+// Values are deterministic zero values: empty string / 0 / false / [] / {}, the first tsunion literal,
+// and nested mockY() calls. omitempty fields are populated too.
+func renderMockFactories(registry *tsInterfaceRegistry) (string, error) {
+	if len(registry.defs) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	writeTSMarker(&b, "Mock Factories")
+	for _, def := range registry.defs {
+		if def.Type == nil {
+			continue
+		}
+		m := &mockWriter{registry: registry, root: def.Type}
+		fields, err := m.objectFields(def.Type, "  ")
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("/**\n")
+		b.WriteString(" * Build a deterministic ")
+		b.WriteString(def.Name)
+		b.WriteString(" for tests and stories.\n")
+		b.WriteString(" * 构建确定性的 ")
+		b.WriteString(def.Name)
+		b.WriteString(" 示例数据，供测试与 Storybook 使用。\n")
+		b.WriteString(" */\n")
+		b.WriteString("export function mock")
+		b.WriteString(def.Name)
+		b.WriteString("(overrides?: Partial<")
+		b.WriteString(def.Name)
+		b.WriteString(">): ")
+		b.WriteString(def.Name)
+		b.WriteString(" {\n")
+		b.WriteString("  return {\n")
+		b.WriteString(fields)
+		b.WriteString("    ...overrides,\n")
+		b.WriteString("  };\n")
+		b.WriteString("}\n\n")
+	}
+	writeTSMarkerEnd(&b, "Mock Factories")
+	return b.String(), nil
+}
+
+type mockWriter struct {
+	registry *tsInterfaceRegistry
+	root     reflect.Type
+}
+
+func (m *mockWriter) objectFields(t reflect.Type, indent string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, _, ok := jsonFieldMeta(f)
+		if !ok {
+			continue
+		}
+		value, err := m.valueFromType(f.Type, indent+"  ")
+		if err != nil {
+			return "", err
+		}
+		if unionValues, ok, err := tsUnionValuesFromField(f); err != nil {
+			return "", err
+		} else if ok {
+			value = tsUnionType(unionValues[:1])
+		}
+		propName, err := tsPropName(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(indent)
+		b.WriteString("  ")
+		b.WriteString(propName)
+		b.WriteString(": ")
+		b.WriteString(value)
+		b.WriteString(",\n")
+	}
+	return b.String(), nil
+}
+
+func (m *mockWriter) valueFromType(t reflect.Type, indent string) (string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return "'" + tsMockTime + "'", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "FormData" {
+		return "new FormData()", nil
+	}
+	if t.PkgPath() == "mime/multipart" && t.Name() == "FileHeader" {
+		return "new Blob()", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "RawBytes" {
+		return "new Uint8Array()", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamResponse" {
+		return "new Blob()", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamRequest" {
+		return "new ReadableStream<Uint8Array>()", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "false", nil
+	case reflect.String:
+		return "''", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64:
+		return "0", nil
+	case reflect.Int64, reflect.Uint64:
+		switch TSInt64MappingMode {
+		case TSInt64ModeString:
+			return "'0'", nil
+		case TSInt64ModeBigInt:
+			return "0n", nil
+		}
+		return "0", nil
+	case reflect.Struct:
+		if t.Name() != "" && !m.registry.inline[t] {
+			name, err := m.registry.ensureNamedStructType(t)
+			if err != nil {
+				return "", err
+			}
+			// A type that leads back to the root would recurse forever at runtime.
+			if t == m.root || reachesNamedStruct(t, m.root) {
+				return "{} as " + name, nil
+			}
+			return "mock" + name + "()", nil
+		}
+		fields, err := m.objectFields(t, indent)
+		if err != nil {
+			return "", err
+		}
+		return "{\n" + fields + indent + "}", nil
+	case reflect.Map:
+		return "{}", nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "''", nil
+		}
+		return "[]", nil
+	default:
+		return "null", nil
+	}
+}
+
+// reachesNamedStruct reports whether target is reachable from `from` through direct struct
+// fields only; slices and maps mock to empty values, so they never recurse.
+func reachesNamedStruct(from reflect.Type, target reflect.Type) bool {
+	return reachesStructByValue(from, target, map[reflect.Type]bool{})
+}
+
+func reachesStructByValue(t reflect.Type, target reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if _, _, ok := jsonFieldMeta(f); !ok {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft == target || reachesStructByValue(ft, target, seen) {
+			return true
+		}
+	}
+	return false
+}
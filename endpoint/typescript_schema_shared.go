@@ -1,19 +1,24 @@
 package endpoint
 
 import (
+	"encoding"
 	"fmt"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type tsInterfaceDef struct {
 	Name      string
 	Body      string
 	Validator string
+	Describe  string
+	Doc       string
 	Sig       string
+	Type      reflect.Type
 }
 
 type TSInt64Mode string
@@ -21,6 +26,11 @@ type TSInt64Mode string
 const (
 	TSInt64ModeNumber TSInt64Mode = "number"
 	TSInt64ModeString TSInt64Mode = "string"
+	// TSInt64ModeBigInt maps int64/uint64 to `bigint`. JSON numbers above 2^53 lose precision in
+	// JSON.parse, so tag such fields with `json:",string"`: requests serialize bigint as a decimal
+	// string and HTTP responses revive the known int64 fields back to bigint. WebSocket messages
+	// are sent the same way but received as-is; pass a `deserialize` option to revive them.
+	TSInt64ModeBigInt TSInt64Mode = "bigint"
 )
 
 // TSInt64MappingMode controls how int64/uint64 are rendered in generated TypeScript.
@@ -33,30 +43,275 @@ func SetTSInt64MappingMode(mode TSInt64Mode) {
 	switch mode {
 	case TSInt64ModeString:
 		TSInt64MappingMode = TSInt64ModeString
+	case TSInt64ModeBigInt:
+		TSInt64MappingMode = TSInt64ModeBigInt
 	default:
 		TSInt64MappingMode = TSInt64ModeNumber
 	}
 }
 
+// TSKeyQuoting controls how property keys are written in generated TypeScript.
+type TSKeyQuoting string
+
+const (
+	// TSKeyQuotingAuto quotes only keys that are not valid identifiers.
+	TSKeyQuotingAuto TSKeyQuoting = "auto"
+	// TSKeyQuotingAlways quotes every key, matching JSON.
+	TSKeyQuotingAlways TSKeyQuoting = "always"
+	// TSKeyQuotingNever never quotes keys; generation fails on a key that is not a valid identifier.
+	TSKeyQuotingNever TSKeyQuoting = "never"
+)
+
+// TSKeyQuotingMode controls property key quoting in generated interfaces.
+// Default is `auto`, which keeps identifier keys bare.
+var TSKeyQuotingMode = TSKeyQuotingAuto
+
+// SetTSKeyQuoting changes property key quoting for TypeScript generation.
+// Unsupported values fallback to TSKeyQuotingAuto.
+func SetTSKeyQuoting(mode TSKeyQuoting) {
+	switch mode {
+	case TSKeyQuotingAlways, TSKeyQuotingNever:
+		TSKeyQuotingMode = mode
+	default:
+		TSKeyQuotingMode = TSKeyQuotingAuto
+	}
+}
+
+// TSAutoDateParsing controls whether generated clients turn response dates into Date.
+// HTTP clients only convert the JSON paths of time.Time fields, never other ISO-looking strings,
+// and skip fields tagged `tsnodate:"true"`. WebSocket messages are not tied to one Go type, so
+// the WebSocket client keeps matching ISO-looking strings unless this is disabled. Default is true.
+var TSAutoDateParsing = true
+
+// SetTSAutoDateParsing enables or disables Date conversion of time.Time response fields.
+func SetTSAutoDateParsing(enabled bool) {
+	TSAutoDateParsing = enabled
+}
+
+// TSInlineStructMaxFields inlines nested named structs with at most this many fields
+// when only one endpoint uses them, instead of emitting a top-level interface.
+// Endpoint body/response/params types, shared types and recursive types stay named.
+// Default is 0, which disables inlining.
+var TSInlineStructMaxFields = 0
+
+// SetTSInlineStructMaxFields changes the field limit for inlining single-use nested structs.
+// Values <= 0 disable inlining.
+func SetTSInlineStructMaxFields(maxFields int) {
+	if maxFields < 0 {
+		maxFields = 0
+	}
+	TSInlineStructMaxFields = maxFields
+}
+
+var (
+	tsTypeDocsMu sync.RWMutex
+	tsTypeDocs   = map[reflect.Type]string{}
+)
+
+// RegisterTSDoc attaches a JSDoc block to the interface generated for t (pointers are unwrapped),
+// e.g. RegisterTSDoc(reflect.TypeOf(Person{}), "Person profile\n人员档案").
+// Each line of a multi-line doc becomes one JSDoc line. An empty doc removes the registration.
+func RegisterTSDoc(t reflect.Type, doc string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	tsTypeDocsMu.Lock()
+	defer tsTypeDocsMu.Unlock()
+	if strings.TrimSpace(doc) == "" {
+		delete(tsTypeDocs, t)
+		return
+	}
+	tsTypeDocs[t] = doc
+}
+
+func tsDocForType(t reflect.Type) string {
+	tsTypeDocsMu.RLock()
+	defer tsTypeDocsMu.RUnlock()
+	return tsTypeDocs[t]
+}
+
+// collectInlineStructTypes returns nested named structs that qualify for inlining:
+// at most maxFields json fields, reachable from exactly one endpoint, never an endpoint's
+// top-level type and not recursive.
+func collectInlineStructTypes(endpoints []EndpointLike, maxFields int) map[reflect.Type]bool {
+	if maxFields <= 0 {
+		return nil
+	}
+	users := map[reflect.Type]int{}
+	topLevel := map[reflect.Type]bool{}
+	recursive := map[reflect.Type]bool{}
+	for _, e := range endpoints {
+		if !isEndpointEnabled(e) {
+			continue
+		}
+		meta := e.EndpointMeta()
+		roots := []reflect.Type{meta.PathParamsType, meta.QueryParamsType, meta.HeaderParamsType, meta.CookieParamsType, meta.RequestBodyType}
+		for _, r := range meta.Responses {
+			roots = append(roots, r.BodyType)
+		}
+		seen := map[reflect.Type]bool{}
+		for _, root := range roots {
+			if root == nil {
+				continue
+			}
+			for root.Kind() == reflect.Ptr {
+				root = root.Elem()
+			}
+			topLevel[root] = true
+			walkNamedStructTypes(root, seen, map[reflect.Type]bool{}, recursive)
+		}
+		for t := range seen {
+			users[t]++
+		}
+	}
+	out := map[reflect.Type]bool{}
+	for t, n := range users {
+		if n == 1 && !topLevel[t] && !recursive[t] && countJSONFields(t) <= maxFields {
+			out[t] = true
+		}
+	}
+	return out
+}
+
+func walkNamedStructTypes(t reflect.Type, seen map[reflect.Type]bool, stack map[reflect.Type]bool, recursive map[reflect.Type]bool) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || (t.PkgPath() == "time" && t.Name() == "Time") {
+		return
+	}
+	if t.Name() != "" {
+		if stack[t] {
+			recursive[t] = true
+			return
+		}
+		seen[t] = true
+		stack[t] = true
+		defer delete(stack, t)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if _, _, ok := jsonFieldMeta(f); ok {
+			walkNamedStructTypes(f.Type, seen, stack, recursive)
+		}
+	}
+}
+
+func countJSONFields(t reflect.Type) int {
+	n := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if _, _, ok := jsonFieldMeta(f); ok {
+			n++
+		}
+	}
+	return n
+}
+
 func tsInt64TypeAndSig() (string, string) {
-	if TSInt64MappingMode == TSInt64ModeString {
+	switch TSInt64MappingMode {
+	case TSInt64ModeString:
 		return "string", "int64_as_string"
+	case TSInt64ModeBigInt:
+		return "bigint", "int64_as_bigint"
 	}
 	return "number", "int64_as_number"
 }
 
 func tsInt64ValidatorExpr(valueExpr string) string {
-	if TSInt64MappingMode == TSInt64ModeString {
-		return "typeof " + valueExpr + " === 'string'"
+	ts, _ := tsInt64TypeAndSig()
+	return "typeof " + valueExpr + " === '" + ts + "'"
+}
+
+// tsBigIntPaths lists the dot-separated JSON paths of int64/uint64 values inside t, used by
+// the generated reviveBigInts in TSInt64ModeBigInt. See tsLeafPaths for the path syntax.
+func tsBigIntPaths(t reflect.Type) []string {
+	return tsLeafPaths(t, func(t reflect.Type, f *reflect.StructField) bool {
+		if f != nil {
+			if _, isUnion, _ := tsUnionValuesFromField(*f); isUnion {
+				return false
+			}
+		}
+		return t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64
+	})
+}
+
+// tsDatePaths lists the JSON paths of time.Time values inside t, used by the generated reviveDates.
+// Fields tagged `tsnodate:"true"` keep their wire string.
+func tsDatePaths(t reflect.Type) []string {
+	return tsLeafPaths(t, func(t reflect.Type, f *reflect.StructField) bool {
+		if f != nil && strings.TrimSpace(f.Tag.Get("tsnodate")) == "true" {
+			return false
+		}
+		return t.PkgPath() == "time" && t.Name() == "Time"
+	})
+}
+
+// tsLeafPaths lists the dot-separated JSON paths of values inside t accepted by isLeaf.
+// `*` matches every array element or map value and the empty path is t itself.
+// f is the struct field holding the value, or nil for elements and the root.
+// Recursive types are walked once, so deeper levels are not listed.
+func tsLeafPaths(t reflect.Type, isLeaf func(t reflect.Type, f *reflect.StructField) bool) []string {
+	var paths []string
+	var walk func(t reflect.Type, f *reflect.StructField, prefix []string, stack map[reflect.Type]bool)
+	walk = func(t reflect.Type, f *reflect.StructField, prefix []string, stack map[reflect.Type]bool) {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if isLeaf(t, f) {
+			paths = append(paths, strings.Join(prefix, "."))
+			return
+		}
+		switch t.Kind() {
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() != reflect.Uint8 {
+				walk(t.Elem(), nil, append(prefix[:len(prefix):len(prefix)], "*"), stack)
+			}
+		case reflect.Map:
+			walk(t.Elem(), nil, append(prefix[:len(prefix):len(prefix)], "*"), stack)
+		case reflect.Struct:
+			if (t.PkgPath() == "time" && t.Name() == "Time") || stack[t] {
+				return
+			}
+			stack[t] = true
+			defer delete(stack, t)
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if field.PkgPath != "" {
+					continue
+				}
+				name, _, ok := jsonFieldMeta(field)
+				if !ok {
+					continue
+				}
+				walk(field.Type, &field, append(prefix[:len(prefix):len(prefix)], name), stack)
+			}
+		}
 	}
-	return "typeof " + valueExpr + " === 'number'"
+	if t != nil && t.Kind() != reflect.Invalid {
+		walk(t, nil, nil, map[reflect.Type]bool{})
+	}
+	return paths
 }
 
 type tsInterfaceRegistry struct {
 	defs       []tsInterfaceDef
 	sigToName  map[string]string
 	nameCount  map[string]int
+	usedNames  map[string]struct{}
 	typeToName map[reflect.Type]string
+	// unions holds `tsunionname` aliases, keyed by alias name.
+	unions map[string]tsNamedUnion
+	// inline holds named structs rendered as anonymous object types; see TSInlineStructMaxFields.
+	inline map[reflect.Type]bool
+	// paramTags holds the fallback name tag (`uri`/`form`) of path/query param structs; see fieldNameMeta.
+	paramTags map[reflect.Type]string
 }
 
 func newTSInterfaceRegistry() *tsInterfaceRegistry {
@@ -64,7 +319,10 @@ func newTSInterfaceRegistry() *tsInterfaceRegistry {
 		defs:       make([]tsInterfaceDef, 0),
 		sigToName:  map[string]string{},
 		nameCount:  map[string]int{},
+		usedNames:  map[string]struct{}{},
 		typeToName: map[reflect.Type]string{},
+		unions:     map[string]tsNamedUnion{},
+		paramTags:  map[reflect.Type]string{},
 	}
 }
 
@@ -82,15 +340,23 @@ func (r *tsInterfaceRegistry) ensureNamedStructType(t reflect.Type) (string, err
 		return existing, nil
 	}
 
-	base := sanitizeTypeName(t.Name())
+	base := sanitizeTypeName(genericTypeName(t.Name()))
 	if base == "" {
 		base = "AnonymousType"
 	}
+	// Same-name types (e.g. from different packages) get numbered names in encounter order.
+	// A numbered name can also be a real Go type name (Item2), so skip names already taken.
 	name := base
-	if count := r.nameCount[base]; count > 0 {
-		name = fmt.Sprintf("%s%d", base, count+1)
+	for count := r.nameCount[base]; ; count++ {
+		if count > 0 {
+			name = fmt.Sprintf("%s%d", base, count+1)
+		}
+		if _, taken := r.usedNames[name]; !taken {
+			r.nameCount[base] = count + 1
+			break
+		}
 	}
-	r.nameCount[base]++
+	r.usedNames[name] = struct{}{}
 	r.typeToName[t] = name
 
 	body, sig, err := renderStructBodyByType(t, r)
@@ -101,6 +367,13 @@ func (r *tsInterfaceRegistry) ensureNamedStructType(t reflect.Type) (string, err
 	if err != nil {
 		return "", err
 	}
+	describe := ""
+	if TSDevDescribeHelpers {
+		describe, err = renderStructDescriberByType(t, r, name)
+		if err != nil {
+			return "", err
+		}
+	}
 	namedSig := "named:" + t.PkgPath() + "." + t.Name() + ":" + sig
 	if existing, ok := r.sigToName[namedSig]; ok {
 		r.typeToName[t] = existing
@@ -111,12 +384,27 @@ func (r *tsInterfaceRegistry) ensureNamedStructType(t reflect.Type) (string, err
 		Name:      name,
 		Body:      body,
 		Validator: validator,
+		Describe:  describe,
+		Doc:       tsDocForType(t),
 		Sig:       namedSig,
+		Type:      t,
 	})
 	r.sigToName[namedSig] = name
 	return name, nil
 }
 
+var typeArgPackageRegexp = regexp.MustCompile(`[A-Za-z0-9_.~-]+/|[A-Za-z0-9_]+\.`)
+
+// genericTypeName drops package paths from the type arguments of an instantiated generic type,
+// so Paginated[github.com/x/app.User] is named PaginatedUser rather than PaginatedGithubComXAppUser.
+func genericTypeName(name string) string {
+	open := strings.Index(name, "[")
+	if open < 0 {
+		return name
+	}
+	return name[:open] + typeArgPackageRegexp.ReplaceAllString(name[open:], "")
+}
+
 func sanitizeTypeName(s string) string {
 	s = toUpperCamel(s)
 	if s == "" {
@@ -166,7 +454,7 @@ func renderStructBodyByType(t reflect.Type, registry *tsInterfaceRegistry) (stri
 		if f.PkgPath != "" {
 			continue
 		}
-		name, optional, ok := jsonFieldMeta(f)
+		name, optional, ok := registry.fieldMeta(t, f)
 		if !ok {
 			continue
 		}
@@ -175,22 +463,25 @@ func renderStructBodyByType(t reflect.Type, registry *tsInterfaceRegistry) (stri
 		if err != nil {
 			return "", "", err
 		}
-		if unionValues, ok, err := tsUnionValuesFromField(f); err != nil {
+		if union, ok, err := registry.fieldUnion(f); err != nil {
 			return "", "", err
 		} else if ok {
-			fieldType = tsUnionType(unionValues)
-			fieldSig = "union[" + tsUnionSig(unionValues) + "]"
+			fieldType = union.typeExpr()
+			fieldSig = "union[" + union.Name + ":" + tsUnionSig(union.Values) + "]"
 		}
 		separator := ";"
 		if isMultilineObjectType(fieldType) {
 			separator = ","
 		}
-		propName := tsPropName(name)
+		propName, err := tsPropName(name)
+		if err != nil {
+			return "", "", err
+		}
 		if optional {
 			propName += "?"
 		}
-		if tsdoc := strings.TrimSpace(f.Tag.Get("tsdoc")); tsdoc != "" {
-			lines = append(lines, renderTSFieldComment(tsdoc))
+		if doc := tsFieldDoc(f); doc != "" {
+			lines = append(lines, renderTSFieldComment(doc))
 		}
 		lines = append(lines, fmt.Sprintf("  %s: %s%s\n", propName, fieldType, separator))
 		sigs = append(sigs, name+fmt.Sprintf("(%t):", optional)+fieldSig)
@@ -222,7 +513,7 @@ func renderStructValidatorByType(t reflect.Type, registry *tsInterfaceRegistry,
 		if f.PkgPath != "" {
 			continue
 		}
-		name, optional, ok := jsonFieldMeta(f)
+		name, optional, ok := registry.fieldMeta(t, f)
 		if !ok {
 			continue
 		}
@@ -231,10 +522,10 @@ func renderStructValidatorByType(t reflect.Type, registry *tsInterfaceRegistry,
 		if err != nil {
 			return "", err
 		}
-		if unionValues, ok, err := tsUnionValuesFromField(f); err != nil {
+		if union, ok, err := registry.fieldUnion(f); err != nil {
 			return "", err
 		} else if ok {
-			expr = tsUnionValidatorExpr(valueExpr, unionValues)
+			expr = union.validatorExpr(valueExpr)
 		}
 		if optional {
 			b.WriteString("  if (obj[")
@@ -256,6 +547,56 @@ func renderStructValidatorByType(t reflect.Type, registry *tsInterfaceRegistry,
 	return b.String(), nil
 }
 
+// renderStructDescriberByType renders `describeX()` returning field metadata for dev tooling.
+func renderStructDescriberByType(t reflect.Type, registry *tsInterfaceRegistry, interfaceName string) (string, error) {
+	var b strings.Builder
+	b.WriteString("/**\n")
+	b.WriteString(" * Describe the fields of ")
+	b.WriteString(interfaceName)
+	b.WriteString(" at runtime (dev tooling, form builders).\n")
+	b.WriteString(" * 在运行时描述 ")
+	b.WriteString(interfaceName)
+	b.WriteString(" 的字段（供开发工具、表单生成器使用）。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export function describe")
+	b.WriteString(interfaceName)
+	b.WriteString("(): TSFieldDescriptor[] {\n")
+	b.WriteString("  return [\n")
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, optional, ok := registry.fieldMeta(t, f)
+		if !ok {
+			continue
+		}
+		fieldType, _, err := tsTypeFromType(f.Type, registry)
+		if err != nil {
+			return "", err
+		}
+		if union, ok, err := registry.fieldUnion(f); err != nil {
+			return "", err
+		} else if ok {
+			fieldType = union.typeExpr()
+		}
+		b.WriteString("    { name: ")
+		b.WriteString(strconv.Quote(name))
+		b.WriteString(", type: ")
+		b.WriteString(strconv.Quote(fieldType))
+		b.WriteString(", optional: ")
+		b.WriteString(strconv.FormatBool(optional))
+		if tsdoc := strings.TrimSpace(f.Tag.Get("tsdoc")); tsdoc != "" {
+			b.WriteString(", description: ")
+			b.WriteString(strconv.Quote(tsdoc))
+		}
+		b.WriteString(" },\n")
+	}
+	b.WriteString("  ];\n")
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
 func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInterfaceRegistry, depth int) (string, error) {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -267,12 +608,18 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "FormData" {
 		return valueExpr + " instanceof FormData", nil
 	}
+	if t.PkgPath() == "mime/multipart" && t.Name() == "FileHeader" {
+		return valueExpr + " instanceof Blob", nil
+	}
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "RawBytes" {
 		return valueExpr + " instanceof Uint8Array", nil
 	}
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamResponse" {
 		return valueExpr + " instanceof Blob", nil
 	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamRequest" {
+		return "(" + valueExpr + " instanceof ReadableStream || typeof (" + valueExpr + " as any)?.[Symbol.asyncIterator] === 'function')", nil
+	}
 
 	switch t.Kind() {
 	case reflect.Bool:
@@ -286,6 +633,9 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 	case reflect.Int64, reflect.Uint64:
 		return tsInt64ValidatorExpr(valueExpr), nil
 	case reflect.Struct:
+		if t.Name() != "" && registry.inline[t] {
+			return tsInlineStructValidatorExpr(t, valueExpr, registry, depth)
+		}
 		if t.Name() != "" {
 			name, err := registry.ensureNamedStructType(t)
 			if err != nil {
@@ -295,7 +645,7 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 		}
 		return "isPlainObject(" + valueExpr + ")", nil
 	case reflect.Map:
-		if t.Key().Kind() != reflect.String {
+		if !isJSONMapKey(t.Key()) {
 			return "isPlainObject(" + valueExpr + ")", nil
 		}
 		itemName := fmt.Sprintf("v%d", depth+1)
@@ -303,6 +653,9 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 		if err != nil {
 			return "", err
 		}
+		if elemExpr == "true" {
+			return "isPlainObject(" + valueExpr + ")", nil
+		}
 		return "isPlainObject(" + valueExpr + ") && Object.values(" + valueExpr + ").every((" + itemName + ") => " + elemExpr + ")", nil
 	case reflect.Slice, reflect.Array:
 		if t.Elem().Kind() == reflect.Uint8 {
@@ -313,6 +666,9 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 		if err != nil {
 			return "", err
 		}
+		if elemExpr == "true" {
+			return "Array.isArray(" + valueExpr + ")", nil
+		}
 		return "Array.isArray(" + valueExpr + ") && " + valueExpr + ".every((" + itemName + ") => " + elemExpr + ")", nil
 	case reflect.Interface:
 		return "true", nil
@@ -321,19 +677,78 @@ func tsValidatorExprFromType(t reflect.Type, valueExpr string, registry *tsInter
 	}
 }
 
+// tsInlineStructValidatorExpr validates an inlined struct field by field, like validateX would.
+func tsInlineStructValidatorExpr(t reflect.Type, valueExpr string, registry *tsInterfaceRegistry, depth int) (string, error) {
+	obj := "(" + valueExpr + " as Record<string, unknown>)"
+	parts := []string{"isPlainObject(" + valueExpr + ")"}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, optional, ok := registry.fieldMeta(t, f)
+		if !ok {
+			continue
+		}
+		fieldExpr := obj + "[" + strconv.Quote(name) + "]"
+		expr, err := tsValidatorExprFromType(f.Type, fieldExpr, registry, depth+1)
+		if err != nil {
+			return "", err
+		}
+		if union, ok, err := registry.fieldUnion(f); err != nil {
+			return "", err
+		} else if ok {
+			expr = union.validatorExpr(fieldExpr)
+		}
+		if optional {
+			parts = append(parts, "("+fieldExpr+" === undefined || ("+expr+"))")
+			continue
+		}
+		parts = append(parts, strconv.Quote(name)+" in "+obj+" && ("+expr+")")
+	}
+	return "(" + strings.Join(parts, " && ") + ")", nil
+}
+
+// tsFieldDoc is the JSDoc text of a field: its `tsdoc`, followed by `@deprecated <reason>` when the
+// field has a `tsdeprecated` tag (an empty reason emits a bare `@deprecated`).
+func tsFieldDoc(f reflect.StructField) string {
+	doc := strings.TrimSpace(f.Tag.Get("tsdoc"))
+	reason, deprecated := f.Tag.Lookup("tsdeprecated")
+	if !deprecated {
+		return doc
+	}
+	tag := strings.TrimSpace("@deprecated " + strings.TrimSpace(reason))
+	if doc == "" {
+		return tag
+	}
+	return doc + "\n" + tag
+}
+
 func renderTSFieldComment(comment string) string {
+	return renderTSDocBlock(comment, "  ")
+}
+
+// renderTSInterfaceDoc renders the RegisterTSDoc block placed before `export interface`.
+func renderTSInterfaceDoc(doc string) string {
+	if strings.TrimSpace(doc) == "" {
+		return ""
+	}
+	return renderTSDocBlock(strings.TrimSpace(doc), "")
+}
+
+func renderTSDocBlock(comment string, indent string) string {
 	lines := strings.Split(escapeTSComment(comment), "\n")
 	if len(lines) == 1 {
-		return fmt.Sprintf("  /** %s */\n", strings.TrimSpace(lines[0]))
+		return fmt.Sprintf("%s/** %s */\n", indent, strings.TrimSpace(lines[0]))
 	}
 	var b strings.Builder
-	b.WriteString("  /**\n")
+	b.WriteString(indent + "/**\n")
 	for _, line := range lines {
-		b.WriteString("   * ")
+		b.WriteString(indent + " * ")
 		b.WriteString(strings.TrimSpace(line))
 		b.WriteString("\n")
 	}
-	b.WriteString("   */\n")
+	b.WriteString(indent + " */\n")
 	return b.String()
 }
 
@@ -363,7 +778,10 @@ func renderMapBody(v reflect.Value, registry *tsInterfaceRegistry) (string, stri
 		if err != nil {
 			return "", "", err
 		}
-		prop := tsPropName(name)
+		prop, err := tsPropName(name)
+		if err != nil {
+			return "", "", err
+		}
 		separator := ";"
 		if isMultilineObjectType(fieldType) {
 			separator = ","
@@ -407,6 +825,20 @@ func tsTypeFromValue(v reflect.Value, registry *tsInterfaceRegistry) (string, st
 	return tsTypeFromType(v.Type(), registry)
 }
 
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isJSONMapKey reports whether encoding/json writes map keys of type t as object keys
+// (strings, integers and encoding.TextMarshaler), so the value type can be kept.
+func isJSONMapKey(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
 func tsTypeFromType(t reflect.Type, registry *tsInterfaceRegistry) (string, string, error) {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -418,12 +850,18 @@ func tsTypeFromType(t reflect.Type, registry *tsInterfaceRegistry) (string, stri
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "FormData" {
 		return "FormData", "formdata", nil
 	}
+	if t.PkgPath() == "mime/multipart" && t.Name() == "FileHeader" {
+		return "Blob", "file", nil
+	}
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "RawBytes" {
 		return "Uint8Array", "rawbytes", nil
 	}
 	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamResponse" {
 		return "Blob", "blob", nil
 	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamRequest" {
+		return "StreamRequestBody", "streamrequest", nil
+	}
 
 	switch t.Kind() {
 	case reflect.Bool:
@@ -438,7 +876,7 @@ func tsTypeFromType(t reflect.Type, registry *tsInterfaceRegistry) (string, stri
 		tsType, sig := tsInt64TypeAndSig()
 		return tsType, sig, nil
 	case reflect.Struct:
-		if t.Name() != "" {
+		if t.Name() != "" && !registry.inline[t] {
 			name, err := registry.ensureNamedStructType(t)
 			if err != nil {
 				return "", "", err
@@ -451,7 +889,7 @@ func tsTypeFromType(t reflect.Type, registry *tsInterfaceRegistry) (string, stri
 		}
 		return "{\n" + body + "}", "obj" + sig, nil
 	case reflect.Map:
-		if t.Key().Kind() != reflect.String {
+		if !isJSONMapKey(t.Key()) {
 			return "Record<string, unknown>", "record_unknown", nil
 		}
 		elemType, elemSig, err := tsTypeFromType(t.Elem(), registry)
@@ -476,19 +914,57 @@ func tsTypeFromType(t reflect.Type, registry *tsInterfaceRegistry) (string, stri
 }
 
 func jsonFieldMeta(f reflect.StructField) (string, bool, bool) {
+	return fieldNameMeta(f, "")
+}
+
+// fieldNameMeta resolves a TS property name with precedence `json` tag, then fallbackTag
+// (`uri` for path params, `form` for query params), then the Go field name.
+// `json:"-"` or `<fallbackTag>:"-"` hides the field; optional follows `json:",omitempty"` only.
+func fieldNameMeta(f reflect.StructField, fallbackTag string) (string, bool, bool) {
 	tag := f.Tag.Get("json")
 	if tag == "-" {
 		return "", false, false
 	}
 	optional := strings.Contains(tag, ",omitempty")
-	if tag == "" {
-		return f.Name, optional, true
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name, optional, true
 	}
-	name := strings.Split(tag, ",")[0]
-	if name == "" {
-		return f.Name, optional, true
+	if name, found, ignored := nameFromStructTag(f, fallbackTag); ignored {
+		return "", false, false
+	} else if found {
+		return name, optional, true
+	}
+	return f.Name, optional, true
+}
+
+// fieldMeta is fieldNameMeta with the fallback tag registered for the struct owning f.
+// Query params with a `default` tag are optional, because the server fills them in when absent.
+func (r *tsInterfaceRegistry) fieldMeta(owner reflect.Type, f reflect.StructField) (string, bool, bool) {
+	if r == nil {
+		return jsonFieldMeta(f)
+	}
+	tag := r.paramTags[owner]
+	name, optional, ok := fieldNameMeta(f, tag)
+	if tag == "form" {
+		if _, hasDefault := f.Tag.Lookup("default"); hasDefault {
+			optional = true
+		}
+	}
+	return name, optional, ok
+}
+
+// setParamTag registers the fallback name tag of a path/query param struct before it is rendered.
+// The first registration wins, like the interface name of a reused type.
+func (r *tsInterfaceRegistry) setParamTag(t reflect.Type, tag string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	if _, ok := r.paramTags[t]; !ok {
+		r.paramTags[t] = tag
 	}
-	return name, optional, true
 }
 
 type tsUnionLiteral struct {
@@ -570,6 +1046,98 @@ func parseTSUnionLiteral(base reflect.Type, raw string) (tsUnionLiteral, error)
 	}
 }
 
+// tsNamedUnion is a tsunion literal set. Name is set for `tsunionname` fields, which share one
+// exported `type Name = ...` alias and `isName` guard instead of repeating the literals inline.
+type tsNamedUnion struct {
+	Name   string
+	Values []tsUnionLiteral
+}
+
+func (u tsNamedUnion) typeExpr() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return tsUnionType(u.Values)
+}
+
+func (u tsNamedUnion) validatorExpr(valueExpr string) string {
+	if u.Name != "" {
+		return "is" + u.Name + "(" + valueExpr + ")"
+	}
+	return tsUnionValidatorExpr(valueExpr, u.Values)
+}
+
+// fieldUnion reads a field's tsunion and registers its `tsunionname` alias, if any.
+// Reusing an alias name with different literals is an error.
+func (r *tsInterfaceRegistry) fieldUnion(f reflect.StructField) (tsNamedUnion, bool, error) {
+	values, ok, err := tsUnionValuesFromField(f)
+	if err != nil || !ok {
+		return tsNamedUnion{}, ok, err
+	}
+	rawName := strings.TrimSpace(f.Tag.Get("tsunionname"))
+	if rawName == "" || r == nil {
+		return tsNamedUnion{Values: values}, true, nil
+	}
+	name := sanitizeTypeName(rawName)
+	if name == "" {
+		return tsNamedUnion{}, false, fmt.Errorf("field %s: invalid tsunionname %q", f.Name, rawName)
+	}
+	union := tsNamedUnion{Name: name, Values: values}
+	if existing, ok := r.unions[name]; ok {
+		if tsUnionSig(existing.Values) != tsUnionSig(values) {
+			return tsNamedUnion{}, false, fmt.Errorf("field %s: tsunionname %s is already used for %s", f.Name, name, tsUnionType(existing.Values))
+		}
+		return union, true, nil
+	}
+	if _, taken := r.usedNames[name]; taken {
+		return tsNamedUnion{}, false, fmt.Errorf("field %s: tsunionname %s conflicts with a generated type name", f.Name, name)
+	}
+	r.usedNames[name] = struct{}{}
+	r.unions[name] = union
+	return union, true, nil
+}
+
+// renderNamedUnions renders the `tsunionname` aliases with their `isX` guards, sorted by name.
+func renderNamedUnions(registry *tsInterfaceRegistry) string {
+	names := make([]string, 0, len(registry.unions))
+	for name := range registry.unions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		union := registry.unions[name]
+		b.WriteString("// -----------------------------------------------------\n")
+		b.WriteString("// TYPE: ")
+		b.WriteString(name)
+		b.WriteString("\n")
+		b.WriteString("// -----------------------------------------------------\n")
+		b.WriteString("export type ")
+		b.WriteString(name)
+		b.WriteString(" = ")
+		b.WriteString(tsUnionType(union.Values))
+		b.WriteString(";\n\n")
+		b.WriteString("/**\n")
+		b.WriteString(" * Check whether a value is a ")
+		b.WriteString(name)
+		b.WriteString(" literal.\n")
+		b.WriteString(" * 校验一个值是否为 ")
+		b.WriteString(name)
+		b.WriteString(" 字面量。\n")
+		b.WriteString(" */\n")
+		b.WriteString("export function is")
+		b.WriteString(name)
+		b.WriteString("(value: unknown): value is ")
+		b.WriteString(name)
+		b.WriteString(" {\n")
+		b.WriteString("  return ")
+		b.WriteString(tsUnionValidatorExpr("value", union.Values))
+		b.WriteString(";\n")
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
 func tsUnionType(values []tsUnionLiteral) string {
 	parts := make([]string, 0, len(values))
 	for _, v := range values {
@@ -621,11 +1189,15 @@ func tsUnionValidatorExpr(valueExpr string, values []tsUnionLiteral) string {
 
 var tsIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-func tsPropName(name string) string {
-	if tsIdentifierRegexp.MatchString(name) {
-		return name
+func tsPropName(name string) (string, error) {
+	isIdent := tsIdentifierRegexp.MatchString(name)
+	if !isIdent && TSKeyQuotingMode == TSKeyQuotingNever {
+		return "", fmt.Errorf("property key %q is not a valid identifier and key quoting is %q", name, TSKeyQuotingNever)
+	}
+	if isIdent && TSKeyQuotingMode != TSKeyQuotingAlways {
+		return name, nil
 	}
-	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`
+	return `"` + strings.ReplaceAll(name, `"`, `\"`) + `"`, nil
 }
 
 func escapeTSComment(s string) string {
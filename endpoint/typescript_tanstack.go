@@ -0,0 +1,131 @@
+package endpoint
+
+import "strings"
+
+// defaultTanstackQueryModule is the package hooks are imported from when none is configured.
+const defaultTanstackQueryModule = "@tanstack/vue-query"
+
+// renderTanstackTS renders TanStack Query hooks for the endpoint classes of renderAxiosTS.
+// GET endpoints become useXxxQuery hooks keyed by apiQueryKey(NAME, params);
+// other methods become useXxxMutation hooks.
+func renderTanstackTS(metas []axiosFuncMeta) string {
+	if len(metas) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	writeTSMarker(&b, "TanStack Query")
+	b.WriteString("/**\n")
+	b.WriteString(" * Query key of a generated endpoint: `[NAME]` or `[NAME, params]`.\n")
+	b.WriteString(" * Pass only the NAME (e.g. `GetPersonByIDGet.NAME`) to invalidate every cached call of that endpoint.\n")
+	b.WriteString(" * 生成端点的查询 key：`[NAME]` 或 `[NAME, params]`；只传 NAME 可让该端点的全部缓存失效。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const apiQueryKey = (name: string, params?: unknown): readonly unknown[] =>\n")
+	b.WriteString("  params === undefined ? [name] : [name, params];\n\n")
+	for _, m := range metas {
+		className := axiosClassName(m.FuncName, m.Method)
+		hookBase := "use" + toUpperCamel(m.FuncName)
+		if m.APIDescription != "" {
+			b.WriteString("/** ")
+			b.WriteString(escapeTSComment(m.APIDescription))
+			b.WriteString(" */\n")
+		}
+		if m.Method == "GET" {
+			writeTanstackQueryHook(&b, m, className, hookBase+"Query")
+			continue
+		}
+		writeTanstackMutationHook(&b, m, className, hookBase+"Mutation")
+	}
+	writeTSMarkerEnd(&b, "TanStack Query")
+	return b.String()
+}
+
+func writeTanstackQueryHook(b *strings.Builder, m axiosFuncMeta, className string, hookName string) {
+	args := make([]string, 0, 2)
+	callArgs := make([]string, 0, 3)
+	keyVars := "undefined"
+	if m.HasParams {
+		args = append(args, "params: "+m.ParamsType)
+		callArgs = append(callArgs, "params")
+		keyVars = "params"
+	}
+	if m.HasReqBody {
+		args = append(args, "requestBody: "+m.RequestType)
+		callArgs = append(callArgs, "requestBody")
+		if m.HasParams {
+			keyVars = "{ params, requestBody }"
+		} else {
+			keyVars = "requestBody"
+		}
+	}
+	callArgs = append(callArgs, "{ signal }")
+	b.WriteString("export function ")
+	b.WriteString(hookName)
+	b.WriteString("(")
+	b.WriteString(strings.Join(args, ", "))
+	b.WriteString(") {\n")
+	b.WriteString("  return useQuery({\n")
+	b.WriteString("    queryKey: apiQueryKey(")
+	b.WriteString(className)
+	b.WriteString(".NAME")
+	if keyVars != "undefined" {
+		b.WriteString(", ")
+		b.WriteString(keyVars)
+	}
+	b.WriteString("),\n")
+	b.WriteString("    queryFn: ({ signal }: { signal: AbortSignal }) => ")
+	b.WriteString(className)
+	b.WriteString(".request(")
+	b.WriteString(strings.Join(callArgs, ", "))
+	b.WriteString("),\n")
+	b.WriteString("  });\n")
+	b.WriteString("}\n\n")
+}
+
+func writeTanstackMutationHook(b *strings.Builder, m axiosFuncMeta, className string, hookName string) {
+	variables := ""
+	call := ""
+	switch {
+	case m.HasParams && m.HasReqBody:
+		variables = "variables: { params: " + m.ParamsType + "; requestBody: " + m.RequestType + " }"
+		call = "variables.params, variables.requestBody"
+	case m.HasParams:
+		variables = "params: " + m.ParamsType
+		call = "params"
+	case m.HasReqBody:
+		variables = "requestBody: " + m.RequestType
+		call = "requestBody"
+	}
+	b.WriteString("export function ")
+	b.WriteString(hookName)
+	b.WriteString("() {\n")
+	b.WriteString("  return useMutation({\n")
+	b.WriteString("    mutationFn: (")
+	b.WriteString(variables)
+	b.WriteString(") => ")
+	b.WriteString(className)
+	b.WriteString(".request(")
+	b.WriteString(call)
+	b.WriteString("),\n")
+	b.WriteString("  });\n")
+	b.WriteString("}\n\n")
+}
+
+// tanstackImports lists the hook factories used by renderTanstackTS output.
+func tanstackImports(metas []axiosFuncMeta) []string {
+	hasQuery, hasMutation := false, false
+	for _, m := range metas {
+		if m.Method == "GET" {
+			hasQuery = true
+		} else {
+			hasMutation = true
+		}
+	}
+	out := make([]string, 0, 2)
+	if hasMutation {
+		out = append(out, "useMutation")
+	}
+	if hasQuery {
+		out = append(out, "useQuery")
+	}
+	return out
+}
@@ -9,12 +9,21 @@ import (
 	"strings"
 )
 
-// UnifiedTSExportOptions controls output paths for unified TS export.
-// UnifiedTSExportOptions 用于配置统一 TS 导出的输出路径。
+// UnifiedTSExportOptions controls output paths and generated features for unified TS export.
+// UnifiedTSExportOptions 用于配置统一 TS 导出的输出路径与生成的功能。
 type UnifiedTSExportOptions struct {
 	ServerTSPath    string
 	WebSocketTSPath string
 	SchemaTSPath    string
+	// IndexTSPath, when set, adds a barrel file re-exporting all three files plus an `apiClient`
+	// object mapping each endpoint NAME to its request function (or websocket client factory).
+	// IndexTSPath 非空时额外生成聚合入口文件：重新导出三个文件，并生成 `apiClient` 对象，
+	// 将每个端点的 NAME 映射到其请求函数（websocket 端点为客户端工厂函数）。
+	IndexTSPath string
+	// TSExportOptions selects the client library and optional features of the server file, as for
+	// ServerAPI.ExportTSWithOptions; the zero value matches ExportTS.
+	// TSExportOptions 选择服务端文件的客户端库与可选功能（与 ServerAPI.ExportTSWithOptions 相同）；零值与 ExportTS 一致。
+	TSExportOptions
 }
 
 // ExportUnifiedAPIsToTSFiles exports ServerAPI and WebSocketAPI into two TS files,
@@ -31,11 +40,11 @@ func ExportUnifiedAPIsToTSFiles(serverAPI ServerAPI, wsAPI WebSocketAPI, options
 	if strings.TrimSpace(options.SchemaTSPath) == "" {
 		return fmt.Errorf("schema ts path is required")
 	}
-	if filepath.IsAbs(options.ServerTSPath) || filepath.IsAbs(options.WebSocketTSPath) || filepath.IsAbs(options.SchemaTSPath) {
+	if filepath.IsAbs(options.ServerTSPath) || filepath.IsAbs(options.WebSocketTSPath) || filepath.IsAbs(options.SchemaTSPath) || filepath.IsAbs(options.IndexTSPath) {
 		return fmt.Errorf("all ts paths must be relative")
 	}
 
-	serverCode, err := generateAxiosFromEndpoints(serverAPI.BasePath, serverAPI.GroupPath, serverAPI.Endpoints)
+	serverCode, err := options.generate(serverAPI.BasePath, serverAPI.GroupPath, serverAPI.Endpoints)
 	if err != nil {
 		return err
 	}
@@ -77,9 +86,152 @@ func ExportUnifiedAPIsToTSFiles(serverAPI ServerAPI, wsAPI WebSocketAPI, options
 	if err := writeRelativeTSFile(options.WebSocketTSPath, wsCodeBody); err != nil {
 		return err
 	}
+	if strings.TrimSpace(options.IndexTSPath) != "" {
+		indexCode := renderUnifiedIndexTS(options, serverCodeBody, wsCodeBody, typeNames)
+		if err := writeRelativeTSFile(options.IndexTSPath, indexCode); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// tsClientEntry is one apiClient member: an endpoint class and how to call it.
+type tsClientEntry struct {
+	ClassName  string
+	Name       string
+	Method     string
+	ClientType string
+	ServerType string
+}
+
+var (
+	tsClassHeaderRe = regexp.MustCompile(`(?m)^export class ([A-Za-z_][A-Za-z0-9_]*)(.*) \{\n  static readonly NAME = '((?:[^'\\]|\\.)*)' as const;`)
+	tsExportNameRe  = regexp.MustCompile(`(?m)^export\s+(interface|type|class|const|function|async function)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// collectClientEntries finds endpoint classes in generated code. HTTP classes map to their static
+// request/connect method; websocket classes (extending TypedWebSocketClient) to a constructor factory.
+func collectClientEntries(code string) []tsClientEntry {
+	matches := tsClassHeaderRe.FindAllStringSubmatchIndex(code, -1)
+	entries := make([]tsClientEntry, 0, len(matches))
+	for i, m := range matches {
+		end := len(code)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		entry := tsClientEntry{
+			ClassName: code[m[2]:m[3]],
+			Name:      strings.ReplaceAll(code[m[6]:m[7]], "\\'", "'"),
+		}
+		header := code[m[4]:m[5]]
+		body := code[m[1]:end]
+		const sendPrefix = "<TSend = "
+		const extends = "> extends TypedWebSocketClient<"
+		switch {
+		case strings.HasPrefix(header, sendPrefix) && strings.Contains(header, extends):
+			split := strings.Index(header, extends)
+			entry.ClientType = header[len(sendPrefix):split]
+			serverType, _, _ := strings.Cut(header[split+len(extends):], ", TSend, ")
+			entry.ServerType = serverType
+		case strings.Contains(body, "\n  static async request("):
+			entry.Method = "request"
+		case strings.Contains(body, "\n  static connect("):
+			entry.Method = "connect"
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// exportedTSNames lists the names exported by generated code, split into type-only and value exports.
+func exportedTSNames(code string) ([]string, []string) {
+	typeNames := make([]string, 0)
+	valueNames := make([]string, 0)
+	for _, m := range tsExportNameRe.FindAllStringSubmatch(code, -1) {
+		switch m[1] {
+		case "interface", "type":
+			typeNames = append(typeNames, m[2])
+		default:
+			valueNames = append(valueNames, m[2])
+		}
+	}
+	sort.Strings(typeNames)
+	sort.Strings(valueNames)
+	return uniqueStrings(typeNames), uniqueStrings(valueNames)
+}
+
+// renderUnifiedIndexTS renders the barrel file: star re-exports keep tree-shaking intact, and
+// apiClient is keyed by each class's NAME constant. Repeated NAMEs (same path, different methods)
+// fall back to the lowerCamel class name.
+func renderUnifiedIndexTS(options UnifiedTSExportOptions, serverCode, wsCode string, schemaTypeNames []string) string {
+	serverImport := buildTSImportPath(options.IndexTSPath, options.ServerTSPath)
+	wsImport := buildTSImportPath(options.IndexTSPath, options.WebSocketTSPath)
+	schemaImport := buildTSImportPath(options.IndexTSPath, options.SchemaTSPath)
+
+	entries := append(collectClientEntries(serverCode), collectClientEntries(wsCode)...)
+	nameCount := map[string]int{}
+	for _, e := range entries {
+		nameCount[e.Name]++
+	}
+
+	var client strings.Builder
+	client.WriteString("/**\n")
+	client.WriteString(" * Every endpoint keyed by its NAME. HTTP entries are the static request functions;\n")
+	client.WriteString(" * websocket entries create a new client.\n")
+	client.WriteString(" * 按 NAME 索引的全部端点：HTTP 端点为静态请求函数，websocket 端点为创建客户端的工厂函数。\n")
+	client.WriteString(" */\n")
+	client.WriteString("export const apiClient = {\n")
+	for _, e := range entries {
+		client.WriteString("  ")
+		if nameCount[e.Name] > 1 {
+			client.WriteString(toLowerCamel(e.ClassName))
+		} else {
+			client.WriteString("[")
+			client.WriteString(e.ClassName)
+			client.WriteString(".NAME]")
+		}
+		client.WriteString(": ")
+		if e.Method != "" {
+			client.WriteString(e.ClassName)
+			client.WriteString(".")
+			client.WriteString(e.Method)
+		} else {
+			client.WriteString("<TSend = ")
+			client.WriteString(e.ClientType)
+			client.WriteString(">(options: WebSocketConvertOptions<TSend, ")
+			client.WriteString(e.ServerType)
+			client.WriteString(">) => new ")
+			client.WriteString(e.ClassName)
+			client.WriteString("<TSend>(options)")
+		}
+		client.WriteString(",\n")
+	}
+	client.WriteString("} as const;\n\n")
+	client.WriteString("export type APIClient = typeof apiClient;\n")
+	clientCode := client.String()
+
+	imports := buildImportStatements(schemaImport, usedSymbolsInCode(schemaTypeNames, clientCode), nil)
+	serverTypes, serverValues := exportedTSNames(serverCode)
+	imports = append(imports, buildImportStatements(serverImport, usedSymbolsInCode(serverTypes, clientCode), usedSymbolsInCode(serverValues, clientCode))...)
+	wsTypes, wsValues := exportedTSNames(wsCode)
+	imports = append(imports, buildImportStatements(wsImport, usedSymbolsInCode(wsTypes, clientCode), usedSymbolsInCode(wsValues, clientCode))...)
+
+	var b strings.Builder
+	writeTSBanner(&b, "Nuxt Gin API Index")
+	for _, stmt := range imports {
+		b.WriteString(stmt)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("export * from '" + schemaImport + "';\n")
+	b.WriteString("export * from '" + serverImport + "';\n")
+	b.WriteString("export * from '" + wsImport + "';\n\n")
+	b.WriteString(clientCode)
+	return finalizeTypeScriptCode(b.String())
+}
+
 type tsExportBlock struct {
 	Kind string
 	Name string
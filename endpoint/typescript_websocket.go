@@ -19,6 +19,23 @@ type wsFuncMeta struct {
 	MessageTypes        []string
 	ClientPayloadByType map[string]string
 	ServerPayloadByType map[string]string
+	RPCMethods          []string
+	RPCParamsByMethod   map[string]string
+	RPCResultByMethod   map[string]string
+	StateMachine        *WebSocketStateMachine
+	SendKind            string
+	ReceiveKind         string
+}
+
+// TSWebSocketJSONRPC controls whether the websocket client gets a JSON-RPC 2.0 layer:
+// a `call(method, params)` method on TypedWebSocketClient and one typed `rpcXxx()` method per
+// method registered with RegisterWebSocketRPCMethod.
+// Default is false to keep the generated client unchanged for non-RPC apps.
+var TSWebSocketJSONRPC = false
+
+// SetTSWebSocketJSONRPC enables or disables JSON-RPC method generation for websocket clients.
+func SetTSWebSocketJSONRPC(enabled bool) {
+	TSWebSocketJSONRPC = enabled
 }
 
 // GenerateWebSocketClientFromEndpoints generates TypeScript websocket client source code from endpoints.
@@ -45,14 +62,30 @@ func generateWebSocketClientFromEndpoints(basePath string, groupPath string, end
 		if err := validateWebSocketPayloadTypeMappings(meta); err != nil {
 			return "", fmt.Errorf("websocket endpoint[%d] validation failed: %w", i, err)
 		}
+		if err := validateWebSocketStateMachine(meta); err != nil {
+			return "", fmt.Errorf("websocket endpoint[%d] validation failed: %w", i, err)
+		}
 
 		base := wsBaseName(meta, i)
 
-		clientType, _, err := tsTypeFromType(meta.ClientMessageType, registry)
+		var hints WebSocketEndpointTSHints
+		if hintProvider, ok := e.(WebSocketEndpointTSHintsProvider); ok {
+			hints = hintProvider.WebSocketEndpointTSHints()
+		}
+		sendKind, err := wsMessageKind(hints.ClientMessageKind)
+		if err != nil {
+			return "", fmt.Errorf("websocket endpoint[%d] client message kind: %w", i, err)
+		}
+		receiveKind, err := wsMessageKind(hints.ServerMessageKind)
+		if err != nil {
+			return "", fmt.Errorf("websocket endpoint[%d] server message kind: %w", i, err)
+		}
+
+		clientType, err := wsMessageTSType(sendKind, meta.ClientMessageType, registry)
 		if err != nil {
 			return "", fmt.Errorf("build client message type for websocket endpoint[%d]: %w", i, err)
 		}
-		serverType, _, err := tsTypeFromType(meta.ServerMessageType, registry)
+		serverType, err := wsMessageTSType(receiveKind, meta.ServerMessageType, registry)
 		if err != nil {
 			return "", fmt.Errorf("build server message type for websocket endpoint[%d]: %w", i, err)
 		}
@@ -79,6 +112,32 @@ func generateWebSocketClientFromEndpoints(basePath string, groupPath string, end
 			serverPayloadByType[msgType] = payloadTSType
 		}
 
+		var rpcMethods []string
+		rpcParamsByMethod := map[string]string{}
+		rpcResultByMethod := map[string]string{}
+		if TSWebSocketJSONRPC {
+			for method, paramsType := range meta.RPCParamTypes {
+				paramsTSType := "void"
+				if isValidType(paramsType) {
+					paramsTSType, _, err = tsTypeFromType(paramsType, registry)
+					if err != nil {
+						return "", fmt.Errorf("build rpc params type for websocket endpoint[%d] method %q: %w", i, method, err)
+					}
+				}
+				resultTSType := "void"
+				if resultType := meta.RPCResultTypes[method]; isValidType(resultType) {
+					resultTSType, _, err = tsTypeFromType(resultType, registry)
+					if err != nil {
+						return "", fmt.Errorf("build rpc result type for websocket endpoint[%d] method %q: %w", i, method, err)
+					}
+				}
+				rpcMethods = append(rpcMethods, method)
+				rpcParamsByMethod[method] = paramsTSType
+				rpcResultByMethod[method] = resultTSType
+			}
+			sort.Strings(rpcMethods)
+		}
+
 		metas = append(metas, wsFuncMeta{
 			FuncName:            toLowerCamel(base),
 			Path:                meta.Path,
@@ -88,6 +147,12 @@ func generateWebSocketClientFromEndpoints(basePath string, groupPath string, end
 			MessageTypes:        normalizeMessageTypes(meta.MessageTypes),
 			ClientPayloadByType: clientPayloadByType,
 			ServerPayloadByType: serverPayloadByType,
+			RPCMethods:          rpcMethods,
+			RPCParamsByMethod:   rpcParamsByMethod,
+			RPCResultByMethod:   rpcResultByMethod,
+			StateMachine:        meta.StateMachine,
+			SendKind:            sendKind,
+			ReceiveKind:         receiveKind,
 		})
 	}
 	sort.Slice(metas, func(i, j int) bool {
@@ -162,9 +227,14 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	writeTSMarker(&b, "Runtime Helpers")
 	b.WriteString("const isPlainObject = (value: unknown): value is Record<string, unknown> =>\n")
 	b.WriteString("  Object.prototype.toString.call(value) === '[object Object]';\n\n")
-	b.WriteString("const isoDateLike = /^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}(?:\\.\\d{1,9})?(?:Z|[+\\-]\\d{2}:\\d{2})$/;\n\n")
+	if TSAutoDateParsing {
+		b.WriteString("const isoDateLike = /^\\d{4}-\\d{2}-\\d{2}T\\d{2}:\\d{2}:\\d{2}(?:\\.\\d{1,9})?(?:Z|[+\\-]\\d{2}:\\d{2})$/;\n\n")
+	}
 	b.WriteString("const normalizeWsRequestJSON = (value: unknown): unknown => {\n")
 	b.WriteString("  if (value instanceof Date) return value.toISOString();\n")
+	if TSInt64MappingMode == TSInt64ModeBigInt {
+		b.WriteString("  if (typeof value === 'bigint') return value.toString();\n")
+	}
 	b.WriteString("  if (Array.isArray(value)) return value.map(normalizeWsRequestJSON);\n")
 	b.WriteString("  if (isPlainObject(value)) {\n")
 	b.WriteString("    const out: Record<string, unknown> = {};\n")
@@ -175,10 +245,12 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("};\n\n")
 	b.WriteString("const normalizeWsResponseJSON = (value: unknown): unknown => {\n")
 	b.WriteString("  if (Array.isArray(value)) return value.map(normalizeWsResponseJSON);\n")
-	b.WriteString("  if (typeof value === 'string' && isoDateLike.test(value)) {\n")
-	b.WriteString("    const date = new Date(value);\n")
-	b.WriteString("    if (!Number.isNaN(date.getTime())) return date;\n")
-	b.WriteString("  }\n")
+	if TSAutoDateParsing {
+		b.WriteString("  if (typeof value === 'string' && isoDateLike.test(value)) {\n")
+		b.WriteString("    const date = new Date(value);\n")
+		b.WriteString("    if (!Number.isNaN(date.getTime())) return date;\n")
+		b.WriteString("  }\n")
+	}
 	b.WriteString("  if (isPlainObject(value)) {\n")
 	b.WriteString("    const out: Record<string, unknown> = {};\n")
 	b.WriteString("    for (const [k, v] of Object.entries(value)) out[k] = normalizeWsResponseJSON(v);\n")
@@ -187,9 +259,115 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  return value;\n")
 	b.WriteString("};\n\n")
 
+	b.WriteString("/**\n")
+	b.WriteString(" * Standard close codes (RFC 6455), e.g. for checking lastClose?.code or calling close(WebSocketCloseCode.Normal).\n")
+	b.WriteString(" * 标准关闭码（RFC 6455），可用于判断 lastClose?.code 或调用 close(WebSocketCloseCode.Normal)。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const WebSocketCloseCode = {\n")
+	b.WriteString("  Normal: 1000,\n")
+	b.WriteString("  GoingAway: 1001,\n")
+	b.WriteString("  ProtocolError: 1002,\n")
+	b.WriteString("  UnsupportedData: 1003,\n")
+	b.WriteString("  NoStatus: 1005,\n")
+	b.WriteString("  Abnormal: 1006,\n")
+	b.WriteString("  InvalidPayload: 1007,\n")
+	b.WriteString("  PolicyViolation: 1008,\n")
+	b.WriteString("  MessageTooBig: 1009,\n")
+	b.WriteString("  MandatoryExtension: 1010,\n")
+	b.WriteString("  InternalError: 1011,\n")
+	b.WriteString("  ServiceRestart: 1012,\n")
+	b.WriteString("  TryAgainLater: 1013,\n")
+	b.WriteString("} as const;\n")
+	b.WriteString("export type WebSocketCloseCode = (typeof WebSocketCloseCode)[keyof typeof WebSocketCloseCode];\n\n")
+	b.WriteString("export interface WebSocketReconnectOptions {\n")
+	b.WriteString("  /** Reconnect automatically after an unexpected close. 非主动关闭后自动重连。 */\n")
+	b.WriteString("  enabled?: boolean;\n")
+	b.WriteString("  /** Max consecutive attempts; unlimited when omitted. 最大连续重连次数，缺省不限。 */\n")
+	b.WriteString("  maxRetries?: number;\n")
+	b.WriteString("  /** Base delay of the first retry in ms. 首次重连的基础延迟（毫秒）。 */\n")
+	b.WriteString("  baseDelayMs?: number;\n")
+	b.WriteString("  /** Alias of baseDelayMs. baseDelayMs 的别名。 */\n")
+	b.WriteString("  backoffMs?: number;\n")
+	b.WriteString("  /** Upper bound of a single retry delay in ms. 单次重连延迟上限（毫秒）。 */\n")
+	b.WriteString("  maxDelayMs?: number;\n")
+	b.WriteString("  /** Full jitter: pick a random delay in [0, capped delay]. 全抖动：在 [0, 上限] 内随机取值。 */\n")
+	b.WriteString("  jitter?: boolean;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("export const DEFAULT_WEBSOCKET_RECONNECT = {\n")
+	b.WriteString("  backoffMs: 500,\n")
+	b.WriteString("  maxDelayMs: 30000,\n")
+	b.WriteString("  jitter: true,\n")
+	b.WriteString("} as const;\n\n")
+	b.WriteString("/**\n")
+	b.WriteString(" * Compute the delay before reconnect attempt `attempt` (0-based) using capped exponential backoff.\n")
+	b.WriteString(" * 使用带上限的指数退避计算第 `attempt` 次（从 0 开始）重连前的等待时间。\n")
+	b.WriteString(" */\n")
+	b.WriteString("export const computeReconnectDelay = (\n")
+	b.WriteString("  attempt: number,\n")
+	b.WriteString("  options?: WebSocketReconnectOptions,\n")
+	b.WriteString("  random: () => number = Math.random\n")
+	b.WriteString("): number => {\n")
+	b.WriteString("  const backoffMs = Math.max(0, options?.baseDelayMs ?? options?.backoffMs ?? DEFAULT_WEBSOCKET_RECONNECT.backoffMs);\n")
+	b.WriteString("  const maxDelayMs = Math.max(0, options?.maxDelayMs ?? DEFAULT_WEBSOCKET_RECONNECT.maxDelayMs);\n")
+	b.WriteString("  const jitter = options?.jitter ?? DEFAULT_WEBSOCKET_RECONNECT.jitter;\n")
+	b.WriteString("  const exponent = Math.max(0, Math.floor(attempt));\n")
+	b.WriteString("  const capped = Math.min(maxDelayMs, backoffMs * 2 ** exponent);\n")
+	b.WriteString("  return jitter ? Math.floor(random() * capped) : capped;\n")
+	b.WriteString("};\n\n")
+
+	b.WriteString("export interface WebSocketHeartbeatOptions {\n")
+	b.WriteString("  /** Interval between heartbeats in ms. 心跳间隔（毫秒）。 */\n")
+	b.WriteString("  intervalMs: number;\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Heartbeat message, defaults to `{ type: 'ping' }`. A custom type must be registered on the server\n")
+	b.WriteString("   * or set as its HeartbeatMessageType, otherwise the server closes the connection.\n")
+	b.WriteString("   * 心跳消息，默认 `{ type: 'ping' }`；自定义类型需在服务端注册处理器或设置为 HeartbeatMessageType，否则服务端会关闭连接。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  message?: unknown;\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("export interface WebSocketWaitOpenOptions {\n")
+	b.WriteString("  /** Abort waiting. 中止等待。 */\n")
+	b.WriteString("  signal?: AbortSignal;\n")
+	b.WriteString("  /** Reject if not open within this many ms. 超过该毫秒数仍未打开则 reject。 */\n")
+	b.WriteString("  timeoutMs?: number;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("export interface WebSocketLatencyOptions {\n")
+	b.WriteString("  /** Reject if no echo arrives within this many ms. Default 5000. 超时未收到回显则 reject，默认 5000。 */\n")
+	b.WriteString("  timeoutMs?: number;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("export interface WebSocketSendBufferOptions {\n")
+	b.WriteString("  /** Buffer messages sent while not open and flush them in order on every (re)connect. 未打开时缓冲消息，每次（重新）连接后按顺序发送。 */\n")
+	b.WriteString("  enabled?: boolean;\n")
+	b.WriteString("  /** Drop a buffered message older than this many ms at flush time. 发送时丢弃缓冲超过该毫秒数的消息。 */\n")
+	b.WriteString("  ttlMs?: number;\n")
+	b.WriteString("  /** Max buffered messages; the oldest is dropped when exceeded. 最大缓冲条数，超出时丢弃最旧的消息。 */\n")
+	b.WriteString("  maxSize?: number;\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString("/** How messages are framed: JSON text (default), plain text or binary. 消息帧格式：JSON 文本（默认）、纯文本或二进制。 */\n")
+	b.WriteString("export type WebSocketMessageKind = 'json' | 'text' | 'binary';\n\n")
 	b.WriteString("export interface WebSocketConvertOptions<TSend = unknown, TReceive = unknown> {\n")
 	b.WriteString("  serialize?: (value: TSend) => unknown;\n")
 	b.WriteString("  deserialize?: (value: unknown) => TReceive;\n")
+	b.WriteString("  /** Frame format of sent messages, default 'json'. 发送消息的帧格式，默认 'json'。 */\n")
+	b.WriteString("  sendKind?: WebSocketMessageKind;\n")
+	b.WriteString("  /** Frame format of received messages, default 'json'; 'binary' delivers Uint8Array. 接收消息的帧格式，默认 'json'；'binary' 时收到 Uint8Array。 */\n")
+	b.WriteString("  receiveKind?: WebSocketMessageKind;\n")
+	b.WriteString("  reconnect?: WebSocketReconnectOptions;\n")
+	b.WriteString("  heartbeat?: WebSocketHeartbeatOptions;\n")
+	b.WriteString("  /** Buffer sends across reconnect windows instead of throwing on a closed socket. 重连期间缓冲发送，而不是在已关闭的 socket 上抛错。 */\n")
+	b.WriteString("  retryAfterReconnect?: WebSocketSendBufferOptions;\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Queue send() calls made before the socket is open (status !== 'open') and flush them in order on open.\n")
+	b.WriteString("   * Queued messages survive a reconnect; they are dropped (counted in messagesDropped) on close() or when\n")
+	b.WriteString("   * the socket closes with no reconnect scheduled. messagesSent only counts frames actually written.\n")
+	b.WriteString("   * 在 socket 打开之前（status !== 'open'）调用 send() 时先入队，open 时按顺序发送；\n")
+	b.WriteString("   * 队列在重连期间保留，主动 close() 或关闭后不再重连时丢弃（计入 messagesDropped）；messagesSent 只统计真正写出的帧。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  queueWhileConnecting?: boolean;\n")
+	b.WriteString("  /** Query params appended to the URL, e.g. `{ token }` checked by BeforeUpgrade. 追加到 URL 的查询参数，例如供 BeforeUpgrade 校验的 `{ token }`。 */\n")
+	b.WriteString("  query?: Record<string, string>;\n")
 	b.WriteString("}\n\n")
 
 	b.WriteString("export interface TypedHandlerOptions<TReceive, TPayload> {\n")
@@ -225,7 +403,7 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  }\n")
 	b.WriteString("  return '80';\n")
 	b.WriteString("};\n\n")
-	b.WriteString("const resolveWebSocketURL = (url: string): string => {\n")
+	b.WriteString("const resolveWebSocketBaseURL = (url: string): string => {\n")
 	b.WriteString("  if (url.startsWith('ws://') || url.startsWith('wss://')) return url;\n")
 	b.WriteString("  if (url.startsWith('http://')) return `ws://${url.slice(7)}`;\n")
 	b.WriteString("  if (url.startsWith('https://')) return `wss://${url.slice(8)}`;\n")
@@ -243,6 +421,21 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  return url;\n")
 	b.WriteString("};\n\n")
 
+	b.WriteString("const nowMs = (): number =>\n")
+	b.WriteString("  typeof performance !== 'undefined' && typeof performance.now === 'function' ? performance.now() : Date.now();\n\n")
+	b.WriteString("const resolveWebSocketURL = (url: string, query?: Record<string, string>): string => {\n")
+	b.WriteString("  const resolved = resolveWebSocketBaseURL(url);\n")
+	b.WriteString("  if (!query) return resolved;\n")
+	b.WriteString("  const params = new URLSearchParams();\n")
+	b.WriteString("  for (const [k, v] of Object.entries(query)) {\n")
+	b.WriteString("    if (v === undefined || v === null) continue;\n")
+	b.WriteString("    params.append(k, String(v));\n")
+	b.WriteString("  }\n")
+	b.WriteString("  const search = params.toString();\n")
+	b.WriteString("  if (!search) return resolved;\n")
+	b.WriteString("  return `${resolved}${resolved.includes('?') ? '&' : '?'}${search}`;\n")
+	b.WriteString("};\n\n")
+
 	b.WriteString("const joinURLPath = (baseURL: string, path: string): string => {\n")
 	b.WriteString("  const base = baseURL.trim();\n")
 	b.WriteString("  const p = path.trim();\n")
@@ -252,6 +445,40 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  const trimmedPath = p.replace(/^\\/+/, '');\n")
 	b.WriteString("  return trimmedBase.startsWith('/') ? `${trimmedBase}/${trimmedPath}` : `/${trimmedBase}/${trimmedPath}`;\n")
 	b.WriteString("};\n\n")
+	if TSWebSocketJSONRPC {
+		b.WriteString("export interface WebSocketRPCCallOptions {\n")
+		b.WriteString("  /** Reject when no response arrives in time. Default 30000. 超时未响应则 reject，默认 30000。 */\n")
+		b.WriteString("  timeoutMs?: number;\n")
+		b.WriteString("}\n\n")
+		b.WriteString("/**\n")
+		b.WriteString(" * Error object returned by a JSON-RPC method.\n")
+		b.WriteString(" * JSON-RPC 方法返回的错误对象。\n")
+		b.WriteString(" */\n")
+		b.WriteString("export class JsonRpcError extends Error {\n")
+		b.WriteString("  constructor(\n")
+		b.WriteString("    public readonly code: number,\n")
+		b.WriteString("    message: string,\n")
+		b.WriteString("    public readonly data?: unknown\n")
+		b.WriteString("  ) {\n")
+		b.WriteString("    super(message);\n")
+		b.WriteString("    this.name = 'JsonRpcError';\n")
+		b.WriteString("  }\n")
+		b.WriteString("}\n\n")
+	}
+	for _, m := range metas {
+		if m.StateMachine != nil {
+			b.WriteString("/**\n")
+			b.WriteString(" * Client protocol states: allowed send types per state and state changes on received types.\n")
+			b.WriteString(" * 客户端协议状态：每个状态允许发送的消息类型，以及收到某类型消息后的状态切换。\n")
+			b.WriteString(" */\n")
+			b.WriteString("export interface WebSocketStateMachine {\n")
+			b.WriteString("  initial: string;\n")
+			b.WriteString("  allowed: Record<string, readonly string[]>;\n")
+			b.WriteString("  transitions: Record<string, string>;\n")
+			b.WriteString("}\n\n")
+			break
+		}
+	}
 	writeTSMarkerEnd(&b, "Runtime Helpers")
 
 	writeTSMarker(&b, "Typed WebSocket Client")
@@ -260,7 +487,7 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString(" * 通用的类型化 WebSocket 客户端，支持全量消息订阅与按 type 订阅。\n")
 	b.WriteString(" */\n")
 	b.WriteString("export class TypedWebSocketClient<TReceive = unknown, TSend = unknown, TType extends string = string> {\n")
-	b.WriteString("  public readonly socket: WebSocket;\n")
+	b.WriteString("  public socket!: WebSocket;\n")
 	b.WriteString("  public readonly url: string;\n")
 	b.WriteString("  public status: 'connecting' | 'open' | 'closing' | 'closed' = 'connecting';\n")
 	b.WriteString("  public lastError?: Event;\n")
@@ -270,13 +497,40 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  public messagesSent = 0;\n")
 	b.WriteString("  public messagesReceived = 0;\n")
 	b.WriteString("  public reconnectCount = 0;\n")
+	b.WriteString("  public lastLatencyMs?: number;\n")
+	b.WriteString("  public messagesFlushed = 0;\n")
+	b.WriteString("  public messagesDropped = 0;\n")
 	b.WriteString("  private readonly serialize: (value: TSend) => unknown;\n")
 	b.WriteString("  private readonly deserialize: (value: unknown) => TReceive;\n")
+	b.WriteString("  private readonly sendKind: WebSocketMessageKind;\n")
+	b.WriteString("  private readonly receiveKind: WebSocketMessageKind;\n")
+	b.WriteString("  private readonly reconnectOptions?: WebSocketReconnectOptions;\n")
+	b.WriteString("  private reconnectTimer?: ReturnType<typeof setTimeout>;\n")
+	b.WriteString("  private readonly heartbeatOptions?: WebSocketHeartbeatOptions;\n")
+	b.WriteString("  private heartbeatTimer?: ReturnType<typeof setInterval>;\n")
+	b.WriteString("  private readonly sendBufferOptions?: WebSocketSendBufferOptions;\n")
+	b.WriteString("  private readonly queueWhileConnecting: boolean;\n")
+	b.WriteString("  private readonly sendBuffer: { data: string | ArrayBuffer | ArrayBufferView; queuedAt: number }[] = [];\n")
+	b.WriteString("  private manuallyClosed = false;\n")
+	b.WriteString("  private reconnecting = false;\n")
+	b.WriteString("  private latencySeq = 0;\n")
+	b.WriteString("  private readonly latencyPending = new Map<string, (receivedAt: number) => void>();\n")
 	b.WriteString("  private readonly messageListeners = new Set<(message: TReceive) => void>();\n")
+	b.WriteString("  private readonly binaryListeners = new Set<(data: Uint8Array) => void>();\n")
 	b.WriteString("  private readonly openListeners = new Set<(event: Event) => void>();\n")
 	b.WriteString("  private readonly closeListeners = new Set<(event: CloseEvent) => void>();\n")
 	b.WriteString("  private readonly errorListeners = new Set<(event: Event) => void>();\n")
-	b.WriteString("  private readonly typedListeners = new Map<TType, Set<(message: TReceive) => void>>();\n\n")
+	b.WriteString("  private readonly reconnectingListeners = new Set<(attempt: number) => void>();\n")
+	b.WriteString("  private readonly reconnectedListeners = new Set<() => void>();\n")
+	b.WriteString("  private readonly typedListeners = new Map<TType, Set<(message: TReceive) => void>>();\n")
+	if TSWebSocketJSONRPC {
+		b.WriteString("  private rpcSeq = 0;\n")
+		b.WriteString("  private readonly rpcPending = new Map<\n")
+		b.WriteString("    number,\n")
+		b.WriteString("    { resolve: (result: unknown) => void; reject: (error: Error) => void; timer: ReturnType<typeof setTimeout> }\n")
+		b.WriteString("  >();\n")
+	}
+	b.WriteString("\n")
 	b.WriteString("  /**\n")
 	b.WriteString("   * Create a websocket client and connect immediately.\n")
 	b.WriteString("   * 创建 websocket 客户端并立即发起连接。\n")
@@ -285,13 +539,41 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("  url: string,\n")
 	b.WriteString("  options: WebSocketConvertOptions<TSend, TReceive>\n")
 	b.WriteString("  ) {\n")
-	b.WriteString("    const resolvedURL = resolveWebSocketURL(url);\n")
-	b.WriteString("    this.url = resolvedURL;\n")
-	b.WriteString("    this.socket = new WebSocket(resolvedURL);\n")
-	b.WriteString("    this.serialize = options?.serialize ?? ((value: TSend) => normalizeWsRequestJSON(value));\n")
-	b.WriteString("    this.deserialize = options?.deserialize ?? ((value: unknown) => normalizeWsResponseJSON(value) as TReceive);\n")
+	b.WriteString("    this.url = resolveWebSocketURL(url, options?.query);\n")
+	b.WriteString("    this.sendKind = options?.sendKind ?? 'json';\n")
+	b.WriteString("    this.receiveKind = options?.receiveKind ?? 'json';\n")
+	b.WriteString("    this.serialize = options?.serialize ?? ((value: TSend) => (this.sendKind === 'json' ? normalizeWsRequestJSON(value) : value));\n")
+	b.WriteString("    this.deserialize =\n")
+	b.WriteString("      options?.deserialize ?? ((value: unknown) => (this.receiveKind === 'json' ? normalizeWsResponseJSON(value) : value) as TReceive);\n")
+	b.WriteString("    this.reconnectOptions = options?.reconnect;\n")
+	b.WriteString("    this.heartbeatOptions = options?.heartbeat;\n")
+	b.WriteString("    this.sendBufferOptions = options?.retryAfterReconnect;\n")
+	b.WriteString("    this.queueWhileConnecting = options?.queueWhileConnecting ?? false;\n")
+	b.WriteString("    this.connect();\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Open a new underlying socket. Listeners live on the client, so they survive reconnects.\n")
+	b.WriteString("   * 创建新的底层 socket；监听器保存在客户端上，重连后自动继续生效。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  private connect(): void {\n")
+	b.WriteString("    const socket = new WebSocket(this.url);\n")
+	b.WriteString("    socket.binaryType = 'arraybuffer';\n")
+	b.WriteString("    this.socket = socket;\n")
+	b.WriteString("    this.status = 'connecting';\n")
 	b.WriteString("\n")
-	b.WriteString("    this.socket.addEventListener('message', (event) => {\n")
+	b.WriteString("    socket.addEventListener('message', (event) => {\n")
+	b.WriteString("      if (socket !== this.socket) return;\n")
+	b.WriteString("      if (event.data instanceof ArrayBuffer) {\n")
+	b.WriteString("        this.messagesReceived += 1;\n")
+	b.WriteString("        this.emitBinary(new Uint8Array(event.data));\n")
+	b.WriteString("        if (this.receiveKind === 'binary') this.emitMessage(this.deserialize(new Uint8Array(event.data)));\n")
+	b.WriteString("        return;\n")
+	b.WriteString("      }\n")
+	b.WriteString("      if (this.receiveKind !== 'json') {\n")
+	b.WriteString("        this.messagesReceived += 1;\n")
+	b.WriteString("        this.emitMessage(this.deserialize(event.data));\n")
+	b.WriteString("        return;\n")
+	b.WriteString("      }\n")
 	b.WriteString("      let payload: unknown = event.data;\n")
 	b.WriteString("      if (typeof payload === 'string') {\n")
 	b.WriteString("        try {\n")
@@ -300,23 +582,66 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("          // keep raw payload\n")
 	b.WriteString("        }\n")
 	b.WriteString("      }\n")
+	if TSWebSocketJSONRPC {
+		b.WriteString("      if (isPlainObject(payload) && payload.jsonrpc === '2.0' && typeof payload.id === 'number') {\n")
+		b.WriteString("        const pending = this.rpcPending.get(payload.id);\n")
+		b.WriteString("        if (pending) {\n")
+		b.WriteString("          this.rpcPending.delete(payload.id);\n")
+		b.WriteString("          clearTimeout(pending.timer);\n")
+		b.WriteString("          this.messagesReceived += 1;\n")
+		b.WriteString("          const error = payload.error;\n")
+		b.WriteString("          if (isPlainObject(error)) {\n")
+		b.WriteString("            pending.reject(new JsonRpcError(Number(error.code), String(error.message ?? ''), error.data));\n")
+		b.WriteString("          } else {\n")
+		b.WriteString("            pending.resolve(normalizeWsResponseJSON(payload.result));\n")
+		b.WriteString("          }\n")
+		b.WriteString("          return;\n")
+		b.WriteString("        }\n")
+		b.WriteString("      }\n")
+	}
+	b.WriteString("      if (isPlainObject(payload) && payload.type === 'ack' && typeof payload.ackId === 'string') {\n")
+	b.WriteString("        const settle = this.latencyPending.get(payload.ackId);\n")
+	b.WriteString("        if (settle) {\n")
+	b.WriteString("          settle(nowMs());\n")
+	b.WriteString("          return;\n")
+	b.WriteString("        }\n")
+	b.WriteString("      }\n")
+	b.WriteString("      if (isPlainObject(payload) && payload.type !== 'ack' && typeof payload.ackId === 'string') {\n")
+	b.WriteString("        this.sendAck(payload.ackId);\n")
+	b.WriteString("      }\n")
 	b.WriteString("      const message = this.deserialize(payload);\n")
 	b.WriteString("      this.messagesReceived += 1;\n")
 	b.WriteString("      this.emitMessage(message);\n")
 	b.WriteString("    });\n")
-	b.WriteString("    this.socket.addEventListener('open', (event) => {\n")
+	b.WriteString("    socket.addEventListener('open', (event) => {\n")
+	b.WriteString("      if (socket !== this.socket) return;\n")
 	b.WriteString("      this.status = 'open';\n")
+	b.WriteString("      this.reconnectCount = 0;\n")
+	b.WriteString("      this.startHeartbeat();\n")
+	b.WriteString("      this.flushSendBuffer();\n")
 	b.WriteString("      this.connectedAt = new Date();\n")
 	b.WriteString("      this.closedAt = undefined;\n")
 	b.WriteString("      for (const listener of this.openListeners) listener(event);\n")
+	b.WriteString("      if (this.reconnecting) {\n")
+	b.WriteString("        this.reconnecting = false;\n")
+	b.WriteString("        for (const listener of this.reconnectedListeners) listener();\n")
+	b.WriteString("      }\n")
 	b.WriteString("    });\n")
-	b.WriteString("    this.socket.addEventListener('close', (event) => {\n")
+	b.WriteString("    socket.addEventListener('close', (event) => {\n")
+	b.WriteString("      if (socket !== this.socket) return;\n")
 	b.WriteString("      this.status = 'closed';\n")
 	b.WriteString("      this.lastClose = event;\n")
 	b.WriteString("      this.closedAt = new Date();\n")
+	b.WriteString("      this.stopHeartbeat();\n")
+	if TSWebSocketJSONRPC {
+		b.WriteString("      this.rejectPendingCalls(new Error('WebSocket closed before JSON-RPC response'));\n")
+	}
 	b.WriteString("      for (const listener of this.closeListeners) listener(event);\n")
+	b.WriteString("      this.scheduleReconnect();\n")
+	b.WriteString("      if (this.reconnectTimer === undefined) this.dropSendBuffer();\n")
 	b.WriteString("    });\n")
-	b.WriteString("    this.socket.addEventListener('error', (event) => {\n")
+	b.WriteString("    socket.addEventListener('error', (event) => {\n")
+	b.WriteString("      if (socket !== this.socket) return;\n")
 	b.WriteString("      this.lastError = event;\n")
 	b.WriteString("      for (const listener of this.errorListeners) listener(event);\n")
 	b.WriteString("    });\n")
@@ -336,21 +661,162 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("    return this.readyState === WebSocket.OPEN;\n")
 	b.WriteString("  }\n\n")
 	b.WriteString("  /**\n")
+	b.WriteString("   * Delay before the next reconnect attempt, based on reconnectCount and reconnect options.\n")
+	b.WriteString("   * 根据 reconnectCount 与重连配置计算下一次重连前的等待时间。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  nextReconnectDelay(): number {\n")
+	b.WriteString("    return computeReconnectDelay(this.reconnectCount, this.reconnectOptions);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Wait until the socket is open. Rejects on error/close before open, timeout or abort.\n")
+	b.WriteString("   * 等待连接打开；若打开前发生 error/close、超时或被中止则 reject。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  waitOpen(options?: WebSocketWaitOpenOptions): Promise<void> {\n")
+	b.WriteString("    if (this.isOpen) return Promise.resolve();\n")
+	b.WriteString("    return new Promise<void>((resolve, reject) => {\n")
+	b.WriteString("      let timer: ReturnType<typeof setTimeout> | undefined;\n")
+	b.WriteString("      const signal = options?.signal;\n")
+	b.WriteString("      const cleanup = () => {\n")
+	b.WriteString("        offOpen();\n")
+	b.WriteString("        offError();\n")
+	b.WriteString("        offClose();\n")
+	b.WriteString("        if (timer !== undefined) clearTimeout(timer);\n")
+	b.WriteString("        signal?.removeEventListener('abort', onAbort);\n")
+	b.WriteString("      };\n")
+	b.WriteString("      const onAbort = () => {\n")
+	b.WriteString("        cleanup();\n")
+	b.WriteString("        reject(new Error('WebSocket waitOpen aborted'));\n")
+	b.WriteString("      };\n")
+	b.WriteString("      const offOpen = this.onOpen(() => {\n")
+	b.WriteString("        cleanup();\n")
+	b.WriteString("        resolve();\n")
+	b.WriteString("      });\n")
+	b.WriteString("      const offError = this.onError(() => {\n")
+	b.WriteString("        cleanup();\n")
+	b.WriteString("        reject(new Error('WebSocket error before open'));\n")
+	b.WriteString("      });\n")
+	b.WriteString("      const offClose = this.onClose((event) => {\n")
+	b.WriteString("        cleanup();\n")
+	b.WriteString("        reject(new Error(`WebSocket closed before open (code ${event.code})`));\n")
+	b.WriteString("      });\n")
+	b.WriteString("      if (signal) {\n")
+	b.WriteString("        if (signal.aborted) {\n")
+	b.WriteString("          onAbort();\n")
+	b.WriteString("          return;\n")
+	b.WriteString("        }\n")
+	b.WriteString("        signal.addEventListener('abort', onAbort, { once: true });\n")
+	b.WriteString("      }\n")
+	b.WriteString("      const timeoutMs = options?.timeoutMs;\n")
+	b.WriteString("      if (timeoutMs !== undefined && timeoutMs > 0) {\n")
+	b.WriteString("        timer = setTimeout(() => {\n")
+	b.WriteString("          cleanup();\n")
+	b.WriteString("          reject(new Error(`WebSocket did not open within ${timeoutMs}ms`));\n")
+	b.WriteString("        }, timeoutMs);\n")
+	b.WriteString("      }\n")
+	b.WriteString("    });\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
 	b.WriteString("   * Send one typed message.\n")
-	b.WriteString("   * 发送一条类型化消息。\n")
+	b.WriteString("   * With retryAfterReconnect or queueWhileConnecting enabled, messages sent while not open are buffered until the next open.\n")
+	b.WriteString("   * 发送一条类型化消息；启用 retryAfterReconnect 或 queueWhileConnecting 时，未打开期间发送的消息会缓冲到下次打开。\n")
 	b.WriteString("   */\n")
 	b.WriteString("  send(message: TSend): void {\n")
-	b.WriteString("    const data = this.serialize(message);\n")
-	b.WriteString("    this.socket.send(JSON.stringify(data));\n")
+	b.WriteString("    const data = this.encode(message);\n")
+	b.WriteString("    if (this.shouldQueueSend()) {\n")
+	b.WriteString("      this.sendBuffer.push({ data, queuedAt: Date.now() });\n")
+	b.WriteString("      const maxSize = this.sendBufferOptions?.maxSize;\n")
+	b.WriteString("      while (maxSize !== undefined && maxSize >= 0 && this.sendBuffer.length > maxSize) {\n")
+	b.WriteString("        this.sendBuffer.shift();\n")
+	b.WriteString("        this.messagesDropped += 1;\n")
+	b.WriteString("      }\n")
+	b.WriteString("      return;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    this.socket.send(data);\n")
+	b.WriteString("    this.messagesSent += 1;\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Number of messages waiting for the next open.\n")
+	b.WriteString("   * 等待下次打开后发送的消息数量。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  get bufferedCount(): number {\n")
+	b.WriteString("    return this.sendBuffer.length;\n")
+	b.WriteString("  }\n\n")
+	if TSWebSocketJSONRPC {
+		b.WriteString("  /**\n")
+		b.WriteString("   * Call a JSON-RPC 2.0 method and resolve with its result (rejects with JsonRpcError on error).\n")
+		b.WriteString("   * 调用 JSON-RPC 2.0 方法，按响应 id 匹配并 resolve 结果；出错时以 JsonRpcError reject。\n")
+		b.WriteString("   */\n")
+		b.WriteString("  call<TResult = unknown>(method: string, params?: unknown, options?: WebSocketRPCCallOptions): Promise<TResult> {\n")
+		b.WriteString("    if (!this.isOpen) return Promise.reject(new Error('WebSocket is not open'));\n")
+		b.WriteString("    const id = ++this.rpcSeq;\n")
+		b.WriteString("    return new Promise<TResult>((resolve, reject) => {\n")
+		b.WriteString("      const timer = setTimeout(() => {\n")
+		b.WriteString("        this.rpcPending.delete(id);\n")
+		b.WriteString("        reject(new Error(`JSON-RPC call ${method} timed out`));\n")
+		b.WriteString("      }, options?.timeoutMs ?? 30000);\n")
+		b.WriteString("      this.rpcPending.set(id, { resolve: resolve as (result: unknown) => void, reject, timer });\n")
+		b.WriteString("      const request: Record<string, unknown> = { jsonrpc: '2.0', id, method };\n")
+		b.WriteString("      if (params !== undefined) request.params = normalizeWsRequestJSON(params);\n")
+		b.WriteString("      this.socket.send(JSON.stringify(request));\n")
+		b.WriteString("      this.messagesSent += 1;\n")
+		b.WriteString("    });\n")
+		b.WriteString("  }\n\n")
+		b.WriteString("  private rejectPendingCalls(error: Error): void {\n")
+		b.WriteString("    for (const pending of this.rpcPending.values()) {\n")
+		b.WriteString("      clearTimeout(pending.timer);\n")
+		b.WriteString("      pending.reject(error);\n")
+		b.WriteString("    }\n")
+		b.WriteString("    this.rpcPending.clear();\n")
+		b.WriteString("  }\n\n")
+	}
+	b.WriteString("  /**\n")
+	b.WriteString("   * Send a ping carrying an ack id and resolve with the round-trip time in ms once the server echoes it.\n")
+	b.WriteString("   * The result is also stored in lastLatencyMs.\n")
+	b.WriteString("   * 发送带 ackId 的 ping，收到服务端回显后以往返毫秒数 resolve，并记录到 lastLatencyMs。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  measureLatency(options?: WebSocketLatencyOptions): Promise<number> {\n")
+	b.WriteString("    if (!this.isOpen) return Promise.reject(new Error('WebSocket is not open'));\n")
+	b.WriteString("    const ackId = `latency-${++this.latencySeq}`;\n")
+	b.WriteString("    const startedAt = nowMs();\n")
+	b.WriteString("    return new Promise<number>((resolve, reject) => {\n")
+	b.WriteString("      const timer = setTimeout(() => {\n")
+	b.WriteString("        this.latencyPending.delete(ackId);\n")
+	b.WriteString("        reject(new Error('WebSocket latency ping timed out'));\n")
+	b.WriteString("      }, options?.timeoutMs ?? 5000);\n")
+	b.WriteString("      this.latencyPending.set(ackId, (receivedAt) => {\n")
+	b.WriteString("        clearTimeout(timer);\n")
+	b.WriteString("        this.latencyPending.delete(ackId);\n")
+	b.WriteString("        const latency = receivedAt - startedAt;\n")
+	b.WriteString("        this.lastLatencyMs = latency;\n")
+	b.WriteString("        resolve(latency);\n")
+	b.WriteString("      });\n")
+	b.WriteString("      this.socket.send(JSON.stringify({ type: 'ping', ackId }));\n")
+	b.WriteString("    });\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Send one binary frame.\n")
+	b.WriteString("   * 发送一个二进制帧。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  sendBinary(data: ArrayBuffer | Uint8Array): void {\n")
+	b.WriteString("    this.socket.send(data);\n")
 	b.WriteString("    this.messagesSent += 1;\n")
 	b.WriteString("  }\n\n")
 	b.WriteString("  /**\n")
-	b.WriteString("   * Close the websocket connection.\n")
-	b.WriteString("   * 主动关闭 websocket 连接。\n")
+	b.WriteString("   * Close the websocket connection with an optional close code and reason, and stop reconnecting.\n")
+	b.WriteString("   * Browsers only accept 1000 (WebSocketCloseCode.Normal) or 3000-4999 here.\n")
+	b.WriteString("   * 主动关闭 websocket 连接（可选关闭码与原因），并停止自动重连；浏览器只允许 1000（WebSocketCloseCode.Normal）或 3000-4999。\n")
 	b.WriteString("   */\n")
-	b.WriteString("  close(): void {\n")
+	b.WriteString("  close(code?: number, reason?: string): void {\n")
+	b.WriteString("    this.manuallyClosed = true;\n")
+	b.WriteString("    this.reconnecting = false;\n")
+	b.WriteString("    this.stopHeartbeat();\n")
+	b.WriteString("    if (this.reconnectTimer !== undefined) {\n")
+	b.WriteString("      clearTimeout(this.reconnectTimer);\n")
+	b.WriteString("      this.reconnectTimer = undefined;\n")
+	b.WriteString("    }\n")
+	b.WriteString("    this.dropSendBuffer();\n")
 	b.WriteString("    this.status = 'closing';\n")
-	b.WriteString("    this.socket.close();\n")
+	b.WriteString("    this.socket.close(code, reason);\n")
 	b.WriteString("  }\n\n")
 	b.WriteString("  /**\n")
 	b.WriteString("   * Subscribe to all incoming messages.\n")
@@ -361,6 +827,14 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("    return () => this.messageListeners.delete(handler);\n")
 	b.WriteString("  }\n\n")
 	b.WriteString("  /**\n")
+	b.WriteString("   * Subscribe to incoming binary frames.\n")
+	b.WriteString("   * 订阅接收到的二进制帧。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  onBinary(handler: (data: Uint8Array) => void): () => void {\n")
+	b.WriteString("    this.binaryListeners.add(handler);\n")
+	b.WriteString("    return () => this.binaryListeners.delete(handler);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
 	b.WriteString("   * Subscribe to websocket open event.\n")
 	b.WriteString("   * 订阅 websocket 打开事件。\n")
 	b.WriteString("   */\n")
@@ -385,6 +859,22 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("    return () => this.errorListeners.delete(handler);\n")
 	b.WriteString("  }\n\n")
 	b.WriteString("  /**\n")
+	b.WriteString("   * Subscribe to scheduled reconnect attempts (1-based). Never fires when reconnect is disabled.\n")
+	b.WriteString("   * 订阅计划中的重连尝试（attempt 从 1 开始）；未启用重连时不会触发。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  onReconnecting(handler: (attempt: number) => void): () => void {\n")
+	b.WriteString("    this.reconnectingListeners.add(handler);\n")
+	b.WriteString("    return () => this.reconnectingListeners.delete(handler);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
+	b.WriteString("   * Subscribe to successful reconnects, fired after the open listeners of a reconnected socket.\n")
+	b.WriteString("   * 订阅重连成功事件，在重连后的 socket 触发 open 监听之后调用。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  onReconnected(handler: () => void): () => void {\n")
+	b.WriteString("    this.reconnectedListeners.add(handler);\n")
+	b.WriteString("    return () => this.reconnectedListeners.delete(handler);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  /**\n")
 	b.WriteString("   * Subscribe to messages by the `type` field.\n")
 	b.WriteString("   * 按消息的 `type` 字段进行订阅。\n")
 	b.WriteString("   */\n")
@@ -419,6 +909,74 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	b.WriteString("      handler(payload, message);\n")
 	b.WriteString("    });\n")
 	b.WriteString("  }\n\n")
+	b.WriteString("  private startHeartbeat(): void {\n")
+	b.WriteString("    this.stopHeartbeat();\n")
+	b.WriteString("    const options = this.heartbeatOptions;\n")
+	b.WriteString("    if (!options || !(options.intervalMs > 0)) return;\n")
+	b.WriteString("    this.heartbeatTimer = setInterval(() => {\n")
+	b.WriteString("      if (!this.isOpen) return;\n")
+	b.WriteString("      this.socket.send(JSON.stringify(options.message ?? { type: 'ping' }));\n")
+	b.WriteString("    }, options.intervalMs);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private stopHeartbeat(): void {\n")
+	b.WriteString("    if (this.heartbeatTimer === undefined) return;\n")
+	b.WriteString("    clearInterval(this.heartbeatTimer);\n")
+	b.WriteString("    this.heartbeatTimer = undefined;\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private encode(message: TSend): string | ArrayBuffer | ArrayBufferView {\n")
+	b.WriteString("    const value = this.serialize(message);\n")
+	b.WriteString("    if (this.sendKind === 'binary') return value as ArrayBuffer | ArrayBufferView;\n")
+	b.WriteString("    if (this.sendKind === 'text') return String(value);\n")
+	b.WriteString("    return JSON.stringify(value);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private shouldQueueSend(): boolean {\n")
+	b.WriteString("    if (this.isOpen || this.manuallyClosed) return false;\n")
+	b.WriteString("    return this.queueWhileConnecting || this.sendBufferOptions?.enabled === true;\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private dropSendBuffer(): void {\n")
+	b.WriteString("    this.messagesDropped += this.sendBuffer.length;\n")
+	b.WriteString("    this.sendBuffer.length = 0;\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private flushSendBuffer(): void {\n")
+	b.WriteString("    const ttlMs = this.sendBufferOptions?.ttlMs;\n")
+	b.WriteString("    const now = Date.now();\n")
+	b.WriteString("    while (this.sendBuffer.length > 0 && this.isOpen) {\n")
+	b.WriteString("      const item = this.sendBuffer.shift()!;\n")
+	b.WriteString("      if (ttlMs !== undefined && now - item.queuedAt > ttlMs) {\n")
+	b.WriteString("        this.messagesDropped += 1;\n")
+	b.WriteString("        continue;\n")
+	b.WriteString("      }\n")
+	b.WriteString("      this.socket.send(item.data);\n")
+	b.WriteString("      this.messagesSent += 1;\n")
+	b.WriteString("      this.messagesFlushed += 1;\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private scheduleReconnect(): void {\n")
+	b.WriteString("    const options = this.reconnectOptions;\n")
+	b.WriteString("    if (this.manuallyClosed || !options?.enabled) return;\n")
+	b.WriteString("    if (options.maxRetries !== undefined && this.reconnectCount >= options.maxRetries) return;\n")
+	b.WriteString("    const delay = this.nextReconnectDelay();\n")
+	b.WriteString("    this.reconnectCount += 1;\n")
+	b.WriteString("    this.reconnecting = true;\n")
+	b.WriteString("    this.reconnectTimer = setTimeout(() => {\n")
+	b.WriteString("      this.reconnectTimer = undefined;\n")
+	b.WriteString("      if (!this.manuallyClosed) this.connect();\n")
+	b.WriteString("    }, delay);\n")
+	b.WriteString("    for (const listener of this.reconnectingListeners) listener(this.reconnectCount);\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private sendAck(ackId: string): void {\n")
+	b.WriteString("    if (!this.isOpen) return;\n")
+	b.WriteString("    this.socket.send(JSON.stringify({ type: 'ack', ackId }));\n")
+	b.WriteString("  }\n\n")
+	b.WriteString("  private emitBinary(data: Uint8Array): void {\n")
+	b.WriteString("    for (const listener of this.binaryListeners) {\n")
+	b.WriteString("      try {\n")
+	b.WriteString("        listener(data);\n")
+	b.WriteString("      } catch {\n")
+	b.WriteString("        // ignore single listener errors and continue dispatch\n")
+	b.WriteString("      }\n")
+	b.WriteString("    }\n")
+	b.WriteString("  }\n\n")
 	b.WriteString("  private emitMessage(message: TReceive): void {\n")
 	b.WriteString("    for (const listener of this.messageListeners) {\n")
 	b.WriteString("      try {\n")
@@ -461,6 +1019,7 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 		b.WriteString("// 兜底：只有 interface 无法表达时才使用 type。\n")
 		b.WriteString("// =====================================================\n\n")
 	}
+	b.WriteString(renderNamedUnions(registry))
 	sortedDefs := append([]tsInterfaceDef(nil), registry.defs...)
 	sort.Slice(sortedDefs, func(i, j int) bool {
 		return sortedDefs[i].Name < sortedDefs[j].Name
@@ -471,6 +1030,7 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 		b.WriteString(def.Name)
 		b.WriteString("\n")
 		b.WriteString("// -----------------------------------------------------\n")
+		b.WriteString(renderTSInterfaceDoc(def.Doc))
 		b.WriteString("export interface ")
 		b.WriteString(def.Name)
 		b.WriteString(" {\n")
@@ -615,15 +1175,44 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 		b.WriteString(".NAME;\n")
 		b.WriteString("  public readonly endpointPath = ")
 		b.WriteString(className)
-		b.WriteString(".FULL_PATH;\n\n")
+		b.WriteString(".FULL_PATH;\n")
+		if m.StateMachine != nil {
+			writeWebSocketStateMachineMembers(&b, className, m.StateMachine)
+		}
+		b.WriteString("\n")
 		b.WriteString("  constructor(options: WebSocketConvertOptions<TSend, ")
 		b.WriteString(m.ServerType)
 		b.WriteString(">) {\n")
 		b.WriteString("    const url = ")
 		b.WriteString(className)
 		b.WriteString(".FULL_PATH;\n")
-		b.WriteString("    super(url, options);\n")
+		if kinds := wsKindDefaults(m); kinds != "" {
+			b.WriteString("    super(url, { ")
+			b.WriteString(kinds)
+			b.WriteString("...options });\n")
+		} else {
+			b.WriteString("    super(url, options);\n")
+		}
+		if m.StateMachine != nil {
+			b.WriteString("    this.onOpen(() => {\n")
+			b.WriteString("      this.protocolState = ")
+			b.WriteString(className)
+			b.WriteString(".STATE_MACHINE.initial;\n")
+			b.WriteString("    });\n")
+			b.WriteString("    for (const [type, next] of Object.entries(")
+			b.WriteString(className)
+			b.WriteString(".STATE_MACHINE.transitions)) {\n")
+			b.WriteString("      this.onType(type as ")
+			b.WriteString(messageTypeAlias)
+			b.WriteString(", () => {\n")
+			b.WriteString("        this.protocolState = next;\n")
+			b.WriteString("      });\n")
+			b.WriteString("    }\n")
+		}
 		b.WriteString("  }\n\n")
+		if m.StateMachine != nil {
+			writeWebSocketStateGuard(&b, className)
+		}
 		if len(m.ServerPayloadByType) > 0 {
 			b.WriteString("  onTypedMessage<TType extends ")
 			b.WriteString(messageTypeAlias)
@@ -772,6 +1361,40 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 			b.WriteString(", payload } as TSend);\n")
 			b.WriteString("  }\n\n")
 		}
+		for _, method := range m.RPCMethods {
+			paramsType := m.RPCParamsByMethod[method]
+			resultType := m.RPCResultByMethod[method]
+			b.WriteString("  /**\n")
+			b.WriteString("   * Call JSON-RPC method ")
+			b.WriteString(strconv.Quote(method))
+			b.WriteString(".\n")
+			b.WriteString("   * 调用 JSON-RPC 方法 ")
+			b.WriteString(strconv.Quote(method))
+			b.WriteString("。\n")
+			b.WriteString("   */\n")
+			b.WriteString("  rpc")
+			b.WriteString(wsMessageTypeMethodSuffix(method))
+			b.WriteString("(")
+			if paramsType != "void" {
+				b.WriteString("params: ")
+				b.WriteString(paramsType)
+				b.WriteString(", ")
+			}
+			b.WriteString("options?: WebSocketRPCCallOptions): Promise<")
+			b.WriteString(resultType)
+			b.WriteString("> {\n")
+			b.WriteString("    return this.call<")
+			b.WriteString(resultType)
+			b.WriteString(">(")
+			b.WriteString(strconv.Quote(method))
+			if paramsType != "void" {
+				b.WriteString(", params")
+			} else {
+				b.WriteString(", undefined")
+			}
+			b.WriteString(", options);\n")
+			b.WriteString("  }\n\n")
+		}
 		b.WriteString("}\n")
 		b.WriteString("export function create")
 		b.WriteString(className)
@@ -793,6 +1416,44 @@ func renderWebSocketTS(basePath string, groupPath string, registry *tsInterfaceR
 	return finalizeTypeScriptCode(b.String()), nil
 }
 
+// wsKindDefaults renders the non-JSON sendKind/receiveKind defaults passed to the base client.
+func wsKindDefaults(m wsFuncMeta) string {
+	out := ""
+	if m.SendKind != "json" {
+		out += "sendKind: '" + m.SendKind + "', "
+	}
+	if m.ReceiveKind != "json" {
+		out += "receiveKind: '" + m.ReceiveKind + "', "
+	}
+	return out
+}
+
+// wsMessageKind maps a websocket TSKind hint to the TS WebSocketMessageKind; empty means JSON.
+func wsMessageKind(kind TSKind) (string, error) {
+	switch kind {
+	case "", TSKindJSON:
+		return "json", nil
+	case TSKindText:
+		return "text", nil
+	case TSKindBytes:
+		return "binary", nil
+	default:
+		return "", fmt.Errorf("unsupported websocket message kind %q (use json, text or bytes)", kind)
+	}
+}
+
+// wsMessageTSType returns the TS type of messages with the given kind, building JSON types from t.
+func wsMessageTSType(kind string, t reflect.Type, registry *tsInterfaceRegistry) (string, error) {
+	switch kind {
+	case "text":
+		return "string", nil
+	case "binary":
+		return "Uint8Array", nil
+	}
+	tsType, _, err := tsTypeFromType(t, registry)
+	return tsType, err
+}
+
 func normalizeMessageTypes(types []string) []string {
 	seen := map[string]struct{}{}
 	out := make([]string, 0, len(types))
@@ -889,3 +1550,64 @@ func sortMessageTypesByDeclaredOrder(declared []string, payloadMap map[string]st
 	out = append(out, rest...)
 	return out
 }
+
+func writeWebSocketStateMachineMembers(b *strings.Builder, className string, sm *WebSocketStateMachine) {
+	quote := func(v string) string { return "'" + strings.ReplaceAll(v, "'", "\\'") + "'" }
+	b.WriteString("  static readonly STATE_MACHINE: WebSocketStateMachine = {\n")
+	b.WriteString("    initial: ")
+	b.WriteString(quote(sm.Initial))
+	b.WriteString(",\n")
+	b.WriteString("    allowed: {\n")
+	states := make([]string, 0, len(sm.Allowed))
+	for state := range sm.Allowed {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		types := make([]string, 0, len(sm.Allowed[state]))
+		for _, t := range sm.Allowed[state] {
+			types = append(types, quote(t))
+		}
+		b.WriteString("      ")
+		b.WriteString(quote(state))
+		b.WriteString(": [")
+		b.WriteString(strings.Join(types, ", "))
+		b.WriteString("],\n")
+	}
+	b.WriteString("    },\n")
+	b.WriteString("    transitions: {\n")
+	received := make([]string, 0, len(sm.Transitions))
+	for t := range sm.Transitions {
+		received = append(received, t)
+	}
+	sort.Strings(received)
+	for _, t := range received {
+		b.WriteString("      ")
+		b.WriteString(quote(t))
+		b.WriteString(": ")
+		b.WriteString(quote(sm.Transitions[t]))
+		b.WriteString(",\n")
+	}
+	b.WriteString("    },\n")
+	b.WriteString("  };\n")
+	b.WriteString("  public protocolState: string = ")
+	b.WriteString(className)
+	b.WriteString(".STATE_MACHINE.initial;\n")
+}
+
+func writeWebSocketStateGuard(b *strings.Builder, className string) {
+	b.WriteString("  /**\n")
+	b.WriteString("   * Send one typed message, throwing when its type is not allowed in the current protocol state.\n")
+	b.WriteString("   * 发送一条类型化消息；若其类型在当前协议状态下不被允许则抛出异常。\n")
+	b.WriteString("   */\n")
+	b.WriteString("  send(message: TSend): void {\n")
+	b.WriteString("    const type = isPlainObject(message) ? message.type : undefined;\n")
+	b.WriteString("    const allowed = ")
+	b.WriteString(className)
+	b.WriteString(".STATE_MACHINE.allowed[this.protocolState];\n")
+	b.WriteString("    if (typeof type === 'string' && allowed !== undefined && !allowed.includes(type)) {\n")
+	b.WriteString("      throw new Error(`WebSocket message \"${type}\" is not allowed in state \"${this.protocolState}\"`);\n")
+	b.WriteString("    }\n")
+	b.WriteString("    super.send(message);\n")
+	b.WriteString("  }\n\n")
+}
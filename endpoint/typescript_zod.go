@@ -0,0 +1,182 @@
+package endpoint
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// renderZodSchemas renders `export const XSchema = z.object({...})` for every registered interface.
+// Defs are walked in registration order, which registers nested types before their parents,
+// so references are direct; only recursive (forward) references fall back to z.lazy.
+func renderZodSchemas(registry *tsInterfaceRegistry) (string, error) {
+	if len(registry.defs) == 0 {
+		return "", nil
+	}
+	var b strings.Builder
+	writeTSMarker(&b, "Zod Schemas")
+	emitted := map[string]bool{}
+	for _, def := range registry.defs {
+		if def.Type == nil {
+			continue
+		}
+		z := &zodWriter{registry: registry, emitted: emitted}
+		expr, err := z.objectExpr(def.Type, "")
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("/** Zod schema of ")
+		b.WriteString(def.Name)
+		b.WriteString(". ")
+		b.WriteString(def.Name)
+		b.WriteString(" 的 Zod schema。 */\n")
+		b.WriteString("export const ")
+		b.WriteString(def.Name)
+		b.WriteString("Schema")
+		if z.lazy {
+			// Recursive schemas need an explicit annotation for TypeScript to infer the const.
+			b.WriteString(": z.ZodType<")
+			b.WriteString(def.Name)
+			b.WriteString(">")
+		}
+		b.WriteString(" = ")
+		b.WriteString(expr)
+		b.WriteString(";\n\n")
+		emitted[def.Name] = true
+	}
+	writeTSMarkerEnd(&b, "Zod Schemas")
+	return b.String(), nil
+}
+
+type zodWriter struct {
+	registry *tsInterfaceRegistry
+	emitted  map[string]bool
+	lazy     bool
+}
+
+func (z *zodWriter) objectExpr(t reflect.Type, indent string) (string, error) {
+	lines := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name, optional, ok := jsonFieldMeta(f)
+		if !ok {
+			continue
+		}
+		expr, err := z.exprFromType(f.Type, indent+"  ")
+		if err != nil {
+			return "", err
+		}
+		if unionValues, ok, err := tsUnionValuesFromField(f); err != nil {
+			return "", err
+		} else if ok {
+			expr = zodUnionExpr(unionValues)
+		}
+		if optional {
+			expr += ".optional()"
+		}
+		propName, err := tsPropName(name)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, indent+"  "+propName+": "+expr+",\n")
+	}
+	if len(lines) == 0 {
+		return "z.object({})", nil
+	}
+	return "z.object({\n" + strings.Join(lines, "") + indent + "})", nil
+}
+
+func (z *zodWriter) exprFromType(t reflect.Type, indent string) (string, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.PkgPath() == "time" && t.Name() == "Time" {
+		return "z.string()", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "FormData" {
+		return "z.instanceof(FormData)", nil
+	}
+	if t.PkgPath() == "mime/multipart" && t.Name() == "FileHeader" {
+		return "z.instanceof(Blob)", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "RawBytes" {
+		return "z.instanceof(Uint8Array)", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamResponse" {
+		return "z.instanceof(Blob)", nil
+	}
+	if t.PkgPath() == "github.com/RapboyGao/nuxtGin/endpoint" && t.Name() == "StreamRequest" {
+		return "z.unknown()", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "z.boolean()", nil
+	case reflect.String:
+		return "z.string()", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Float32, reflect.Float64:
+		return "z.number()", nil
+	case reflect.Int64, reflect.Uint64:
+		switch TSInt64MappingMode {
+		case TSInt64ModeString:
+			return "z.string()", nil
+		case TSInt64ModeBigInt:
+			return "z.bigint()", nil
+		}
+		return "z.number()", nil
+	case reflect.Struct:
+		if t.Name() != "" && !z.registry.inline[t] {
+			name, err := z.registry.ensureNamedStructType(t)
+			if err != nil {
+				return "", err
+			}
+			if z.emitted[name] {
+				return name + "Schema", nil
+			}
+			z.lazy = true
+			return "z.lazy(() => " + name + "Schema)", nil
+		}
+		return z.objectExpr(t, indent)
+	case reflect.Map:
+		if !isJSONMapKey(t.Key()) {
+			return "z.record(z.string(), z.unknown())", nil
+		}
+		elem, err := z.exprFromType(t.Elem(), indent)
+		if err != nil {
+			return "", err
+		}
+		return "z.record(z.string(), " + elem + ")", nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "z.string()", nil
+		}
+		elem, err := z.exprFromType(t.Elem(), indent)
+		if err != nil {
+			return "", err
+		}
+		return "z.array(" + elem + ")", nil
+	default:
+		return "z.unknown()", nil
+	}
+}
+
+func zodUnionExpr(values []tsUnionLiteral) string {
+	literals := make([]string, 0, len(values))
+	for _, v := range values {
+		value := v.Value
+		if v.Type == "string" {
+			value = "'" + strings.ReplaceAll(v.Value, "'", "\\'") + "'"
+		}
+		literals = append(literals, fmt.Sprintf("z.literal(%s)", value))
+	}
+	if len(literals) == 1 {
+		return literals[0]
+	}
+	return "z.union([" + strings.Join(literals, ", ") + "])"
+}
@@ -0,0 +1,115 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// JSON-RPC 2.0 error codes used by the websocket RPC layer.
+// WebSocket RPC 层使用的 JSON-RPC 2.0 错误码。
+const (
+	WebSocketRPCInvalidParams  = -32602
+	WebSocketRPCMethodNotFound = -32601
+	WebSocketRPCServerError    = -32000
+)
+
+// WebSocketRPCError is a JSON-RPC 2.0 error object. Handlers may return it to control the code.
+// WebSocketRPCError 是 JSON-RPC 2.0 错误对象；处理器可返回它以指定错误码。
+type WebSocketRPCError struct {
+	Code    int    `json:"code" tsdoc:"错误码 / Error code"`
+	Message string `json:"message" tsdoc:"错误描述 / Error message"`
+	Data    any    `json:"data,omitempty" tsdoc:"附加数据 / Extra data"`
+}
+
+func (e *WebSocketRPCError) Error() string {
+	return e.Message
+}
+
+type wsRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type wsRPCResponse struct {
+	JSONRPC string             `json:"jsonrpc"`
+	ID      json.RawMessage    `json:"id"`
+	Result  json.RawMessage    `json:"result,omitempty"`
+	Error   *WebSocketRPCError `json:"error,omitempty"`
+}
+
+// parseRPCRequest reports whether data is a JSON-RPC 2.0 request frame.
+func parseRPCRequest(data []byte) (wsRPCRequest, bool) {
+	var req wsRPCRequest
+	if !bytes.Contains(data, []byte(`"jsonrpc"`)) {
+		return req, false
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return req, false
+	}
+	return req, req.JSONRPC == "2.0" && req.Method != ""
+}
+
+// handleRPCRequest runs the registered method and replies with a result or error object.
+// Notifications (requests without id) get no reply.
+func (s *WebSocketEndpoint) handleRPCRequest(req wsRPCRequest, ctx *WebSocketContext) error {
+	resp := wsRPCResponse{JSONRPC: "2.0", ID: req.ID}
+	handler := s.RPCHandlers[req.Method]
+	if handler == nil {
+		resp.Error = &WebSocketRPCError{Code: WebSocketRPCMethodNotFound, Message: "method not found: " + req.Method}
+	} else {
+		result, err := handler(req.Params, ctx)
+		if err != nil {
+			var rpcErr *WebSocketRPCError
+			if !errors.As(err, &rpcErr) {
+				rpcErr = &WebSocketRPCError{Code: WebSocketRPCServerError, Message: err.Error()}
+			}
+			resp.Error = rpcErr
+		} else if data, err := json.Marshal(result); err != nil {
+			resp.Error = &WebSocketRPCError{Code: WebSocketRPCServerError, Message: err.Error()}
+		} else {
+			resp.Result = data
+		}
+	}
+	if len(req.ID) == 0 || string(req.ID) == "null" {
+		return nil
+	}
+	return ctx.Send(resp)
+}
+
+// RegisterWebSocketRPCMethod registers a typed JSON-RPC 2.0 method on a websocket endpoint.
+// Requests shaped as {"jsonrpc":"2.0","id":...,"method":...,"params":...} are answered with
+// {"jsonrpc":"2.0","id":...,"result":...} or an error object.
+// RegisterWebSocketRPCMethod 在 websocket 端点上注册强类型的 JSON-RPC 2.0 方法。
+func RegisterWebSocketRPCMethod[Params any, Result any](
+	endpoint *WebSocketEndpoint,
+	method string,
+	handler func(params Params, ctx *WebSocketContext) (Result, error),
+) {
+	if endpoint == nil {
+		return
+	}
+	if endpoint.RPCHandlers == nil {
+		endpoint.RPCHandlers = map[string]func(params json.RawMessage, ctx *WebSocketContext) (any, error){}
+	}
+	if endpoint.RPCParamTypes == nil {
+		endpoint.RPCParamTypes = map[string]reflect.Type{}
+	}
+	if endpoint.RPCResultTypes == nil {
+		endpoint.RPCResultTypes = map[string]reflect.Type{}
+	}
+	endpoint.RPCParamTypes[method] = typeOf[Params]()
+	endpoint.RPCResultTypes[method] = typeOf[Result]()
+	endpoint.RPCHandlers[method] = func(params json.RawMessage, ctx *WebSocketContext) (any, error) {
+		var typed Params
+		if len(params) > 0 {
+			if err := json.Unmarshal(params, &typed); err != nil {
+				return nil, &WebSocketRPCError{Code: WebSocketRPCInvalidParams, Message: err.Error()}
+			}
+		}
+		return handler(typed, ctx)
+	}
+}
@@ -0,0 +1,1065 @@
+package endpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type wsNotice struct {
+	Text string `json:"text"`
+}
+
+// newTestWebSocketServer mounts ws on an httptest server and returns its ws:// URL.
+func newTestWebSocketServer(t *testing.T, ws *WebSocketEndpoint) string {
+	t.Helper()
+	oldMode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(func() {
+		gin.SetMode(oldMode)
+	})
+	engine := gin.New()
+	engine.GET(ws.Path, ws.GinHandler())
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + ws.Path
+}
+
+func dialTestWebSocket(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial %s failed: %v", url, err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// TestWebSocketEndpoint_PublishWhere
+// 这个测试验证按元数据条件广播：
+// 1) OnConnect 中通过 ctx.SetMeta 写入的连接元数据可以被 PublishWhere 的条件函数读取。
+// 2) 只有元数据匹配的客户端收到消息，不匹配的客户端收不到。
+// 3) 条件函数为 nil 时返回错误而不是广播给所有人。
+func TestWebSocketEndpoint_PublishWhere(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "notify"
+	ws.Path = "/ws/notify"
+	connected := make(chan struct{}, 3)
+	ws.OnConnect = func(ctx *WebSocketContext) error {
+		if err := ctx.SetMeta("role", ctx.Request.URL.Query().Get("role")); err != nil {
+			return err
+		}
+		if role, ok := ctx.GetMeta("role"); !ok || role == "" {
+			t.Errorf("expected role metadata to be readable, got %v", role)
+		}
+		connected <- struct{}{}
+		return nil
+	}
+	url := newTestWebSocketServer(t, ws)
+
+	admin1 := dialTestWebSocket(t, url+"?role=admin")
+	admin2 := dialTestWebSocket(t, url+"?role=admin")
+	user := dialTestWebSocket(t, url+"?role=user")
+	for i := 0; i < 3; i++ {
+		select {
+		case <-connected:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for websocket clients to connect")
+		}
+	}
+
+	err := ws.PublishWhere(func(meta map[string]any) bool {
+		return meta["role"] == "admin"
+	}, wsNotice{Text: "admins only"})
+	if err != nil {
+		t.Fatalf("PublishWhere returned error: %v", err)
+	}
+
+	for _, conn := range []*websocket.Conn{admin1, admin2} {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var got wsNotice
+		if err := conn.ReadJSON(&got); err != nil {
+			t.Fatalf("expected admin client to receive message: %v", err)
+		}
+		if got.Text != "admins only" {
+			t.Fatalf("unexpected message: %+v", got)
+		}
+	}
+
+	_ = user.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	var unexpected wsNotice
+	if err := user.ReadJSON(&unexpected); err == nil {
+		t.Fatalf("expected non-matching client not to receive message, got %+v", unexpected)
+	}
+
+	if err := ws.PublishWhere(nil, wsNotice{Text: "everyone"}); err == nil {
+		t.Fatalf("expected nil predicate to return an error")
+	}
+}
+
+// TestWebSocketEndpoint_Heartbeat
+// 这个测试验证服务端心跳：
+// 1) 设置 PingInterval 后服务端按间隔发送 ping 帧，正常回复 pong 的客户端保持连接。
+// 2) 不回复 pong 的客户端在 PongTimeout 内被服务端断开，并触发 OnDisconnect（ping 协程随读循环一起退出）。
+// 3) 客户端发送的 {type:'ping'} 心跳消息在未注册处理器时被忽略，不会导致断开。
+// 4) 自定义的 HeartbeatMessageType（如 keepalive）同样被忽略。
+func TestWebSocketEndpoint_Heartbeat(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "heartbeat"
+	ws.Path = "/ws/heartbeat"
+	ws.PingInterval = 30 * time.Millisecond
+	ws.PongTimeout = 60 * time.Millisecond
+	ws.HeartbeatMessageType = "keepalive"
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	disconnected := make(chan string, 2)
+	ws.OnDisconnect = func(ctx *WebSocketContext, _ error) {
+		disconnected <- ctx.Request.URL.Query().Get("name")
+	}
+	url := newTestWebSocketServer(t, ws)
+
+	var pings atomic.Int32
+	alive := dialTestWebSocket(t, url+"?name=alive")
+	alive.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return alive.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	received := make(chan wsNotice, 1)
+	go func() {
+		for {
+			var msg wsNotice
+			if err := alive.ReadJSON(&msg); err != nil {
+				return
+			}
+			received <- msg
+		}
+	}()
+
+	silent := dialTestWebSocket(t, url+"?name=silent")
+	silent.SetPingHandler(func(string) error { return nil })
+	go func() {
+		for {
+			if _, _, err := silent.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case name := <-disconnected:
+		if name != "silent" {
+			t.Fatalf("expected silent client to be disconnected first, got %q", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected server to close connection without pong")
+	}
+
+	if err := alive.WriteJSON(WebSocketMessage{Type: WebSocketHeartbeatMessageType}); err != nil {
+		t.Fatalf("write heartbeat failed: %v", err)
+	}
+	if err := alive.WriteJSON(WebSocketMessage{Type: "keepalive"}); err != nil {
+		t.Fatalf("write custom heartbeat failed: %v", err)
+	}
+	if err := alive.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "still here"}}); err != nil {
+		t.Fatalf("write echo failed: %v", err)
+	}
+	select {
+	case msg := <-received:
+		if msg.Text != "still here" {
+			t.Fatalf("unexpected echo: %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected client answering pongs to stay connected after heartbeat message")
+	}
+	if pings.Load() < 2 {
+		t.Fatalf("expected periodic pings, got %d", pings.Load())
+	}
+	select {
+	case name := <-disconnected:
+		t.Fatalf("unexpected disconnect of %q", name)
+	default:
+	}
+}
+
+// TestBroadcastWebSocketJSON_ConcurrentWriters
+// 这个测试验证全局广播函数的并发写安全（建议配合 -race 运行）：
+// 1) WebSocketClientsByPath 保存的是带写锁的客户端，而不是裸 *websocket.Conn。
+// 2) 多个 goroutine 同时调用 BroadcastWebSocketJSON、SendWebSocketJSON 与 Publish 时，帧不会交错损坏。
+// 3) 客户端按 JSON 完整收到全部消息。
+func TestBroadcastWebSocketJSON_ConcurrentWriters(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "fanout"
+	ws.Path = "/ws/fanout"
+	ws.SetFullPath("/ws/fanout")
+	connected := make(chan string, 1)
+	ws.OnConnect = func(ctx *WebSocketContext) error {
+		connected <- ctx.ID
+		return nil
+	}
+	url := newTestWebSocketServer(t, ws)
+	conn := dialTestWebSocket(t, url)
+	var clientID string
+	select {
+	case clientID = <-connected:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for websocket client to connect")
+	}
+	if snapshot := SnapshotWebSocketClients("/ws/fanout"); snapshot[clientID] == nil || snapshot[clientID].ID() != clientID {
+		t.Fatalf("expected client %s in the global registry, got %v", clientID, snapshot)
+	}
+
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				text := fmt.Sprintf("w%d-%d", w, i)
+				var err error
+				switch w % 3 {
+				case 0:
+					err = BroadcastWebSocketJSON("/ws/fanout", wsNotice{Text: text})
+				case 1:
+					err = SendWebSocketJSON("/ws/fanout", clientID, wsNotice{Text: text})
+				default:
+					err = ws.Publish(wsNotice{Text: text})
+				}
+				if err != nil {
+					t.Errorf("write %s failed: %v", text, err)
+					return
+				}
+			}
+		}(w)
+	}
+
+	seen := map[string]bool{}
+	for len(seen) < writers*perWriter {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var got wsNotice
+		if err := conn.ReadJSON(&got); err != nil {
+			t.Fatalf("read after %d messages failed: %v", len(seen), err)
+		}
+		seen[got.Text] = true
+	}
+	wg.Wait()
+}
+
+// TestWebSocketEndpoint_MaxConnections
+// 这个测试验证连接数上限：
+// 1) 已有 MaxConnections 个连接时，第 N+1 个连接在升级后以 1013 关闭，并调用 OnRejected，不会调用 OnConnect。
+// 2) 被拒绝的连接不会进入 hub，ConnectedCount 保持为上限。
+// 3) 已有连接断开后，新连接可以再次成功建立。
+func TestWebSocketEndpoint_MaxConnections(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "limited"
+	ws.Path = "/ws/limited"
+	ws.MaxConnections = 2
+	connected := make(chan struct{}, 4)
+	disconnected := make(chan struct{}, 4)
+	rejected := make(chan string, 1)
+	ws.OnConnect = func(_ *WebSocketContext) error {
+		connected <- struct{}{}
+		return nil
+	}
+	ws.OnDisconnect = func(_ *WebSocketContext, _ error) {
+		disconnected <- struct{}{}
+	}
+	ws.OnRejected = func(ctx *gin.Context) {
+		rejected <- ctx.Request.URL.Query().Get("name")
+	}
+	url := newTestWebSocketServer(t, ws)
+	waitFor := func(ch chan struct{}, what string) {
+		t.Helper()
+		select {
+		case <-ch:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+
+	first := dialTestWebSocket(t, url+"?name=first")
+	dialTestWebSocket(t, url+"?name=second")
+	waitFor(connected, "first connection")
+	waitFor(connected, "second connection")
+
+	extra := dialTestWebSocket(t, url+"?name=extra")
+	_ = extra.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := extra.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseTryAgainLater) {
+		t.Fatalf("expected extra connection to be closed with 1013, got %v", err)
+	}
+	select {
+	case name := <-rejected:
+		if name != "extra" {
+			t.Fatalf("expected OnRejected for extra connection, got %q", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected OnRejected to be called")
+	}
+	if count := ws.ConnectedCount(); count != 2 {
+		t.Fatalf("expected rejected connection not to be counted, got %d", count)
+	}
+	select {
+	case <-connected:
+		t.Fatalf("expected OnConnect not to run for a rejected connection")
+	default:
+	}
+
+	_ = first.Close()
+	waitFor(disconnected, "first connection to close")
+	dialTestWebSocket(t, url+"?name=third")
+	waitFor(connected, "connection after a slot was freed")
+}
+
+// TestWebSocketEndpoint_Compression
+// 这个测试验证 permessage-deflate 压缩配置：
+// 1) EnableCompression 开启后，握手响应包含协商的 permessage-deflate 扩展头，压缩后的消息可正常收发。
+// 2) 未开启时握手响应不包含该扩展头。
+func TestWebSocketEndpoint_Compression(t *testing.T) {
+	newEndpoint := func(enabled bool) *WebSocketEndpoint {
+		ws := NewWebSocketEndpoint()
+		ws.Name = "compressed"
+		ws.Path = "/ws/compressed"
+		ws.EnableCompression = enabled
+		ws.CompressionLevel = 6
+		RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+			return payload, nil
+		})
+		return ws
+	}
+	dialer := websocket.Dialer{EnableCompression: true, HandshakeTimeout: 2 * time.Second}
+
+	conn, resp, err := dialer.Dial(newTestWebSocketServer(t, newEndpoint(true)), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if ext := resp.Header.Get("Sec-WebSocket-Extensions"); !strings.Contains(ext, "permessage-deflate") {
+		t.Fatalf("expected negotiated permessage-deflate extension, got %q", ext)
+	}
+	text := strings.Repeat("compress me ", 200)
+	if err := conn.WriteJSON(map[string]any{"type": "echo", "payload": wsNotice{Text: text}}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got wsNotice
+	if err := conn.ReadJSON(&got); err != nil || got.Text != text {
+		t.Fatalf("expected compressed echo, got %q (%v)", got.Text, err)
+	}
+
+	plain, resp, err := dialer.Dial(newTestWebSocketServer(t, newEndpoint(false)), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer plain.Close()
+	if ext := resp.Header.Get("Sec-WebSocket-Extensions"); ext != "" {
+		t.Fatalf("expected no extension without EnableCompression, got %q", ext)
+	}
+}
+
+// TestWebSocketEndpoint_ContextClose
+// 这个测试验证服务端主动断开：
+// 1) handler 内调用 ctx.Close 后，客户端收到指定的关闭码与原因。
+// 2) OnDisconnect 仍会被调用，且错误为 nil。
+// 3) 断开后客户端从 hub 中移除。
+func TestWebSocketEndpoint_ContextClose(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "logout"
+	ws.Path = "/ws/logout"
+	disconnected := make(chan error, 1)
+	ws.OnDisconnect = func(_ *WebSocketContext, err error) {
+		disconnected <- err
+	}
+	RegisterWebSocketTypedHandler(ws, "logout", func(_ wsNotice, ctx *WebSocketContext) (any, error) {
+		return nil, ctx.Close(4001, "logged out")
+	})
+	conn := dialTestWebSocket(t, newTestWebSocketServer(t, ws))
+
+	if err := conn.WriteJSON(map[string]any{"type": "logout", "payload": wsNotice{}}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != 4001 || closeErr.Text != "logged out" {
+		t.Fatalf("expected close 4001 \"logged out\", got %v", err)
+	}
+	select {
+	case err := <-disconnected:
+		if err != nil {
+			t.Fatalf("expected OnDisconnect with nil error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected OnDisconnect to be called")
+	}
+	if count := ws.ConnectedCount(); count != 0 {
+		t.Fatalf("expected client to be removed, got %d", count)
+	}
+}
+
+// TestWebSocketEndpoint_OnMessageError
+// 这个测试验证消息解码错误回调：
+// 1) 无法解码的文本帧会以原始数据和解码错误调用 OnMessageError。
+// 2) 回调返回 false 时跳过该帧，随后的正常消息仍会被处理并回复。
+// 3) 回调返回 true 时连接被关闭，OnDisconnect 收到解码错误。
+func TestWebSocketEndpoint_OnMessageError(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "lenient"
+	ws.Path = "/ws/lenient"
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	fatal := false
+	rawFrames := make(chan string, 2)
+	ws.OnMessageError = func(_ *WebSocketContext, raw []byte, err error) bool {
+		if err == nil {
+			t.Errorf("expected decode error")
+		}
+		rawFrames <- string(raw)
+		return fatal
+	}
+	disconnected := make(chan error, 1)
+	ws.OnDisconnect = func(_ *WebSocketContext, err error) {
+		disconnected <- err
+	}
+	url := newTestWebSocketServer(t, ws)
+
+	conn := dialTestWebSocket(t, url)
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{not json")); err != nil {
+		t.Fatalf("write bad frame failed: %v", err)
+	}
+	if err := conn.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "ok"}}); err != nil {
+		t.Fatalf("write echo failed: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	var got wsNotice
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("expected connection to survive bad frame: %v", err)
+	}
+	if got.Text != "ok" {
+		t.Fatalf("unexpected echo: %#v", got)
+	}
+	if raw := <-rawFrames; raw != "{not json" {
+		t.Fatalf("unexpected raw frame: %q", raw)
+	}
+	_ = conn.Close()
+	<-disconnected
+
+	fatal = true
+	conn = dialTestWebSocket(t, url)
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("{not json")); err != nil {
+		t.Fatalf("write bad frame failed: %v", err)
+	}
+	select {
+	case err := <-disconnected:
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected decode error on disconnect, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected fatal decode error to close the connection")
+	}
+}
+
+// TestWebSocketEndpoint_ReadTimeout
+// 这个测试验证空闲读超时：
+// 1) 设置 ReadTimeout 后，一直不发消息的客户端在超时后被断开并触发 OnDisconnect。
+// 2) 持续发送消息的客户端每次读取都会刷新截止时间，总时长超过 ReadTimeout 也不会被断开。
+// 3) 同时启用心跳时，等待时间不短于 PingInterval + PongTimeout。
+func TestWebSocketEndpoint_ReadTimeout(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "idle"
+	ws.Path = "/ws/idle"
+	ws.ReadTimeout = 80 * time.Millisecond
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	disconnected := make(chan string, 2)
+	ws.OnDisconnect = func(ctx *WebSocketContext, _ error) {
+		disconnected <- ctx.Request.URL.Query().Get("name")
+	}
+	url := newTestWebSocketServer(t, ws)
+
+	dialTestWebSocket(t, url+"?name=idle")
+	active := dialTestWebSocket(t, url+"?name=active")
+	deadline := time.Now().Add(300 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if err := active.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "tick"}}); err != nil {
+			t.Fatalf("write echo failed: %v", err)
+		}
+		_ = active.SetReadDeadline(time.Now().Add(time.Second))
+		var got wsNotice
+		if err := active.ReadJSON(&got); err != nil {
+			t.Fatalf("expected active client to stay connected: %v", err)
+		}
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	select {
+	case name := <-disconnected:
+		if name != "idle" {
+			t.Fatalf("expected idle client to be disconnected, got %q", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected idle client to be disconnected after ReadTimeout")
+	}
+	select {
+	case name := <-disconnected:
+		t.Fatalf("expected active client to stay connected, %q was disconnected", name)
+	default:
+	}
+
+	ws.PingInterval = 100 * time.Millisecond
+	ws.PongTimeout = 50 * time.Millisecond
+	if got := ws.readWait(); got != 150*time.Millisecond {
+		t.Fatalf("expected heartbeat wait to cover ping + pong timeout, got %v", got)
+	}
+	ws.ReadTimeout = time.Second
+	if got := ws.readWait(); got != time.Second {
+		t.Fatalf("expected longer ReadTimeout to win, got %v", got)
+	}
+}
+
+func readTestNotice(t *testing.T, conn *websocket.Conn) wsNotice {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got wsNotice
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("read websocket message failed: %v", err)
+	}
+	return got
+}
+
+// TestWebSocketContext_Rooms
+// 这个测试验证房间管理：
+// 1) JoinRoom 后 RoomMembers 能列出成员，PublishToRoom 只投递给该房间的客户端，两个房间互不影响。
+// 2) LeaveRoom 后不再收到该房间的消息。
+// 3) 客户端断开后自动移出房间，房间为空时被清理。
+func TestWebSocketContext_Rooms(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "rooms"
+	ws.Path = "/ws/rooms"
+	connected := make(chan string, 3)
+	ws.OnConnect = func(ctx *WebSocketContext) error {
+		if err := ctx.JoinRoom(ctx.Request.URL.Query().Get("room")); err != nil {
+			return err
+		}
+		connected <- ctx.ID
+		return nil
+	}
+	RegisterWebSocketTypedHandler(ws, "leave", func(payload wsNotice, ctx *WebSocketContext) (any, error) {
+		if err := ctx.LeaveRoom(payload.Text); err != nil {
+			return nil, err
+		}
+		return wsNotice{Text: "left"}, nil
+	})
+	url := newTestWebSocketServer(t, ws)
+
+	red1 := dialTestWebSocket(t, url+"?room=red")
+	red2 := dialTestWebSocket(t, url+"?room=red")
+	blue := dialTestWebSocket(t, url+"?room=blue")
+	for i := 0; i < 3; i++ {
+		select {
+		case <-connected:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for websocket clients to connect")
+		}
+	}
+	if got := len(ws.RoomMembers("red")); got != 2 {
+		t.Fatalf("expected 2 members in red, got %d", got)
+	}
+	if got := len(ws.RoomMembers("blue")); got != 1 {
+		t.Fatalf("expected 1 member in blue, got %d", got)
+	}
+
+	if err := ws.PublishToRoom("red", wsNotice{Text: "red news"}); err != nil {
+		t.Fatalf("PublishToRoom returned error: %v", err)
+	}
+	if err := ws.PublishToRoom("blue", wsNotice{Text: "blue news"}); err != nil {
+		t.Fatalf("PublishToRoom returned error: %v", err)
+	}
+	for _, conn := range []*websocket.Conn{red1, red2} {
+		if got := readTestNotice(t, conn); got.Text != "red news" {
+			t.Fatalf("expected red member to receive red news, got %+v", got)
+		}
+	}
+	// blue is only in its own room, so the first message it sees must be blue news.
+	if got := readTestNotice(t, blue); got.Text != "blue news" {
+		t.Fatalf("expected room isolation, blue got %+v", got)
+	}
+
+	leave := map[string]any{"type": "leave", "payload": map[string]string{"text": "red"}}
+	if err := red2.WriteJSON(leave); err != nil {
+		t.Fatalf("write leave failed: %v", err)
+	}
+	if got := readTestNotice(t, red2); got.Text != "left" {
+		t.Fatalf("expected leave ack, got %+v", got)
+	}
+	if got := len(ws.RoomMembers("red")); got != 1 {
+		t.Fatalf("expected 1 member in red after leave, got %d", got)
+	}
+	if err := ws.PublishToRoom("red", wsNotice{Text: "only red1"}); err != nil {
+		t.Fatalf("PublishToRoom returned error: %v", err)
+	}
+	if got := readTestNotice(t, red1); got.Text != "only red1" {
+		t.Fatalf("expected remaining member to receive message, got %+v", got)
+	}
+	// A second ack proves red2 did not receive "only red1" in between.
+	if err := red2.WriteJSON(leave); err != nil {
+		t.Fatalf("write leave failed: %v", err)
+	}
+	if got := readTestNotice(t, red2); got.Text != "left" {
+		t.Fatalf("expected client that left not to receive room messages, got %+v", got)
+	}
+
+	_ = red1.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(ws.RoomMembers("red")) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected disconnected client to be removed from room")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	ws.hub.mu.RLock()
+	_, exists := ws.hub.rooms["red"]
+	ws.hub.mu.RUnlock()
+	if exists {
+		t.Fatalf("expected empty room to be cleaned up")
+	}
+}
+
+// TestWebSocketEndpoint_BinaryHandler
+// 这个测试验证二进制帧：
+// 1) 读循环收到 BinaryMessage 时调用 BinaryHandlerFunc，返回值以二进制帧回复。
+// 2) 二进制帧与 JSON 文本消息可以在同一连接上交替使用。
+// 3) 未配置 BinaryHandlerFunc 时二进制帧被忽略，不会断开连接。
+func TestWebSocketEndpoint_BinaryHandler(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "binary"
+	ws.Path = "/ws/binary"
+	ws.BinaryHandlerFunc = func(data []byte, _ *WebSocketContext) ([]byte, error) {
+		out := make([]byte, len(data))
+		for i, v := range data {
+			out[len(data)-1-i] = v
+		}
+		return out, nil
+	}
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	conn := dialTestWebSocket(t, newTestWebSocketServer(t, ws))
+
+	if err := conn.WriteMessage(websocket.BinaryMessage, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("write binary failed: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	frameType, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("read binary reply failed: %v", err)
+	}
+	if frameType != websocket.BinaryMessage || string(data) != string([]byte{3, 2, 1}) {
+		t.Fatalf("expected reversed binary reply, got type=%d data=%v", frameType, data)
+	}
+
+	if err := conn.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "json"}}); err != nil {
+		t.Fatalf("write json failed: %v", err)
+	}
+	if got := readTestNotice(t, conn); got.Text != "json" {
+		t.Fatalf("expected json echo after binary frame, got %+v", got)
+	}
+
+	plain := NewWebSocketEndpoint()
+	plain.Name = "plain"
+	plain.Path = "/ws/plain"
+	RegisterWebSocketTypedHandler(plain, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	plainConn := dialTestWebSocket(t, newTestWebSocketServer(t, plain))
+	if err := plainConn.WriteMessage(websocket.BinaryMessage, []byte{0xff}); err != nil {
+		t.Fatalf("write binary failed: %v", err)
+	}
+	if err := plainConn.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "alive"}}); err != nil {
+		t.Fatalf("write json failed: %v", err)
+	}
+	if got := readTestNotice(t, plainConn); got.Text != "alive" {
+		t.Fatalf("expected binary frame to be ignored without handler, got %+v", got)
+	}
+}
+
+// TestWebSocketContext_SendWithAck
+// 这个测试验证消息确认：
+// 1) SendWithAck 发送的消息带 ackId，客户端回传 {"type":"ack","ackId":...} 后返回 nil。
+// 2) 确认帧由读循环消费，不会进入 MessageHandlers。
+// 3) 客户端不回传时在超时后返回错误。
+func TestWebSocketContext_SendWithAck(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "ack"
+	ws.Path = "/ws/ack"
+	contexts := make(chan *WebSocketContext, 1)
+	ws.OnConnect = func(ctx *WebSocketContext) error {
+		contexts <- ctx
+		return nil
+	}
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	conn := dialTestWebSocket(t, newTestWebSocketServer(t, ws))
+	ctx := <-contexts
+
+	result := make(chan error, 1)
+	go func() {
+		result <- ctx.SendWithAck(wsNotice{Text: "deliver"}, 2*time.Second)
+	}()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got struct {
+		Text  string `json:"text"`
+		AckID string `json:"ackId"`
+	}
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("read ack message failed: %v", err)
+	}
+	if got.Text != "deliver" || got.AckID == "" {
+		t.Fatalf("expected message with ackId, got %+v", got)
+	}
+	if err := conn.WriteJSON(map[string]string{"type": WebSocketAckMessageType, "ackId": got.AckID}); err != nil {
+		t.Fatalf("write ack failed: %v", err)
+	}
+	if err := <-result; err != nil {
+		t.Fatalf("expected SendWithAck to resolve after echo, got %v", err)
+	}
+
+	if err := conn.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "alive"}}); err != nil {
+		t.Fatalf("write json failed: %v", err)
+	}
+	if got := readTestNotice(t, conn); got.Text != "alive" {
+		t.Fatalf("expected connection to stay usable after ack, got %+v", got)
+	}
+
+	go func() {
+		result <- ctx.SendWithAck(wsNotice{Text: "ignored"}, 50*time.Millisecond)
+	}()
+	readTestNotice(t, conn)
+	if err := <-result; err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("expected ack timeout error, got %v", err)
+	}
+}
+
+// TestWebSocketEndpoint_JSONRPC
+// 这个测试验证 JSON-RPC 2.0 服务端：
+// 1) RegisterWebSocketRPCMethod 注册的方法按 method 分发，响应携带相同 id 与 result。
+// 2) 未知方法返回 -32601，参数无法解析返回 -32602，处理器错误返回 -32000 或自定义 WebSocketRPCError。
+// 3) 没有 id 的通知不回复；普通消息仍走 MessageHandlers。
+func TestWebSocketEndpoint_JSONRPC(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "rpc"
+	ws.Path = "/ws/rpc"
+	type addParams struct {
+		A int `json:"a"`
+		B int `json:"b"`
+	}
+	RegisterWebSocketRPCMethod(ws, "math.add", func(p addParams, _ *WebSocketContext) (int, error) {
+		return p.A + p.B, nil
+	})
+	RegisterWebSocketRPCMethod(ws, "fail", func(_ NoParams, _ *WebSocketContext) (NoBody, error) {
+		return NoBody{}, &WebSocketRPCError{Code: 4001, Message: "denied"}
+	})
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	conn := dialTestWebSocket(t, newTestWebSocketServer(t, ws))
+
+	type rpcResponse struct {
+		JSONRPC string             `json:"jsonrpc"`
+		ID      any                `json:"id"`
+		Result  *int               `json:"result"`
+		Error   *WebSocketRPCError `json:"error"`
+	}
+	call := func(frame string) rpcResponse {
+		t.Helper()
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+			t.Fatalf("write rpc request failed: %v", err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var resp rpcResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			t.Fatalf("read rpc response failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := call(`{"jsonrpc":"2.0","id":7,"method":"math.add","params":{"a":2,"b":3}}`)
+	if resp.JSONRPC != "2.0" || resp.ID != float64(7) || resp.Result == nil || *resp.Result != 5 || resp.Error != nil {
+		t.Fatalf("expected result 5 for id 7, got %+v", resp)
+	}
+	resp = call(`{"jsonrpc":"2.0","id":"x","method":"missing"}`)
+	if resp.ID != "x" || resp.Error == nil || resp.Error.Code != WebSocketRPCMethodNotFound {
+		t.Fatalf("expected method not found error, got %+v", resp)
+	}
+	resp = call(`{"jsonrpc":"2.0","id":8,"method":"math.add","params":"bad"}`)
+	if resp.Error == nil || resp.Error.Code != WebSocketRPCInvalidParams {
+		t.Fatalf("expected invalid params error, got %+v", resp)
+	}
+	resp = call(`{"jsonrpc":"2.0","id":9,"method":"fail"}`)
+	if resp.Error == nil || resp.Error.Code != 4001 || resp.Error.Message != "denied" || resp.Result != nil {
+		t.Fatalf("expected custom rpc error, got %+v", resp)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"math.add","params":{"a":1,"b":1}}`)); err != nil {
+		t.Fatalf("write notification failed: %v", err)
+	}
+	if err := conn.WriteJSON(map[string]any{"type": "echo", "payload": map[string]string{"text": "after"}}); err != nil {
+		t.Fatalf("write json failed: %v", err)
+	}
+	if got := readTestNotice(t, conn); got.Text != "after" {
+		t.Fatalf("expected notification to get no reply, got %+v", got)
+	}
+}
+
+// TestWebSocketEndpoint_LatencyPing
+// 这个测试验证延迟测量的服务端回显：
+// 1) 带 ackId 的 ping 由读循环直接回复 {"type":"ack","ackId":...}。
+// 2) 不带 ackId 的心跳 ping 仍被忽略，不产生回复。
+func TestWebSocketEndpoint_LatencyPing(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "latency"
+	ws.Path = "/ws/latency"
+	RegisterWebSocketTypedHandler(ws, "echo", func(payload wsNotice, _ *WebSocketContext) (any, error) {
+		return payload, nil
+	})
+	conn := dialTestWebSocket(t, newTestWebSocketServer(t, ws))
+
+	if err := conn.WriteJSON(map[string]string{"type": WebSocketHeartbeatMessageType}); err != nil {
+		t.Fatalf("write heartbeat failed: %v", err)
+	}
+	if err := conn.WriteJSON(map[string]string{"type": WebSocketHeartbeatMessageType, "ackId": "latency-1"}); err != nil {
+		t.Fatalf("write latency ping failed: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got map[string]string
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("read latency echo failed: %v", err)
+	}
+	if got["type"] != WebSocketAckMessageType || got["ackId"] != "latency-1" {
+		t.Fatalf("expected ack echo for latency ping, got %v", got)
+	}
+}
+
+// TestWebSocketEndpoint_BeforeUpgrade
+// 这个测试验证升级前鉴权：
+// 1) BeforeUpgrade 返回错误时返回 401，不升级连接，OnConnect 不会执行。
+// 2) hook 自行写入响应时保留其状态码。
+// 3) 携带合法 token 时正常升级。
+func TestWebSocketEndpoint_BeforeUpgrade(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "secure"
+	ws.Path = "/ws/secure"
+	ws.BeforeUpgrade = func(ctx *gin.Context) error {
+		switch ctx.Query("token") {
+		case "good":
+			return nil
+		case "banned":
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return fmt.Errorf("banned")
+		default:
+			return fmt.Errorf("missing token")
+		}
+	}
+	var connects atomic.Int32
+	ws.OnConnect = func(_ *WebSocketContext) error {
+		connects.Add(1)
+		return nil
+	}
+	url := newTestWebSocketServer(t, ws)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	if err == nil {
+		t.Fatalf("expected upgrade without token to fail")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %+v", resp)
+	}
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?token=banned", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected hook-written 403 to be kept, got %+v", resp)
+	}
+	if connects.Load() != 0 || ws.ConnectedCount() != 0 {
+		t.Fatalf("expected rejected requests never to be upgraded")
+	}
+
+	conn := dialTestWebSocket(t, url+"?token=good")
+	if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("expected authorized connection to be usable: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for connects.Load() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected OnConnect for authorized client")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// pipeListener serves websocket connections over net.Pipe so benchmarks can open
+// thousands of clients without exhausting file descriptors.
+type pipeListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr{} }
+
+func (l *pipeListener) dial(_ context.Context, _, _ string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// dialPipeWebSocketClients connects n draining clients to ws and returns a counter of received messages.
+func dialPipeWebSocketClients(tb testing.TB, ws *WebSocketEndpoint, n int) *atomic.Int64 {
+	tb.Helper()
+	oldMode := gin.Mode()
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET(ws.Path, ws.GinHandler())
+	ln := &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+	server := &http.Server{Handler: engine}
+	go func() { _ = server.Serve(ln) }()
+
+	dialer := websocket.Dialer{NetDialContext: ln.dial}
+	received := &atomic.Int64{}
+	conns := make([]*websocket.Conn, 0, n)
+	tb.Cleanup(func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		_ = server.Close()
+		gin.SetMode(oldMode)
+	})
+	for i := 0; i < n; i++ {
+		conn, _, err := dialer.Dial("ws://pipe"+ws.Path, nil)
+		if err != nil {
+			tb.Fatalf("dial pipe client %d failed: %v", i, err)
+		}
+		conns = append(conns, conn)
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+				received.Add(1)
+			}
+		}()
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for ws.ConnectedCount() < n {
+		if time.Now().After(deadline) {
+			tb.Fatalf("expected %d connected clients, got %d", n, ws.ConnectedCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return received
+}
+
+// TestWebSocketEndpoint_ConcurrentBroadcast
+// 这个测试验证有界并发广播：
+// 1) Broadcast.Concurrency 大于 1 时，所有客户端都恰好收到一次消息。
+// 2) Concurrency 为 1 时退化为串行，结果一致。
+func TestWebSocketEndpoint_ConcurrentBroadcast(t *testing.T) {
+	ws := NewWebSocketEndpoint()
+	ws.Name = "fanout"
+	ws.Path = "/ws/fanout"
+	const clients = 50
+	received := dialPipeWebSocketClients(t, ws, clients)
+
+	for round, concurrency := range []int{8, 1} {
+		ws.Broadcast = BroadcastOptions{Concurrency: concurrency}
+		if err := ws.Publish(wsNotice{Text: fmt.Sprintf("round %d", round)}); err != nil {
+			t.Fatalf("Publish returned error: %v", err)
+		}
+		want := int64(clients * (round + 1))
+		deadline := time.Now().Add(2 * time.Second)
+		for received.Load() < want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d deliveries with concurrency %d, got %d", want, concurrency, received.Load())
+			}
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(20 * time.Millisecond)
+		if got := received.Load(); got != want {
+			t.Fatalf("expected exactly %d deliveries with concurrency %d, got %d", want, concurrency, got)
+		}
+	}
+}
+
+// BenchmarkWebSocketEndpointPublish compares serial and bounded-concurrent broadcast to 10k clients.
+func BenchmarkWebSocketEndpointPublish(b *testing.B) {
+	clients := 10000
+	if testing.Short() {
+		clients = 1000
+	}
+	ws := NewWebSocketEndpoint()
+	ws.Name = "bench"
+	ws.Path = "/ws/bench"
+	dialPipeWebSocketClients(b, ws, clients)
+	msg := wsNotice{Text: "benchmark"}
+
+	for _, bc := range []struct {
+		name        string
+		concurrency int
+	}{
+		{name: "serial", concurrency: 1},
+		{name: "concurrent", concurrency: defaultWSBroadcastConcurrency},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ws.Broadcast = BroadcastOptions{Concurrency: bc.concurrency}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ws.Publish(msg); err != nil {
+					b.Fatalf("Publish returned error: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package endpoint
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -19,8 +23,22 @@ const (
 	defaultWSReadBufferSize  = 1024
 	defaultWSWriteBufferSize = 1024
 	defaultWSWriteTimeout    = 10 * time.Second
+
+	defaultWSBroadcastConcurrency = 32
 )
 
+// WebSocketHeartbeatMessageType is the message type sent by generated clients as an application-level heartbeat.
+// It is ignored by MessageHandlers dispatch unless a handler is registered for it.
+// A ping carrying an "ackId" (sent by measureLatency) is answered with an ack frame by the read loop.
+// WebSocketHeartbeatMessageType 是生成的客户端发送的应用层心跳消息类型；未注册处理器时服务端会忽略它。
+// 携带 "ackId" 的 ping（由 measureLatency 发送）会由读循环直接回复 ack 帧。
+const WebSocketHeartbeatMessageType = "ping"
+
+// WebSocketAckMessageType is the message type clients send to acknowledge a message carrying an "ackId".
+// Ack frames are consumed by the read loop and never reach handlers.
+// WebSocketAckMessageType 是客户端确认带 "ackId" 的消息时发送的消息类型；确认帧由读循环消费，不会进入处理器。
+const WebSocketAckMessageType = "ack"
+
 // NoMessage is a marker type meaning "no websocket message payload".
 // NoMessage 是一个标记类型，表示“不发送/不接收 websocket 消息体”。
 type NoMessage struct{}
@@ -36,6 +54,22 @@ type WebSocketEndpointMeta struct {
 	MessageTypes       []string
 	ClientPayloadTypes map[string]reflect.Type
 	ServerPayloadTypes map[string]reflect.Type
+	RPCParamTypes      map[string]reflect.Type
+	RPCResultTypes     map[string]reflect.Type
+	StateMachine       *WebSocketStateMachine
+}
+
+// WebSocketStateMachine declares which client message types may be sent in each connection state.
+// The generated client starts in Initial on every open, moves to Transitions[type] when it receives
+// a server message of that type (e.g. an ack) and throws when send() uses a type not in Allowed.
+// States missing from Allowed accept every message type.
+// WebSocketStateMachine 声明每个连接状态下允许发送的客户端消息类型。
+// 生成的客户端在每次连接打开时进入 Initial，收到对应 type 的服务端消息（如 ack）后切换到 Transitions[type]，
+// send() 使用不在 Allowed 中的类型时抛出异常；未出现在 Allowed 中的状态允许发送任意类型。
+type WebSocketStateMachine struct {
+	Initial     string
+	Allowed     map[string][]string
+	Transitions map[string]string
 }
 
 // WebSocketEndpointLike is implemented by WebSocketEndpoint to expose metadata and gin handler.
@@ -46,10 +80,174 @@ type WebSocketEndpointLike interface {
 	SetFullPath(path string)
 }
 
+// WebSocketEndpointTSHints declares how websocket messages are framed, for TS generation.
+// Kinds are TSKindJSON (default when empty), TSKindText or TSKindBytes. Text and binary messages
+// are typed as string / Uint8Array and skip JSON (de)serialization in the generated client.
+// WebSocketEndpointTSHints 声明 websocket 消息的帧格式，用于 TS 生成。
+// 可选 TSKindJSON（为空时的默认值）、TSKindText 或 TSKindBytes；文本与二进制消息在生成的客户端中
+// 类型为 string / Uint8Array，且不做 JSON 序列化与反序列化。
+type WebSocketEndpointTSHints struct {
+	ClientMessageKind TSKind
+	ServerMessageKind TSKind
+}
+
+// WebSocketEndpointTSHintsProvider allows websocket endpoints to customize TS message handling.
+// WebSocketEndpointTSHintsProvider 允许 websocket endpoint 自定义 TS 的消息处理方式。
+type WebSocketEndpointTSHintsProvider interface {
+	WebSocketEndpointTSHints() WebSocketEndpointTSHints
+}
+
 type wsClient struct {
-	id   string
-	conn *websocket.Conn
-	mu   sync.Mutex
+	id     string
+	conn   *websocket.Conn
+	mu     sync.Mutex
+	closed bool
+
+	metaMu sync.RWMutex
+	meta   map[string]any
+
+	acksMu sync.Mutex
+	acks   map[string]chan struct{}
+	done   chan struct{}
+}
+
+func (c *wsClient) setMeta(key string, value any) {
+	c.metaMu.Lock()
+	if c.meta == nil {
+		c.meta = map[string]any{}
+	}
+	c.meta[key] = value
+	c.metaMu.Unlock()
+}
+
+func (c *wsClient) getMeta(key string) (any, bool) {
+	c.metaMu.RLock()
+	defer c.metaMu.RUnlock()
+	value, ok := c.meta[key]
+	return value, ok
+}
+
+func (c *wsClient) snapshotMeta() map[string]any {
+	c.metaMu.RLock()
+	defer c.metaMu.RUnlock()
+	out := make(map[string]any, len(c.meta))
+	for k, v := range c.meta {
+		out[k] = v
+	}
+	return out
+}
+
+func (c *wsClient) sendBinary(data []byte) error {
+	return c.writeMessage(websocket.BinaryMessage, data)
+}
+
+func (c *wsClient) writeMessage(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.conn.SetWriteDeadline(time.Now().Add(defaultWSWriteTimeout)); err != nil {
+		return err
+	}
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// closeWithCode writes a close frame and bounds the pending read so the read loop exits
+// even if the peer never answers the close handshake.
+func (c *wsClient) closeWithCode(code int, reason string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	closeMessage := websocket.FormatCloseMessage(code, reason)
+	if err := c.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(defaultWSWriteTimeout)); err != nil {
+		return err
+	}
+	return c.conn.SetReadDeadline(time.Now().Add(defaultWSWriteTimeout))
+}
+
+func (c *wsClient) closedByServer() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+// sendWithAck attaches an ack id to message and waits until the client echoes it.
+func (c *wsClient) sendWithAck(message any, timeout time.Duration) error {
+	ackID := uuid.NewString()
+	data, err := withAckID(message, ackID)
+	if err != nil {
+		return err
+	}
+	ch := make(chan struct{})
+	c.acksMu.Lock()
+	if c.acks == nil {
+		c.acks = map[string]chan struct{}{}
+	}
+	c.acks[ackID] = ch
+	c.acksMu.Unlock()
+	defer func() {
+		c.acksMu.Lock()
+		delete(c.acks, ackID)
+		c.acksMu.Unlock()
+	}()
+
+	if err := c.send(data); err != nil {
+		return err
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ch:
+		return nil
+	case <-c.done:
+		return fmt.Errorf("websocket client %s disconnected before ack", c.id)
+	case <-timer.C:
+		return fmt.Errorf("websocket ack timeout after %s for client %s", timeout, c.id)
+	}
+}
+
+func (c *wsClient) resolveAck(ackID string) {
+	c.acksMu.Lock()
+	ch, ok := c.acks[ackID]
+	delete(c.acks, ackID)
+	c.acksMu.Unlock()
+	if ok {
+		close(ch)
+	}
+}
+
+// withAckID encodes message as a JSON object with an extra "ackId" field.
+func withAckID(message any, ackID string) (json.RawMessage, error) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return nil, errors.New("websocket ack message must encode to a JSON object")
+	}
+	id, err := json.Marshal(ackID)
+	if err != nil {
+		return nil, err
+	}
+	fields["ackId"] = id
+	return json.Marshal(fields)
+}
+
+// parseClientAck reports the ack id and message type when data is an ack or latency ping frame.
+func parseClientAck(data []byte) (string, string, bool) {
+	if !bytes.Contains(data, []byte(`"ackId"`)) {
+		return "", "", false
+	}
+	var ack struct {
+		Type  string `json:"type"`
+		AckID string `json:"ackId"`
+	}
+	if err := json.Unmarshal(data, &ack); err != nil {
+		return "", "", false
+	}
+	if ack.AckID == "" || (ack.Type != WebSocketAckMessageType && ack.Type != WebSocketHeartbeatMessageType) {
+		return "", "", false
+	}
+	return ack.AckID, ack.Type, true
 }
 
 func (c *wsClient) send(message any) error {
@@ -64,28 +262,94 @@ func (c *wsClient) send(message any) error {
 type wsHub struct {
 	mu      sync.RWMutex
 	clients map[string]*wsClient
+	rooms   map[string]map[string]*wsClient
 }
 
 func newWebSocketHub() *wsHub {
 	return &wsHub{
 		clients: map[string]*wsClient{},
+		rooms:   map[string]map[string]*wsClient{},
 	}
 }
 
-func (h *wsHub) add(conn *websocket.Conn) *wsClient {
-	client := &wsClient{id: uuid.NewString(), conn: conn}
+// add registers conn unless the hub already holds limit clients (limit <= 0 means no limit).
+// The check and the insert happen under one lock, so concurrent upgrades never exceed the limit.
+func (h *wsHub) add(conn *websocket.Conn, limit int) (*wsClient, bool) {
+	client := &wsClient{id: uuid.NewString(), conn: conn, done: make(chan struct{})}
 	h.mu.Lock()
+	defer h.mu.Unlock()
+	if limit > 0 && len(h.clients) >= limit {
+		return nil, false
+	}
 	h.clients[client.id] = client
-	h.mu.Unlock()
-	return client
+	return client, true
 }
 
 func (h *wsHub) remove(id string) {
 	h.mu.Lock()
+	if client, ok := h.clients[id]; ok {
+		close(client.done)
+	}
 	delete(h.clients, id)
+	for room, members := range h.rooms {
+		delete(members, id)
+		if len(members) == 0 {
+			delete(h.rooms, room)
+		}
+	}
 	h.mu.Unlock()
 }
 
+func (h *wsHub) join(room string, id string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	client := h.clients[id]
+	if client == nil {
+		return fmt.Errorf("websocket client not found: %s", id)
+	}
+	members, ok := h.rooms[room]
+	if !ok {
+		members = map[string]*wsClient{}
+		h.rooms[room] = members
+	}
+	members[id] = client
+	return nil
+}
+
+func (h *wsHub) leave(room string, id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	members := h.rooms[room]
+	delete(members, id)
+	if len(members) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+func (h *wsHub) roomMembers(room string) []string {
+	h.mu.RLock()
+	members := h.rooms[room]
+	out := make([]string, 0, len(members))
+	for id := range members {
+		out = append(out, id)
+	}
+	h.mu.RUnlock()
+	sort.Strings(out)
+	return out
+}
+
+func (h *wsHub) broadcastRoom(room string, message any, opts BroadcastOptions) error {
+	h.mu.RLock()
+	members := h.rooms[room]
+	clients := make([]*wsClient, 0, len(members))
+	for _, c := range members {
+		clients = append(clients, c)
+	}
+	h.mu.RUnlock()
+
+	return fanOut(clients, message, opts)
+}
+
 func (h *wsHub) sendTo(id string, message any) error {
 	h.mu.RLock()
 	client := h.clients[id]
@@ -96,7 +360,7 @@ func (h *wsHub) sendTo(id string, message any) error {
 	return client.send(message)
 }
 
-func (h *wsHub) broadcast(message any) error {
+func (h *wsHub) broadcast(message any, opts BroadcastOptions) error {
 	h.mu.RLock()
 	clients := make([]*wsClient, 0, len(h.clients))
 	for _, c := range h.clients {
@@ -104,13 +368,80 @@ func (h *wsHub) broadcast(message any) error {
 	}
 	h.mu.RUnlock()
 
-	var firstErr error
-	for _, c := range clients {
-		if err := c.send(message); err != nil && firstErr == nil {
-			firstErr = err
+	return fanOut(clients, message, opts)
+}
+
+func (h *wsHub) get(id string) *wsClient {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.clients[id]
+}
+
+// broadcastWhere sends message to clients whose metadata matches.
+// The predicate receives a copy, so it cannot mutate client state.
+func (h *wsHub) broadcastWhere(match func(meta map[string]any) bool, message any, opts BroadcastOptions) error {
+	h.mu.RLock()
+	clients := make([]*wsClient, 0, len(h.clients))
+	for _, c := range h.clients {
+		if match(c.snapshotMeta()) {
+			clients = append(clients, c)
 		}
 	}
-	return firstErr
+	h.mu.RUnlock()
+
+	return fanOut(clients, message, opts)
+}
+
+// fanOut writes message to clients using up to opts.Concurrency workers,
+// so one slow client does not delay delivery to the others.
+func fanOut(clients []*wsClient, message any, opts BroadcastOptions) error {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = defaultWSBroadcastConcurrency
+	}
+	if workers > len(clients) {
+		workers = len(clients)
+	}
+
+	errs := make([]error, len(clients))
+	deliver := func(i int) {
+		c := clients[i]
+		err := c.send(message)
+		if err == nil {
+			return
+		}
+		errs[i] = fmt.Errorf("websocket client %s: %w", c.id, err)
+		var netErr net.Error
+		if opts.DropSlowClients && errors.As(err, &netErr) && netErr.Timeout() {
+			// Closing the conn ends its read loop, which unregisters the client.
+			_ = c.conn.Close()
+		}
+	}
+
+	if workers <= 1 {
+		for i := range clients {
+			deliver(i)
+		}
+		return errors.Join(errs...)
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(clients) {
+					return
+				}
+				deliver(i)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
 func (h *wsHub) count() int {
@@ -119,10 +450,42 @@ func (h *wsHub) count() int {
 	return len(h.clients)
 }
 
+// WebSocketClientConn is a connected client in WebSocketClientsByPath. Its write methods share the
+// per-client lock used by Send/Publish, so they are safe to call from any goroutine.
+// WebSocketClientConn 是 WebSocketClientsByPath 中的已连接客户端；其写方法与 Send/Publish 共用同一把客户端写锁，
+// 可在任意 goroutine 中并发调用。
+type WebSocketClientConn struct {
+	client *wsClient
+}
+
+// ID returns the client ID.
+// ID 返回客户端 ID。
+func (c *WebSocketClientConn) ID() string {
+	return c.client.id
+}
+
+// Conn returns the underlying connection. Do not write to it directly; use WriteJSON / WriteMessage.
+// Conn 返回底层连接；不要直接写入，请使用 WriteJSON / WriteMessage。
+func (c *WebSocketClientConn) Conn() *websocket.Conn {
+	return c.client.conn
+}
+
+// WriteJSON writes message as a JSON text frame.
+// WriteJSON 以 JSON 文本帧写入消息。
+func (c *WebSocketClientConn) WriteJSON(message any) error {
+	return c.client.send(message)
+}
+
+// WriteMessage writes one data frame (websocket.TextMessage or websocket.BinaryMessage).
+// WriteMessage 写入一个数据帧（websocket.TextMessage 或 websocket.BinaryMessage）。
+func (c *WebSocketClientConn) WriteMessage(messageType int, data []byte) error {
+	return c.client.writeMessage(messageType, data)
+}
+
 // WebSocketClientsByPath stores all connected clients by websocket full path.
 // WebSocketClientsByPath 按 websocket 完整路径保存所有连接的客户端。
 // 注意：访问请使用 WebSocketClientsByPathMu 加锁。
-var WebSocketClientsByPath = map[string]map[string]*websocket.Conn{}
+var WebSocketClientsByPath = map[string]map[string]*WebSocketClientConn{}
 
 // WebSocketClientsByPathMu guards WebSocketClientsByPath.
 // WebSocketClientsByPathMu 用于保护 WebSocketClientsByPath。
@@ -130,13 +493,13 @@ var WebSocketClientsByPathMu sync.RWMutex
 
 // SnapshotWebSocketClients returns a copy of current clients for the path.
 // SnapshotWebSocketClients 返回指定路径当前客户端的副本。
-func SnapshotWebSocketClients(path string) map[string]*websocket.Conn {
+func SnapshotWebSocketClients(path string) map[string]*WebSocketClientConn {
 	WebSocketClientsByPathMu.RLock()
 	defer WebSocketClientsByPathMu.RUnlock()
 	src := WebSocketClientsByPath[path]
-	out := make(map[string]*websocket.Conn, len(src))
-	for id, conn := range src {
-		out[id] = conn
+	out := make(map[string]*WebSocketClientConn, len(src))
+	for id, client := range src {
+		out[id] = client
 	}
 	return out
 }
@@ -146,8 +509,8 @@ func SnapshotWebSocketClients(path string) map[string]*websocket.Conn {
 func BroadcastWebSocketJSON(path string, message any) error {
 	clients := SnapshotWebSocketClients(path)
 	var firstErr error
-	for _, conn := range clients {
-		if err := conn.WriteJSON(message); err != nil && firstErr == nil {
+	for _, client := range clients {
+		if err := client.WriteJSON(message); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
@@ -158,12 +521,23 @@ func BroadcastWebSocketJSON(path string, message any) error {
 // SendWebSocketJSON 向指定路径的某个客户端发送 JSON。
 func SendWebSocketJSON(path string, clientID string, message any) error {
 	WebSocketClientsByPathMu.RLock()
-	conn := WebSocketClientsByPath[path][clientID]
+	client := WebSocketClientsByPath[path][clientID]
 	WebSocketClientsByPathMu.RUnlock()
-	if conn == nil {
+	if client == nil {
 		return fmt.Errorf("websocket client not found: %s", clientID)
 	}
-	return conn.WriteJSON(message)
+	return client.WriteJSON(message)
+}
+
+// BroadcastOptions controls how Publish/PublishWhere/PublishToRoom fan out to clients.
+// BroadcastOptions 控制 Publish/PublishWhere/PublishToRoom 的并发投递方式。
+type BroadcastOptions struct {
+	// Concurrency is the max number of parallel client writes. Zero means 32; 1 means serial.
+	// Concurrency 为并发写入的最大客户端数；0 表示 32，1 表示串行。
+	Concurrency int
+	// DropSlowClients closes connections whose write times out during a broadcast.
+	// DropSlowClients 为 true 时，广播中写超时的连接会被关闭。
+	DropSlowClients bool
 }
 
 // WebSocketContext provides access to the current connection and publish helpers.
@@ -184,13 +558,111 @@ func (c *WebSocketContext) Send(message any) error {
 	return c.endpoint.hub.sendTo(c.ID, message)
 }
 
+// SendBinary sends a binary frame to the current client.
+// SendBinary 向当前客户端发送二进制帧。
+func (c *WebSocketContext) SendBinary(data []byte) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	return client.sendBinary(data)
+}
+
+// SendWithAck sends message with an extra "ackId" field and waits until the client echoes
+// {"type":"ack","ackId":...}. It returns an error on timeout or disconnect. message must encode
+// to a JSON object. Acks are read by the connection's read loop, so call it from another
+// goroutine rather than from a message handler.
+// SendWithAck 发送附带 "ackId" 字段的消息，并等待客户端回传 {"type":"ack","ackId":...}；超时或断开时返回错误。
+// message 必须编码为 JSON 对象。确认由读循环处理，因此请在其他 goroutine 中调用，而不是在消息处理器中同步调用。
+func (c *WebSocketContext) SendWithAck(message any, timeout time.Duration) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return errors.New("websocket ack timeout must be positive")
+	}
+	return client.sendWithAck(message, timeout)
+}
+
 // Publish broadcasts to all connected clients.
 // Publish 向所有已连接客户端广播消息。
 func (c *WebSocketContext) Publish(message any) error {
 	if c.endpoint == nil {
 		return errors.New("websocket endpoint is nil")
 	}
-	return c.endpoint.hub.broadcast(message)
+	return c.endpoint.hub.broadcast(message, c.endpoint.Broadcast)
+}
+
+// JoinRoom adds the current client to a room.
+// JoinRoom 将当前客户端加入房间。
+func (c *WebSocketContext) JoinRoom(room string) error {
+	if c.endpoint == nil {
+		return errors.New("websocket endpoint is nil")
+	}
+	return c.endpoint.hub.join(room, c.ID)
+}
+
+// LeaveRoom removes the current client from a room. Empty rooms are dropped.
+// LeaveRoom 将当前客户端移出房间；房间为空时自动清理。
+func (c *WebSocketContext) LeaveRoom(room string) error {
+	if c.endpoint == nil {
+		return errors.New("websocket endpoint is nil")
+	}
+	c.endpoint.hub.leave(room, c.ID)
+	return nil
+}
+
+// PublishToRoom broadcasts to all clients in a room.
+// PublishToRoom 向房间内所有客户端广播消息。
+func (c *WebSocketContext) PublishToRoom(room string, message any) error {
+	if c.endpoint == nil {
+		return errors.New("websocket endpoint is nil")
+	}
+	return c.endpoint.hub.broadcastRoom(room, message, c.endpoint.Broadcast)
+}
+
+// SetMeta stores a metadata value on the current connection (e.g. user id, role, org).
+// SetMeta 在当前连接上保存元数据（例如用户 ID、角色、组织）。
+func (c *WebSocketContext) SetMeta(key string, value any) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	client.setMeta(key, value)
+	return nil
+}
+
+// GetMeta reads a metadata value from the current connection.
+// GetMeta 读取当前连接上的元数据。
+func (c *WebSocketContext) GetMeta(key string) (any, bool) {
+	client, err := c.client()
+	if err != nil {
+		return nil, false
+	}
+	return client.getMeta(key)
+}
+
+// Close disconnects the current client with a close frame (e.g. on logout) instead of returning
+// a handler error. The read loop then exits and OnDisconnect runs with a nil error.
+// Close 以关闭帧断开当前客户端（如退出登录），无需通过 handler 返回错误；读循环随后退出，OnDisconnect 收到 nil 错误。
+func (c *WebSocketContext) Close(code int, reason string) error {
+	client, err := c.client()
+	if err != nil {
+		return err
+	}
+	return client.closeWithCode(code, reason)
+}
+
+func (c *WebSocketContext) client() (*wsClient, error) {
+	if c.endpoint == nil {
+		return nil, errors.New("websocket endpoint is nil")
+	}
+	client := c.endpoint.hub.get(c.ID)
+	if client == nil {
+		return nil, fmt.Errorf("websocket client not found: %s", c.ID)
+	}
+	return client, nil
 }
 
 // WebSocketEndpoint is a websocket endpoint definition.
@@ -208,22 +680,95 @@ type WebSocketEndpoint struct {
 	ClientPayloadTypes map[string]reflect.Type
 	ServerPayloadTypes map[string]reflect.Type
 
+	// Optional message framing for TS generation; see WebSocketEndpointTSHints. Empty means JSON.
+	// 可选的消息帧格式（用于 TS 生成），参见 WebSocketEndpointTSHints；为空表示 JSON。
+	ClientMessageKind TSKind
+	ServerMessageKind TSKind
+
 	// Optional upgrader configuration. If zero-value, a default upgrader is used.
 	// Upgrader 可选配置；若为空则使用默认 Upgrader。
 	Upgrader websocket.Upgrader
 
+	// Optional permessage-deflate compression. EnableCompression turns it on for the upgrader;
+	// CompressionLevel (flate level -2..9, 0 keeps gorilla's default) is applied to each connection.
+	// Browsers negotiate the extension automatically, so the generated TS client needs no option.
+	// 可选的 permessage-deflate 压缩：EnableCompression 在 Upgrader 上启用压缩；
+	// CompressionLevel（flate 级别 -2..9，0 表示使用 gorilla 默认值）应用到每个连接。浏览器会自动协商该扩展，生成的 TS 客户端无需额外配置。
+	EnableCompression bool
+	CompressionLevel  int
+
+	// Optional pre-upgrade check (e.g. token auth). Returning an error rejects the request
+	// with 401 unless the hook already wrote a response; the connection is never upgraded.
+	// 可选的升级前校验（如 token 鉴权）；返回错误时拒绝请求（若未写响应则返回 401），不会升级连接。
+	BeforeUpgrade func(ctx *gin.Context) error
+
+	// Optional cap on concurrent connections. When MaxConnections > 0 and the limit is reached,
+	// a new connection is closed right after the upgrade with code 1013 (try again later) and
+	// OnRejected is called instead of OnConnect.
+	// 可选的并发连接上限：MaxConnections > 0 且已达上限时，新连接在升级后立即以 1013（稍后重试）关闭，
+	// 并调用 OnRejected（不会调用 OnConnect）。
+	MaxConnections int
+	OnRejected     func(ctx *gin.Context)
+
 	// Optional hooks.
 	// 可选回调。
 	OnConnect    func(ctx *WebSocketContext) error
 	HandlerFunc  func(message any, ctx *WebSocketContext) (any, error)
 	OnDisconnect func(ctx *WebSocketContext, err error)
 
+	// Optional handler for binary frames. A non-nil result is sent back as a binary frame.
+	// Binary frames are ignored when it is nil.
+	// 可选的二进制帧处理器；返回非 nil 时以二进制帧回复；为空时忽略二进制帧。
+	BinaryHandlerFunc func(data []byte, ctx *WebSocketContext) ([]byte, error)
+
+	// Optional hook for text frames that fail to decode into ClientMessageType. Returning false
+	// skips the frame and keeps reading; returning true (or leaving it nil) closes the connection.
+	// Connection errors such as EOF or close frames are always fatal and never reach this hook.
+	// 可选的消息解码错误回调：文本帧无法解码为 ClientMessageType 时调用；返回 false 则跳过该帧继续读取，
+	// 返回 true（或未设置）则关闭连接；EOF、关闭帧等连接错误始终是致命的，不会进入该回调。
+	OnMessageError func(ctx *WebSocketContext, raw []byte, err error) (fatal bool)
+
 	// Optional typed handlers based on message type.
 	// When MessageHandlers is set, HandlerFunc is ignored.
 	// 可选按消息类型分发的处理器；若设置则忽略 HandlerFunc。
 	MessageHandlers   map[string]func(payload json.RawMessage, ctx *WebSocketContext) (any, error)
 	MessageTypeGetter func(message any) (msgType string, payload json.RawMessage, err error)
 
+	// Optional JSON-RPC 2.0 methods, usually registered via RegisterWebSocketRPCMethod.
+	// Frames with "jsonrpc":"2.0" are answered here and never reach MessageHandlers/HandlerFunc.
+	// 可选的 JSON-RPC 2.0 方法，通常通过 RegisterWebSocketRPCMethod 注册；
+	// 带 "jsonrpc":"2.0" 的帧在此处应答，不会进入 MessageHandlers/HandlerFunc。
+	RPCHandlers    map[string]func(params json.RawMessage, ctx *WebSocketContext) (any, error)
+	RPCParamTypes  map[string]reflect.Type
+	RPCResultTypes map[string]reflect.Type
+
+	// Optional keepalive. When PingInterval > 0, the server sends ping frames at that interval
+	// and closes the connection if no pong arrives within PongTimeout (defaults to PingInterval).
+	// 可选保活：PingInterval > 0 时按间隔发送 ping 帧，若 PongTimeout（默认等于 PingInterval）内未收到 pong 则关闭连接。
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+
+	// Optional idle timeout. When ReadTimeout > 0, a client that sends nothing (no message and,
+	// with PingInterval set, no pong) for that long is disconnected. With heartbeats enabled the
+	// wait never drops below PingInterval + PongTimeout, so pings can keep a quiet client alive.
+	// 可选的空闲超时：ReadTimeout > 0 时，客户端在该时长内没有任何消息（启用 PingInterval 时也没有 pong）则断开连接；
+	// 启用心跳时等待时间不会短于 PingInterval + PongTimeout，因此安静但正常回复 pong 的客户端不会被断开。
+	ReadTimeout time.Duration
+
+	// Optional application heartbeat type, for clients whose heartbeat message is customized.
+	// Unhandled frames of this type (and of WebSocketHeartbeatMessageType) are dropped instead of
+	// failing as an unknown message type.
+	// 可选的应用层心跳消息类型（客户端自定义心跳消息时使用）；未注册处理器时该类型（以及 WebSocketHeartbeatMessageType）的消息会被忽略，而不是当作未知类型报错。
+	HeartbeatMessageType string
+
+	// Optional protocol ordering enforced by the generated TS client; see WebSocketStateMachine.
+	// 可选的协议顺序约束，由生成的 TS 客户端执行；参见 WebSocketStateMachine。
+	StateMachine *WebSocketStateMachine
+
+	// Optional broadcast fan-out configuration.
+	// 可选的广播并发配置。
+	Broadcast BroadcastOptions
+
 	hub      *wsHub
 	fullPath string
 }
@@ -256,6 +801,18 @@ func (s *WebSocketEndpoint) WebSocketMeta() WebSocketEndpointMeta {
 		MessageTypes:       append([]string(nil), s.MessageTypes...),
 		ClientPayloadTypes: copyMessagePayloadTypeMap(s.ClientPayloadTypes),
 		ServerPayloadTypes: copyMessagePayloadTypeMap(s.ServerPayloadTypes),
+		RPCParamTypes:      copyMessagePayloadTypeMap(s.RPCParamTypes),
+		RPCResultTypes:     copyMessagePayloadTypeMap(s.RPCResultTypes),
+		StateMachine:       s.StateMachine,
+	}
+}
+
+// WebSocketEndpointTSHints customizes TS message framing.
+// WebSocketEndpointTSHints 自定义 TS 的消息帧格式。
+func (s *WebSocketEndpoint) WebSocketEndpointTSHints() WebSocketEndpointTSHints {
+	return WebSocketEndpointTSHints{
+		ClientMessageKind: s.ClientMessageKind,
+		ServerMessageKind: s.ServerMessageKind,
 	}
 }
 
@@ -275,6 +832,16 @@ func copyMessagePayloadTypeMap(src map[string]reflect.Type) map[string]reflect.T
 func (s *WebSocketEndpoint) GinHandler() gin.HandlerFunc {
 	s.ensureHub()
 	return func(ctx *gin.Context) {
+		if s.BeforeUpgrade != nil {
+			if err := s.BeforeUpgrade(ctx); err != nil {
+				if !ctx.Writer.Written() {
+					ctx.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+					return
+				}
+				ctx.Abort()
+				return
+			}
+		}
 		upgrader := s.Upgrader
 		if upgrader.CheckOrigin == nil {
 			upgrader.CheckOrigin = func(_ *http.Request) bool { return true }
@@ -285,13 +852,33 @@ func (s *WebSocketEndpoint) GinHandler() gin.HandlerFunc {
 		if upgrader.WriteBufferSize == 0 {
 			upgrader.WriteBufferSize = defaultWSWriteBufferSize
 		}
+		if s.EnableCompression {
+			upgrader.EnableCompression = true
+		}
 
 		conn, err := upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
 		if err != nil {
 			return
 		}
-		client := s.hub.add(conn)
-		s.registerClient(client.id, conn)
+		if s.CompressionLevel != 0 {
+			if err := conn.SetCompressionLevel(s.CompressionLevel); err != nil {
+				closeMessage := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
+				_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(defaultWSWriteTimeout))
+				_ = conn.Close()
+				return
+			}
+		}
+		client, ok := s.hub.add(conn, s.MaxConnections)
+		if !ok {
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too many connections")
+			_ = conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(defaultWSWriteTimeout))
+			_ = conn.Close()
+			if s.OnRejected != nil {
+				s.OnRejected(ctx)
+			}
+			return
+		}
+		s.registerClient(client)
 		wsCtx := &WebSocketContext{
 			ID:       client.id,
 			Conn:     conn,
@@ -308,13 +895,56 @@ func (s *WebSocketEndpoint) GinHandler() gin.HandlerFunc {
 			}
 		}
 
+		stopHeartbeat := s.startHeartbeat(conn)
+		readWait := s.readWait()
 		var readErr error
 		for {
-			message, err := s.readClientMessage(conn)
+			if client.closedByServer() {
+				break
+			}
+			if readWait > 0 {
+				_ = conn.SetReadDeadline(time.Now().Add(readWait))
+			}
+			frameType, data, err := conn.ReadMessage()
 			if err != nil {
 				readErr = err
 				break
 			}
+			if frameType == websocket.BinaryMessage {
+				if err := s.handleBinaryMessage(data, wsCtx); err != nil {
+					readErr = err
+					break
+				}
+				continue
+			}
+			if ackID, msgType, ok := parseClientAck(data); ok {
+				if msgType == WebSocketHeartbeatMessageType {
+					if err := client.send(map[string]string{"type": WebSocketAckMessageType, "ackId": ackID}); err != nil {
+						readErr = err
+						break
+					}
+					continue
+				}
+				client.resolveAck(ackID)
+				continue
+			}
+			if len(s.RPCHandlers) > 0 {
+				if req, ok := parseRPCRequest(data); ok {
+					if err := s.handleRPCRequest(req, wsCtx); err != nil {
+						readErr = err
+						break
+					}
+					continue
+				}
+			}
+			message, err := s.decodeClientMessage(data)
+			if err != nil {
+				if s.OnMessageError != nil && !s.OnMessageError(wsCtx, data, err) {
+					continue
+				}
+				readErr = err
+				break
+			}
 			resp, err := s.handleMessage(message, wsCtx)
 			if err != nil {
 				readErr = err
@@ -328,6 +958,10 @@ func (s *WebSocketEndpoint) GinHandler() gin.HandlerFunc {
 			}
 		}
 
+		if client.closedByServer() {
+			readErr = nil
+		}
+		stopHeartbeat()
 		s.hub.remove(client.id)
 		s.unregisterClient(client.id)
 		_ = conn.Close()
@@ -341,7 +975,31 @@ func (s *WebSocketEndpoint) GinHandler() gin.HandlerFunc {
 // Publish 向所有已连接客户端广播消息。
 func (s *WebSocketEndpoint) Publish(message any) error {
 	s.ensureHub()
-	return s.hub.broadcast(message)
+	return s.hub.broadcast(message, s.Broadcast)
+}
+
+// PublishWhere sends a server message to clients whose metadata matches the predicate.
+// PublishWhere 向元数据满足条件的客户端发送消息（如“通知所有管理员”）。
+func (s *WebSocketEndpoint) PublishWhere(match func(meta map[string]any) bool, message any) error {
+	s.ensureHub()
+	if match == nil {
+		return errors.New("websocket publish predicate is nil")
+	}
+	return s.hub.broadcastWhere(match, message, s.Broadcast)
+}
+
+// PublishToRoom broadcasts a server message to all clients in a room.
+// PublishToRoom 向房间内所有客户端广播消息。
+func (s *WebSocketEndpoint) PublishToRoom(room string, message any) error {
+	s.ensureHub()
+	return s.hub.broadcastRoom(room, message, s.Broadcast)
+}
+
+// RoomMembers returns the sorted client IDs currently in a room.
+// RoomMembers 返回房间内当前客户端 ID（已排序）。
+func (s *WebSocketEndpoint) RoomMembers(room string) []string {
+	s.ensureHub()
+	return s.hub.roomMembers(room)
 }
 
 // SendTo sends a server message to a specific client.
@@ -358,6 +1016,56 @@ func (s *WebSocketEndpoint) ConnectedCount() int {
 	return s.hub.count()
 }
 
+// readWait returns how long a read may wait for the next frame or pong; 0 means no deadline.
+// With heartbeats it covers the wait until the next ping plus the pong timeout, or ReadTimeout if longer.
+func (s *WebSocketEndpoint) readWait() time.Duration {
+	wait := s.ReadTimeout
+	if s.PingInterval > 0 {
+		pongTimeout := s.PongTimeout
+		if pongTimeout <= 0 {
+			pongTimeout = s.PingInterval
+		}
+		wait = max(wait, s.PingInterval+pongTimeout)
+	}
+	return max(wait, 0)
+}
+
+// startHeartbeat starts the ping ticker and returns a stop func that waits for the goroutine to exit.
+func (s *WebSocketEndpoint) startHeartbeat(conn *websocket.Conn) func() {
+	if s.PingInterval <= 0 {
+		return func() {}
+	}
+	pongWait := s.readWait()
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.PingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(defaultWSWriteTimeout)); err != nil {
+					_ = conn.Close()
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
 func (s *WebSocketEndpoint) ensureHub() {
 	if s.hub == nil {
 		s.hub = newWebSocketHub()
@@ -370,7 +1078,7 @@ func (s *WebSocketEndpoint) SetFullPath(path string) {
 	s.fullPath = path
 }
 
-func (s *WebSocketEndpoint) registerClient(id string, conn *websocket.Conn) {
+func (s *WebSocketEndpoint) registerClient(client *wsClient) {
 	path := strings.TrimSpace(s.fullPath)
 	if path == "" {
 		return
@@ -378,10 +1086,10 @@ func (s *WebSocketEndpoint) registerClient(id string, conn *websocket.Conn) {
 	WebSocketClientsByPathMu.Lock()
 	clients, ok := WebSocketClientsByPath[path]
 	if !ok {
-		clients = map[string]*websocket.Conn{}
+		clients = map[string]*WebSocketClientConn{}
 		WebSocketClientsByPath[path] = clients
 	}
-	clients[id] = conn
+	clients[client.id] = &WebSocketClientConn{client: client}
 	WebSocketClientsByPathMu.Unlock()
 }
 
@@ -399,13 +1107,13 @@ func (s *WebSocketEndpoint) unregisterClient(id string) {
 	WebSocketClientsByPathMu.Unlock()
 }
 
-func (s *WebSocketEndpoint) readClientMessage(conn *websocket.Conn) (any, error) {
+func (s *WebSocketEndpoint) decodeClientMessage(data []byte) (any, error) {
 	t := s.ClientMessageType
 	if t == nil {
 		t = reflect.TypeOf(WebSocketMessage{})
 	}
 	valPtr := reflect.New(t)
-	if err := conn.ReadJSON(valPtr.Interface()); err != nil {
+	if err := json.Unmarshal(data, valPtr.Interface()); err != nil {
 		return nil, err
 	}
 	if t.Kind() == reflect.Ptr {
@@ -414,6 +1122,22 @@ func (s *WebSocketEndpoint) readClientMessage(conn *websocket.Conn) (any, error)
 	return valPtr.Elem().Interface(), nil
 }
 
+// handleBinaryMessage dispatches a binary frame to BinaryHandlerFunc.
+// Binary frames are ignored when no binary handler is configured.
+func (s *WebSocketEndpoint) handleBinaryMessage(data []byte, ctx *WebSocketContext) error {
+	if s.BinaryHandlerFunc == nil {
+		return nil
+	}
+	resp, err := s.BinaryHandlerFunc(data, ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	return ctx.SendBinary(resp)
+}
+
 func (s *WebSocketEndpoint) handleMessage(message any, ctx *WebSocketContext) (any, error) {
 	if len(s.MessageHandlers) > 0 {
 		msgType, payload, err := s.extractMessageType(message)
@@ -421,6 +1145,9 @@ func (s *WebSocketEndpoint) handleMessage(message any, ctx *WebSocketContext) (a
 			return nil, err
 		}
 		handler := s.MessageHandlers[msgType]
+		if handler == nil && s.isHeartbeatMessageType(msgType) {
+			return nil, nil
+		}
 		if handler == nil {
 			return nil, fmt.Errorf("websocket handler not found for message type: %s", msgType)
 		}
@@ -432,6 +1159,13 @@ func (s *WebSocketEndpoint) handleMessage(message any, ctx *WebSocketContext) (a
 	return s.HandlerFunc(message, ctx)
 }
 
+func (s *WebSocketEndpoint) isHeartbeatMessageType(msgType string) bool {
+	if msgType == WebSocketHeartbeatMessageType {
+		return true
+	}
+	return s.HeartbeatMessageType != "" && msgType == s.HeartbeatMessageType
+}
+
 func (s *WebSocketEndpoint) extractMessageType(message any) (string, json.RawMessage, error) {
 	if s.MessageTypeGetter != nil {
 		return s.MessageTypeGetter(message)
@@ -5,6 +5,36 @@ import (
 	"strings"
 )
 
+func validateWebSocketStateMachine(meta WebSocketEndpointMeta) error {
+	sm := meta.StateMachine
+	if sm == nil {
+		return nil
+	}
+	if strings.TrimSpace(sm.Initial) == "" {
+		return fmt.Errorf("state machine initial state is required")
+	}
+	known := map[string]bool{}
+	for _, t := range meta.MessageTypes {
+		known[strings.TrimSpace(t)] = true
+	}
+	for state, types := range sm.Allowed {
+		for _, t := range types {
+			if len(known) > 0 && !known[t] {
+				return fmt.Errorf("state %q allows unknown message type %q", state, t)
+			}
+		}
+	}
+	for t, next := range sm.Transitions {
+		if len(known) > 0 && !known[t] {
+			return fmt.Errorf("state transition on unknown message type %q", t)
+		}
+		if strings.TrimSpace(next) == "" {
+			return fmt.Errorf("state transition on %q has an empty target state", t)
+		}
+	}
+	return nil
+}
+
 func validateWebSocketPayloadTypeMappings(meta WebSocketEndpointMeta) error {
 	if len(meta.MessageTypes) == 0 {
 		return nil
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"sort"
+)
+
+// utf8BOM lets Excel detect UTF-8 when opening a CSV file.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+/**
+ * @param dicts 字典数组
+ * @return 所有字典键的并集，按字典序排列
+ */
+func csvColumnsOf(dicts []StringDict) []string {
+	seen := map[string]struct{}{}
+	columns := make([]string, 0)
+	for _, dict := range dicts {
+		for key := range dict {
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			columns = append(columns, key)
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+/**
+ * 将字典数组转换为 CSV：第一行为列名，之后每个字典一行；包含逗号、引号或换行的字段会被自动加引号。
+ * @param dicts 字典数组，缺少的列写为空字符串
+ * @param columnOrder 列顺序；为空时使用所有字典键的并集并按字典序排列
+ * @return CSV 内容
+ */
+func DictsToCSV(dicts []StringDict, columnOrder []string) ([]byte, error) {
+	return dictsToCSV(dicts, columnOrder, false)
+}
+
+/**
+ * 与 DictsToCSV 相同，但输出以 UTF-8 BOM 开头，便于 Excel 正确识别非 ASCII 文本。
+ * @param dicts 字典数组，缺少的列写为空字符串
+ * @param columnOrder 列顺序；为空时使用所有字典键的并集并按字典序排列
+ * @return CSV 内容
+ */
+func DictsToCSVWithBOM(dicts []StringDict, columnOrder []string) ([]byte, error) {
+	return dictsToCSV(dicts, columnOrder, true)
+}
+
+func dictsToCSV(dicts []StringDict, columnOrder []string, withBOM bool) ([]byte, error) {
+	columns := columnOrder
+	if len(columns) == 0 {
+		columns = csvColumnsOf(dicts)
+	}
+	var buffer bytes.Buffer
+	if withBOM {
+		buffer.Write(utf8BOM)
+	}
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(columns))
+	for _, dict := range dicts {
+		for i, column := range columns {
+			record[i] = dict[column]
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+/**
+ * @param path 要写入的文件路径
+ * @param dicts 字典数组
+ * @param columnOrder 列顺序；为空时使用所有字典键的并集并按字典序排列
+ * @return 错误信息
+ */
+func WriteDictsToCSVFile(path string, dicts []StringDict, columnOrder []string) error {
+	return writeDictsToCSVFile(path, dicts, columnOrder, false)
+}
+
+/**
+ * 与 WriteDictsToCSVFile 相同，但文件以 UTF-8 BOM 开头，适合用 Excel 打开。
+ * @param path 要写入的文件路径
+ * @param dicts 字典数组
+ * @param columnOrder 列顺序；为空时使用所有字典键的并集并按字典序排列
+ * @return 错误信息
+ */
+func WriteDictsToCSVFileWithBOM(path string, dicts []StringDict, columnOrder []string) error {
+	return writeDictsToCSVFile(path, dicts, columnOrder, true)
+}
+
+func writeDictsToCSVFile(path string, dicts []StringDict, columnOrder []string, withBOM bool) error {
+	data, err := dictsToCSV(dicts, columnOrder, withBOM)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
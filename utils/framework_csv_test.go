@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestDictsToCSVRoundTrip
+// 这个测试验证字典与 CSV 的往返：
+// 1) 未指定列顺序时使用排序后的键并集，缺少的列写为空字符串。
+// 2) 含逗号、引号与换行的字段解析后保持原值。
+// 3) 指定列顺序时按该顺序输出；WithBOM 变体输出以 UTF-8 BOM 开头，默认版本不带 BOM。
+func TestDictsToCSVRoundTrip(t *testing.T) {
+	dicts := []StringDict{
+		{"name": "Alice", "note": "likes, commas"},
+		{"name": "Bob \"B\"", "city": "上海\n浦东"},
+	}
+	data, err := DictsToCSV(dicts, nil)
+	if err != nil {
+		t.Fatalf("DictsToCSV returned error: %v", err)
+	}
+	if bytes.HasPrefix(data, utf8BOM) {
+		t.Fatalf("expected DictsToCSV to write no BOM")
+	}
+	if withBOM, err := DictsToCSVWithBOM(dicts, nil); err != nil || !bytes.Equal(withBOM, append(append([]byte{}, utf8BOM...), data...)) {
+		t.Fatalf("expected DictsToCSVWithBOM to prefix the same CSV with a BOM, got %q (%v)", withBOM, err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv failed: %v", err)
+	}
+	expected := [][]string{
+		{"city", "name", "note"},
+		{"", "Alice", "likes, commas"},
+		{"上海\n浦东", "Bob \"B\"", ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("unexpected rows:\n got: %q\nwant: %q", rows, expected)
+	}
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	if err := WriteDictsToCSVFileWithBOM(path, dicts, []string{"note", "name"}); err != nil {
+		t.Fatalf("WriteDictsToCSVFileWithBOM returned error: %v", err)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read csv file failed: %v", err)
+	}
+	if !bytes.HasPrefix(written, utf8BOM) {
+		t.Fatalf("expected UTF-8 BOM prefix, got %q", written[:3])
+	}
+	rows, err = csv.NewReader(bytes.NewReader(bytes.TrimPrefix(written, utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv file failed: %v", err)
+	}
+	if rows[0][0] != "note" || rows[0][1] != "name" || rows[1][0] != "likes, commas" || rows[2][1] != "Bob \"B\"" {
+		t.Fatalf("unexpected ordered rows: %q", rows)
+	}
+}
@@ -3,9 +3,9 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	re "github.com/dlclark/regexp2"
 
@@ -85,6 +85,7 @@ func RowsToDict1(rows [][]string, headerIndex int, dataIndex int) [](StringDict)
 }
 
 /**
+ * 会一次性把整张表读入内存，适合小文件；大文件请使用 StreamFirstSheet 逐行处理。
  * @param path Excel文件路径
  * @return 转换后的字符串字典数组
  */
@@ -105,6 +106,44 @@ func ReadFirstSheetRaw(path string) ([][]string, error) {
 	return rows, err
 }
 
+/**
+ * 逐行读取第一个工作表，不会把整张表缓存在内存中，适合几十万行的大文件。
+ * fn 返回错误时立即停止读取并返回该错误；迭代器与文件总会被关闭。
+ * @param path Excel文件路径
+ * @param fn 每一行的处理函数
+ * @return 错误信息
+ */
+func StreamFirstSheet(path string, fn func(row []string) error) (err error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	rows, err := f.Rows(f.GetSheetName(0))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	for rows.Next() {
+		row, err := rows.Columns()
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Error()
+}
+
 /**
  * @param path Excel文件路径
  * @param headerIndex 标题行索引
@@ -112,22 +151,71 @@ func ReadFirstSheetRaw(path string) ([][]string, error) {
  * @return 转换后的字符串字典数组
  */
 func ReadFirstSheet1(path string, headerIndex int, dataIndex int) ([](StringDict), error) {
-	f, err := excelize.OpenFile(path)
+	return ReadSheetByIndex(path, 0, headerIndex, dataIndex)
+}
+
+/**
+ * @param path Excel文件路径
+ * @param sheetName 工作表名称，不存在时返回错误
+ * @param headerIndex 标题行索引
+ * @param dataIndex 数据行索引
+ * @return 转换后的字符串字典数组
+ */
+func ReadSheetByName(path string, sheetName string, headerIndex int, dataIndex int) ([](StringDict), error) {
+	return readSheetDict(path, func(f *excelize.File) (string, error) {
+		if index, err := f.GetSheetIndex(sheetName); err != nil || index < 0 {
+			return "", fmt.Errorf("工作表 %q 不存在", sheetName)
+		}
+		return sheetName, nil
+	}, headerIndex, dataIndex)
+}
+
+/**
+ * @param path Excel文件路径
+ * @param sheetIndex 工作表索引（从 0 开始），超出范围时返回错误
+ * @param headerIndex 标题行索引
+ * @param dataIndex 数据行索引
+ * @return 转换后的字符串字典数组
+ */
+func ReadSheetByIndex(path string, sheetIndex int, headerIndex int, dataIndex int) ([](StringDict), error) {
+	return readSheetDict(path, func(f *excelize.File) (string, error) {
+		names := f.GetSheetList()
+		if sheetIndex < 0 || sheetIndex >= len(names) {
+			return "", fmt.Errorf("工作表索引 %d 超出范围（共 %d 个工作表）", sheetIndex, len(names))
+		}
+		return names[sheetIndex], nil
+	}, headerIndex, dataIndex)
+}
+
+/**
+ * @param path Excel文件路径
+ * @param resolveSheet 根据文件确定要读取的工作表名称
+ * @param headerIndex 标题行索引
+ * @param dataIndex 数据行索引
+ * @return 转换后的字符串字典数组
+ */
+func readSheetDict(path string, resolveSheet func(f *excelize.File) (string, error), headerIndex int, dataIndex int) ([](StringDict), error) {
 	var fakeResult = make([]StringDict, 0) // 用于返回结果
+	f, err := excelize.OpenFile(path)
 	if err != nil {
-		fmt.Println(err)
 		return fakeResult, err
 	}
-	defer func() {
-		if closeErr := f.Close(); closeErr != nil {
-			fmt.Println(err)
-		}
-	}()
-	// 获取 Sheet1 上所有单元格
-	sheets := f.WorkBook.Sheets.Sheet[0]
-	rows, err := f.GetRows(sheets.Name)
-	rowsData := RowsToDict1(rows, 0, 1)
-	return rowsData, err
+	defer f.Close()
+	sheet, err := resolveSheet(f)
+	if err != nil {
+		return fakeResult, err
+	}
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return fakeResult, err
+	}
+	if len(rows) == 0 {
+		return fakeResult, nil // 空工作表返回空结果
+	}
+	if headerIndex < 0 || headerIndex >= len(rows) {
+		return fakeResult, fmt.Errorf("标题行索引 %d 超出范围（共 %d 行）", headerIndex, len(rows))
+	}
+	return RowsToDict1(rows, headerIndex, dataIndex), nil
 }
 
 /**
@@ -155,6 +243,149 @@ func ReadFirstSheet2(path string, colNameExamples []string, dataIndexOffset int)
 	return rowsData, err
 }
 
+/**
+ * 读取第一个工作表并按单元格类型转换：日期格式的单元格返回 time.Time，数字返回 float64，其余保持字符串。
+ * 日期按工作簿的 1900（含 Excel 的 1900 闰年问题）或 1904 纪元换算。
+ * @param path Excel文件路径
+ * @param colNameExamples 列名示例，用于确定标题行的位置
+ * @param dataIndexOffset 数据行索引的偏移量，用于确定数据行的位置
+ * @return 转换后的字典数组
+ */
+func ReadFirstSheetTyped(path string, colNameExamples []string, dataIndexOffset int) ([]map[string]any, error) {
+	result := make([]map[string]any, 0)
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return result, err
+	}
+	defer f.Close()
+	props, err := f.GetWorkbookProps()
+	if err != nil {
+		return result, err
+	}
+	date1904 := props.Date1904 != nil && *props.Date1904
+	sheet := f.GetSheetName(0)
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return result, err
+	}
+	headerIndex := -1
+	for i, rowData := range rows {
+		if allExamplesIncluded(colNameExamples, rowData) {
+			headerIndex = i
+			break
+		}
+	}
+	if headerIndex == -1 {
+		return result, errors.New("未找到标题行")
+	}
+	colNames := rows[headerIndex]
+	for i := headerIndex + dataIndexOffset + 1; i < len(rows); i++ {
+		rowResult := map[string]any{}
+		for j, text := range rows[i] {
+			if j >= len(colNames) {
+				break
+			}
+			value, err := typedCellValue(f, sheet, Address(j+1, i+1), text, date1904)
+			if err != nil {
+				return result, err
+			}
+			rowResult[colNames[j]] = value
+		}
+		result = append(result, rowResult)
+	}
+	return result, nil
+}
+
+/**
+ * @param f Excel文件对象
+ * @param sheet 工作表名称
+ * @param address 单元格地址
+ * @param text 单元格格式化后的文本
+ * @param date1904 工作簿是否使用 1904 纪元
+ * @return time.Time、float64 或原文本
+ */
+func typedCellValue(f *excelize.File, sheet string, address string, text string, date1904 bool) (any, error) {
+	cellType, err := f.GetCellType(sheet, address)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := f.GetCellValue(sheet, address, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, err
+	}
+	switch cellType {
+	case excelize.CellTypeDate:
+		if t, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+			return t, nil
+		}
+		return text, nil
+	case excelize.CellTypeNumber, excelize.CellTypeUnset:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return text, nil
+		}
+		isDate, err := isDateCell(f, sheet, address)
+		if err != nil {
+			return nil, err
+		}
+		if isDate {
+			return excelize.ExcelDateToTime(number, date1904)
+		}
+		return number, nil
+	default:
+		return text, nil
+	}
+}
+
+/**
+ * 判断单元格的数字格式是否为日期/时间格式（内置日期格式 ID 或包含 y/m/d/h/s 的自定义格式）。
+ * @param f Excel文件对象
+ * @param sheet 工作表名称
+ * @param address 单元格地址
+ * @return 是否为日期格式
+ */
+func isDateCell(f *excelize.File, sheet string, address string) (bool, error) {
+	styleID, err := f.GetCellStyle(sheet, address)
+	if err != nil || styleID == 0 {
+		return false, err
+	}
+	style, err := f.GetStyle(styleID)
+	if err != nil {
+		return false, err
+	}
+	if style.CustomNumFmt != nil {
+		return isDateNumFmt(*style.CustomNumFmt), nil
+	}
+	id := style.NumFmt
+	return (id >= 14 && id <= 22) || (id >= 27 && id <= 36) || (id >= 45 && id <= 47) || (id >= 50 && id <= 58), nil
+}
+
+/**
+ * @param format 自定义数字格式
+ * @return 去掉引号内文本、转义字符与颜色/条件段后是否包含日期时间占位符
+ */
+func isDateNumFmt(format string) bool {
+	inQuote, inBracket := false, false
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		case strings.ContainsRune("yYmMdDhHsS", rune(c)):
+			return true
+		}
+	}
+	return false
+}
+
 /**
  * @param rows 二维字符串数组，每一行表示Excel中的一行数据
  * @param keywords 关键词数组，用于确定标题行的位置
@@ -176,109 +407,139 @@ func HeaderIndexByKeywords(rows [][]string, keywords []string) (int, []string, e
 }
 
 /**
- * @param number 要转换的数字
- * @param base 转换的基数
- * @return 转换后的结果
- */
-func mathLog(number float64, base float64) float64 {
-	return math.Log(number) / math.Log(base)
-}
-
-/**
- * @param index 要转换的数字
- * @return 转换后的结果
+ * 列号转列名（从 1 开始的双射 26 进制）：1 → A，26 → Z，27 → AA，702 → ZZ，703 → AAA。
+ * @param index 列号，从 1 开始；小于 1 时返回空字符串
+ * @return 列名
  */
 func IntToCol(index int) string {
-	num := float64(index)
-	howMany := math.Floor(mathLog(num, 26)) // 有多少个 - 1
-	chars := make([]string, 0)              // 所有的字符
-	result := ""
-	for i := howMany; i >= 0; i-- {
-		integer := math.Round(num / math.Pow(26, i))
-		num = math.Mod(num, math.Round(math.Pow(26, i)))
-		chars = append(chars, string(rune(integer+64)))
-	}
-	for _, char := range chars {
-		result += char
+	chars := make([]byte, 0, 3)
+	for n := index; n > 0; n = (n - 1) / 26 {
+		chars = append(chars, byte('A'+(n-1)%26))
 	}
-	return result
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+	return string(chars)
 }
 
 /**
+ * 列名转列号，是 IntToCol 的逆运算（从 1 开始）：A → 1，Z → 26，AA → 27；不区分大小写。
  * @param colName 要转换的列名
- * @return 转换后的结果
+ * @return 列号；包含非字母字符时返回 0
  */
 func ColToInt(colName string) int {
-	newStr := strings.ToUpper(colName)
-	length := len(newStr)
 	res := 0
-	for index, char := range newStr {
-		charCode := int(char) - 64
-		toAdd := charCode * int(math.Pow(26, float64(length)-float64(index)-1))
-		res = res + toAdd
+	for _, char := range strings.ToUpper(colName) {
+		if char < 'A' || char > 'Z' {
+			return 0
+		}
+		res = res*26 + int(char-'A'+1)
 	}
 	return res
 }
 
 /**
- * @param col 要转换的数字
- * @param row 要转换的数字
- * @return 转换后的结果
+ * @param col 列号，从 1 开始（1 → A）
+ * @param row 行号，从 1 开始
+ * @return 单元格地址，如 Address(27, 3) → AA3
  */
 func Address(col int, row int) string {
 	return IntToCol(col) + strconv.FormatInt(int64(row), 10)
 }
 
+// 单元格引用：可选的 Sheet! 或 'Sheet 1'! 前缀，列与行前可带 $（绝对引用）
+const cellRefPattern = `\$?([A-Za-z]+)\$?([0-9]+)`
+const sheetPrefixPattern = `(?:'((?:[^']|'')+)'|([^'!]+))!`
+
+var (
+	fullAddressRegex = re.MustCompile(`^(?:`+sheetPrefixPattern+`)?`+cellRefPattern+`$`, 0)
+	rangeRegex       = re.MustCompile(`^(?:`+sheetPrefixPattern+`)?`+cellRefPattern+`(?::`+cellRefPattern+`)?$`, 0)
+)
+
 /**
- * @param address 要解析的地址
- * @return 解析后的结果
+ * @param match 引用的匹配结果
+ * @return 前缀中的工作表名称（去掉引号），没有前缀时为空字符串
  */
-func ParseAddress(address string) (int, int, error) {
-	col := 0
-	row := 0
+func sheetNameOfMatch(match *re.Match) string {
+	if quoted := match.GroupByNumber(1).String(); quoted != "" {
+		return strings.ReplaceAll(quoted, "''", "'")
+	}
+	return match.GroupByNumber(2).String()
+}
 
-	// 匹配列名部分
-	regexCompiled := re.MustCompile(`[A-Za-z]+`, 0)
-	matches := make([]string, 0)
-	match, err := regexCompiled.FindStringMatch(address)
+/**
+ * @param colName 列名部分
+ * @param rowText 行号部分
+ * @return 列号与行号（均从 1 开始）
+ */
+func parseCellParts(colName string, rowText string) (int, int, error) {
+	col := ColToInt(colName)
+	row, err := strconv.Atoi(rowText)
 	if err != nil {
-		return 0, 0, fmt.Errorf("匹配列名时出错: %w", err)
-	}
-	for match != nil {
-		matches = append(matches, match.String())
-		match, err = regexCompiled.FindNextMatch(match)
-		if err != nil {
-			return 0, 0, fmt.Errorf("匹配列名时出错: %w", err)
-		}
+		return 0, 0, fmt.Errorf("转换行号为整数时出错: %w", err)
 	}
-	if len(matches) == 0 {
-		return 0, 0, errors.New("未找到列名部分")
+	if col < 1 || row < 1 {
+		return 0, 0, fmt.Errorf("无效的单元格: %s%s", colName, rowText)
 	}
-	col = ColToInt(matches[0])
+	return col, row, nil
+}
 
-	// 匹配行号部分
-	regexCompiled = re.MustCompile(`[0-9]+`, 0)
-	matches = make([]string, 0)
-	match, err = regexCompiled.FindStringMatch(address)
+/**
+ * 解析单元格地址，支持绝对引用（$A$1）与工作表前缀（Sheet1!A1、'Sheet 1'!A1）。
+ * @param address 要解析的地址
+ * @return 工作表名称（没有前缀时为空字符串）、列号与行号（均从 1 开始）
+ */
+func ParseFullAddress(address string) (string, int, int, error) {
+	match, err := fullAddressRegex.FindStringMatch(strings.TrimSpace(address))
 	if err != nil {
-		return 0, 0, fmt.Errorf("匹配行号时出错: %w", err)
-	}
-	for match != nil {
-		matches = append(matches, match.String())
-		match, err = regexCompiled.FindNextMatch(match)
-		if err != nil {
-			return 0, 0, fmt.Errorf("匹配行号时出错: %w", err)
-		}
+		return "", 0, 0, fmt.Errorf("匹配单元格地址时出错: %w", err)
 	}
-	if len(matches) == 0 {
-		return 0, 0, errors.New("未找到行号部分")
+	if match == nil {
+		return "", 0, 0, fmt.Errorf("无效的单元格地址: %s", address)
 	}
-	row, err = strconv.Atoi(matches[0])
+	col, row, err := parseCellParts(match.GroupByNumber(3).String(), match.GroupByNumber(4).String())
 	if err != nil {
-		return 0, 0, fmt.Errorf("转换行号为整数时出错: %w", err)
+		return "", 0, 0, err
 	}
+	return sheetNameOfMatch(match), col, row, nil
+}
 
-	return col, row, nil
+/**
+ * 解析单元格地址，忽略 $ 与工作表前缀；需要工作表名称时使用 ParseFullAddress。
+ * @param address 要解析的地址，如 A1、$A$1、Sheet1!B2
+ * @return 列号与行号（均从 1 开始）
+ */
+func ParseAddress(address string) (int, int, error) {
+	_, col, row, err := ParseFullAddress(address)
+	return col, row, err
+}
+
+/**
+ * 解析单元格区域，如 A1:C3、$A$1:$C$3、Sheet1!A1:C3；单个单元格视为只包含它自己的区域。
+ * 起止顺序颠倒时（C3:A1）会被规整为左上到右下。
+ * @param ref 要解析的区域
+ * @return 起始列、起始行、结束列、结束行（均从 1 开始）
+ */
+func ParseRange(ref string) (startCol, startRow, endCol, endRow int, err error) {
+	match, err := rangeRegex.FindStringMatch(strings.TrimSpace(ref))
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("匹配单元格区域时出错: %w", err)
+	}
+	if match == nil {
+		return 0, 0, 0, 0, fmt.Errorf("无效的单元格区域: %s", ref)
+	}
+	startCol, startRow, err = parseCellParts(match.GroupByNumber(3).String(), match.GroupByNumber(4).String())
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	endCol, endRow = startCol, startRow
+	if match.GroupByNumber(5).String() != "" {
+		endCol, endRow, err = parseCellParts(match.GroupByNumber(5).String(), match.GroupByNumber(6).String())
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+	}
+	return min(startCol, endCol), min(startRow, endRow), max(startCol, endCol), max(startRow, endRow), nil
 }
 
 /**
@@ -313,6 +574,54 @@ func Write2[DataType any](wb *excelize.File, sheet string, colName string, rowIn
 	return Write1(wb, sheet, ColToInt(colName), rowIndex, values)
 }
 
+/**
+ * 写入数据并为写入的单元格统一设置样式（样式只通过 NewStyle 创建一次）。
+ * @param wb Excel文件对象
+ * @param sheet 工作表名称
+ * @param colIndex 列索引
+ * @param rowIndex 行索引
+ * @param values 要写入的值
+ * @param style 要应用的样式，如 &excelize.Style{NumFmt: 4}；为 nil 时等同于 Write1
+ * @return 错误数组
+ */
+func WriteWithStyle[DataType any](wb *excelize.File, sheet string, colIndex int, rowIndex int, values [][]DataType, style *excelize.Style) []error {
+	errs := Write1(wb, sheet, colIndex, rowIndex, values)
+	if style == nil {
+		return errs
+	}
+	styleID, err := wb.NewStyle(style)
+	if err != nil {
+		return append(errs, err)
+	}
+	for rowPlus, row := range values {
+		if len(row) == 0 {
+			continue
+		}
+		start := Address(colIndex, rowPlus+rowIndex)
+		end := Address(colIndex+len(row)-1, rowPlus+rowIndex)
+		errs = append(errs, wb.SetCellStyle(sheet, start, end, styleID))
+	}
+	return errs
+}
+
+/**
+ * 从第一列开始写入加粗的标题行，并冻结该行及其上方的行。
+ * @param wb Excel文件对象
+ * @param sheet 工作表名称
+ * @param rowIndex 标题所在行索引
+ * @param headers 标题
+ * @return 错误数组
+ */
+func WriteHeaderRow(wb *excelize.File, sheet string, rowIndex int, headers []string) []error {
+	errs := WriteWithStyle(wb, sheet, 1, rowIndex, [][]string{headers}, &excelize.Style{Font: &excelize.Font{Bold: true}})
+	return append(errs, wb.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      rowIndex,
+		TopLeftCell: Address(1, rowIndex+1),
+		ActivePane:  "bottomLeft",
+	}))
+}
+
 /**
  * @param wb Excel文件对象
  * @param sheet 工作表名称
@@ -0,0 +1,305 @@
+package utils
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/xuri/excelize/v2"
+)
+
+// TestIntToColRoundTrip
+// 这个测试验证列号与列名互转：
+// 1) 边界列 A/Z/AA/AZ/BA/ZZ/AAA 的转换正确。
+// 2) 1..10000 范围内 ColToInt(IntToCol(n)) == n。
+// 3) Address 使用同一套从 1 开始的约定。
+func TestIntToColRoundTrip(t *testing.T) {
+	boundaries := map[int]string{1: "A", 26: "Z", 27: "AA", 52: "AZ", 53: "BA", 702: "ZZ", 703: "AAA"}
+	for n, col := range boundaries {
+		if got := IntToCol(n); got != col {
+			t.Fatalf("IntToCol(%d) = %q, want %q", n, got, col)
+		}
+		if got := ColToInt(col); got != n {
+			t.Fatalf("ColToInt(%q) = %d, want %d", col, got, n)
+		}
+	}
+	for n := 1; n <= 10000; n++ {
+		if got := ColToInt(IntToCol(n)); got != n {
+			t.Fatalf("ColToInt(IntToCol(%d)) = %d (col %q)", n, got, IntToCol(n))
+		}
+	}
+	if got := ColToInt("az"); got != 52 {
+		t.Fatalf("expected ColToInt to ignore case, got %d", got)
+	}
+	if got := Address(27, 3); got != "AA3" {
+		t.Fatalf("Address(27, 3) = %q, want AA3", got)
+	}
+	col, row, err := ParseAddress("AA3")
+	if err != nil || col != 27 || row != 3 {
+		t.Fatalf("ParseAddress(AA3) = %d, %d, %v", col, row, err)
+	}
+}
+
+// writeTestWorkbook 创建包含多个工作表的测试文件：Summary 在第一个，Data 在第二个。
+func writeTestWorkbook(t *testing.T) string {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	if err := f.SetSheetName("Sheet1", "Summary"); err != nil {
+		t.Fatalf("rename sheet failed: %v", err)
+	}
+	if _, err := f.NewSheet("Data"); err != nil {
+		t.Fatalf("create sheet failed: %v", err)
+	}
+	if errs := Write1(f, "Summary", 1, 1, [][]string{{"total"}, {"2"}}); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write summary failed: %v", errs)
+	}
+	rows := [][]string{{"report"}, {"name", "score"}, {"Alice", "95"}, {"Bob", "88"}}
+	if errs := Write1(f, "Data", 1, 1, rows); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write data failed: %v", errs)
+	}
+	path := filepath.Join(t.TempDir(), "multi.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("save workbook failed: %v", err)
+	}
+	return path
+}
+
+// TestReadSheetByNameAndIndex
+// 这个测试验证按名称/索引读取工作表：
+// 1) ReadSheetByName / ReadSheetByIndex 能读取非第一个工作表，并使用传入的标题行与数据行索引。
+// 2) 工作表不存在或索引越界时返回明确的错误。
+// 3) ReadFirstSheet1 不再忽略 headerIndex / dataIndex。
+// 4) 空工作表返回空结果而不是错误。
+func TestReadSheetByNameAndIndex(t *testing.T) {
+	path := writeTestWorkbook(t)
+
+	byName, err := ReadSheetByName(path, "Data", 1, 2)
+	if err != nil {
+		t.Fatalf("ReadSheetByName returned error: %v", err)
+	}
+	if len(byName) != 2 || byName[0]["name"] != "Alice" || byName[1]["score"] != "88" {
+		t.Fatalf("unexpected rows from Data sheet: %v", byName)
+	}
+	byIndex, err := ReadSheetByIndex(path, 1, 1, 2)
+	if err != nil {
+		t.Fatalf("ReadSheetByIndex returned error: %v", err)
+	}
+	if len(byIndex) != 2 || byIndex[1]["name"] != "Bob" {
+		t.Fatalf("unexpected rows from sheet index 1: %v", byIndex)
+	}
+
+	if _, err := ReadSheetByName(path, "Missing", 0, 1); err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Fatalf("expected a clear error for a missing sheet, got %v", err)
+	}
+	if _, err := ReadSheetByIndex(path, 5, 0, 1); err == nil {
+		t.Fatalf("expected an error for an out-of-range sheet index")
+	}
+
+	first, err := ReadFirstSheet1(path, 0, 1)
+	if err != nil {
+		t.Fatalf("ReadFirstSheet1 returned error: %v", err)
+	}
+	if len(first) != 1 || first[0]["total"] != "2" {
+		t.Fatalf("unexpected rows from first sheet: %v", first)
+	}
+	if _, err := ReadFirstSheet1(path, 3, 4); err == nil {
+		t.Fatalf("expected ReadFirstSheet1 to honor headerIndex")
+	}
+
+	empty := excelize.NewFile()
+	defer empty.Close()
+	emptyPath := filepath.Join(t.TempDir(), "empty.xlsx")
+	if err := empty.SaveAs(emptyPath); err != nil {
+		t.Fatalf("save workbook failed: %v", err)
+	}
+	if rows, err := ReadFirstSheet1(emptyPath, 0, 1); err != nil || len(rows) != 0 {
+		t.Fatalf("expected empty result for an empty sheet, got %v, %v", rows, err)
+	}
+}
+
+// TestStreamFirstSheet
+// 这个测试验证逐行读取第一个工作表：
+// 1) fn 按顺序逐行收到每一行。
+// 2) fn 返回错误时立即停止读取，并原样返回该错误。
+func TestStreamFirstSheet(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	rows := [][]string{{"name", "score"}, {"Alice", "95"}, {"Bob", "88"}, {"Cara", "70"}}
+	if errs := Write1(f, "Sheet1", 1, 1, rows); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write rows failed: %v", errs)
+	}
+	path := filepath.Join(t.TempDir(), "stream.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("save workbook failed: %v", err)
+	}
+
+	var names []string
+	if err := StreamFirstSheet(path, func(row []string) error {
+		names = append(names, row[0])
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamFirstSheet returned error: %v", err)
+	}
+	if strings.Join(names, ",") != "name,Alice,Bob,Cara" {
+		t.Fatalf("expected every row in order, got %v", names)
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+	err := StreamFirstSheet(path, func(row []string) error {
+		count++
+		if count == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected the sentinel error from fn, got %v", err)
+	}
+	if count != 2 {
+		t.Fatalf("expected iteration to stop after 2 rows, got %d", count)
+	}
+}
+
+// TestReadFirstSheetTyped
+// 这个测试验证按单元格类型读取：
+// 1) 日期格式的数字单元格返回 time.Time（1900 纪元）。
+// 2) 普通数字返回 float64，文本保持字符串。
+// 3) 自定义日期格式同样识别为日期。
+func TestReadFirstSheetTyped(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	shipped := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := [][]any{{"name", "amount", "shipped", "due"}, {"Alice", 12.5, shipped, 45352}}
+	if errs := Write1(f, "Sheet1", 1, 1, rows); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write rows failed: %v", errs)
+	}
+	format := "yyyy/mm/dd"
+	style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		t.Fatalf("create style failed: %v", err)
+	}
+	if err := f.SetCellStyle("Sheet1", "D2", "D2", style); err != nil {
+		t.Fatalf("set style failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "typed.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("save workbook failed: %v", err)
+	}
+
+	result, err := ReadFirstSheetTyped(path, []string{"name", "amount"}, 0)
+	if err != nil {
+		t.Fatalf("ReadFirstSheetTyped returned error: %v", err)
+	}
+	if len(result) != 1 {
+		t.Fatalf("expected one data row, got %v", result)
+	}
+	row := result[0]
+	if row["name"] != "Alice" {
+		t.Fatalf("expected text to stay a string, got %#v", row["name"])
+	}
+	if row["amount"] != 12.5 {
+		t.Fatalf("expected number as float64, got %#v", row["amount"])
+	}
+	if got, ok := row["shipped"].(time.Time); !ok || !got.Equal(shipped) {
+		t.Fatalf("expected shipped as time.Time %v, got %#v", shipped, row["shipped"])
+	}
+	if got, ok := row["due"].(time.Time); !ok || !got.Equal(shipped) {
+		t.Fatalf("expected custom date format serial 45352 as %v, got %#v", shipped, row["due"])
+	}
+}
+
+// TestParseAddressAndRange
+// 这个测试验证地址与区域解析：
+// 1) $A$1 这类绝对引用与普通地址解析结果一致。
+// 2) 带空格的工作表前缀（含引号形式）返回工作表名称。
+// 3) A1:C3 区域返回起止行列，单个单元格视为单格区域。
+// 4) 非法输入返回错误。
+func TestParseAddressAndRange(t *testing.T) {
+	col, row, err := ParseAddress("$A$1")
+	if err != nil || col != 1 || row != 1 {
+		t.Fatalf("ParseAddress($A$1) = %d, %d, %v", col, row, err)
+	}
+
+	for _, address := range []string{"Sheet 1!B2", "'Sheet 1'!$B$2"} {
+		sheet, col, row, err := ParseFullAddress(address)
+		if err != nil || sheet != "Sheet 1" || col != 2 || row != 2 {
+			t.Fatalf("ParseFullAddress(%q) = %q, %d, %d, %v", address, sheet, col, row, err)
+		}
+	}
+
+	startCol, startRow, endCol, endRow, err := ParseRange("A1:C3")
+	if err != nil || startCol != 1 || startRow != 1 || endCol != 3 || endRow != 3 {
+		t.Fatalf("ParseRange(A1:C3) = %d, %d, %d, %d, %v", startCol, startRow, endCol, endRow, err)
+	}
+	startCol, startRow, endCol, endRow, err = ParseRange("Data!$AA$10")
+	if err != nil || startCol != 27 || startRow != 10 || endCol != 27 || endRow != 10 {
+		t.Fatalf("ParseRange(Data!$AA$10) = %d, %d, %d, %d, %v", startCol, startRow, endCol, endRow, err)
+	}
+
+	for _, bad := range []string{"", "A0", "1A", "A1:B", "A1B2"} {
+		if _, _, _, _, err := ParseRange(bad); err == nil {
+			t.Fatalf("expected ParseRange(%q) to fail", bad)
+		}
+	}
+}
+
+// TestWriteWithStyleAndHeaderRow
+// 这个测试验证带样式写入：
+// 1) WriteWithStyle 写入的区域共享同一个样式 ID，且样式包含指定数字格式。
+// 2) WriteHeaderRow 写入加粗标题并冻结标题行。
+// 3) 保存后重新打开文件，样式仍然存在。
+func TestWriteWithStyleAndHeaderRow(t *testing.T) {
+	f := excelize.NewFile()
+	defer f.Close()
+	if errs := WriteHeaderRow(f, "Sheet1", 1, []string{"item", "price"}); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write header failed: %v", errs)
+	}
+	values := [][]float64{{1, 9.5}, {2, 12.25}}
+	if errs := WriteWithStyle(f, "Sheet1", 1, 2, values, &excelize.Style{NumFmt: 4}); lo.SomeBy(errs, func(err error) bool { return err != nil }) {
+		t.Fatalf("write values failed: %v", errs)
+	}
+	path := filepath.Join(t.TempDir(), "styled.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("save workbook failed: %v", err)
+	}
+
+	opened, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("open workbook failed: %v", err)
+	}
+	defer opened.Close()
+
+	first, err := opened.GetCellStyle("Sheet1", "A2")
+	if err != nil {
+		t.Fatalf("get style failed: %v", err)
+	}
+	last, err := opened.GetCellStyle("Sheet1", "B3")
+	if err != nil {
+		t.Fatalf("get style failed: %v", err)
+	}
+	if first == 0 || first != last {
+		t.Fatalf("expected one shared style over A2:B3, got %d and %d", first, last)
+	}
+	style, err := opened.GetStyle(first)
+	if err != nil || style.NumFmt != 4 {
+		t.Fatalf("expected NumFmt 4, got %+v (%v)", style, err)
+	}
+
+	headerID, err := opened.GetCellStyle("Sheet1", "B1")
+	if err != nil {
+		t.Fatalf("get header style failed: %v", err)
+	}
+	header, err := opened.GetStyle(headerID)
+	if err != nil || header.Font == nil || !header.Font.Bold {
+		t.Fatalf("expected bold header style, got %+v (%v)", header, err)
+	}
+	panes, err := opened.GetPanes("Sheet1")
+	if err != nil || !panes.Freeze || panes.YSplit != 1 || panes.TopLeftCell != "A2" {
+		t.Fatalf("expected header row frozen, got %+v (%v)", panes, err)
+	}
+}